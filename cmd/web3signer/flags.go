@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mowind/web3signer-go/internal/config"
 	"github.com/spf13/cobra"
@@ -62,42 +63,345 @@ var flags = []Flag{
 		Description:  "CORS allowed origins (comma-separated), use '*' to allow all origins, empty means localhost only",
 		BindTo:       "http.allowed-origins",
 	},
+	{
+		Name:         "cors-allowed-headers",
+		DefaultValue: []string{},
+		Description:  "CORS allowed request headers (comma-separated), empty means Content-Type and Authorization",
+		BindTo:       "http.allowed-headers",
+	},
+	{
+		Name:         "http-require-json-content-type",
+		DefaultValue: false,
+		Description:  "Reject JSON-RPC requests whose Content-Type is not application/json with 415",
+		BindTo:       "http.require-json-content-type",
+	},
+	{
+		Name:         "http-allow-missing-jsonrpc-version",
+		DefaultValue: false,
+		Description:  "Accept JSON-RPC requests without a jsonrpc field as version 2.0 (explicit other versions are still rejected)",
+		BindTo:       "http.allow-missing-jsonrpc-version",
+	},
+	{
+		Name:         "http-debug-config-enabled",
+		DefaultValue: false,
+		Description:  "Expose the redacted effective configuration at GET /debug/config (subject to the same auth as signing)",
+		BindTo:       "http.debug-config-enabled",
+	},
+	{
+		Name:         "http-response-compression-threshold",
+		DefaultValue: config.DefaultResponseCompressionThreshold,
+		Description:  "Gzip JSON-RPC responses larger than this many bytes when the client accepts gzip (0 disables)",
+		BindTo:       "http.response-compression-threshold",
+	},
+	{
+		Name:         "http-stream-batch-threshold",
+		DefaultValue: 0,
+		Description:  "Write the responses of batches with at least this many entries in request order as they complete instead of buffering the whole array (0 disables)",
+		BindTo:       "http.stream-batch-threshold",
+	},
+	{
+		Name:         "http-request-timeout",
+		DefaultValue: config.DefaultRequestTimeout,
+		Description:  "Maximum time to handle a non-signing JSON-RPC request (0 disables)",
+		BindTo:       "http.request-timeout",
+	},
+	{
+		Name:         "http-sign-request-timeout",
+		DefaultValue: time.Duration(0),
+		Description:  "Maximum time to handle a signing request, including KMS approval; eth_sendTransaction and web3signer_signRawTransaction are exempt (0 disables)",
+		BindTo:       "http.sign-request-timeout",
+	},
+	{
+		Name:         "http-method-timeouts",
+		DefaultValue: map[string]string{},
+		Description:  "Per-method handling timeouts overriding the request and sign timeouts, e.g. eth_chainId=2s,eth_getLogs=1m (0 disables)",
+		BindTo:       "http.method-timeouts",
+	},
+	{
+		Name:         "http-read-timeout",
+		DefaultValue: config.DefaultHTTPReadTimeout,
+		Description:  "Maximum time to read an entire request, including the body",
+		BindTo:       "http.read-timeout",
+	},
+	{
+		Name:         "http-read-header-timeout",
+		DefaultValue: config.DefaultHTTPReadHeaderTimeout,
+		Description:  "Maximum time to read request headers",
+		BindTo:       "http.read-header-timeout",
+	},
+	{
+		Name:         "http-write-timeout",
+		DefaultValue: config.DefaultHTTPWriteTimeout,
+		Description:  "Maximum time to write a response; must exceed the KMS approval wait for synchronous sign requests",
+		BindTo:       "http.write-timeout",
+	},
+	{
+		Name:         "http-idle-timeout",
+		DefaultValue: config.DefaultHTTPIdleTimeout,
+		Description:  "Maximum time to keep an idle keep-alive connection open",
+		BindTo:       "http.idle-timeout",
+	},
+	{
+		Name:         "http-batch-workers",
+		DefaultValue: config.DefaultBatchWorkers,
+		Description:  "Maximum workers used to process a single batch request",
+		BindTo:       "http.batch-workers",
+	},
+	{
+		Name:         "http-batch-queue-size",
+		DefaultValue: 0,
+		Description:  "Task queue buffer size per batch request (0 buffers the whole batch)",
+		BindTo:       "http.batch-queue-size",
+	},
+	{
+		Name:         "http-max-batch-workers",
+		DefaultValue: 0,
+		Description:  "Maximum batch workers across all concurrent batch requests (0 disables the cap)",
+		BindTo:       "http.max-batch-workers",
+	},
+
+	// 签名后端配置
+	{
+		Name:         "signer-backend",
+		DefaultValue: config.DefaultSignerBackend,
+		Description:  "Signing backend: kms (MPC-KMS), pkcs11 (HSM, requires a build with -tags pkcs11) or local (development only)",
+		BindTo:       "signer.backend",
+	},
+	{
+		Name:         "signer-cosmos-enabled",
+		DefaultValue: false,
+		Description:  "Expose cosmos_signAmino and cosmos_signDirect to sign Cosmos SDK transactions with the same secp256k1 key",
+		BindTo:       "signer.cosmos-enabled",
+	},
+	{
+		Name:         "signer-key-rotation-enabled",
+		DefaultValue: false,
+		Description:  "Expose web3signer_setDefaultKey to switch the default signing key to another registered key without a restart",
+		BindTo:       "signer.key-rotation-enabled",
+	},
+	{
+		Name:         "signer-chain-id",
+		DefaultValue: int64(0),
+		Description:  "Expected chain ID, checked against the downstream eth_chainId at startup (0 uses the downstream chain ID)",
+		BindTo:       "signer.chain-id",
+	},
+	{
+		Name:         "signer-chain-id-mismatch",
+		DefaultValue: config.DefaultChainIDMismatch,
+		Description:  "What to do when signer-chain-id differs from the downstream chain ID: fatal or warn",
+		BindTo:       "signer.chain-id-mismatch",
+	},
+	{
+		Name:         "signer-chain-id-policy",
+		DefaultValue: config.DefaultChainIDPolicy,
+		Description:  "With signer-chain-id-mismatch=warn, which chain ID to sign with when downstream disagrees: config, downstream or fail",
+		BindTo:       "signer.chain-id-policy",
+	},
+	{
+		Name:         "signer-chain-id-refresh-interval",
+		DefaultValue: config.DefaultChainIDRefreshInterval,
+		Description:  "How long the downstream chain ID is cached when checking it against signer-chain-id before signing (0 queries on every signature)",
+		BindTo:       "signer.chain-id-refresh-interval",
+	},
+	{
+		Name:         "signer-verify-deterministic-nonce",
+		DefaultValue: false,
+		Description:  "Reject local backend signatures that do not use the RFC 6979 deterministic nonce",
+		BindTo:       "signer.verify-deterministic-nonce",
+	},
+	{
+		Name:         "signer-sign-batch-max-items",
+		DefaultValue: config.DefaultSignBatchMaxItems,
+		Description:  "Maximum number of messages in one web3signer_signBatch request",
+		BindTo:       "signer.sign-batch-max-items",
+	},
+	{
+		Name:         "signer-sign-batch-concurrency",
+		DefaultValue: config.DefaultSignBatchConcurrency,
+		Description:  "Maximum number of messages of a web3signer_signBatch request signed at the same time",
+		BindTo:       "signer.sign-batch-concurrency",
+	},
+	{
+		Name:         "signer-allowed-key-ids",
+		DefaultValue: []string{},
+		Description:  "Key IDs clients may sign with over JSON-RPC (comma-separated), empty allows all registered keys",
+		BindTo:       "signer.allowed-key-ids",
+	},
+	{
+		Name:         "local-keystore-file",
+		DefaultValue: "",
+		Description:  "Encrypted JSON keystore for the local signer backend (development only; falls back to the WEB3SIGNER_DEV_PRIVATE_KEY environment variable)",
+		BindTo:       "signer.local.keystore-file",
+	},
+	{
+		Name:         "local-keystore-password",
+		DefaultValue: "",
+		Description:  "Password of the local signer keystore",
+		BindTo:       "signer.local.keystore-password",
+	},
+	{
+		Name:         "pkcs11-module",
+		DefaultValue: "",
+		Description:  "Path to the PKCS#11 library (required for the pkcs11 signer backend)",
+		BindTo:       "signer.pkcs11.module",
+	},
+	{
+		Name:         "pkcs11-token-label",
+		DefaultValue: "",
+		Description:  "Label of the HSM token holding the signing key",
+		BindTo:       "signer.pkcs11.token-label",
+	},
+	{
+		Name:         "pkcs11-pin",
+		DefaultValue: "",
+		Description:  "User PIN for the HSM token",
+		BindTo:       "signer.pkcs11.pin",
+	},
+	{
+		Name:         "pkcs11-key-label",
+		DefaultValue: "",
+		Description:  "CKA_LABEL of the secp256k1 signing key (required for the pkcs11 signer backend)",
+		BindTo:       "signer.pkcs11.key-label",
+	},
 
 	// MPC-KMS 配置
 	{
 		Name:         "kms-endpoint",
 		DefaultValue: "",
-		Description:  "MPC-KMS endpoint URL (required)",
+		Description:  "MPC-KMS endpoint URL (required for the kms signer backend)",
 		BindTo:       "kms.endpoint",
-		Required:     true,
+	},
+	{
+		Name:         "kms-fallback-endpoints",
+		DefaultValue: []string{},
+		Description:  "Fallback MPC-KMS endpoint URLs (comma-separated), tried in order when the primary endpoint is unreachable",
+		BindTo:       "kms.fallback-endpoints",
+	},
+	{
+		Name:         "kms-proxy",
+		DefaultValue: "",
+		Description:  "Outbound proxy URL for MPC-KMS requests (http, https or socks5; credentials as userinfo)",
+		BindTo:       "kms.proxy",
+	},
+	{
+		Name:         "kms-tls-min-version",
+		DefaultValue: "",
+		Description:  "Minimum TLS version for HTTPS connections to MPC-KMS: 1.0, 1.1, 1.2 or 1.3 (empty uses the Go default)",
+		BindTo:       "kms.tls-min-version",
+	},
+	{
+		Name:         "kms-tls-ca-file",
+		DefaultValue: "",
+		Description:  "PEM bundle of additional CA certificates trusted for MPC-KMS, e.g. a private CA",
+		BindTo:       "kms.tls-ca-file",
+	},
+	{
+		Name:         "kms-tls-insecure-skip-verify",
+		DefaultValue: false,
+		Description:  "Skip MPC-KMS TLS certificate verification (development only, never use in production)",
+		BindTo:       "kms.tls-insecure-skip-verify",
+	},
+	{
+		Name:         "kms-headers",
+		DefaultValue: map[string]string{},
+		Description:  "Static headers added to every MPC-KMS request, e.g. x-tenant-id=acme (values are never logged)",
+		BindTo:       "kms.headers",
+	},
+	{
+		Name:         "kms-signed-headers",
+		DefaultValue: []string{},
+		Description:  "Names of kms-headers entries to include in the HMAC signing string, in order (comma-separated)",
+		BindTo:       "kms.signed-headers",
 	},
 	{
 		Name:         "kms-access-key-id",
 		DefaultValue: "",
-		Description:  "MPC-KMS access key ID (required)",
+		Description:  "MPC-KMS access key ID (required for the kms signer backend)",
 		BindTo:       "kms.access-key-id",
-		Required:     true,
 	},
 	{
 		Name:         "kms-secret-key",
 		DefaultValue: "",
-		Description:  "MPC-KMS secret key (required)",
+		Description:  "MPC-KMS secret key (required for the kms signer backend)",
 		BindTo:       "kms.secret-key",
-		Required:     true,
 	},
 	{
 		Name:         "kms-key-id",
 		DefaultValue: "",
-		Description:  "MPC-KMS key ID (required)",
+		Description:  "MPC-KMS key ID (required unless --kms-discover-keys is set, in which case it selects the default key)",
 		BindTo:       "kms.key-id",
-		Required:     true,
 	},
 	{
 		Name:         "kms-address",
 		DefaultValue: "",
-		Description:  "Ethereum address managed by MPC-KMS (required)",
+		Description:  "Ethereum address managed by MPC-KMS (required unless --kms-discover-keys is set)",
 		BindTo:       "kms.address",
-		Required:     true,
+	},
+	{
+		Name:         "kms-discover-keys",
+		DefaultValue: false,
+		Description:  "Discover all keys accessible with the KMS credentials at startup and register each of them",
+		BindTo:       "kms.discover-keys",
+	},
+	{
+		Name:         "kms-max-poll-attempts",
+		DefaultValue: config.DefaultKMSMaxPollAttempts,
+		Description:  "Maximum number of status checks for a KMS approval task, regardless of poll interval",
+		BindTo:       "kms.max-poll-attempts",
+	},
+	{
+		Name:         "kms-startup-self-test",
+		DefaultValue: false,
+		Description:  "Sign a fixed test hash at startup and abort unless it recovers to the configured address",
+		BindTo:       "kms.startup-self-test",
+	},
+	{
+		Name:         "kms-verify-content-sha256",
+		DefaultValue: false,
+		Description:  "Debug: recompute Content-SHA256 from the request body actually sent to the KMS and warn if it differs from the signed one",
+		BindTo:       "kms.verify-content-sha256",
+	},
+	{
+		Name:         "kms-async-approval",
+		DefaultValue: false,
+		Description:  "Return the KMS task ID immediately when message signing requires approval instead of polling; transaction signing still waits",
+		BindTo:       "kms.async-approval",
+	},
+	{
+		Name:         "kms-allow-task-cancel",
+		DefaultValue: false,
+		Description:  "Expose web3signer_cancelTask so JSON-RPC clients can cancel pending KMS approval tasks",
+		BindTo:       "kms.allow-task-cancel",
+	},
+	{
+		Name:         "kms-approval-dedup-window",
+		DefaultValue: time.Duration(0),
+		Description:  "Reuse the pending approval task of an identical sign request (same key, message and summary) made within this window instead of creating a new task (0 disables)",
+		BindTo:       "kms.approval-dedup-window",
+	},
+	{
+		Name:         "kms-summary-max-field-length",
+		DefaultValue: config.DefaultKMSSummaryMaxFieldLength,
+		Description:  "Maximum characters per approval summary field (from, to, amount, token, remark) before truncation",
+		BindTo:       "kms.summary-max-field-length",
+	},
+	{
+		Name:         "kms-summary-eth-decimals",
+		DefaultValue: config.DefaultKMSSummaryETHDecimals,
+		Description:  "Decimals used to convert ETH approval summary amounts from wei to ETH",
+		BindTo:       "kms.summary-eth-decimals",
+	},
+	{
+		Name:         "kms-summary-amount-precision",
+		DefaultValue: 0,
+		Description:  "Maximum fractional digits of approval summary amounts, rounded half-up (0 shows all digits)",
+		BindTo:       "kms.summary-amount-precision",
+	},
+	{
+		Name:         "kms-summary-token-decimals",
+		DefaultValue: map[string]string{},
+		Description:  "Decimals of tokens shown in approval summaries as symbol=decimals pairs (e.g. USDT=6,DAI=18); amounts of unlisted tokens stay raw integers",
+		BindTo:       "kms.summary-token-decimals",
 	},
 
 	// 下游服务配置
@@ -119,6 +423,292 @@ var flags = []Flag{
 		Description:  "Downstream HTTP service path",
 		BindTo:       "downstream.http-path",
 	},
+	{
+		Name:         "downstream-health-check-method",
+		DefaultValue: config.DefaultDownstreamHealthCheckMethod,
+		Description:  "JSON-RPC method (called without params) used to check the downstream node in the connection test and /ready probe, e.g. eth_blockNumber or net_version",
+		BindTo:       "downstream.health-check-method",
+	},
+	{
+		Name:         "downstream-rpc-health-check-interval",
+		DefaultValue: time.Duration(0),
+		Description:  "Periodically call downstream-health-check-method on the connection used to fill nonces and fees, and recreate it when the call fails (0 disables)",
+		BindTo:       "downstream.rpc-health-check-interval",
+	},
+	{
+		Name:         "downstream-max-retries",
+		DefaultValue: config.DefaultDownstreamMaxRetries,
+		Description:  "Maximum retries with jittered backoff for batch forwarding on connection errors (0 disables)",
+		BindTo:       "downstream.max-retries",
+	},
+	{
+		Name:         "downstream-request-timeout",
+		DefaultValue: config.DefaultDownstreamRequestTimeout,
+		Description:  "Timeout for a single downstream request (the shorter of this and the caller's deadline applies)",
+		BindTo:       "downstream.request-timeout",
+	},
+	{
+		Name:         "downstream-send-raw-transaction-timeout",
+		DefaultValue: config.DefaultDownstreamSendRawTxTimeout,
+		Description:  "Timeout for downstream requests containing eth_sendRawTransaction",
+		BindTo:       "downstream.send-raw-transaction-timeout",
+	},
+	{
+		Name:         "downstream-cache-ttl",
+		DefaultValue: time.Duration(0),
+		Description:  "Cache downstream results of immutable queries (eth_chainId, eth_getBlockByHash, mined eth_getTransactionReceipt) for this long (0 disables)",
+		BindTo:       "downstream.cache-ttl",
+	},
+	{
+		Name:         "downstream-cache-size",
+		DefaultValue: config.DefaultDownstreamCacheSize,
+		Description:  "Maximum number of cached downstream responses",
+		BindTo:       "downstream.cache-size",
+	},
+	{
+		Name:         "downstream-max-response-bytes",
+		DefaultValue: config.DefaultDownstreamMaxResponseBytes,
+		Description:  "Maximum size in bytes of a downstream response body; raise it for large eth_getLogs or debug_trace* results",
+		BindTo:       "downstream.max-response-bytes",
+	},
+	{
+		Name:         "downstream-fee-history-cache-interval",
+		DefaultValue: time.Duration(0),
+		Description:  "Refresh a local eth_feeHistory cache from downstream at this interval and serve covered queries from it (0 disables)",
+		BindTo:       "downstream.fee-history-cache-interval",
+	},
+	{
+		Name:         "downstream-fee-history-cache-max-age",
+		DefaultValue: time.Duration(0),
+		Description:  "Stop serving cached eth_feeHistory results older than this and forward live instead (defaults to twice the refresh interval)",
+		BindTo:       "downstream.fee-history-cache-max-age",
+	},
+	{
+		Name:         "downstream-fee-history-cache-blocks",
+		DefaultValue: config.DefaultFeeHistoryCacheBlocks,
+		Description:  "Number of most recent blocks kept in the eth_feeHistory cache",
+		BindTo:       "downstream.fee-history-cache-blocks",
+	},
+	{
+		Name:         "downstream-fee-history-cache-percentiles",
+		DefaultValue: []string{},
+		Description:  "Reward percentiles fetched into the eth_feeHistory cache; queries asking for other percentiles are forwarded (e.g. 10,50,90)",
+		BindTo:       "downstream.fee-history-cache-percentiles",
+	},
+	{
+		Name:         "downstream-proxy",
+		DefaultValue: "",
+		Description:  "Outbound proxy URL for forwarded downstream requests (http, https or socks5; credentials as userinfo)",
+		BindTo:       "downstream.proxy",
+	},
+	{
+		Name:         "downstream-tls-min-version",
+		DefaultValue: "",
+		Description:  "Minimum TLS version for HTTPS connections to downstream: 1.0, 1.1, 1.2 or 1.3 (empty uses the Go default)",
+		BindTo:       "downstream.tls-min-version",
+	},
+	{
+		Name:         "downstream-tls-ca-file",
+		DefaultValue: "",
+		Description:  "PEM bundle of additional CA certificates trusted for downstream, e.g. a private CA",
+		BindTo:       "downstream.tls-ca-file",
+	},
+	{
+		Name:         "downstream-tls-insecure-skip-verify",
+		DefaultValue: false,
+		Description:  "Skip downstream TLS certificate verification (development only, never use in production)",
+		BindTo:       "downstream.tls-insecure-skip-verify",
+	},
+	{
+		Name:         "downstream-headers",
+		DefaultValue: map[string]string{},
+		Description:  "Static headers added to every forwarded downstream request, e.g. x-api-key=secret (values are never logged)",
+		BindTo:       "downstream.headers",
+	},
+
+	// 交易填充配置
+	{
+		Name:         "tx-fee-history-enabled",
+		DefaultValue: false,
+		Description:  "Use eth_feeHistory to fill EIP-1559 fees instead of eth_gasPrice",
+		BindTo:       "transaction.fee-history-enabled",
+	},
+	{
+		Name:         "tx-fee-history-blocks",
+		DefaultValue: config.DefaultFeeHistoryBlocks,
+		Description:  "Number of recent blocks sampled by eth_feeHistory",
+		BindTo:       "transaction.fee-history-blocks",
+	},
+	{
+		Name:         "tx-fee-history-percentile",
+		DefaultValue: config.DefaultFeeHistoryPercentile,
+		Description:  "Reward percentile (0-100) used for maxPriorityFeePerGas",
+		BindTo:       "transaction.fee-history-percentile",
+	},
+	{
+		Name:         "tx-base-fee-multiplier",
+		DefaultValue: config.DefaultBaseFeeMultiplier,
+		Description:  "Multiplier applied to the base fee when computing maxFeePerGas",
+		BindTo:       "transaction.base-fee-multiplier",
+	},
+	{
+		Name:         "tx-idempotency-ttl-seconds",
+		DefaultValue: config.DefaultIdempotencyTTLSeconds,
+		Description:  "How long eth_sendTransaction results are remembered per idempotency key (0 disables idempotency keys)",
+		BindTo:       "transaction.idempotency-ttl-seconds",
+	},
+	{
+		Name:         "tx-idempotency-cache-size",
+		DefaultValue: config.DefaultIdempotencyCacheSize,
+		Description:  "Maximum number of idempotency keys kept in memory (0 disables idempotency keys)",
+		BindTo:       "transaction.idempotency-cache-size",
+	},
+	{
+		Name:         "tx-replay-ttl-seconds",
+		DefaultValue: 0,
+		Description:  "Window in which a resubmitted identical transaction from the same sender returns the original hash without signing or forwarding (0 disables)",
+		BindTo:       "transaction.replay-ttl-seconds",
+	},
+	{
+		Name:         "tx-replay-cache-size",
+		DefaultValue: config.DefaultReplayCacheSize,
+		Description:  "Maximum number of forwarded transactions kept for replay detection",
+		BindTo:       "transaction.replay-cache-size",
+	},
+	{
+		Name:         "tx-pending-tx-ttl-seconds",
+		DefaultValue: 0,
+		Description:  "Window in which eth_getTransactionByHash returns a pending result for a just-sent transaction the downstream node does not know yet (0 disables)",
+		BindTo:       "transaction.pending-tx-ttl-seconds",
+	},
+	{
+		Name:         "tx-pending-tx-cache-size",
+		DefaultValue: config.DefaultPendingTxCacheSize,
+		Description:  "Maximum number of sent transactions kept for eth_getTransactionByHash correlation",
+		BindTo:       "transaction.pending-tx-cache-size",
+	},
+	{
+		Name:         "tx-nonce-gap-check-interval",
+		DefaultValue: time.Duration(0),
+		Description:  "Interval for comparing the highest broadcast nonce with the downstream pending nonce; gaps are only reported, never reset (0 disables)",
+		BindTo:       "transaction.nonce-gap-check-interval",
+	},
+	{
+		Name:         "tx-nonce-gap-threshold",
+		DefaultValue: config.DefaultNonceGapThreshold,
+		Description:  "Log a warning when a nonce gap persists longer than this",
+		BindTo:       "transaction.nonce-gap-threshold",
+	},
+	{
+		Name:         "tx-nonce-gap-limit",
+		DefaultValue: 0,
+		Description:  "Reject transactions whose nonce is this many or more ahead of the confirmed nonce (0 disables the limit)",
+		BindTo:       "transaction.nonce-gap-limit",
+	},
+	{
+		Name:         "tx-sign-cache-size",
+		DefaultValue: 0,
+		Description:  "Cache up to this many eth_sign signatures by key and message; only safe with deterministic signing (0 disables)",
+		BindTo:       "transaction.sign-cache-size",
+	},
+	{
+		Name:         "tx-max-gas-limit",
+		DefaultValue: int64(0),
+		Description:  "Reject transactions whose gas limit exceeds this value, e.g. the block gas limit (0 disables)",
+		BindTo:       "transaction.max-gas-limit",
+	},
+	{
+		Name:         "tx-strict-eip712-domain",
+		DefaultValue: false,
+		Description:  "Reject EIP-712 typed data whose domain omits chainId",
+		BindTo:       "transaction.strict-eip712-domain",
+	},
+	{
+		Name:         "tx-inject-call-from",
+		DefaultValue: false,
+		Description:  "Use the signer's address as 'from' for eth_call requests that omit it",
+		BindTo:       "transaction.inject-call-from",
+	},
+	{
+		Name:         "tx-signature-format",
+		DefaultValue: "compact",
+		Description:  "eth_sign signature output format (compact, rsv)",
+		BindTo:       "transaction.signature-format",
+	},
+	{
+		Name:         "tx-default-type",
+		DefaultValue: config.TxTypeAuto,
+		Description:  "Transaction type for signing requests: auto (inferred from the fee fields), legacy or eip1559",
+		BindTo:       "transaction.default-type",
+	},
+	{
+		Name:         "tx-allowed-chain-ids",
+		DefaultValue: []string{},
+		Description:  "Additional chain IDs an eth_signTransaction request may select with its chainId field; the signer's own chain ID is always allowed",
+		BindTo:       "transaction.allowed-chain-ids",
+	},
+	{
+		Name:         "tx-fanout-jitter-ms",
+		DefaultValue: 0,
+		Description:  "Wait a random delay of up to this many milliseconds before forwarding each signed transaction (0 disables)",
+		BindTo:       "transaction.fanout-jitter-ms",
+	},
+	{
+		Name:         "tx-require-from",
+		DefaultValue: false,
+		Description:  "Reject transactions that omit 'from' instead of signing them with the signer's address",
+		BindTo:       "transaction.require-from",
+	},
+	{
+		Name:         "tx-preflight-balance-check",
+		DefaultValue: false,
+		Description:  "Before signing eth_sendTransaction, check that the signer's balance covers value + gas * gas price (adds an eth_getBalance call)",
+		BindTo:       "transaction.preflight-balance-check",
+	},
+	{
+		Name:         "tx-nonce-block-tag",
+		DefaultValue: config.BlockTagLatest,
+		Description:  "Block tag for eth_getTransactionCount when filling in a missing nonce (latest, pending, safe, finalized, earliest)",
+		BindTo:       "transaction.nonce-block-tag",
+	},
+	{
+		Name:         "tx-estimate-gas-block-tag",
+		DefaultValue: "",
+		Description:  "Block tag for eth_estimateGas when filling in a missing gas limit (latest, pending, safe, finalized, earliest); empty omits it",
+		BindTo:       "transaction.estimate-gas-block-tag",
+	},
+
+	// 预签名策略配置
+	{
+		Name:         "policy-endpoint",
+		DefaultValue: "",
+		Description:  "Policy engine URL consulted before signing any transaction, e.g. an OPA decision endpoint (empty disables)",
+		BindTo:       "policy.endpoint",
+	},
+	{
+		Name:         "policy-timeout",
+		DefaultValue: config.DefaultPolicyTimeout,
+		Description:  "Maximum time to wait for a policy decision",
+		BindTo:       "policy.timeout",
+	},
+	{
+		Name:         "policy-max-calldata-bytes",
+		DefaultValue: config.DefaultMaxCalldataBytes,
+		Description:  "Maximum transaction data size in bytes (0 means unlimited); contract deployments may need a higher limit",
+		BindTo:       "policy.max-calldata-bytes",
+	},
+	{
+		Name:         "policy-max-access-list-entries",
+		DefaultValue: config.DefaultMaxAccessListEntries,
+		Description:  "Maximum number of access list entries (addresses) in a transaction (0 means unlimited)",
+		BindTo:       "policy.max-access-list-entries",
+	},
+	{
+		Name:         "policy-max-access-list-storage-keys",
+		DefaultValue: config.DefaultMaxAccessListStorageKeys,
+		Description:  "Maximum number of storage keys per access list entry (0 means unlimited)",
+		BindTo:       "policy.max-access-list-storage-keys",
+	},
 
 	// 日志配置
 	{
@@ -133,6 +723,36 @@ var flags = []Flag{
 		Description:  "Log format (json or text)",
 		BindTo:       "log.format",
 	},
+	{
+		Name:         "log-slow-request-ms",
+		DefaultValue: 0,
+		Description:  "Log requests slower than this many milliseconds at warn level regardless of log-level (0 disables)",
+		BindTo:       "log.slow-request-ms",
+	},
+	{
+		Name:         "log-mask-addresses",
+		DefaultValue: false,
+		Description:  "Truncate addresses in info and higher level log fields to 0x1234…7890; debug logs keep full addresses",
+		BindTo:       "log.mask-addresses",
+	},
+	{
+		Name:         "log-debug-sample-rate",
+		DefaultValue: config.DefaultLogDebugSampleRate,
+		Description:  "Fraction (0-1] of debug log entries that keep full KMS request/response bodies and JSON-RPC params and results; warn and error entries always keep them",
+		BindTo:       "log.debug-sample-rate",
+	},
+	{
+		Name:         "log-access-output",
+		DefaultValue: "",
+		Description:  "Write a structured access log (method, status, duration, client IP, request ID) to stdout, stderr or a file path (empty disables)",
+		BindTo:       "log.access-output",
+	},
+	{
+		Name:         "log-access-format",
+		DefaultValue: config.DefaultAccessLogFormat,
+		Description:  "Access log format (json, text), independent of log-format",
+		BindTo:       "log.access-format",
+	},
 }
 
 // registerFlags 注册所有命令行标志
@@ -146,10 +766,16 @@ func registerFlags(cmd *cobra.Command) error {
 			cmd.Flags().Int(flag.Name, v, flag.Description)
 		case int64:
 			cmd.Flags().Int64(flag.Name, v, flag.Description)
+		case float64:
+			cmd.Flags().Float64(flag.Name, v, flag.Description)
 		case bool:
 			cmd.Flags().Bool(flag.Name, v, flag.Description)
+		case time.Duration:
+			cmd.Flags().Duration(flag.Name, v, flag.Description)
 		case []string:
 			cmd.Flags().StringSlice(flag.Name, v, flag.Description)
+		case map[string]string:
+			cmd.Flags().StringToString(flag.Name, v, flag.Description)
 		default:
 			return fmt.Errorf("unsupported flag type: %T for flag %s", v, flag.Name)
 		}
@@ -0,0 +1,14 @@
+//go:build pkcs11
+
+package main
+
+import (
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/server"
+	"github.com/mowind/web3signer-go/internal/signer"
+)
+
+// pkcs11Opener 使用 cgo 加载的 PKCS#11 库打开 HSM 会话（以 -tags pkcs11 构建）
+var pkcs11Opener server.PKCS11SessionOpener = func(cfg config.PKCS11Config) (signer.PKCS11Session, error) {
+	return signer.OpenPKCS11Session(cfg.Module, cfg.TokenLabel, cfg.PIN)
+}
@@ -0,0 +1,8 @@
+//go:build !pkcs11
+
+package main
+
+import "github.com/mowind/web3signer-go/internal/server"
+
+// pkcs11Opener 默认构建不链接 PKCS#11 库，pkcs11 后端不可用
+var pkcs11Opener server.PKCS11SessionOpener
@@ -92,12 +92,16 @@ func run(cmd *cobra.Command, args []string) {
 		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
 		os.Exit(1)
 	}
+	if err := validateSignerBackend(&cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		os.Exit(1)
+	}
 
 	// 打印配置摘要
 	fmt.Printf("Starting web3signer-go with configuration: %s\n", cfg.String())
 
 	// 创建并启动服务器
-	server := server.New(&cfg)
+	server := server.NewBuilder(&cfg).WithPKCS11SessionOpener(pkcs11Opener).Build()
 	if err := server.Start(); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to start server: %v\n", err)
 		os.Exit(1)
@@ -107,6 +111,16 @@ func run(cmd *cobra.Command, args []string) {
 	waitForInterrupt(server)
 }
 
+// validateSignerBackend 检查签名后端在本程序中可用
+//
+// PKCS#11 绑定需要 cgo，只有以 -tags pkcs11 构建的程序才包含 PKCS#11 会话实现
+func validateSignerBackend(cfg *config.Config) error {
+	if cfg.Signer.Backend == config.SignerBackendPKCS11 && pkcs11Opener == nil {
+		return fmt.Errorf("signer-backend pkcs11 is not supported by this binary: rebuild with -tags pkcs11 (requires cgo), or use kms or local")
+	}
+	return nil
+}
+
 // waitForInterrupt 等待中断信号并优雅关闭服务器
 func waitForInterrupt(server *server.Server) {
 	// 创建信号通道
@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mowind/web3signer-go/internal/utils"
 )
@@ -25,6 +28,15 @@ type Config struct {
 
 	// 认证配置
 	Auth AuthConfig `mapstructure:"auth"`
+
+	// 交易填充配置
+	Transaction TransactionConfig `mapstructure:"transaction"`
+
+	// 预签名策略配置
+	Policy PolicyConfig `mapstructure:"policy"`
+
+	// 签名后端配置
+	Signer SignerConfig `mapstructure:"signer"`
 }
 
 // HTTPConfig 定义 HTTP 服务器配置
@@ -36,6 +48,36 @@ type HTTPConfig struct {
 	TLSAutoRedirect  bool     `mapstructure:"tls-auto-redirect"`
 	MaxRequestSizeMB int64    `mapstructure:"max-request-size-mb"` // 最大请求体大小（MB），用于防止DoS攻击
 	AllowedOrigins   []string `mapstructure:"allowed-origins"`     // CORS 允许的源列表，支持 "*" 允许所有源
+	AllowedHeaders   []string `mapstructure:"allowed-headers"`     // CORS 预检允许的请求头列表
+
+	RequireJSONContentType bool `mapstructure:"require-json-content-type"` // 是否拒绝 Content-Type 非 application/json 的请求（返回 415）
+
+	AllowMissingJSONRPCVersion bool `mapstructure:"allow-missing-jsonrpc-version"` // 是否接受缺少 jsonrpc 字段的请求（视为 2.0），显式的错误版本仍被拒绝
+
+	DebugConfigEnabled bool `mapstructure:"debug-config-enabled"` // 是否启用 /debug/config 端点（返回脱敏后的生效配置）
+
+	ResponseCompressionThreshold int64 `mapstructure:"response-compression-threshold"` // 响应超过该大小（字节）时 gzip 压缩，0 表示不压缩
+	StreamBatchThreshold         int   `mapstructure:"stream-batch-threshold"`         // 批量响应条数达到该值时逐条流式写出，0 表示不流式写出
+
+	RequestTimeout     time.Duration `mapstructure:"request-timeout"`      // 非签名方法的处理超时，0 表示不限制
+	SignRequestTimeout time.Duration `mapstructure:"sign-request-timeout"` // 签名方法（可能等待审批）的处理超时，0 表示不限制；eth_sendTransaction 与 web3signer_signRawTransaction 不受限制
+
+	MethodTimeouts map[string]time.Duration `mapstructure:"method-timeouts"` // 按方法覆盖处理超时，未配置的方法使用 request-timeout / sign-request-timeout
+
+	BatchWorkers    int `mapstructure:"batch-workers"`     // 单个批量请求的最大 worker 数
+	BatchQueueSize  int `mapstructure:"batch-queue-size"`  // 批量任务通道缓冲大小，0 表示与批量大小相同
+	MaxBatchWorkers int `mapstructure:"max-batch-workers"` // 所有并发批量请求的 worker 总数上限，0 表示不限制
+
+	ReadTimeout       time.Duration `mapstructure:"read-timeout"`        // 读取完整请求（含请求体）的超时
+	ReadHeaderTimeout time.Duration `mapstructure:"read-header-timeout"` // 读取请求头的超时，防止 slowloris 攻击
+	WriteTimeout      time.Duration `mapstructure:"write-timeout"`       // 写出响应的超时，须覆盖同步签名的 KMS 审批等待时间
+	IdleTimeout       time.Duration `mapstructure:"idle-timeout"`        // keep-alive 连接的空闲超时
+}
+
+// untimedMethods 不受处理超时限制的方法（小写）：超时后仍会在后台签名并广播，客户端重试会重复发送
+var untimedMethods = map[string]bool{
+	"eth_sendtransaction":           true,
+	"web3signer_signrawtransaction": true,
 }
 
 // Validate 验证 HTTP 配置
@@ -46,6 +88,47 @@ func (c *HTTPConfig) Validate() error {
 	if c.Port <= 0 || c.Port > MaxPort {
 		return fmt.Errorf("http-port must be between 1 and %d", MaxPort)
 	}
+	if c.ResponseCompressionThreshold < 0 {
+		return fmt.Errorf("http-response-compression-threshold must be non-negative")
+	}
+	if c.StreamBatchThreshold < 0 {
+		return fmt.Errorf("http-stream-batch-threshold must be non-negative")
+	}
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("http-request-timeout must be non-negative")
+	}
+	if c.SignRequestTimeout < 0 {
+		return fmt.Errorf("http-sign-request-timeout must be non-negative")
+	}
+	for method, timeout := range c.MethodTimeouts {
+		if timeout < 0 {
+			return fmt.Errorf("http-method-timeouts: timeout for %s must be non-negative", method)
+		}
+		if timeout > 0 && untimedMethods[strings.ToLower(method)] {
+			return fmt.Errorf("http-method-timeouts: %s cannot have a timeout, since it would keep signing and broadcasting after the client was told it failed", method)
+		}
+	}
+	if c.BatchWorkers < 0 {
+		return fmt.Errorf("http-batch-workers must be non-negative")
+	}
+	if c.BatchQueueSize < 0 {
+		return fmt.Errorf("http-batch-queue-size must be non-negative")
+	}
+	if c.MaxBatchWorkers < 0 {
+		return fmt.Errorf("http-max-batch-workers must be non-negative")
+	}
+	if c.ReadTimeout < 0 {
+		return fmt.Errorf("http-read-timeout must be non-negative")
+	}
+	if c.ReadHeaderTimeout < 0 {
+		return fmt.Errorf("http-read-header-timeout must be non-negative")
+	}
+	if c.WriteTimeout < 0 {
+		return fmt.Errorf("http-write-timeout must be non-negative")
+	}
+	if c.IdleTimeout < 0 {
+		return fmt.Errorf("http-idle-timeout must be non-negative")
+	}
 	if c.TLSCertFile != "" && c.TLSKeyFile == "" {
 		return fmt.Errorf("tls-key-file is required when tls-cert-file is set")
 	}
@@ -65,11 +148,29 @@ func (c *HTTPConfig) Validate() error {
 	if c.MaxRequestSizeMB <= 0 {
 		c.MaxRequestSizeMB = 10
 	}
+	if c.BatchWorkers == 0 {
+		c.BatchWorkers = DefaultBatchWorkers
+	}
+	if c.ReadTimeout == 0 {
+		c.ReadTimeout = DefaultHTTPReadTimeout
+	}
+	if c.ReadHeaderTimeout == 0 {
+		c.ReadHeaderTimeout = DefaultHTTPReadHeaderTimeout
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = DefaultHTTPWriteTimeout
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = DefaultHTTPIdleTimeout
+	}
 
 	// 设置安全的默认CORS允许源
 	if len(c.AllowedOrigins) == 0 {
 		c.AllowedOrigins = []string{"http://localhost:*", "http://127.0.0.1:*"}
 	}
+	if len(c.AllowedHeaders) == 0 {
+		c.AllowedHeaders = []string{"Content-Type", "Authorization"}
+	}
 
 	return nil
 }
@@ -81,6 +182,91 @@ type KMSConfig struct {
 	SecretKey   string `mapstructure:"secret-key"`
 	KeyID       string `mapstructure:"key-id"`
 	Address     string `mapstructure:"address"` // KMS管理的以太坊地址
+
+	FallbackEndpoints []string `mapstructure:"fallback-endpoints"` // 主端点连接失败时依次尝试的备用端点
+
+	DiscoverKeys bool `mapstructure:"discover-keys"` // 启动时从 KMS 发现凭证可访问的所有密钥
+
+	MaxPollAttempts int `mapstructure:"max-poll-attempts"` // 审批任务最大轮询次数，与轮询间隔无关
+
+	StartupSelfTest bool `mapstructure:"startup-self-test"` // 启动时签名测试哈希并校验恢复出的地址
+
+	AsyncApproval bool `mapstructure:"async-approval"` // 消息签名需要审批时立即返回任务 ID，不在服务端轮询；交易签名仍同步等待审批
+
+	AllowTaskCancel bool `mapstructure:"allow-task-cancel"` // 注册 web3signer_cancelTask，允许客户端取消待审批任务
+
+	ApprovalDedupWindow time.Duration `mapstructure:"approval-dedup-window"` // 该时间内相同签名请求（密钥、消息、摘要）复用待审批任务，0 表示不去重
+
+	SummaryMaxFieldLength int `mapstructure:"summary-max-field-length"` // 审批摘要每个字段的最大字符数，超出部分截断
+
+	SummaryETHDecimals     int               `mapstructure:"summary-eth-decimals"`     // 审批摘要中 ETH 金额的小数位数，0 表示默认 18 位
+	SummaryAmountPrecision int               `mapstructure:"summary-amount-precision"` // 审批摘要金额最多显示的小数位数，超出部分四舍五入，0 表示不限制
+	SummaryTokenDecimals   map[string]string `mapstructure:"summary-token-decimals"`   // 代币符号到小数位数的映射（如 USDT=6），未登记的代币金额保持原始整数
+
+	Proxy string `mapstructure:"proxy"` // 访问 KMS 使用的出站代理（http/https/socks5），认证信息写在 URL userinfo 中
+
+	TLSMinVersion         string `mapstructure:"tls-min-version"`          // HTTPS 连接的最低 TLS 版本（1.0/1.1/1.2/1.3），空表示使用 Go 默认值
+	TLSCAFile             string `mapstructure:"tls-ca-file"`              // 额外信任的 CA 证书（PEM），用于私有 CA 签发的 KMS 证书
+	TLSInsecureSkipVerify bool   `mapstructure:"tls-insecure-skip-verify"` // 跳过服务端证书校验，仅限开发环境
+
+	Headers       map[string]string `mapstructure:"headers"`        // 每个 KMS 请求附带的静态请求头（如租户、项目），值不会写入日志
+	SignedHeaders []string          `mapstructure:"signed-headers"` // 需要加入 HMAC 签名字符串的 headers 名称，按顺序追加
+
+	VerifyContentSHA256 bool `mapstructure:"verify-content-sha256"` // 调试用：签名后按实际发送的请求体重新计算 Content-SHA256，不一致时记录告警
+}
+
+// kmsReservedHeaders 由 HMAC 认证计算、不允许通过配置覆盖的请求头
+var kmsReservedHeaders = []string{"Authorization", "Date", "Content-Type", "Content-SHA256", "Content-Length", "Host"}
+
+// HeaderValue 按名称（不区分大小写）查找配置的静态请求头
+func (c *KMSConfig) HeaderValue(name string) (string, bool) {
+	for key, value := range c.Headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// SummaryDecimals 返回审批摘要中指定代币金额的小数位数
+//
+// 代币符号不区分大小写，先查 SummaryTokenDecimals，未登记的 ETH 使用 SummaryETHDecimals；
+// 其他未登记的代币返回 false，金额保持原始整数
+func (c *KMSConfig) SummaryDecimals(token string) (int, bool) {
+	for symbol, value := range c.SummaryTokenDecimals {
+		if strings.EqualFold(symbol, token) {
+			decimals, err := strconv.Atoi(value)
+			return decimals, err == nil
+		}
+	}
+	if strings.EqualFold(token, "ETH") {
+		if c.SummaryETHDecimals == 0 {
+			return DefaultKMSSummaryETHDecimals, true
+		}
+		return c.SummaryETHDecimals, true
+	}
+	return 0, false
+}
+
+// ApprovalTimeout 返回同步签名等待 KMS 审批的最长时间
+//
+// 轮询在 KMSTaskPollingTimeout 或 MaxPollAttempts 次状态查询后结束，以先到者为准
+func (c *KMSConfig) ApprovalTimeout() time.Duration {
+	maxAttempts := c.MaxPollAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultKMSMaxPollAttempts
+	}
+	if byAttempts := time.Duration(maxAttempts) * KMSTaskPollInterval; byAttempts < KMSTaskPollingTimeout {
+		return byAttempts
+	}
+	return KMSTaskPollingTimeout
+}
+
+// Endpoints 返回按故障转移顺序排列的 KMS 端点：主端点在前，备用端点在后
+func (c *KMSConfig) Endpoints() []string {
+	endpoints := make([]string, 0, 1+len(c.FallbackEndpoints))
+	endpoints = append(endpoints, c.Endpoint)
+	return append(endpoints, c.FallbackEndpoints...)
 }
 
 // Validate 验证 KMS 配置
@@ -94,6 +280,66 @@ func (c *KMSConfig) Validate() error {
 	if c.SecretKey == "" {
 		return fmt.Errorf("kms-secret-key is required")
 	}
+	for _, endpoint := range c.FallbackEndpoints {
+		if endpoint == "" {
+			return fmt.Errorf("kms-fallback-endpoints must not contain empty entries")
+		}
+	}
+	if c.MaxPollAttempts == 0 {
+		c.MaxPollAttempts = DefaultKMSMaxPollAttempts
+	}
+	if c.MaxPollAttempts < 0 {
+		return fmt.Errorf("kms-max-poll-attempts must be positive")
+	}
+	if c.ApprovalDedupWindow < 0 {
+		return fmt.Errorf("kms-approval-dedup-window must not be negative")
+	}
+	if c.SummaryMaxFieldLength < 0 {
+		return fmt.Errorf("kms-summary-max-field-length must not be negative")
+	}
+	if c.SummaryMaxFieldLength == 0 {
+		c.SummaryMaxFieldLength = DefaultKMSSummaryMaxFieldLength
+	}
+	if c.SummaryETHDecimals < 0 || c.SummaryETHDecimals > MaxKMSSummaryDecimals {
+		return fmt.Errorf("kms-summary-eth-decimals must be between 0 and %d", MaxKMSSummaryDecimals)
+	}
+	if c.SummaryETHDecimals == 0 {
+		c.SummaryETHDecimals = DefaultKMSSummaryETHDecimals
+	}
+	if c.SummaryAmountPrecision < 0 {
+		return fmt.Errorf("kms-summary-amount-precision must not be negative")
+	}
+	for symbol, value := range c.SummaryTokenDecimals {
+		if symbol == "" {
+			return fmt.Errorf("kms-summary-token-decimals must not contain empty token symbols")
+		}
+		if decimals, err := strconv.Atoi(value); err != nil || decimals < 0 || decimals > MaxKMSSummaryDecimals {
+			return fmt.Errorf("kms-summary-token-decimals: decimals of %s must be an integer between 0 and %d, got %q", symbol, MaxKMSSummaryDecimals, value)
+		}
+	}
+	if c.Proxy != "" {
+		if _, err := utils.ParseProxyURL(c.Proxy); err != nil {
+			return fmt.Errorf("kms-proxy: %w", err)
+		}
+	}
+	if err := validateTLSClient("kms", c.TLSMinVersion, c.TLSCAFile); err != nil {
+		return err
+	}
+	if err := utils.ValidateHeaders(c.Headers, kmsReservedHeaders...); err != nil {
+		return fmt.Errorf("kms-headers: %w", err)
+	}
+	for _, name := range c.SignedHeaders {
+		if _, ok := c.HeaderValue(name); !ok {
+			return fmt.Errorf("kms-signed-headers: %q is not configured in kms-headers", name)
+		}
+	}
+	// 启用密钥发现时，key-id 仅用于选择默认密钥，地址从 KMS 获取
+	if c.DiscoverKeys {
+		if c.Address != "" && !utils.IsValidEthAddress(c.Address) {
+			return fmt.Errorf("kms-address has invalid Ethereum address format: '%s'", c.Address)
+		}
+		return nil
+	}
 	if c.KeyID == "" {
 		return fmt.Errorf("kms-key-id is required")
 	}
@@ -107,13 +353,56 @@ func (c *KMSConfig) Validate() error {
 	return nil
 }
 
+// validateTLSClient 校验出站 HTTPS 的最低 TLS 版本和 CA 证书文件，prefix 为参数名前缀
+func validateTLSClient(prefix, minVersion, caFile string) error {
+	if _, err := utils.ParseTLSVersion(minVersion); err != nil {
+		return fmt.Errorf("%s-tls-min-version: %w", prefix, err)
+	}
+	if caFile != "" {
+		if _, err := os.Stat(caFile); err != nil {
+			return fmt.Errorf("%s-tls-ca-file does not exist: %s", prefix, caFile)
+		}
+	}
+	return nil
+}
+
 // DownstreamConfig 定义下游服务配置
 type DownstreamConfig struct {
 	HTTPHost string `mapstructure:"http-host"` // 完整的host，如 http://127.0.0.1 或 https://api.example.com
 	HTTPPort int    `mapstructure:"http-port"` // 端口，如果host中已包含端口或不需要端口，可以为0
 	HTTPPath string `mapstructure:"http-path"` // 路径，如 /api/v1/jsonrpc
+
+	MaxRetries int `mapstructure:"max-retries"` // 批量转发遇到连接错误时的最大重试次数，0 表示不重试
+
+	RequestTimeout            time.Duration `mapstructure:"request-timeout"`              // 单次下游请求超时，与调用方 context 截止时间取较短者
+	SendRawTransactionTimeout time.Duration `mapstructure:"send-raw-transaction-timeout"` // 包含 eth_sendRawTransaction 的请求超时
+
+	CacheTTL  time.Duration `mapstructure:"cache-ttl"`  // 不可变查询方法（eth_chainId、eth_getBlockByHash 等）响应缓存时间，0 表示不缓存
+	CacheSize int           `mapstructure:"cache-size"` // 下游响应缓存最大条目数
+
+	MaxResponseBytes int64 `mapstructure:"max-response-bytes"` // 下游响应体最大字节数（解压后），超出时返回无效响应错误
+
+	FeeHistoryCacheInterval    time.Duration `mapstructure:"fee-history-cache-interval"`    // eth_feeHistory 本地缓存刷新间隔，0 表示不缓存、始终转发
+	FeeHistoryCacheMaxAge      time.Duration `mapstructure:"fee-history-cache-max-age"`     // 缓存的新鲜度上限，超过后回退为实时转发，默认刷新间隔的 2 倍
+	FeeHistoryCacheBlocks      int           `mapstructure:"fee-history-cache-blocks"`      // 缓存覆盖的最近区块数
+	FeeHistoryCachePercentiles []float64     `mapstructure:"fee-history-cache-percentiles"` // 缓存的奖励百分位，请求的百分位与之完全一致时才使用缓存
+
+	Proxy string `mapstructure:"proxy"` // 访问下游服务使用的出站代理（http/https/socks5），认证信息写在 URL userinfo 中
+
+	TLSMinVersion         string `mapstructure:"tls-min-version"`          // HTTPS 连接的最低 TLS 版本（1.0/1.1/1.2/1.3），空表示使用 Go 默认值
+	TLSCAFile             string `mapstructure:"tls-ca-file"`              // 额外信任的 CA 证书（PEM），用于私有 CA 签发的下游证书
+	TLSInsecureSkipVerify bool   `mapstructure:"tls-insecure-skip-verify"` // 跳过服务端证书校验，仅限开发环境
+
+	Headers map[string]string `mapstructure:"headers"` // 每个转发请求附带的静态请求头（如 API key），值不会写入日志
+
+	HealthCheckMethod string `mapstructure:"health-check-method"` // 连接测试与 /ready 探针调用的 JSON-RPC 方法（无参数），部分服务商会限流或禁用某些方法
+
+	RPCHealthCheckInterval time.Duration `mapstructure:"rpc-health-check-interval"` // 定期检查签名查询用下游 RPC 连接的间隔，失败时重建连接，0 表示不检查
 }
 
+// downstreamReservedHeaders 下游客户端自行设置、不允许通过配置覆盖的请求头
+var downstreamReservedHeaders = []string{"Content-Type", "Content-Length", "Accept", "Accept-Encoding", "Host"}
+
 // Validate 验证下游服务配置
 func (c *DownstreamConfig) Validate() error {
 	if c.HTTPHost == "" {
@@ -129,6 +418,59 @@ func (c *DownstreamConfig) Validate() error {
 	if c.HTTPPath == "" {
 		return fmt.Errorf("downstream-http-path is required")
 	}
+	if c.HealthCheckMethod == "" {
+		c.HealthCheckMethod = DefaultDownstreamHealthCheckMethod
+	}
+	if strings.ContainsAny(c.HealthCheckMethod, " \t\r\n") {
+		return fmt.Errorf("downstream-health-check-method must be a non-empty JSON-RPC method name, got: %q", c.HealthCheckMethod)
+	}
+	if c.RPCHealthCheckInterval < 0 {
+		return fmt.Errorf("downstream-rpc-health-check-interval must be non-negative")
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("downstream-max-retries must be non-negative")
+	}
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("downstream-request-timeout must be non-negative")
+	}
+	if c.SendRawTransactionTimeout < 0 {
+		return fmt.Errorf("downstream-send-raw-transaction-timeout must be non-negative")
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("downstream-cache-ttl must be non-negative")
+	}
+	if c.CacheSize < 0 {
+		return fmt.Errorf("downstream-cache-size must be non-negative")
+	}
+	if c.CacheSize == 0 {
+		c.CacheSize = DefaultDownstreamCacheSize
+	}
+	if c.MaxResponseBytes < 0 {
+		return fmt.Errorf("downstream-max-response-bytes must be non-negative")
+	}
+	if c.MaxResponseBytes == 0 {
+		c.MaxResponseBytes = DefaultDownstreamMaxResponseBytes
+	}
+	if err := c.validateFeeHistoryCache(); err != nil {
+		return err
+	}
+	if c.Proxy != "" {
+		if _, err := utils.ParseProxyURL(c.Proxy); err != nil {
+			return fmt.Errorf("downstream-proxy: %w", err)
+		}
+	}
+	if err := validateTLSClient("downstream", c.TLSMinVersion, c.TLSCAFile); err != nil {
+		return err
+	}
+	if err := utils.ValidateHeaders(c.Headers, downstreamReservedHeaders...); err != nil {
+		return fmt.Errorf("downstream-headers: %w", err)
+	}
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = DefaultDownstreamRequestTimeout
+	}
+	if c.SendRawTransactionTimeout == 0 {
+		c.SendRawTransactionTimeout = DefaultDownstreamSendRawTxTimeout
+	}
 	// 确保路径以/开头
 	if !strings.HasPrefix(c.HTTPPath, "/") {
 		c.HTTPPath = "/" + c.HTTPPath
@@ -136,17 +478,76 @@ func (c *DownstreamConfig) Validate() error {
 	return nil
 }
 
+// validateFeeHistoryCache 验证 eth_feeHistory 缓存配置并填充默认值
+func (c *DownstreamConfig) validateFeeHistoryCache() error {
+	if c.FeeHistoryCacheInterval < 0 {
+		return fmt.Errorf("downstream-fee-history-cache-interval must be non-negative")
+	}
+	if c.FeeHistoryCacheMaxAge < 0 {
+		return fmt.Errorf("downstream-fee-history-cache-max-age must be non-negative")
+	}
+	if c.FeeHistoryCacheInterval == 0 {
+		return nil
+	}
+	if c.FeeHistoryCacheMaxAge == 0 {
+		c.FeeHistoryCacheMaxAge = 2 * c.FeeHistoryCacheInterval
+	}
+	if c.FeeHistoryCacheMaxAge < c.FeeHistoryCacheInterval {
+		return fmt.Errorf("downstream-fee-history-cache-max-age must not be less than downstream-fee-history-cache-interval")
+	}
+	if c.FeeHistoryCacheBlocks == 0 {
+		c.FeeHistoryCacheBlocks = DefaultFeeHistoryCacheBlocks
+	}
+	if c.FeeHistoryCacheBlocks < 1 || c.FeeHistoryCacheBlocks > MaxFeeHistoryBlocks {
+		return fmt.Errorf("downstream-fee-history-cache-blocks must be between 1 and %d", MaxFeeHistoryBlocks)
+	}
+	// 节点要求奖励百分位单调递增且位于 [0, 100]
+	for i, p := range c.FeeHistoryCachePercentiles {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("downstream-fee-history-cache-percentiles must be between 0 and 100")
+		}
+		if i > 0 && p <= c.FeeHistoryCachePercentiles[i-1] {
+			return fmt.Errorf("downstream-fee-history-cache-percentiles must be strictly increasing")
+		}
+	}
+	return nil
+}
+
 // BuildURL 构建完整的下游服务URL
+//
+// 规范化规则：
+//   - 未带 scheme 的 host 默认使用 http://
+//   - host 未包含端口且 HTTPPort > 0 时追加端口，IPv6 字面量会加上方括号
+//   - host 自带的路径与 HTTPPath 拼接，避免出现重复或缺失的 /
 func (c *DownstreamConfig) BuildURL() string {
-	baseURL := c.HTTPHost
-	if c.HTTPPort > 0 {
-		u, err := url.Parse(baseURL)
-		if err == nil && u.Port() == "" {
-			baseURL = strings.TrimSuffix(baseURL, "/")
-			baseURL = fmt.Sprintf("%s:%d", baseURL, c.HTTPPort)
+	host := strings.TrimSpace(c.HTTPHost)
+	if !strings.Contains(host, "://") {
+		// 裸 IPv6 字面量（如 ::1）需要加方括号才能被正确解析
+		if ip := net.ParseIP(strings.TrimSuffix(host, "/")); ip != nil && ip.To4() == nil {
+			host = "[" + ip.String() + "]"
+		}
+		host = "http://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil || u.Host == "" {
+		return c.HTTPHost + c.HTTPPath
+	}
+
+	if c.HTTPPort > 0 && u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(c.HTTPPort))
+	}
+
+	if c.HTTPPath != "" {
+		path := c.HTTPPath
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
 		}
+		u.Path = strings.TrimSuffix(u.Path, "/") + path
+		u.RawPath = ""
 	}
-	return baseURL + c.HTTPPath
+
+	return u.String()
 }
 
 func hasPort(urlStr string) bool {
@@ -161,6 +562,15 @@ func hasPort(urlStr string) bool {
 type LogConfig struct {
 	Level  string `mapstructure:"level"`  // 日志级别
 	Format string `mapstructure:"format"` // 日志格式 (json/text)
+
+	SlowRequestMs int `mapstructure:"slow-request-ms"` // 请求处理超过该毫秒数时记录 warn 日志（不受日志级别限制），0 表示不记录
+
+	MaskAddresses bool `mapstructure:"mask-addresses"` // info 及以上级别日志字段中的地址截断为 0x1234…7890，debug 日志保留完整地址
+
+	DebugSampleRate float64 `mapstructure:"debug-sample-rate"` // 保留 KMS 请求/响应体与 JSON-RPC params/result 的 debug 日志比例 (0, 1]，0 表示全部保留；warn 及以上级别始终完整记录
+
+	AccessOutput string `mapstructure:"access-output"` // 访问日志输出（stdout、stderr 或文件路径），为空表示不记录访问日志
+	AccessFormat string `mapstructure:"access-format"` // 访问日志格式 (json/text)，与运行日志格式相互独立
 }
 
 // Validate 验证日志配置
@@ -178,27 +588,105 @@ func (c *LogConfig) Validate() error {
 		return fmt.Errorf("log-format must be one of: json, text, got: %s", c.Format)
 	}
 
+	if c.SlowRequestMs < 0 {
+		return fmt.Errorf("log-slow-request-ms must be non-negative")
+	}
+
+	if c.DebugSampleRate < 0 || c.DebugSampleRate > 1 {
+		return fmt.Errorf("log-debug-sample-rate must be between 0 and 1, got: %g", c.DebugSampleRate)
+	}
+	if c.DebugSampleRate == 0 {
+		c.DebugSampleRate = DefaultLogDebugSampleRate
+	}
+
+	if c.AccessFormat == "" {
+		c.AccessFormat = DefaultAccessLogFormat
+	}
+	if !validLogFormats[strings.ToLower(c.AccessFormat)] {
+		return fmt.Errorf("log-access-format must be one of: json, text, got: %s", c.AccessFormat)
+	}
+
 	return nil
 }
 
 // Validate 验证配置是否有效
 func (c *Config) Validate() error {
+	return c.ValidateAll()
+}
+
+// ValidateAll 验证所有子配置并汇总全部错误
+//
+// 与逐个返回不同，所有子配置都会被验证，便于一次修正全部配置问题。
+// 无错误时返回 nil，否则返回 ValidationErrors。
+func (c *Config) ValidateAll() error {
 	// 设置默认值
 	if c.Log.Level == "" {
 		c.Log.Level = DefaultLogLevel
 	}
 
-	// 验证所有子配置
-	validators := []Validator{&c.HTTP, &c.KMS, &c.Downstream, &c.Log}
+	var errs ValidationErrors
+	validators := []Validator{&c.HTTP, &c.Downstream, &c.Log, &c.Transaction, &c.Policy, &c.Signer}
+	// 仅 KMS 签名后端需要 KMS 配置
+	if c.Signer.Backend == "" || c.Signer.Backend == SignerBackendKMS {
+		validators = append(validators, &c.KMS)
+	}
 	for _, v := range validators {
 		if err := v.Validate(); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
+	if err := c.validateWriteTimeout(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateWriteTimeout 确保写超时足以覆盖同步签名请求的 KMS 审批等待
+//
+// 否则审批完成前连接已被关闭，客户端收不到签名结果；异步审批模式下交易签名仍同步等待审批
+func (c *Config) validateWriteTimeout() error {
+	if c.HTTP.WriteTimeout <= 0 {
+		return nil
+	}
+	if c.Signer.Backend != "" && c.Signer.Backend != SignerBackendKMS {
+		return nil
+	}
 
+	// eth_sendTransaction 等方法不受 http-sign-request-timeout 限制，会等待完整的审批时间
+	signWindow := c.KMS.ApprovalTimeout()
+	if c.HTTP.WriteTimeout <= signWindow {
+		return fmt.Errorf("http-write-timeout (%s) must exceed the KMS approval wait for synchronous sign requests (%s); raise it or lower kms-max-poll-attempts",
+			c.HTTP.WriteTimeout, signWindow)
+	}
 	return nil
 }
 
+// ValidationErrors 配置验证错误列表
+type ValidationErrors []error
+
+// Error 返回所有错误的汇总信息；仅有一个错误时与原错误信息一致
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d configuration errors:", len(e))
+	for _, err := range e {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap 支持 errors.Is / errors.As 匹配其中任一错误
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
+
 // AuthConfig 定义认证配置
 type AuthConfig struct {
 	Enabled   bool     `mapstructure:"enabled"`   // 是否启用认证
@@ -216,17 +704,306 @@ func (c *AuthConfig) Validate() error {
 	return nil
 }
 
+// TransactionConfig 定义 eth_sendTransaction 填充交易字段时的配置
+type TransactionConfig struct {
+	FeeHistoryEnabled    bool     `mapstructure:"fee-history-enabled"`    // EIP-1559 交易是否使用 eth_feeHistory 估算费用
+	FeeHistoryBlocks     int      `mapstructure:"fee-history-blocks"`     // eth_feeHistory 查询的区块数
+	FeeHistoryPercentile *float64 `mapstructure:"fee-history-percentile"` // 优先费采样百分位（0-100），未设置时使用默认值；0 取区块内最低优先费
+	BaseFeeMultiplier    float64  `mapstructure:"base-fee-multiplier"`    // maxFeePerGas = baseFee * multiplier + priorityFee
+
+	IdempotencyTTLSeconds int `mapstructure:"idempotency-ttl-seconds"` // 幂等键结果缓存时间（秒），0 表示不启用幂等键
+	IdempotencyCacheSize  int `mapstructure:"idempotency-cache-size"`  // 幂等键缓存最大条目数，0 表示不启用幂等键
+
+	ReplayTTLSeconds int `mapstructure:"replay-ttl-seconds"` // 已转发交易的重放检测窗口（秒），0 表示不检测
+	ReplayCacheSize  int `mapstructure:"replay-cache-size"`  // 重放检测缓存最大条目数
+
+	PendingTxTTLSeconds int `mapstructure:"pending-tx-ttl-seconds"` // 已发送交易在 eth_getTransactionByHash 中合成 pending 结果的时间（秒），0 表示不合成
+	PendingTxCacheSize  int `mapstructure:"pending-tx-cache-size"`  // 已发送交易缓存最大条目数
+
+	NonceGapCheckInterval time.Duration `mapstructure:"nonce-gap-check-interval"` // 比较已广播 nonce 与下游 pending nonce 的间隔，0 表示不检测
+	NonceGapThreshold     time.Duration `mapstructure:"nonce-gap-threshold"`      // nonce 缺口持续超过该时间时告警
+
+	NonceGapLimit int `mapstructure:"nonce-gap-limit"` // 交易 nonce 最多领先已确认 nonce 的数量，达到时拒绝签名，0 表示不限制
+
+	SignCacheSize int `mapstructure:"sign-cache-size"` // eth_sign 签名缓存最大条目数，0 表示不缓存（仅适用于确定性签名）
+
+	MaxGasLimit uint64 `mapstructure:"max-gas-limit"` // 允许的最大 gas limit（通常为区块 gas 上限），0 表示不限制
+
+	StrictEIP712Domain bool `mapstructure:"strict-eip712-domain"` // 拒绝未声明 chainId 的 EIP-712 域
+
+	InjectCallFrom bool `mapstructure:"inject-call-from"` // eth_call 未指定 from 时使用签名地址
+
+	SignatureFormat string `mapstructure:"signature-format"` // eth_sign 签名输出格式（compact/rsv）
+
+	DefaultType string `mapstructure:"default-type"` // 交易类型（auto/legacy/eip1559），auto 按请求字段推断
+
+	AllowedChainIDs []uint64 `mapstructure:"allowed-chain-ids"` // eth_signTransaction 可通过 chainId 字段指定的其它链 ID，签名器自身的链 ID 始终允许
+
+	FanoutJitterMs int `mapstructure:"fanout-jitter-ms"` // 转发已签名交易前随机等待的最长时间（毫秒），0 表示不等待
+
+	RequireFrom bool `mapstructure:"require-from"` // 要求交易显式指定 from；关闭时未指定 from 的交易使用签名地址
+
+	PreflightBalanceCheck bool `mapstructure:"preflight-balance-check"` // eth_sendTransaction 签名前检查余额是否足以支付 value + gas * 单价
+
+	NonceBlockTag       string `mapstructure:"nonce-block-tag"`        // 自动填充 nonce 时 eth_getTransactionCount 使用的区块标签
+	EstimateGasBlockTag string `mapstructure:"estimate-gas-block-tag"` // 自动估算 gas 时 eth_estimateGas 使用的区块标签，为空时不传（由节点决定）
+}
+
+// Validate 验证交易填充配置
+func (c *TransactionConfig) Validate() error {
+	if c.FeeHistoryBlocks == 0 {
+		c.FeeHistoryBlocks = DefaultFeeHistoryBlocks
+	}
+	if c.FeeHistoryPercentile == nil {
+		percentile := DefaultFeeHistoryPercentile
+		c.FeeHistoryPercentile = &percentile
+	}
+	if c.BaseFeeMultiplier == 0 {
+		c.BaseFeeMultiplier = DefaultBaseFeeMultiplier
+	}
+	if c.ReplayCacheSize == 0 {
+		c.ReplayCacheSize = DefaultReplayCacheSize
+	}
+	if c.PendingTxCacheSize == 0 {
+		c.PendingTxCacheSize = DefaultPendingTxCacheSize
+	}
+	if c.NonceGapThreshold == 0 {
+		c.NonceGapThreshold = DefaultNonceGapThreshold
+	}
+
+	if c.FeeHistoryBlocks < 1 || c.FeeHistoryBlocks > MaxFeeHistoryBlocks {
+		return fmt.Errorf("tx-fee-history-blocks must be between 1 and %d", MaxFeeHistoryBlocks)
+	}
+	if *c.FeeHistoryPercentile < 0 || *c.FeeHistoryPercentile > 100 {
+		return fmt.Errorf("tx-fee-history-percentile must be between 0 and 100")
+	}
+	if c.BaseFeeMultiplier < 1 {
+		return fmt.Errorf("tx-base-fee-multiplier must be at least 1")
+	}
+	if c.IdempotencyTTLSeconds < 0 {
+		return fmt.Errorf("tx-idempotency-ttl-seconds must be non-negative")
+	}
+	if c.IdempotencyCacheSize < 0 {
+		return fmt.Errorf("tx-idempotency-cache-size must be non-negative")
+	}
+	if c.ReplayTTLSeconds < 0 {
+		return fmt.Errorf("tx-replay-ttl-seconds must be non-negative")
+	}
+	if c.ReplayCacheSize < 0 {
+		return fmt.Errorf("tx-replay-cache-size must be non-negative")
+	}
+	if c.PendingTxTTLSeconds < 0 {
+		return fmt.Errorf("tx-pending-tx-ttl-seconds must be non-negative")
+	}
+	if c.PendingTxCacheSize < 0 {
+		return fmt.Errorf("tx-pending-tx-cache-size must be non-negative")
+	}
+	if c.NonceGapCheckInterval < 0 {
+		return fmt.Errorf("tx-nonce-gap-check-interval must be non-negative")
+	}
+	if c.NonceGapThreshold < 0 {
+		return fmt.Errorf("tx-nonce-gap-threshold must be non-negative")
+	}
+	if c.NonceGapLimit < 0 {
+		return fmt.Errorf("tx-nonce-gap-limit must be non-negative")
+	}
+	if c.SignCacheSize < 0 {
+		return fmt.Errorf("tx-sign-cache-size must be non-negative")
+	}
+	if c.FanoutJitterMs < 0 {
+		return fmt.Errorf("tx-fanout-jitter-ms must be non-negative")
+	}
+
+	c.SignatureFormat = strings.ToLower(c.SignatureFormat)
+	if c.SignatureFormat == "" {
+		c.SignatureFormat = SignatureFormatCompact
+	}
+	if c.SignatureFormat != SignatureFormatCompact && c.SignatureFormat != SignatureFormatRSV {
+		return fmt.Errorf("tx-signature-format must be one of: compact, rsv, got: %s", c.SignatureFormat)
+	}
+
+	c.DefaultType = strings.ToLower(c.DefaultType)
+	if c.DefaultType == "" {
+		c.DefaultType = TxTypeAuto
+	}
+	if c.DefaultType != TxTypeAuto && c.DefaultType != TxTypeLegacy && c.DefaultType != TxTypeEIP1559 {
+		return fmt.Errorf("tx-default-type must be one of: auto, legacy, eip1559, got: %s", c.DefaultType)
+	}
+
+	c.NonceBlockTag = strings.ToLower(c.NonceBlockTag)
+	if c.NonceBlockTag == "" {
+		c.NonceBlockTag = BlockTagLatest
+	}
+	if !isBlockTag(c.NonceBlockTag) {
+		return fmt.Errorf("tx-nonce-block-tag must be one of: latest, pending, safe, finalized, earliest, got: %s", c.NonceBlockTag)
+	}
+	c.EstimateGasBlockTag = strings.ToLower(c.EstimateGasBlockTag)
+	if c.EstimateGasBlockTag != "" && !isBlockTag(c.EstimateGasBlockTag) {
+		return fmt.Errorf("tx-estimate-gas-block-tag must be one of: latest, pending, safe, finalized, earliest, got: %s", c.EstimateGasBlockTag)
+	}
+	return nil
+}
+
+// isBlockTag 判断是否为 JSON-RPC 规范定义的区块标签
+func isBlockTag(tag string) bool {
+	switch tag {
+	case BlockTagLatest, BlockTagPending, BlockTagSafe, BlockTagFinalized, BlockTagEarliest:
+		return true
+	}
+	return false
+}
+
+// PolicyConfig 定义签名交易前调用的外部策略引擎（如 OPA）配置
+type PolicyConfig struct {
+	Endpoint string        `mapstructure:"endpoint"` // 策略决策 URL，为空表示不检查
+	Timeout  time.Duration `mapstructure:"timeout"`  // 等待策略决策的超时
+
+	MaxCalldataBytes int `mapstructure:"max-calldata-bytes"` // 交易 data 的最大字节数，0 表示不限制；合约部署可能需要调高
+
+	MaxAccessListEntries     int `mapstructure:"max-access-list-entries"`      // 交易访问列表的最大条目（地址）数，0 表示不限制
+	MaxAccessListStorageKeys int `mapstructure:"max-access-list-storage-keys"` // 访问列表每个条目的最大存储键数，0 表示不限制
+}
+
+// Validate 验证策略配置
+func (c *PolicyConfig) Validate() error {
+	if c.Timeout == 0 {
+		c.Timeout = DefaultPolicyTimeout
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("policy-timeout must be non-negative")
+	}
+	if c.MaxCalldataBytes < 0 {
+		return fmt.Errorf("policy-max-calldata-bytes must be non-negative")
+	}
+	if c.MaxAccessListEntries < 0 {
+		return fmt.Errorf("policy-max-access-list-entries must be non-negative")
+	}
+	if c.MaxAccessListStorageKeys < 0 {
+		return fmt.Errorf("policy-max-access-list-storage-keys must be non-negative")
+	}
+	if c.Endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("policy-endpoint must be an http(s) URL, got: %s", c.Endpoint)
+	}
+	return nil
+}
+
+// SignerConfig 定义签名后端配置
+type SignerConfig struct {
+	Backend string            `mapstructure:"backend"` // 签名后端 (kms/pkcs11/local)
+	PKCS11  PKCS11Config      `mapstructure:"pkcs11"`  // PKCS#11 HSM 配置，仅 pkcs11 后端使用
+	Local   LocalSignerConfig `mapstructure:"local"`   // 本地密钥配置，仅 local 后端使用（仅限开发/测试）
+
+	CosmosEnabled bool `mapstructure:"cosmos-enabled"` // 是否注册 cosmos_signAmino / cosmos_signDirect 方法
+
+	KeyRotationEnabled bool `mapstructure:"key-rotation-enabled"` // 是否注册 web3signer_setDefaultKey 方法，允许运行时切换默认密钥
+
+	ChainID         int64  `mapstructure:"chain-id"`          // 期望的链 ID，启动时与下游 eth_chainId 比较，0 表示直接使用下游返回的链 ID
+	ChainIDMismatch string `mapstructure:"chain-id-mismatch"` // 链 ID 与下游不一致时的处理 (fatal/warn)
+
+	// chain-id-mismatch 为 warn 时，签名前按该策略决定使用哪个链 ID (config/downstream/fail)
+	ChainIDPolicy          string        `mapstructure:"chain-id-policy"`
+	ChainIDRefreshInterval time.Duration `mapstructure:"chain-id-refresh-interval"` // 下游链 ID 缓存时间，0 表示每次签名都查询
+
+	VerifyDeterministicNonce bool `mapstructure:"verify-deterministic-nonce"` // local 后端签名后校验是否使用 RFC 6979 确定性 k
+
+	SignBatchMaxItems    int `mapstructure:"sign-batch-max-items"`   // web3signer_signBatch 单次请求的最大消息数
+	SignBatchConcurrency int `mapstructure:"sign-batch-concurrency"` // web3signer_signBatch 同时进行的签名数
+
+	AllowedKeyIDs []string `mapstructure:"allowed-key-ids"` // 允许客户端通过 JSON-RPC 签名使用的密钥 ID，为空表示允许所有密钥
+}
+
+// LocalSignerConfig 定义本地密钥签名配置
+//
+// 未配置 keystore 文件时从环境变量 WEB3SIGNER_DEV_PRIVATE_KEY 读取明文私钥
+type LocalSignerConfig struct {
+	KeystoreFile     string `mapstructure:"keystore-file"`     // 加密 JSON keystore 文件路径
+	KeystorePassword string `mapstructure:"keystore-password"` // keystore 解密密码
+}
+
+// PKCS11Config 定义 PKCS#11 HSM 配置
+type PKCS11Config struct {
+	Module     string `mapstructure:"module"`      // PKCS#11 库路径
+	TokenLabel string `mapstructure:"token-label"` // HSM token 标签
+	PIN        string `mapstructure:"pin"`         // token 用户 PIN
+	KeyLabel   string `mapstructure:"key-label"`   // 签名密钥的 CKA_LABEL
+}
+
+// Validate 验证签名后端配置
+func (c *SignerConfig) Validate() error {
+	if c.Backend == "" {
+		c.Backend = DefaultSignerBackend
+	}
+	if c.ChainID < 0 {
+		return fmt.Errorf("signer-chain-id must be non-negative")
+	}
+	if c.ChainIDMismatch == "" {
+		c.ChainIDMismatch = DefaultChainIDMismatch
+	}
+	if c.ChainIDMismatch != ChainIDMismatchFatal && c.ChainIDMismatch != ChainIDMismatchWarn {
+		return fmt.Errorf("signer-chain-id-mismatch must be one of: fatal, warn, got: %s", c.ChainIDMismatch)
+	}
+	if c.ChainIDPolicy == "" {
+		c.ChainIDPolicy = DefaultChainIDPolicy
+	}
+	if c.ChainIDPolicy != ChainIDPolicyConfig && c.ChainIDPolicy != ChainIDPolicyDownstream && c.ChainIDPolicy != ChainIDPolicyFail {
+		return fmt.Errorf("signer-chain-id-policy must be one of: config, downstream, fail, got: %s", c.ChainIDPolicy)
+	}
+	if c.ChainIDRefreshInterval < 0 {
+		return fmt.Errorf("signer-chain-id-refresh-interval must be non-negative")
+	}
+	if c.SignBatchMaxItems < 0 {
+		return fmt.Errorf("signer-sign-batch-max-items must be non-negative")
+	}
+	if c.SignBatchMaxItems == 0 {
+		c.SignBatchMaxItems = DefaultSignBatchMaxItems
+	}
+	if c.SignBatchConcurrency < 0 {
+		return fmt.Errorf("signer-sign-batch-concurrency must be non-negative")
+	}
+	if c.SignBatchConcurrency == 0 {
+		c.SignBatchConcurrency = DefaultSignBatchConcurrency
+	}
+	for _, keyID := range c.AllowedKeyIDs {
+		if strings.TrimSpace(keyID) == "" {
+			return fmt.Errorf("signer-allowed-key-ids must not contain empty key IDs")
+		}
+	}
+	switch c.Backend {
+	case SignerBackendKMS:
+		return nil
+	case SignerBackendPKCS11:
+		if c.PKCS11.Module == "" {
+			return fmt.Errorf("pkcs11-module is required for the pkcs11 signer backend")
+		}
+		if c.PKCS11.KeyLabel == "" {
+			return fmt.Errorf("pkcs11-key-label is required for the pkcs11 signer backend")
+		}
+		return nil
+	case SignerBackendLocal:
+		if c.Local.KeystoreFile == "" && os.Getenv(LocalPrivateKeyEnv) == "" {
+			return fmt.Errorf("local-keystore-file or the %s environment variable is required for the local signer backend", LocalPrivateKeyEnv)
+		}
+		return nil
+	default:
+		return fmt.Errorf("signer-backend must be one of: kms, pkcs11, local, got: %s", c.Backend)
+	}
+}
+
 // String 返回配置的安全摘要（不包含敏感信息）
 func (c *Config) String() string {
 	return fmt.Sprintf(
 		"HTTP: {Host: %s, Port: %d}, "+
-			"KMS: {Endpoint: %s, KeyID: %s, AccessKeyID: [REDACTED], SecretKey: [REDACTED]}, "+
-			"Downstream: {Host: %s, Port: %d, Path: %s}, "+
+			"KMS: {Endpoint: %s, KeyID: %s, AccessKeyID: [REDACTED], SecretKey: [REDACTED], Headers: %s}, "+
+			"Downstream: {Host: %s, Port: %d, Path: %s, Headers: %s}, "+
 			"Log: {Level: %s, Format: %s}, "+
 			"Auth: {Enabled: %v, Secret: [REDACTED], Whitelist: %v}",
 		c.HTTP.Host, c.HTTP.Port,
-		c.KMS.Endpoint, c.KMS.KeyID,
-		c.Downstream.HTTPHost, c.Downstream.HTTPPort, c.Downstream.HTTPPath,
+		c.KMS.Endpoint, c.KMS.KeyID, utils.RedactHeaders(c.KMS.Headers),
+		c.Downstream.HTTPHost, c.Downstream.HTTPPort, c.Downstream.HTTPPath, utils.RedactHeaders(c.Downstream.Headers),
 		c.Log.Level, c.Log.Format,
 		c.Auth.Enabled, c.Auth.Whitelist,
 	)
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 const (
 	// MaxPort 最大端口号
 	MaxPort = 65535
@@ -26,6 +28,33 @@ const (
 	DefaultHTTPPort = 9000
 	// DefaultMaxRequestSizeMB 默认最大请求大小（MB）
 	DefaultMaxRequestSizeMB int64 = 10
+	// DefaultResponseCompressionThreshold 默认响应 gzip 压缩阈值（1MB）
+	DefaultResponseCompressionThreshold int64 = 1024 * 1024
+	// DefaultRequestTimeout 默认非签名方法的处理超时
+	DefaultRequestTimeout = 2 * time.Minute
+	// DefaultBatchWorkers 默认单个批量请求的最大 worker 数
+	DefaultBatchWorkers = 50
+	// DefaultHTTPReadTimeout 默认读取完整请求的超时
+	DefaultHTTPReadTimeout = 30 * time.Second
+	// DefaultHTTPReadHeaderTimeout 默认读取请求头的超时
+	DefaultHTTPReadHeaderTimeout = 5 * time.Second
+	// DefaultHTTPWriteTimeout 默认写出响应的超时，覆盖默认的 KMS 审批等待时间
+	DefaultHTTPWriteTimeout = 6 * time.Minute
+	// DefaultHTTPIdleTimeout 默认 keep-alive 连接空闲超时
+	DefaultHTTPIdleTimeout = 2 * time.Minute
+
+	// DefaultKMSMaxPollAttempts 默认审批任务最大轮询次数
+	DefaultKMSMaxPollAttempts = 120
+	// DefaultKMSSummaryMaxFieldLength 默认审批摘要字段最大字符数
+	DefaultKMSSummaryMaxFieldLength = 256
+	// DefaultKMSSummaryETHDecimals 默认审批摘要中 ETH 金额的小数位数
+	DefaultKMSSummaryETHDecimals = 18
+	// MaxKMSSummaryDecimals 审批摘要金额允许配置的最大小数位数（uint256 最多 78 位十进制数）
+	MaxKMSSummaryDecimals = 77
+	// KMSTaskPollInterval 同步签名轮询审批任务状态的间隔
+	KMSTaskPollInterval = 5 * time.Second
+	// KMSTaskPollingTimeout 同步签名轮询审批任务的最长时间
+	KMSTaskPollingTimeout = 5 * time.Minute
 
 	// DefaultDownstreamHost 默认下游服务主机（完整URL）
 	DefaultDownstreamHost = "http://localhost"
@@ -33,11 +62,112 @@ const (
 	DefaultDownstreamPort = 8545
 	// DefaultDownstreamPath 默认下游服务路径
 	DefaultDownstreamPath = "/"
+	// DefaultDownstreamHealthCheckMethod 默认下游健康检查方法
+	DefaultDownstreamHealthCheckMethod = "web3_clientVersion"
+	// DefaultDownstreamMaxRetries 默认下游批量转发重试次数（不重试）
+	DefaultDownstreamMaxRetries = 0
+	// DefaultDownstreamRequestTimeout 默认单次下游请求超时
+	DefaultDownstreamRequestTimeout = 30 * time.Second
+	// DefaultDownstreamSendRawTxTimeout 默认 eth_sendRawTransaction 请求超时（广播可能较慢）
+	DefaultDownstreamSendRawTxTimeout = 60 * time.Second
+	// DefaultDownstreamCacheSize 默认下游响应缓存最大条目数
+	DefaultDownstreamCacheSize = 10000
+	// DefaultDownstreamMaxResponseBytes 默认下游响应体最大字节数（32 MiB）
+	DefaultDownstreamMaxResponseBytes int64 = 32 * 1024 * 1024
+
+	// DefaultFeeHistoryBlocks 默认 eth_feeHistory 查询区块数
+	DefaultFeeHistoryBlocks = 10
+	// MaxFeeHistoryBlocks eth_feeHistory 允许的最大区块数
+	MaxFeeHistoryBlocks = 1024
+	// DefaultFeeHistoryCacheBlocks 默认 eth_feeHistory 缓存覆盖的区块数
+	DefaultFeeHistoryCacheBlocks = 64
+	// DefaultFeeHistoryPercentile 默认优先费采样百分位
+	DefaultFeeHistoryPercentile = 50.0
+	// DefaultBaseFeeMultiplier 默认 baseFee 倍数
+	DefaultBaseFeeMultiplier = 2.0
+	// DefaultIdempotencyTTLSeconds 默认幂等键结果缓存时间（秒）
+	DefaultIdempotencyTTLSeconds = 600
+	// DefaultIdempotencyCacheSize 默认幂等键缓存最大条目数
+	DefaultIdempotencyCacheSize = 10000
+	// DefaultPolicyTimeout 默认等待策略决策的超时
+	DefaultPolicyTimeout = 5 * time.Second
+	// DefaultMaxCalldataBytes 默认交易 data 的最大字节数（128KB）
+	DefaultMaxCalldataBytes = 128 * 1024
+	// DefaultMaxAccessListEntries 默认交易访问列表的最大条目数
+	DefaultMaxAccessListEntries = 1024
+	// DefaultMaxAccessListStorageKeys 默认访问列表每个条目的最大存储键数
+	DefaultMaxAccessListStorageKeys = 1024
+	// DefaultReplayCacheSize 默认重放检测缓存最大条目数
+	DefaultReplayCacheSize = 10000
+	// DefaultPendingTxCacheSize 默认已发送交易缓存最大条目数
+	DefaultPendingTxCacheSize = 10000
+	// DefaultNonceGapThreshold 默认 nonce 缺口告警阈值
+	DefaultNonceGapThreshold = 2 * time.Minute
+
+	// SignerBackendKMS 使用 MPC-KMS 签名（默认）
+	SignerBackendKMS = "kms"
+	// SignerBackendPKCS11 使用 PKCS#11 HSM 签名
+	SignerBackendPKCS11 = "pkcs11"
+	// SignerBackendLocal 使用本地 keystore 或明文私钥签名（仅限开发/测试）
+	SignerBackendLocal = "local"
+	// LocalPrivateKeyEnv local 后端读取明文私钥的环境变量（仅限开发/测试）
+	LocalPrivateKeyEnv = "WEB3SIGNER_DEV_PRIVATE_KEY"
+	// DefaultSignerBackend 默认签名后端
+	DefaultSignerBackend = SignerBackendKMS
+
+	// ChainIDMismatchFatal 配置的链 ID 与下游不一致时终止启动（默认）
+	ChainIDMismatchFatal = "fatal"
+	// ChainIDMismatchWarn 配置的链 ID 与下游不一致时仅记录告警，仍使用配置的链 ID 签名
+	ChainIDMismatchWarn = "warn"
+	// DefaultChainIDMismatch 默认链 ID 不一致处理方式
+	DefaultChainIDMismatch = ChainIDMismatchFatal
+
+	// ChainIDPolicyConfig 运行期间链 ID 不一致时使用配置的链 ID 签名（默认）
+	ChainIDPolicyConfig = "config"
+	// ChainIDPolicyDownstream 运行期间链 ID 不一致时使用下游报告的链 ID 签名
+	ChainIDPolicyDownstream = "downstream"
+	// ChainIDPolicyFail 运行期间链 ID 不一致时拒绝签名
+	ChainIDPolicyFail = "fail"
+	// DefaultChainIDPolicy 默认运行期链 ID 不一致处理策略
+	DefaultChainIDPolicy = ChainIDPolicyConfig
+	// DefaultChainIDRefreshInterval 默认下游链 ID 重新查询间隔
+	DefaultChainIDRefreshInterval = time.Minute
+	// DefaultSignBatchMaxItems 默认 web3signer_signBatch 单次请求的最大消息数
+	DefaultSignBatchMaxItems = 100
+	// DefaultSignBatchConcurrency 默认 web3signer_signBatch 并发签名数
+	DefaultSignBatchConcurrency = 4
+
+	// SignatureFormatCompact 签名输出为 65 字节 r || s || v 十六进制（默认）
+	SignatureFormatCompact = "compact"
+	// SignatureFormatRSV 签名输出为 {r, s, v} 对象
+	SignatureFormatRSV = "rsv"
+
+	// TxTypeAuto 按请求字段推断交易类型（默认）
+	TxTypeAuto = "auto"
+	// TxTypeLegacy 所有交易按 legacy（type 0）签名
+	TxTypeLegacy = "legacy"
+	// TxTypeEIP1559 所有交易按 EIP-1559（type 2）签名
+	TxTypeEIP1559 = "eip1559"
+
+	// BlockTagLatest 最新区块（nonce 查询默认使用）
+	BlockTagLatest = "latest"
+	// BlockTagPending 包含交易池中待打包交易的 pending 状态
+	BlockTagPending = "pending"
+	// BlockTagSafe 共识层标记为 safe 的区块
+	BlockTagSafe = "safe"
+	// BlockTagFinalized 共识层已最终确认的区块
+	BlockTagFinalized = "finalized"
+	// BlockTagEarliest 创世区块
+	BlockTagEarliest = "earliest"
 
 	// DefaultLogLevel 默认日志级别
 	DefaultLogLevel = LogLevelInfo
 	// DefaultLogFormat 默认日志格式
 	DefaultLogFormat = LogFormatText
+	// DefaultAccessLogFormat 默认访问日志格式，便于日志采集
+	DefaultAccessLogFormat = LogFormatJSON
+	// DefaultLogDebugSampleRate 默认保留全部 debug 日志中的请求/响应体
+	DefaultLogDebugSampleRate = 1.0
 )
 
 // Validator 验证器接口
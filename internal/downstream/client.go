@@ -2,12 +2,16 @@ package downstream
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mowind/web3signer-go/internal/config"
@@ -28,8 +32,9 @@ type Client struct {
 
 // NewClient creates a new downstream service client.
 //
-// The client is configured with a 30-second timeout and
-// connection pooling (100 max idle connections per host).
+// The client uses connection pooling (100 max idle connections per host).
+// Timeouts are applied per request from DownstreamConfig.RequestTimeout and
+// DownstreamConfig.SendRawTransactionTimeout rather than on the http.Client.
 //
 // Parameters:
 //   - cfg: Downstream service configuration (host, port, path)
@@ -38,11 +43,26 @@ type Client struct {
 // Returns:
 //   - *Client: A new downstream client instance
 func NewClient(cfg *config.DownstreamConfig, logger *logrus.Logger) *Client {
+	transport := utils.CreateTransport(100, 90*time.Second)
+	// Response deadlines are governed by the per-request context so that
+	// eth_sendRawTransaction can wait longer than ordinary calls.
+	transport.ResponseHeaderTimeout = 0
+	if err := utils.SetProxy(transport, cfg.Proxy); err != nil {
+		// 配置校验已拒绝无效代理，这里只会在未校验的配置下发生
+		logger.WithError(err).Warn("Ignoring invalid downstream proxy, connecting directly")
+	}
+	if err := utils.SetTLS(transport, cfg.TLSMinVersion, cfg.TLSCAFile, cfg.TLSInsecureSkipVerify); err != nil {
+		// 配置校验已检查版本和 CA 文件，这里只会在未校验的配置或文件内容无效时发生
+		logger.WithError(err).Error("Ignoring invalid downstream TLS settings, using the default TLS configuration")
+	}
+	if cfg.TLSInsecureSkipVerify {
+		logger.Warn("Downstream TLS certificate verification is DISABLED: connections can be intercepted. For development only, NEVER use in production")
+	}
+
 	return &Client{
 		config: cfg,
 		httpClient: &http.Client{
-			Timeout:   30 * time.Second,
-			Transport: utils.CreateTransport(100, 90*time.Second),
+			Transport: transport,
 		},
 		logger: logger,
 	}
@@ -61,9 +81,16 @@ func (c *Client) performHTTPRequest(ctx context.Context, reqData []byte) (io.Rea
 		return nil, WrapError(err, ErrorCodeRequestFailed, "failed to create HTTP request")
 	}
 
-	// Set headers
+	// Set headers; configured static headers (e.g. API keys) first so the
+	// protocol headers below always win
+	for name, value := range c.config.Headers {
+		httpReq.Header.Set(name, value)
+	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
+	// Negotiating encodings explicitly disables net/http's transparent gzip handling,
+	// so every compressed body goes through decodeResponseBody and its size cap.
+	httpReq.Header.Set("Accept-Encoding", "gzip, deflate")
 
 	// Execute request
 	resp, err := c.httpClient.Do(httpReq)
@@ -83,12 +110,120 @@ func (c *Client) performHTTPRequest(ctx context.Context, reqData []byte) (io.Rea
 			resp.StatusCode, string(respBody)))
 	}
 
-	return resp.Body, nil
+	body, err := decodeResponseBody(resp)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
+	if c.config.MaxResponseBytes > 0 {
+		body = &limitedBody{
+			reader: io.LimitReader(body, c.config.MaxResponseBytes),
+			body:   body,
+		}
+	}
+
+	return body, nil
+}
+
+// errResponseTooLarge is returned when a response body exceeds
+// DownstreamConfig.MaxResponseBytes.
+var errResponseTooLarge = errors.New("response exceeds downstream-max-response-bytes")
+
+// limitedBody enforces DownstreamConfig.MaxResponseBytes on a response body.
+type limitedBody struct {
+	reader io.Reader // io.LimitReader over body
+	body   io.ReadCloser
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	n, err := l.reader.Read(p)
+	if err == io.EOF && l.reader.(*io.LimitedReader).N <= 0 {
+		// Probe for one more byte to distinguish an exact fit from overflow
+		var probe [1]byte
+		if m, _ := l.body.Read(probe[:]); m > 0 {
+			return n, errResponseTooLarge
+		}
+	}
+	return n, err
+}
+
+func (l *limitedBody) Close() error {
+	return l.body.Close()
+}
+
+// maxDecompressedResponseSize caps the size of a decompressed downstream response
+// body to protect against decompression bombs.
+var maxDecompressedResponseSize int64 = 32 * 1024 * 1024
+
+// errDecompressedTooLarge is returned when a compressed response inflates past
+// maxDecompressedResponseSize.
+var errDecompressedTooLarge = errors.New("decompressed response exceeds size limit")
+
+// decodeResponseBody wraps the response body according to its Content-Encoding.
+//
+// Supports gzip and deflate; the decompressed body is capped at
+// maxDecompressedResponseSize.
+func decodeResponseBody(resp *http.Response) (io.ReadCloser, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	var reader io.ReadCloser
+	switch encoding {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, WrapError(err, ErrorCodeInvalidResponse, "failed to decode gzip response")
+		}
+		reader = gz
+	case "deflate":
+		reader = flate.NewReader(resp.Body)
+	default:
+		return nil, InvalidResponseError(fmt.Errorf("unsupported Content-Encoding %q", encoding))
+	}
+
+	return &decompressedBody{
+		reader: reader,
+		body:   resp.Body,
+		remain: maxDecompressedResponseSize,
+	}, nil
+}
+
+// decompressedBody enforces maxDecompressedResponseSize on a decompressing reader
+// and closes both the decompressor and the underlying response body.
+type decompressedBody struct {
+	reader io.ReadCloser
+	body   io.Closer
+	remain int64
+}
+
+func (d *decompressedBody) Read(p []byte) (int, error) {
+	if d.remain <= 0 {
+		// Probe for one more byte to distinguish an exact fit from overflow
+		var probe [1]byte
+		if n, _ := d.reader.Read(probe[:]); n > 0 {
+			return 0, errDecompressedTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > d.remain {
+		p = p[:d.remain]
+	}
+	n, err := d.reader.Read(p)
+	d.remain -= int64(n)
+	return n, err
+}
+
+func (d *decompressedBody) Close() error {
+	_ = d.reader.Close()
+	return d.body.Close()
 }
 
 // ForwardRequest forwards a single JSON-RPC request to downstream service.
 //
-// This method validates response ID matching and logs warnings on mismatch.
+// This method validates that the response ID matches the request ID and
+// returns an IDMismatchError when they differ.
 //
 // Parameters:
 //   - ctx: Context for request (supports cancellation and timeout)
@@ -104,6 +239,9 @@ func (c *Client) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jso
 		return nil, WrapError(err, ErrorCodeInvalidResponse, "failed to marshal request")
 	}
 
+	ctx, cancel := c.withRequestTimeout(ctx, req.Method == "eth_sendRawTransaction")
+	defer cancel()
+
 	// Execute HTTP request
 	bodyReader, err := c.performHTTPRequest(ctx, reqData)
 	if err != nil {
@@ -122,13 +260,14 @@ func (c *Client) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jso
 		return nil, InvalidResponseError(err)
 	}
 
-	// Validate response ID
+	// Validate response ID: a mismatch may indicate a proxy mixing up responses
 	if req.ID != nil && jsonResp.ID != nil {
 		if !compareIDs(req.ID, jsonResp.ID) {
 			c.logger.WithFields(logrus.Fields{
 				"request_id":  req.ID,
 				"response_id": jsonResp.ID,
 			}).Warn("JSON-RPC ID mismatch in response")
+			return nil, IDMismatchError(req.ID, jsonResp.ID)
 		}
 	} else if req.ID != nil {
 		jsonResp.ID = req.ID
@@ -140,15 +279,22 @@ func (c *Client) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jso
 // ForwardBatchRequest forwards a batch of JSON-RPC requests.
 //
 // This method preserves response order and validates:
-//   - Response count matches request count
-//   - Response IDs match request IDs (with warnings on mismatch)
+//   - Response count matches the number of requests that have an id;
+//     notifications (requests without an id) get no response from the node
+//   - Responses are matched to requests by id, falling back to order
+//     (with warnings on mismatch)
+//
+// On connection errors the whole batch is retried up to DownstreamConfig.MaxRetries
+// times with jittered exponential backoff. Batches containing non-idempotent methods
+// (e.g. eth_sendRawTransaction) are never retried, since a failed attempt may
+// already have been submitted.
 //
 // Parameters:
 //   - ctx: Context for request (supports cancellation and timeout)
 //   - requests: The JSON-RPC requests to forward
 //
 // Returns:
-//   - []jsonrpc.Response: Ordered responses matching request order
+//   - []jsonrpc.Response: Ordered responses matching request order; entries for notifications are zero values
 //   - error: An error if forwarding fails
 func (c *Client) ForwardBatchRequest(ctx context.Context, requests []jsonrpc.Request) ([]jsonrpc.Response, error) {
 	// Serialize batch request
@@ -157,6 +303,34 @@ func (c *Client) ForwardBatchRequest(ctx context.Context, requests []jsonrpc.Req
 		return nil, WrapError(err, ErrorCodeInvalidResponse, "failed to marshal batch request")
 	}
 
+	maxRetries := c.config.MaxRetries
+	if !isIdempotentBatch(requests) {
+		maxRetries = 0
+	}
+
+	var responses []jsonrpc.Response
+	var lastErr error
+	err = utils.Retry(ctx, retryBackoff(), maxRetries+1, func(attempt int) error {
+		if attempt > 0 {
+			c.logger.WithFields(logrus.Fields{
+				"attempt": attempt,
+				"error":   lastErr.Error(),
+			}).Warn("Batch forwarding failed, retrying")
+		}
+		responses, lastErr = c.forwardBatchOnce(ctx, requests, reqData)
+		return lastErr
+	}, IsConnectionError)
+	if err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// forwardBatchOnce performs a single batch forwarding attempt.
+func (c *Client) forwardBatchOnce(ctx context.Context, requests []jsonrpc.Request, reqData []byte) ([]jsonrpc.Response, error) {
+	ctx, cancel := c.withRequestTimeout(ctx, containsMethod(requests, "eth_sendRawTransaction"))
+	defer cancel()
+
 	// Execute HTTP request
 	bodyReader, err := c.performHTTPRequest(ctx, reqData)
 	if err != nil {
@@ -174,6 +348,17 @@ func (c *Client) ForwardBatchRequest(ctx context.Context, requests []jsonrpc.Req
 		return nil, WrapError(err, ErrorCodeInvalidResponse, "failed to read response body")
 	}
 
+	// 通知（无 id 的请求）按规范不返回响应，只对带 id 的请求计数
+	expected := 0
+	for i := range requests {
+		if requests[i].ID != nil {
+			expected++
+		}
+	}
+	if expected == 0 {
+		return make([]jsonrpc.Response, len(requests)), nil
+	}
+
 	// Parse batch response
 	var jsonResponses []jsonrpc.Response
 	if err := json.Unmarshal(respBody, &jsonResponses); err != nil {
@@ -186,26 +371,108 @@ func (c *Client) ForwardBatchRequest(ctx context.Context, requests []jsonrpc.Req
 	}
 
 	// Validate response count
-	if len(jsonResponses) != len(requests) {
-		return nil, BatchSizeMismatchError(len(requests), len(jsonResponses))
-	}
-
-	// Validate response IDs
-	for i := range jsonResponses {
-		if requests[i].ID != nil && jsonResponses[i].ID != nil {
-			if !compareIDs(requests[i].ID, jsonResponses[i].ID) {
-				c.logger.WithFields(logrus.Fields{
-					"index":       i,
-					"request_id":  requests[i].ID,
-					"response_id": jsonResponses[i].ID,
-				}).Warn("JSON-RPC ID mismatch in batch response")
+	if len(jsonResponses) != expected {
+		return nil, BatchSizeMismatchError(expected, len(jsonResponses))
+	}
+
+	return c.alignBatchResponses(requests, jsonResponses), nil
+}
+
+// alignBatchResponses 将下游响应按请求顺序排列，通知对应位置留空
+//
+// 先按 id 匹配；id 缺失或无法匹配的响应按顺序填入剩余的带 id 请求
+func (c *Client) alignBatchResponses(requests []jsonrpc.Request, responses []jsonrpc.Response) []jsonrpc.Response {
+	aligned := make([]jsonrpc.Response, len(requests))
+	filled := make([]bool, len(requests))
+	unmatched := make([]int, 0)
+	for j := range responses {
+		matched := false
+		if responses[j].ID != nil {
+			for i := range requests {
+				if !filled[i] && requests[i].ID != nil && compareIDs(requests[i].ID, responses[j].ID) {
+					aligned[i], filled[i], matched = responses[j], true, true
+					break
+				}
 			}
-		} else if requests[i].ID != nil {
-			jsonResponses[i].ID = requests[i].ID
+		}
+		if !matched {
+			unmatched = append(unmatched, j)
 		}
 	}
 
-	return jsonResponses, nil
+	for i := range requests {
+		if filled[i] || requests[i].ID == nil || len(unmatched) == 0 {
+			continue
+		}
+		resp := responses[unmatched[0]]
+		unmatched = unmatched[1:]
+		if resp.ID != nil {
+			c.logger.WithFields(logrus.Fields{
+				"index":       i,
+				"request_id":  requests[i].ID,
+				"response_id": resp.ID,
+			}).Warn("JSON-RPC ID mismatch in batch response")
+		} else {
+			resp.ID = requests[i].ID
+		}
+		aligned[i], filled[i] = resp, true
+	}
+	return aligned
+}
+
+// 批量重试的退避参数
+var (
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = 2 * time.Second
+)
+
+// nonIdempotentMethods 重复提交可能产生副作用的方法，包含这些方法的批次不重试
+var nonIdempotentMethods = map[string]bool{
+	"eth_sendRawTransaction": true,
+	"eth_sendTransaction":    true,
+}
+
+// isIdempotentBatch 检查批次是否可以安全重试
+func isIdempotentBatch(requests []jsonrpc.Request) bool {
+	for i := range requests {
+		if nonIdempotentMethods[requests[i].Method] {
+			return false
+		}
+	}
+	return true
+}
+
+// retryBackoff 返回批量重试的退避策略（full jitter）
+func retryBackoff() utils.Backoff {
+	return utils.Backoff{Initial: retryBaseDelay, Max: retryMaxDelay, Multiplier: 2, Jitter: true}
+}
+
+// withRequestTimeout derives a context bounded by the configured request timeout.
+//
+// context.WithTimeout keeps the caller's deadline when it is earlier, so the
+// shorter of the two always applies.
+func (c *Client) withRequestTimeout(ctx context.Context, sendRaw bool) (context.Context, context.CancelFunc) {
+	timeout := c.config.RequestTimeout
+	if timeout <= 0 {
+		timeout = config.DefaultDownstreamRequestTimeout
+	}
+	if sendRaw {
+		timeout = c.config.SendRawTransactionTimeout
+		if timeout <= 0 {
+			timeout = config.DefaultDownstreamSendRawTxTimeout
+		}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// containsMethod reports whether any request in the batch calls method.
+func containsMethod(requests []jsonrpc.Request, method string) bool {
+	for i := range requests {
+		if requests[i].Method == method {
+			return true
+		}
+	}
+	return false
 }
 
 // toString 高效地将不同类型转换为字符串
@@ -272,26 +539,36 @@ func compareIDs(id1, id2 interface{}) bool {
 
 // TestConnection tests connectivity to downstream Ethereum node.
 //
-// This method sends a web3_clientVersion request to verify
-// the node is reachable and responsive.
+// This method sends the configured health-check request
+// (DownstreamConfig.HealthCheckMethod, web3_clientVersion by default) without
+// parameters to verify the node is reachable and serves the method.
 //
 // Parameters:
 //   - ctx: Context for request (supports cancellation and timeout)
 //
 // Returns:
-//   - error: An error if connection test fails
+//   - error: An error if the request fails or the node returns a JSON-RPC error
 func (c *Client) TestConnection(ctx context.Context) error {
+	method := c.config.HealthCheckMethod
+	if method == "" {
+		method = config.DefaultDownstreamHealthCheckMethod
+	}
+
 	// 创建一个简单的测试请求
 	testReq := jsonrpc.Request{
 		JSONRPC: "2.0",
-		Method:  "web3_clientVersion",
+		Method:  method,
 		ID:      1,
 	}
 
-	_, err := c.ForwardRequest(ctx, &testReq)
+	resp, err := c.ForwardRequest(ctx, &testReq)
 	if err != nil {
 		return ConnectionError(fmt.Errorf("connection test failed: %w", err))
 	}
+	// 服务商禁用或限流健康检查方法时返回 JSON-RPC 错误，同样视为不可用
+	if resp.Error != nil {
+		return RequestError(fmt.Errorf("connection test %s failed: %s", method, resp.Error.Message))
+	}
 
 	return nil
 }
@@ -316,6 +593,14 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// CloseIdleConnections drops the client's pooled keep-alive connections, so
+// the next request dials the downstream node again. The client stays usable.
+func (c *Client) CloseIdleConnections() {
+	if c.httpClient != nil {
+		c.httpClient.CloseIdleConnections()
+	}
+}
+
 // GetTransport returns HTTP transport used by client.
 // This is primarily for testing purposes to verify connection pool configuration.
 func (c *Client) GetTransport() *http.Transport {
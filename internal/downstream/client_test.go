@@ -2,12 +2,19 @@ package downstream
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -295,6 +302,17 @@ func TestClient_ForwardRequest_ErrorHandling(t *testing.T) {
 			expectedError:  "invalid response from downstream service",
 			checkErrorType: IsInvalidResponseError,
 		},
+		{
+			name: "mismatched response ID",
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				// 模拟代理返回了其他请求的响应
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"0x10","id":42}`))
+			},
+			expectedError:  "response ID mismatch: expected 1, got 42",
+			checkErrorType: IsIDMismatchError,
+		},
 		{
 			name: "timeout",
 			serverHandler: func(w http.ResponseWriter, r *http.Request) {
@@ -390,6 +408,43 @@ func TestClient_TestConnection(t *testing.T) {
 	}
 }
 
+func TestClient_TestConnection_HealthCheckMethod(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpc.Request
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		received = req.Method
+		resp := jsonrpc.Response{JSONRPC: "2.0", Result: json.RawMessage(`"0x10"`), ID: req.ID}
+		if req.Method == "web3_clientVersion" {
+			resp = jsonrpc.Response{JSONRPC: "2.0", Error: &jsonrpc.Error{Code: -32601, Message: "method disabled"}, ID: req.ID}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	t.Run("configured method", func(t *testing.T) {
+		client := newValidatedClient(t, &config.DownstreamConfig{HTTPHost: server.URL, HTTPPath: "/", HealthCheckMethod: "eth_blockNumber"})
+		if err := client.TestConnection(context.Background()); err != nil {
+			t.Errorf("TestConnection failed: %v", err)
+		}
+		if received != "eth_blockNumber" {
+			t.Errorf("health check method = %s, want eth_blockNumber", received)
+		}
+	})
+
+	t.Run("method rejected by node", func(t *testing.T) {
+		client := newValidatedClient(t, &config.DownstreamConfig{HTTPHost: server.URL, HTTPPath: "/"})
+		err := client.TestConnection(context.Background())
+		if err == nil || !contains(err.Error(), "method disabled") {
+			t.Errorf("TestConnection error = %v, want JSON-RPC error reported", err)
+		}
+		if received != config.DefaultDownstreamHealthCheckMethod {
+			t.Errorf("health check method = %s, want default %s", received, config.DefaultDownstreamHealthCheckMethod)
+		}
+	})
+}
+
 func TestClient_GetEndpoint(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -560,6 +615,225 @@ func TestSimpleForwarder(t *testing.T) {
 	}
 }
 
+func TestClient_ForwardBatchRequest_Retry(t *testing.T) {
+	origBase, origMax := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 5*time.Millisecond
+	defer func() { retryBaseDelay, retryMaxDelay = origBase, origMax }()
+
+	// 前 failures 次请求直接断开连接，之后正常返回
+	newFlakyServer := func(failures int32, attempts *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(attempts, 1) <= failures {
+				hj, _ := w.(http.Hijacker)
+				conn, _, _ := hj.Hijack()
+				_ = conn.Close()
+				return
+			}
+			var requests []jsonrpc.Request
+			_ = json.NewDecoder(r.Body).Decode(&requests)
+			responses := make([]jsonrpc.Response, len(requests))
+			for i := range requests {
+				responses[i] = jsonrpc.Response{JSONRPC: "2.0", Result: json.RawMessage(`"0x1"`), ID: requests[i].ID}
+			}
+			_ = json.NewEncoder(w).Encode(responses)
+		}))
+	}
+
+	tests := []struct {
+		name             string
+		maxRetries       int
+		failures         int32
+		method           string
+		expectErr        bool
+		expectedAttempts int32
+	}{
+		{name: "retries until success", maxRetries: 3, failures: 2, method: "eth_blockNumber", expectErr: false, expectedAttempts: 3},
+		{name: "gives up after max retries", maxRetries: 1, failures: 5, method: "eth_blockNumber", expectErr: true, expectedAttempts: 2},
+		{name: "no retries by default", maxRetries: 0, failures: 1, method: "eth_blockNumber", expectErr: true, expectedAttempts: 1},
+		{name: "non-idempotent batch not retried", maxRetries: 3, failures: 1, method: "eth_sendRawTransaction", expectErr: true, expectedAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := newFlakyServer(tt.failures, &attempts)
+			defer server.Close()
+
+			client := newValidatedClient(t, &config.DownstreamConfig{
+				HTTPHost:   server.URL,
+				HTTPPath:   "/",
+				MaxRetries: tt.maxRetries,
+			})
+
+			requests := []jsonrpc.Request{
+				{JSONRPC: "2.0", Method: "eth_chainId", ID: 1},
+				{JSONRPC: "2.0", Method: tt.method, ID: 2},
+			}
+			responses, err := client.ForwardBatchRequest(context.Background(), requests)
+			if tt.expectErr {
+				if err == nil || !IsConnectionError(err) {
+					t.Errorf("Expected connection error, got %v", err)
+				}
+			} else if err != nil || len(responses) != 2 {
+				t.Errorf("Expected 2 responses, got %d (err: %v)", len(responses), err)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestBackoffWithJitter(t *testing.T) {
+	for attempt := 0; attempt < 70; attempt++ {
+		delay := retryBackoff().Delay(attempt)
+		if delay <= 0 || delay > retryMaxDelay {
+			t.Errorf("attempt %d: delay %v out of range (0, %v]", attempt, delay, retryMaxDelay)
+		}
+	}
+}
+
+func TestClient_CompressedResponses(t *testing.T) {
+	compress := func(encoding string, data []byte) []byte {
+		var buf bytes.Buffer
+		switch encoding {
+		case "gzip":
+			w := gzip.NewWriter(&buf)
+			_, _ = w.Write(data)
+			_ = w.Close()
+		case "deflate":
+			w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+			_, _ = w.Write(data)
+			_ = w.Close()
+		default:
+			buf.Write(data)
+		}
+		return buf.Bytes()
+	}
+
+	newServer := func(encoding string, payload []byte) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if encoding != "" {
+				w.Header().Set("Content-Encoding", encoding)
+			}
+			_, _ = w.Write(compress(encoding, payload))
+		}))
+	}
+
+	tests := []struct {
+		name      string
+		encoding  string
+		batch     bool
+		limit     int64
+		expectErr bool
+	}{
+		{name: "gzip single", encoding: "gzip"},
+		{name: "deflate single", encoding: "deflate"},
+		{name: "gzip batch", encoding: "gzip", batch: true},
+		{name: "deflate batch", encoding: "deflate", batch: true},
+		{name: "plain single", encoding: ""},
+		{name: "gzip over size limit", encoding: "gzip", limit: 10, expectErr: true},
+		{name: "unsupported encoding", encoding: "br", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.limit > 0 {
+				orig := maxDecompressedResponseSize
+				maxDecompressedResponseSize = tt.limit
+				defer func() { maxDecompressedResponseSize = orig }()
+			}
+
+			payload := []byte(`{"jsonrpc":"2.0","result":"0x1","id":1}`)
+			if tt.batch {
+				payload = []byte(`[{"jsonrpc":"2.0","result":"0x1","id":1},{"jsonrpc":"2.0","result":"0x2","id":2}]`)
+			}
+			server := newServer(tt.encoding, payload)
+			defer server.Close()
+
+			client := newValidatedClient(t, &config.DownstreamConfig{HTTPHost: server.URL, HTTPPath: "/"})
+
+			if tt.batch {
+				responses, err := client.ForwardBatchRequest(context.Background(), []jsonrpc.Request{
+					{JSONRPC: "2.0", Method: "eth_chainId", ID: 1},
+					{JSONRPC: "2.0", Method: "eth_blockNumber", ID: 2},
+				})
+				if err != nil || len(responses) != 2 || string(responses[1].Result) != `"0x2"` {
+					t.Errorf("unexpected batch result: %v (err: %v)", responses, err)
+				}
+				return
+			}
+
+			resp, err := client.ForwardRequest(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_chainId", ID: 1})
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error, got response %v", resp)
+				}
+				return
+			}
+			if err != nil || string(resp.Result) != `"0x1"` {
+				t.Errorf("unexpected result: %v (err: %v)", resp, err)
+			}
+		})
+	}
+}
+
+func TestClient_MaxResponseBytes(t *testing.T) {
+	single := `{"jsonrpc":"2.0","result":"0x1","id":1}`
+	batch := `[{"jsonrpc":"2.0","result":"0x1","id":1},{"jsonrpc":"2.0","result":"0x2","id":2}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if bytes.HasPrefix(body, []byte("[")) {
+			_, _ = w.Write([]byte(batch))
+			return
+		}
+		_, _ = w.Write([]byte(single))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		limit     int64
+		batch     bool
+		expectErr bool
+	}{
+		{name: "single exact fit", limit: int64(len(single))},
+		{name: "single over limit", limit: int64(len(single)) - 1, expectErr: true},
+		{name: "batch exact fit", limit: int64(len(batch)), batch: true},
+		{name: "batch over limit", limit: int64(len(batch)) - 1, batch: true, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newValidatedClient(t, &config.DownstreamConfig{
+				HTTPHost: server.URL, HTTPPath: "/", MaxResponseBytes: tt.limit,
+			})
+
+			var err error
+			if tt.batch {
+				_, err = client.ForwardBatchRequest(context.Background(), []jsonrpc.Request{
+					{JSONRPC: "2.0", Method: "eth_chainId", ID: 1},
+					{JSONRPC: "2.0", Method: "eth_blockNumber", ID: 2},
+				})
+			} else {
+				_, err = client.ForwardRequest(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_chainId", ID: 1})
+			}
+
+			if !tt.expectErr {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if !IsInvalidResponseError(err) || !errors.Is(err, errResponseTooLarge) {
+				t.Errorf("Expected response too large error, got %v", err)
+			}
+		})
+	}
+}
+
 // contains 检查字符串是否包含子串
 func contains(s, substr string) bool {
 	return bytes.Contains([]byte(s), []byte(substr))
@@ -596,3 +870,164 @@ func BenchmarkCompareIDs_Sprintf(b *testing.B) {
 		_ = s == "12345"
 	}
 }
+
+func TestClient_RequestTimeout(t *testing.T) {
+	// 服务器按方法延迟响应
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpc.Request
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		_ = json.NewEncoder(w).Encode(jsonrpc.Response{JSONRPC: "2.0", Result: json.RawMessage(`"0x1"`), ID: req.ID})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		method        string
+		callerTimeout time.Duration
+		expectTimeout bool
+	}{
+		{name: "ordinary request exceeds request timeout", method: "eth_chainId", expectTimeout: true},
+		{name: "send raw transaction uses longer timeout", method: "eth_sendRawTransaction", expectTimeout: false},
+		{name: "shorter caller deadline wins", method: "eth_sendRawTransaction", callerTimeout: 20 * time.Millisecond, expectTimeout: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newValidatedClient(t, &config.DownstreamConfig{
+				HTTPHost:                  server.URL,
+				HTTPPath:                  "/",
+				RequestTimeout:            30 * time.Millisecond,
+				SendRawTransactionTimeout: 2 * time.Second,
+			})
+
+			ctx := context.Background()
+			if tt.callerTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.callerTimeout)
+				defer cancel()
+			}
+
+			_, err := client.ForwardRequest(ctx, &jsonrpc.Request{JSONRPC: "2.0", Method: tt.method, ID: 1})
+			if tt.expectTimeout {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("Expected deadline exceeded error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_Proxy(t *testing.T) {
+	// 作为 HTTP 代理的测试服务器：收到绝对 URI 请求并直接返回响应
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.Host + " " + r.Header.Get("Proxy-Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"0x1","id":1}`))
+	}))
+	defer proxy.Close()
+
+	client := newValidatedClient(t, &config.DownstreamConfig{
+		HTTPHost: "http://node.internal:8545",
+		HTTPPath: "/",
+		Proxy:    "http://user:pass@" + proxy.Listener.Addr().String(),
+	})
+
+	resp, err := client.ForwardRequest(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_chainId", ID: 1})
+	if err != nil {
+		t.Fatalf("ForwardRequest() error = %v", err)
+	}
+	if string(resp.Result) != `"0x1"` {
+		t.Errorf("result = %s, want \"0x1\"", resp.Result)
+	}
+
+	// Basic dXNlcjpwYXNz = base64("user:pass")
+	if got, want := proxied.Load(), "node.internal:8545 Basic dXNlcjpwYXNz"; got != want {
+		t.Errorf("proxied request = %v, want %q", got, want)
+	}
+}
+
+func TestClient_StaticHeaders(t *testing.T) {
+	var apiKey, contentType atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey.Store(r.Header.Get("X-Api-Key"))
+		contentType.Store(r.Header.Get("Content-Type"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"jsonrpc":"2.0","result":"0x1","id":1}]`))
+	}))
+	defer server.Close()
+
+	client := newValidatedClient(t, &config.DownstreamConfig{
+		HTTPHost: server.URL,
+		HTTPPath: "/",
+		Headers:  map[string]string{"x-api-key": "secret"},
+	})
+
+	_, err := client.ForwardBatchRequest(context.Background(), []jsonrpc.Request{{JSONRPC: "2.0", Method: "eth_chainId", ID: 1}})
+	if err != nil {
+		t.Fatalf("ForwardBatchRequest() error = %v", err)
+	}
+	if got := apiKey.Load(); got != "secret" {
+		t.Errorf("x-api-key = %v, want secret", got)
+	}
+	if got := contentType.Load(); got != "application/json" {
+		t.Errorf("Content-Type = %v, want application/json", got)
+	}
+}
+
+func TestClient_TLSCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"0x1","id":1}`))
+	}))
+	defer server.Close()
+
+	// 测试服务器证书由私有 CA（自签名）签发，不在系统根证书中
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	request := &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_chainId", ID: 1}
+
+	t.Run("untrusted without CA file", func(t *testing.T) {
+		client := newValidatedClient(t, &config.DownstreamConfig{HTTPHost: server.URL, HTTPPath: "/"})
+		if _, err := client.ForwardRequest(context.Background(), request); err == nil {
+			t.Fatal("Expected certificate verification error")
+		}
+	})
+
+	t.Run("trusted with CA file", func(t *testing.T) {
+		client := newValidatedClient(t, &config.DownstreamConfig{
+			HTTPHost:      server.URL,
+			HTTPPath:      "/",
+			TLSMinVersion: "1.2",
+			TLSCAFile:     caFile,
+		})
+		resp, err := client.ForwardRequest(context.Background(), request)
+		if err != nil {
+			t.Fatalf("ForwardRequest() error = %v", err)
+		}
+		if string(resp.Result) != `"0x1"` {
+			t.Errorf("result = %s, want \"0x1\"", resp.Result)
+		}
+	})
+
+	t.Run("insecure skip verify", func(t *testing.T) {
+		client := newValidatedClient(t, &config.DownstreamConfig{
+			HTTPHost:              server.URL,
+			HTTPPath:              "/",
+			TLSInsecureSkipVerify: true,
+		})
+		if _, err := client.ForwardRequest(context.Background(), request); err != nil {
+			t.Fatalf("ForwardRequest() error = %v", err)
+		}
+	})
+}
@@ -73,6 +73,14 @@ func IsInvalidResponseError(err error) bool {
 	return false
 }
 
+// IsIDMismatchError 检查是否是ID不匹配错误
+func IsIDMismatchError(err error) bool {
+	if e, ok := err.(*Error); ok {
+		return e.Code == ErrorCodeIDMismatch
+	}
+	return false
+}
+
 // WrapError 包装错误为下游服务错误
 func WrapError(err error, code ErrorCode, message string) error {
 	if err == nil {
@@ -0,0 +1,154 @@
+package downstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/umbracle/ethgo"
+)
+
+// RPCClient makes typed JSON-RPC calls (nonce, balance, gas, chain ID) to the
+// downstream node through a ClientInterface.
+//
+// Calls go through the same HTTP client as forwarded requests, so the
+// configured proxy, TLS settings, static headers and timeouts apply to them.
+// A JSON-RPC error returned by the node is reported as *jsonrpc.Error; any
+// other error means the node could not be reached or answered invalidly.
+type RPCClient struct {
+	client ClientInterface
+	nextID atomic.Int64
+}
+
+// NewRPCClient creates an RPC client that sends calls through client.
+//
+// Parameters:
+//   - client: The downstream client carrying the requests
+//
+// Returns:
+//   - *RPCClient: A new RPC client
+func NewRPCClient(client ClientInterface) *RPCClient {
+	return &RPCClient{client: client}
+}
+
+// Call invokes a JSON-RPC method and decodes its result into out.
+//
+// Parameters:
+//   - method: The JSON-RPC method
+//   - out: Pointer the result is decoded into; nil discards it
+//   - params: Positional parameters, encoded as JSON
+//
+// Returns:
+//   - error: *jsonrpc.Error if the node returned an error, otherwise the transport or decoding error
+func (c *RPCClient) Call(method string, out interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s params: %w", method, err)
+	}
+
+	resp, err := c.client.ForwardRequest(context.Background(), &jsonrpc.Request{
+		JSONRPC: jsonrpc.JSONRPCVersion,
+		Method:  method,
+		Params:  rawParams,
+		ID:      c.nextID.Add(1),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return InvalidResponseError(fmt.Errorf("failed to decode %s result: %w", method, err))
+	}
+	return nil
+}
+
+// ChainID returns the chain ID reported by eth_chainId.
+func (c *RPCClient) ChainID() (*big.Int, error) {
+	var out string
+	if err := c.Call("eth_chainId", &out); err != nil {
+		return nil, err
+	}
+	return parseHexBig(out)
+}
+
+// GetNonce returns the transaction count of addr at the given block.
+func (c *RPCClient) GetNonce(addr ethgo.Address, block ethgo.BlockNumberOrHash) (uint64, error) {
+	var out string
+	if err := c.Call("eth_getTransactionCount", &out, addr, block.Location()); err != nil {
+		return 0, err
+	}
+	return parseHexUint64(out)
+}
+
+// GetBalance returns the balance of addr in wei at the given block.
+func (c *RPCClient) GetBalance(addr ethgo.Address, block ethgo.BlockNumberOrHash) (*big.Int, error) {
+	var out string
+	if err := c.Call("eth_getBalance", &out, addr, block.Location()); err != nil {
+		return nil, err
+	}
+	return parseHexBig(out)
+}
+
+// GasPrice returns the node's current gas price in wei.
+func (c *RPCClient) GasPrice() (uint64, error) {
+	var out string
+	if err := c.Call("eth_gasPrice", &out); err != nil {
+		return 0, err
+	}
+	return parseHexUint64(out)
+}
+
+// EstimateGas returns the gas the node estimates msg needs.
+func (c *RPCClient) EstimateGas(msg *ethgo.CallMsg) (uint64, error) {
+	var out string
+	if err := c.Call("eth_estimateGas", &out, msg); err != nil {
+		return 0, err
+	}
+	return parseHexUint64(out)
+}
+
+// CloseIdleConnections drops pooled connections of the underlying client,
+// so the next call dials the node again. Clients that do not pool
+// connections are left untouched.
+func (c *RPCClient) CloseIdleConnections() {
+	if closer, ok := c.client.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// parseHexUint64 解析 0x 前缀的十六进制数量
+func parseHexUint64(s string) (uint64, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return 0, InvalidResponseError(fmt.Errorf("invalid hex quantity %q", s))
+	}
+	v, err := strconv.ParseUint(s[2:], 16, 64)
+	if err != nil {
+		return 0, InvalidResponseError(fmt.Errorf("invalid hex quantity %q: %w", s, err))
+	}
+	return v, nil
+}
+
+// parseHexBig 解析 0x 前缀的十六进制大整数
+func parseHexBig(s string) (*big.Int, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, InvalidResponseError(fmt.Errorf("invalid hex quantity %q", s))
+	}
+	v, ok := new(big.Int).SetString(s[2:], 16)
+	if !ok {
+		return nil, InvalidResponseError(fmt.Errorf("invalid hex quantity %q", s))
+	}
+	return v, nil
+}
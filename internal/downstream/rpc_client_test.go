@@ -0,0 +1,140 @@
+package downstream
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/umbracle/ethgo"
+)
+
+// newRPCTestServer 按方法返回固定结果的 JSON-RPC 测试服务器，未登记的方法返回 JSON-RPC 错误
+func newRPCTestServer(results map[string]string, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		result, ok := results[req.Method]
+		if !ok {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":-32601,"message":"method not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":` + result + `}`))
+	}))
+}
+
+func TestRPCClient_Calls(t *testing.T) {
+	var requests atomic.Int32
+	server := newRPCTestServer(map[string]string{
+		"eth_chainId":             `"0x89"`,
+		"eth_getTransactionCount": `"0x5"`,
+		"eth_getBalance":          `"0xde0b6b3a7640000"`,
+		"eth_gasPrice":            `"0x3b9aca00"`,
+		"eth_estimateGas":         `"0x5208"`,
+	}, &requests)
+	defer server.Close()
+
+	rpc := NewRPCClient(newValidatedClient(t, &config.DownstreamConfig{HTTPHost: server.URL, HTTPPath: "/"}))
+	addr := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	if chainID, err := rpc.ChainID(); err != nil || chainID.Int64() != 137 {
+		t.Errorf("ChainID() = %v, %v; want 137", chainID, err)
+	}
+	if nonce, err := rpc.GetNonce(addr, ethgo.Pending); err != nil || nonce != 5 {
+		t.Errorf("GetNonce() = %d, %v; want 5", nonce, err)
+	}
+	if balance, err := rpc.GetBalance(addr, ethgo.Latest); err != nil || balance.String() != "1000000000000000000" {
+		t.Errorf("GetBalance() = %v, %v; want 1 ether", balance, err)
+	}
+	if price, err := rpc.GasPrice(); err != nil || price != 1000000000 {
+		t.Errorf("GasPrice() = %d, %v; want 1 gwei", price, err)
+	}
+	if gas, err := rpc.EstimateGas(&ethgo.CallMsg{From: addr, To: &addr}); err != nil || gas != 21000 {
+		t.Errorf("EstimateGas() = %d, %v; want 21000", gas, err)
+	}
+
+	// 下游返回的 JSON-RPC 错误以 *jsonrpc.Error 返回，与连接错误区分
+	var rpcErr *jsonrpc.Error
+	if err := rpc.Call("eth_unknown", nil); !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Errorf("Call() error = %v, want JSON-RPC error -32601", err)
+	}
+	if got := requests.Load(); got != 6 {
+		t.Errorf("server received %d requests, want 6", got)
+	}
+}
+
+func TestRPCClient_Proxy(t *testing.T) {
+	// 查询下游的调用与转发请求一样经过代理，下游地址本身不可直接访问
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.Host)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"0x1","id":1}`))
+	}))
+	defer proxy.Close()
+
+	rpc := NewRPCClient(newValidatedClient(t, &config.DownstreamConfig{
+		HTTPHost: "http://node.internal:8545",
+		HTTPPath: "/",
+		Proxy:    "http://" + proxy.Listener.Addr().String(),
+	}))
+
+	chainID, err := rpc.ChainID()
+	if err != nil || chainID.Int64() != 1 {
+		t.Fatalf("ChainID() = %v, %v; want 1 through the proxy", chainID, err)
+	}
+	if got := proxied.Load(); got != "node.internal:8545" {
+		t.Errorf("proxied host = %v, want node.internal:8545", got)
+	}
+}
+
+func TestRPCClient_TLSCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"0x1","id":1}`))
+	}))
+	defer server.Close()
+
+	// 查询下游的调用与转发请求一样使用配置的私有 CA 与最低 TLS 版本
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	untrusted := NewRPCClient(newValidatedClient(t, &config.DownstreamConfig{HTTPHost: server.URL, HTTPPath: "/"}))
+	if _, err := untrusted.ChainID(); err == nil {
+		t.Error("Expected certificate verification error without CA file")
+	}
+
+	trusted := NewRPCClient(newValidatedClient(t, &config.DownstreamConfig{
+		HTTPHost:      server.URL,
+		HTTPPath:      "/",
+		TLSMinVersion: "1.2",
+		TLSCAFile:     caFile,
+	}))
+	if chainID, err := trusted.ChainID(); err != nil || chainID.Int64() != 1 {
+		t.Errorf("ChainID() = %v, %v; want 1 with CA file", chainID, err)
+	}
+
+	insecure := NewRPCClient(newValidatedClient(t, &config.DownstreamConfig{
+		HTTPHost:              server.URL,
+		HTTPPath:              "/",
+		TLSInsecureSkipVerify: true,
+	}))
+	if _, err := insecure.ChainID(); err != nil {
+		t.Errorf("ChainID() error = %v with insecure skip verify", err)
+	}
+}
@@ -3,4 +3,10 @@ package jsonrpc
 const (
 	// JSONRPCVersion JSON-RPC 版本
 	JSONRPCVersion = "2.0"
+
+	// MaxParamsDepth params 允许的最大嵌套深度
+	MaxParamsDepth = 32
+
+	// MaxArrayLength 请求中单个数组允许的最大元素数
+	MaxArrayLength = 10000
 )
@@ -22,6 +22,18 @@ const (
 	// 服务器错误（-32000 到 -32099 为服务器保留错误码）
 	CodeServerErrorStart = -32000
 	CodeServerErrorEnd   = -32099
+
+	// 请求处理超时
+	CodeRequestTimeout = -32001
+
+	// 签名请求等待 KMS 审批（异步审批模式）
+	CodeApprovalPending = -32002
+
+	// 交易被预签名策略拒绝
+	CodePolicyDenied = -32003
+
+	// 签名密钥不允许通过 JSON-RPC 使用
+	CodeKeyNotAllowed = -32004
 )
 
 // 标准错误
@@ -55,6 +67,12 @@ var (
 		Code:    CodeInternalError,
 		Message: "Internal error",
 	}
+
+	// RequestTimeoutError 表示请求处理超时
+	RequestTimeoutError = &Error{
+		Code:    CodeRequestTimeout,
+		Message: "Request timeout",
+	}
 )
 
 // NewServerError 创建服务器错误
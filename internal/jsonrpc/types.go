@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 )
@@ -28,30 +29,62 @@ type Error struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// ParseOptions 控制请求解析的严格程度，零值为严格模式
+type ParseOptions struct {
+	// AllowMissingVersion 接受缺少 jsonrpc 字段的请求（旧版客户端）并视为 2.0，显式的错误版本仍会被拒绝
+	AllowMissingVersion bool
+}
+
 // ParseRequest 解析 JSON-RPC 请求
+//
+// 解析前先对原始数据做一次不分配内存的结构扫描，嵌套过深或数组过大的请求
+// 会以 ParseError 拒绝，避免恶意输入导致过量内存占用。
 func ParseRequest(data []byte) ([]Request, error) {
-	// 尝试解析为单个请求
-	var singleReq Request
-	if err := json.Unmarshal(data, &singleReq); err == nil {
-		// 验证单个请求
+	return ParseRequestWithOptions(data, ParseOptions{})
+}
+
+// ParseRequestWithOptions 按 opts 解析 JSON-RPC 请求，其余语义与 ParseRequest 相同
+func ParseRequestWithOptions(data []byte, opts ParseOptions) ([]Request, error) {
+	trimmed := bytes.TrimSpace(data)
+	if err := checkStructure(trimmed, MaxParamsDepth+2); err != nil {
+		return nil, err
+	}
+
+	// 单个请求
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		var singleReq Request
+		if err := json.Unmarshal(trimmed, &singleReq); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC request: %v", err)
+		}
+		if err := checkParams(&singleReq); err != nil {
+			return nil, err
+		}
+		opts.normalizeVersion(&singleReq, trimmed)
 		if err := validateRequest(&singleReq); err != nil {
 			return nil, err
 		}
 		return []Request{singleReq}, nil
 	}
 
-	// 尝试解析为批量请求
-	var batchReqs []Request
-	if err := json.Unmarshal(data, &batchReqs); err != nil {
+	// 批量请求
+	var rawReqs []json.RawMessage
+	if err := json.Unmarshal(trimmed, &rawReqs); err != nil {
 		return nil, fmt.Errorf("invalid JSON-RPC request: %v", err)
 	}
 
-	// 验证批量请求
-	if len(batchReqs) == 0 {
+	if len(rawReqs) == 0 {
 		return nil, fmt.Errorf("empty batch request")
 	}
 
-	for i := range batchReqs {
+	batchReqs := make([]Request, len(rawReqs))
+	for i, raw := range rawReqs {
+		if err := json.Unmarshal(raw, &batchReqs[i]); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC request: %v", err)
+		}
+		if err := checkParams(&batchReqs[i]); err != nil {
+			return nil, err
+		}
+		opts.normalizeVersion(&batchReqs[i], raw)
 		if err := validateRequest(&batchReqs[i]); err != nil {
 			return nil, fmt.Errorf("request at index %d: %v", i, err)
 		}
@@ -60,6 +93,148 @@ func ParseRequest(data []byte) ([]Request, error) {
 	return batchReqs, nil
 }
 
+// BatchEntry 表示宽松解析后的单个批量条目
+//
+// Err 非 nil 时表示该条目无效，Request 中仅 ID（如果能解析出来）可用
+type BatchEntry struct {
+	Request Request
+	Err     *Error
+}
+
+// ParseBatchLenient 宽松解析 JSON-RPC 请求
+//
+// 与 ParseRequest 不同，批量请求中的每个条目独立解析：
+// 无效条目不会导致整个批次失败，而是以 InvalidRequest 错误占位，并保持原有顺序。
+// 单个请求（非数组）沿用 ParseRequest 的严格语义。
+func ParseBatchLenient(data []byte) ([]BatchEntry, error) {
+	return ParseBatchLenientWithOptions(data, ParseOptions{})
+}
+
+// ParseBatchLenientWithOptions 按 opts 宽松解析 JSON-RPC 请求，其余语义与 ParseBatchLenient 相同
+func ParseBatchLenientWithOptions(data []byte, opts ParseOptions) ([]BatchEntry, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		requests, err := ParseRequestWithOptions(data, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []BatchEntry{{Request: requests[0]}}, nil
+	}
+
+	if err := checkStructure(trimmed, MaxParamsDepth+2); err != nil {
+		return nil, err
+	}
+
+	var rawEntries []json.RawMessage
+	if err := json.Unmarshal(trimmed, &rawEntries); err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC request: %v", err)
+	}
+
+	if len(rawEntries) == 0 {
+		return nil, fmt.Errorf("empty batch request")
+	}
+
+	entries := make([]BatchEntry, len(rawEntries))
+	for i, raw := range rawEntries {
+		if err := json.Unmarshal(raw, &entries[i].Request); err != nil {
+			entries[i] = BatchEntry{Err: NewCustomError(CodeInvalidRequest, InvalidRequestError.Message, err.Error())}
+			continue
+		}
+		if err := checkParams(&entries[i].Request); err != nil {
+			return nil, err
+		}
+		opts.normalizeVersion(&entries[i].Request, raw)
+		if err := validateRequest(&entries[i].Request); err != nil {
+			entries[i].Err = NewCustomError(CodeInvalidRequest, InvalidRequestError.Message, err.Error())
+			if !isValidID(entries[i].Request.ID) {
+				entries[i].Request.ID = nil
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// checkParams 检查请求参数的嵌套深度是否超过 MaxParamsDepth
+func checkParams(req *Request) error {
+	return checkStructure(req.Params, MaxParamsDepth)
+}
+
+// checkStructure 扫描 JSON 数据的嵌套深度与数组长度
+//
+// 扫描只跟踪括号与字符串边界，不校验语法（语法错误由后续的 json.Unmarshal 报告），
+// 也不分配与输入大小相关的内存：栈深度受 maxDepth 限制。
+// 超出限制时返回 ParseError。
+func checkStructure(data []byte, maxDepth int) error {
+	var (
+		inString bool
+		escaped  bool
+		depth    int
+		// counts 记录每层数组已出现的逗号数；对象层记为 -1
+		counts = make([]int, 0, maxDepth)
+	)
+
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '[', '{':
+			depth++
+			if depth > maxDepth {
+				return NewCustomError(CodeParseError, ParseError.Message,
+					fmt.Sprintf("params nesting depth exceeds maximum of %d", MaxParamsDepth))
+			}
+			if c == '[' {
+				counts = append(counts, 0)
+			} else {
+				counts = append(counts, -1)
+			}
+		case ']', '}':
+			if depth > 0 {
+				depth--
+				counts = counts[:depth]
+			}
+		case ',':
+			if depth > 0 && counts[depth-1] >= 0 {
+				counts[depth-1]++
+				if counts[depth-1] >= MaxArrayLength {
+					return NewCustomError(CodeParseError, ParseError.Message,
+						fmt.Sprintf("array length exceeds maximum of %d", MaxArrayLength))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizeVersion 在允许缺省版本时，将未携带 jsonrpc 字段（或为 null）的请求视为 2.0
+//
+// 显式给出的空字符串或其它版本保持原样，由 validateRequest 拒绝
+func (o ParseOptions) normalizeVersion(req *Request, raw []byte) {
+	if !o.AllowMissingVersion || req.JSONRPC != "" {
+		return
+	}
+	var probe struct {
+		JSONRPC *json.RawMessage `json:"jsonrpc"`
+	}
+	if err := json.Unmarshal(raw, &probe); err == nil && probe.JSONRPC == nil {
+		req.JSONRPC = JSONRPCVersion
+	}
+}
+
 // validateRequest 验证单个请求
 func validateRequest(req *Request) error {
 	if req.JSONRPC != JSONRPCVersion {
@@ -70,19 +245,23 @@ func validateRequest(req *Request) error {
 		return fmt.Errorf("method is required")
 	}
 
-	// ID 可以是 null、字符串或数字
-	if req.ID != nil {
-		switch v := req.ID.(type) {
-		case string, float64, int, int64:
-			// 有效类型
-		default:
-			return fmt.Errorf("invalid id type: %T", v)
-		}
+	if !isValidID(req.ID) {
+		return fmt.Errorf("invalid id type: %T", req.ID)
 	}
 
 	return nil
 }
 
+// isValidID 检查 ID 类型是否合法：ID 可以是 null、字符串或数字
+func isValidID(id interface{}) bool {
+	switch id.(type) {
+	case nil, string, float64, int, int64:
+		return true
+	default:
+		return false
+	}
+}
+
 // NewResponse 创建成功响应
 func NewResponse(id interface{}, result interface{}) (*Response, error) {
 	resultJSON, err := json.Marshal(result)
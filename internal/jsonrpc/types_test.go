@@ -2,6 +2,8 @@ package jsonrpc
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -450,3 +452,182 @@ func TestNewResponse_ErrorCases(t *testing.T) {
 		}
 	})
 }
+
+func TestParseBatchLenient(t *testing.T) {
+	t.Run("malformed entries keep their position", func(t *testing.T) {
+		data := `[
+			{"jsonrpc":"2.0","method":"eth_blockNumber","id":1},
+			1,
+			{"jsonrpc":"2.0","method":"","id":3},
+			{"jsonrpc":"2.0","method":"eth_chainId","id":{"bad":true}},
+			{"jsonrpc":"2.0","method":"eth_chainId","id":5}
+		]`
+
+		entries, err := ParseBatchLenient([]byte(data))
+		if err != nil {
+			t.Fatalf("ParseBatchLenient failed: %v", err)
+		}
+		if len(entries) != 5 {
+			t.Fatalf("Expected 5 entries, got %d", len(entries))
+		}
+
+		if entries[0].Err != nil || entries[0].Request.Method != "eth_blockNumber" {
+			t.Errorf("Expected entry 0 to be valid, got %+v", entries[0])
+		}
+		if entries[1].Err == nil || entries[1].Err.Code != CodeInvalidRequest || entries[1].Request.ID != nil {
+			t.Errorf("Expected entry 1 to be invalid with null id, got %+v", entries[1])
+		}
+		if entries[2].Err == nil || entries[2].Request.ID != float64(3) {
+			t.Errorf("Expected entry 2 to be invalid with id 3, got %+v", entries[2])
+		}
+		if entries[3].Err == nil || entries[3].Request.ID != nil {
+			t.Errorf("Expected entry 3 to be invalid with null id, got %+v", entries[3])
+		}
+		if entries[4].Err != nil || entries[4].Request.ID != float64(5) {
+			t.Errorf("Expected entry 4 to be valid, got %+v", entries[4])
+		}
+	})
+
+	t.Run("single request is strict", func(t *testing.T) {
+		if _, err := ParseBatchLenient([]byte(`{"jsonrpc":"2.0","method":"","id":1}`)); err == nil {
+			t.Error("Expected error for invalid single request")
+		}
+
+		entries, err := ParseBatchLenient([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+		if err != nil {
+			t.Fatalf("ParseBatchLenient failed: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Err != nil {
+			t.Errorf("Expected one valid entry, got %+v", entries)
+		}
+	})
+
+	t.Run("invalid batch", func(t *testing.T) {
+		for _, data := range []string{`[]`, `[{"jsonrpc":"2.0"`, ``} {
+			if _, err := ParseBatchLenient([]byte(data)); err == nil {
+				t.Errorf("Expected error for %q", data)
+			}
+		}
+	})
+}
+
+func nestedParams(depth int) string {
+	return `{"jsonrpc":"2.0","method":"eth_call","params":` +
+		strings.Repeat("[", depth) + strings.Repeat("]", depth) + `,"id":1}`
+}
+
+func TestParseRequest_Limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "params at max depth", data: nestedParams(MaxParamsDepth)},
+		{name: "params exceeding max depth", data: nestedParams(MaxParamsDepth + 1), wantErr: true},
+		{name: "batch with deep params", data: "[" + nestedParams(MaxParamsDepth+1) + "]", wantErr: true},
+		{name: "brackets inside strings are ignored", data: `{"jsonrpc":"2.0","method":"eth_call","params":["` + strings.Repeat("[{", 100) + `\"]"],"id":1}`},
+		{name: "array at max length", data: `{"jsonrpc":"2.0","method":"eth_call","params":[` + strings.Repeat("0,", MaxArrayLength-1) + `0],"id":1}`},
+		{name: "array exceeding max length", data: `{"jsonrpc":"2.0","method":"eth_call","params":[` + strings.Repeat("0,", MaxArrayLength) + `0],"id":1}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRequest([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+			var rpcErr *Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != CodeParseError {
+				t.Errorf("Expected ParseError, got %v", err)
+			}
+		})
+	}
+
+	t.Run("lenient batch with deep params", func(t *testing.T) {
+		data := "[" + nestedParams(MaxParamsDepth+1) + "]"
+		if _, err := ParseBatchLenient([]byte(data)); err == nil {
+			t.Error("Expected error for deeply nested params")
+		}
+	})
+}
+
+func FuzzParseRequest(f *testing.F) {
+	seeds := []string{
+		`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`,
+		`[{"jsonrpc":"2.0","method":"eth_chainId","id":1},{"jsonrpc":"2.0","method":"eth_blockNumber"}]`,
+		`{"jsonrpc":"2.0","method":"eth_call","params":[{"to":"0x00"},"latest"],"id":"a"}`,
+		`[]`,
+		`[[[[`,
+		`"\\"`,
+		``,
+		nestedParams(MaxParamsDepth + 1),
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		requests, err := ParseRequest(data)
+		if err == nil && len(requests) == 0 {
+			t.Errorf("ParseRequest returned no requests and no error for %q", data)
+		}
+		_, _ = ParseBatchLenient(data)
+	})
+}
+
+func TestParseRequestWithOptions_AllowMissingVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		allow   bool
+		wantErr bool
+	}{
+		{name: "missing version rejected by default", data: `{"method":"eth_chainId","id":1}`, wantErr: true},
+		{name: "missing version accepted", data: `{"method":"eth_chainId","id":1}`, allow: true},
+		{name: "null version accepted", data: `{"jsonrpc":null,"method":"eth_chainId","id":1}`, allow: true},
+		{name: "explicit 1.0 still rejected", data: `{"jsonrpc":"1.0","method":"eth_chainId","id":1}`, allow: true, wantErr: true},
+		{name: "explicit empty version still rejected", data: `{"jsonrpc":"","method":"eth_chainId","id":1}`, allow: true, wantErr: true},
+		{name: "batch with missing version accepted", data: `[{"method":"eth_chainId","id":1},{"jsonrpc":"2.0","method":"eth_blockNumber","id":2}]`, allow: true},
+		{name: "batch with wrong version rejected", data: `[{"method":"eth_chainId","id":1},{"jsonrpc":"1.0","method":"eth_blockNumber","id":2}]`, allow: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, err := ParseRequestWithOptions([]byte(tt.data), ParseOptions{AllowMissingVersion: tt.allow})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRequestWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, req := range requests {
+				if req.JSONRPC != JSONRPCVersion {
+					t.Errorf("Expected jsonrpc to be normalized to %s, got %q", JSONRPCVersion, req.JSONRPC)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBatchLenientWithOptions_AllowMissingVersion(t *testing.T) {
+	data := `[{"method":"eth_chainId","id":1},{"jsonrpc":"1.0","method":"eth_chainId","id":2}]`
+
+	entries, err := ParseBatchLenientWithOptions([]byte(data), ParseOptions{AllowMissingVersion: true})
+	if err != nil {
+		t.Fatalf("ParseBatchLenientWithOptions() error = %v", err)
+	}
+	if entries[0].Err != nil || entries[0].Request.JSONRPC != JSONRPCVersion {
+		t.Errorf("Expected entry without version to be accepted as 2.0, got %+v", entries[0])
+	}
+	if entries[1].Err == nil || entries[1].Err.Code != CodeInvalidRequest {
+		t.Errorf("Expected explicit 1.0 entry to be rejected, got %+v", entries[1])
+	}
+
+	entries, err = ParseBatchLenient([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseBatchLenient() error = %v", err)
+	}
+	if entries[0].Err == nil {
+		t.Error("Expected entry without version to be rejected in strict mode")
+	}
+}
@@ -0,0 +1,91 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/config"
+	apperrors "github.com/mowind/web3signer-go/internal/errors"
+)
+
+func TestClient_AuthFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		operation string
+		call      func(c *Client) error
+	}{
+		{
+			name:      "sign unauthorized",
+			status:    http.StatusUnauthorized,
+			operation: kmsOperationSign,
+			call: func(c *Client) error {
+				_, err := c.Sign(context.Background(), "key-1", []byte("message"))
+				return err
+			},
+		},
+		{
+			name:      "sign forbidden",
+			status:    http.StatusForbidden,
+			operation: kmsOperationSign,
+			call: func(c *Client) error {
+				_, err := c.Sign(context.Background(), "key-1", []byte("message"))
+				return err
+			},
+		},
+		{
+			name:      "get task unauthorized",
+			status:    http.StatusUnauthorized,
+			operation: kmsOperationGetTask,
+			call: func(c *Client) error {
+				_, err := c.GetTaskResult(context.Background(), "task-1")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_ = json.NewEncoder(w).Encode(ErrorResponse{Code: tt.status, Message: "signature expired"})
+			}))
+			defer server.Close()
+
+			client := NewClient(&config.KMSConfig{
+				Endpoint:          server.URL,
+				FallbackEndpoints: []string{server.URL},
+				AccessKeyID:       "AK1234567890",
+				SecretKey:         "wrong-secret",
+			}, defaultLogger())
+
+			before := authFailures.Value(tt.operation)
+			err := tt.call(client)
+
+			var appErr *apperrors.AppError
+			if !errors.As(err, &appErr) || appErr.Type != apperrors.ErrorTypeKMSAuth {
+				t.Fatalf("Expected KMS auth error, got %v", err)
+			}
+			if appErr.Context["status_code"] != tt.status {
+				t.Errorf("status_code context = %v, want %d", appErr.Context["status_code"], tt.status)
+			}
+			if apperrors.IsRetryable(appErr) {
+				t.Error("KMS auth errors must not be retryable")
+			}
+			if got := atomic.LoadInt32(&requests); got != 1 {
+				t.Errorf("KMS received %d requests, want 1 (no retry or failover)", got)
+			}
+			if got := authFailures.Value(tt.operation) - before; got != 1 {
+				t.Errorf("auth failure counter increased by %d, want 1", got)
+			}
+		})
+	}
+}
@@ -4,16 +4,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
 	"github.com/mowind/web3signer-go/internal/config"
+	apperrors "github.com/mowind/web3signer-go/internal/errors"
 	"github.com/sirupsen/logrus"
 )
 
+// taskPollingTimeout bounds how long WaitForTaskCompletion polls an approval task.
+const taskPollingTimeout = config.KMSTaskPollingTimeout
+
 // Client is an MPC-KMS client for signing operations.
 //
 // It wraps an HTTP client with HMAC-SHA256 authentication and provides
@@ -22,11 +29,25 @@ type Client struct {
 	kmsConfig  *config.KMSConfig
 	httpClient HTTPClientInterface
 	logger     *logrus.Logger
+	clock      Clock
+
+	// summarySanitizer 发送前清理审批摘要字段，nil 表示使用按配置长度限制的默认清理器
+	summarySanitizer SummarySanitizer
 
 	// URL caching to avoid repeated string concatenation
 	signURL         string
 	taskURLTemplate string
 	urlMu           sync.RWMutex
+
+	// taskEndpoints 记录创建审批任务的端点，任务只能在该端点上轮询
+	taskEndpoints taskEndpointMap
+
+	// pendingTasks 记录正在轮询的审批任务（taskID -> PendingTask）
+	pendingTasks sync.Map
+
+	// approvalTasks 记录去重窗口内待审批的任务（去重键 -> approvalEntry）
+	approvalTasks map[string]*approvalEntry
+	approvalMu    sync.Mutex
 }
 
 // NewClient creates a new MPC-KMS client with default HTTP client.
@@ -42,6 +63,7 @@ func NewClient(kmsCfg *config.KMSConfig, logger *logrus.Logger) *Client {
 		kmsConfig:  kmsCfg,
 		httpClient: NewHTTPClient(kmsCfg, logger),
 		logger:     logger,
+		clock:      RealClock(),
 	}
 }
 
@@ -61,6 +83,7 @@ func NewClientWithHTTPClient(kmsCfg *config.KMSConfig, logger *logrus.Logger, ht
 		kmsConfig:  kmsCfg,
 		httpClient: httpClient,
 		logger:     logger,
+		clock:      RealClock(),
 	}
 }
 
@@ -80,7 +103,83 @@ func NewClientWithLogger(kmsCfg *config.KMSConfig, logger *logrus.Logger, httpCl
 		kmsConfig:  kmsCfg,
 		httpClient: httpClient,
 		logger:     logger,
+		clock:      RealClock(),
+	}
+}
+
+// WithSummarySanitizer replaces the sanitizer applied to approval summaries.
+//
+// Every string field of a SignSummary passes through the sanitizer before it
+// is sent; see NewSummarySanitizer for the default behavior.
+//
+// Parameters:
+//   - sanitizer: The sanitizer to use; nil restores the default
+//
+// Returns:
+//   - *Client: The same client for chaining
+func (c *Client) WithSummarySanitizer(sanitizer SummarySanitizer) *Client {
+	c.summarySanitizer = sanitizer
+	return c
+}
+
+// sanitizeSummary 返回清理后的摘要副本，已登记小数位数的代币金额先转换为可读单位
+func (c *Client) sanitizeSummary(summary *SignSummary) *SignSummary {
+	sanitizer := c.summarySanitizer
+	if sanitizer == nil {
+		sanitizer = NewSummarySanitizer(c.kmsConfig.SummaryMaxFieldLength)
+	}
+	if summary != nil {
+		if decimals, ok := c.kmsConfig.SummaryDecimals(summary.Token); ok {
+			summary = summary.WithDisplayAmount(decimals, c.kmsConfig.SummaryAmountPrecision)
+		}
+	}
+	return summary.Sanitize(sanitizer)
+}
+
+// WithClock replaces the clock used for durations and task polling.
+//
+// If the underlying HTTP client is the default *HTTPClient, its Date header
+// generation is switched to the same clock.
+//
+// Parameters:
+//   - clock: The clock to use; nil restores the real clock
+//
+// Returns:
+//   - *Client: The same client for chaining
+func (c *Client) WithClock(clock Clock) *Client {
+	if clock == nil {
+		clock = RealClock()
+	}
+	c.clock = clock
+	if hc, ok := c.httpClient.(*HTTPClient); ok {
+		hc.WithClock(clock)
 	}
+	return c
+}
+
+// Clock returns the clock used for durations and task polling.
+//
+// PendingTask start times are taken from this clock.
+//
+// Returns:
+//   - Clock: The client's clock
+func (c *Client) Clock() Clock {
+	return c.clock
+}
+
+// TaskKeyID returns the key ID of an approval task created by this client.
+//
+// Tasks are known from creation until they are seen in a final state under
+// async approval, or for 24 hours for tasks nobody polls.
+//
+// Parameters:
+//   - taskID: The task ID
+//
+// Returns:
+//   - string: The key ID the task signs with
+//   - bool: False if the task was not created by this client or is no longer tracked
+func (c *Client) TaskKeyID(taskID string) (string, bool) {
+	return c.taskEndpoints.loadKeyID(taskID, c.clock.Now())
 }
 
 // resetURLCache resets the cached URLs. Used for testing when the endpoint changes.
@@ -131,6 +230,95 @@ func (c *Client) getTaskURL(taskID string) string {
 	return fmt.Sprintf("%s%s", c.taskURLTemplate, taskID)
 }
 
+// signURLFor returns the sign URL of keyID on the given endpoint.
+func (c *Client) signURLFor(endpoint, keyID string) string {
+	if endpoint == c.kmsConfig.Endpoint {
+		return c.getSignURL(keyID)
+	}
+	return fmt.Sprintf("%s/api/v1/keys/%s/sign", endpoint, keyID)
+}
+
+// taskURLFor returns the task URL of taskID on the endpoint that created it.
+func (c *Client) taskURLFor(taskID string) string {
+	endpoint, ok := c.taskEndpoints.load(taskID, c.clock.Now())
+	if !ok || endpoint == c.kmsConfig.Endpoint {
+		return c.getTaskURL(taskID)
+	}
+	return fmt.Sprintf("%s/api/v1/tasks/%s", endpoint, taskID)
+}
+
+// doWithFailover sends a request to each configured KMS endpoint in turn.
+//
+// It moves to the next endpoint only on connection errors, i.e. when no HTTP
+// response was received. Any HTTP response, including error statuses, is
+// returned as-is together with the endpoint that produced it.
+//
+// Requests other than GET, such as the sign task POST, fail over only when
+// the request provably never left this process (dial, DNS or proxy connect
+// errors). Errors after the request may have been sent, e.g. a timeout or
+// a reset connection while waiting for the response, are returned without
+// trying the next endpoint, so the task is not created twice.
+//
+// Parameters:
+//   - ctx: Context for the request (supports cancellation and timeout)
+//   - method: The HTTP method
+//   - body: The request body; nil for requests without a body
+//   - urlFor: Builds the request URL for an endpoint
+//
+// Returns:
+//   - *http.Response: The HTTP response
+//   - string: The endpoint that returned the response
+//   - error: The last connection error if no endpoint could be reached
+func (c *Client) doWithFailover(ctx context.Context, method string, body []byte, urlFor func(endpoint string) string) (*http.Response, string, error) {
+	endpoints := c.kmsConfig.Endpoints()
+
+	var lastErr error
+	for i, endpoint := range endpoints {
+		var bodyReader io.Reader = http.NoBody
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, urlFor(endpoint), bodyReader)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create request: %w", err)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			return resp, endpoint, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			break
+		}
+		if method != http.MethodGet && !requestNotSent(err) {
+			break
+		}
+		if i < len(endpoints)-1 {
+			c.logger.WithFields(logrus.Fields{
+				"endpoint": endpoint,
+				"next":     endpoints[i+1],
+				"error":    err.Error(),
+			}).Warn("KMS endpoint unreachable, failing over to next endpoint")
+		}
+	}
+	return nil, "", lastErr
+}
+
+// requestNotSent 判断请求是否确定未发出：拨号、DNS 解析或连接代理失败时服务端不可能收到请求
+func requestNotSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "proxyconnect")
+}
+
 // Sign signs the given message using the specified key ID.
 //
 // This is a convenience method that calls SignWithOptions with default encoding (HEX).
@@ -154,9 +342,18 @@ func (c *Client) Sign(ctx context.Context, keyID string, message []byte) ([]byte
 //   - Transaction summary for approval workflow
 //   - Callback URL for asynchronous notifications
 //
+// Summary fields are sanitized before sending, see WithSummarySanitizer.
+//
 // If the request requires approval (returns HTTP 201), it automatically polls
 // for task completion with a 5-minute timeout.
 //
+// When the endpoint cannot be reached, the request is retried on the configured
+// fallback endpoints. Approval tasks are polled on the endpoint that created them.
+//
+// With KMSConfig.ApprovalDedupWindow set, a request identical to one that
+// created a still-pending approval task within the window (same key ID,
+// encoding, message and summary) waits on that task instead of creating a new one.
+//
 // Parameters:
 //   - ctx: Context for the request (supports cancellation and timeout)
 //   - keyID: The KMS key identifier to use for signing
@@ -169,7 +366,9 @@ func (c *Client) Sign(ctx context.Context, keyID string, message []byte) ([]byte
 //   - []byte: The signature bytes
 //   - error: An error if the signing operation fails
 func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []byte, encoding DataEncoding, summary *SignSummary, callbackURL string) ([]byte, error) {
-	startTime := time.Now()
+	startTime := c.clock.Now()
+	// 摘要会展示在审批界面上，发送前转换金额单位、清理控制字符和 HTML 并限制长度
+	summary = c.sanitizeSummary(summary)
 
 	// 记录请求开始
 	c.logger.WithFields(logrus.Fields{
@@ -180,6 +379,44 @@ func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []by
 		"has_callback": callbackURL != "",
 	}).Info("Starting sign request")
 
+	// 相同请求在去重窗口内已有待审批任务时复用该任务，不再创建新任务。
+	// 预留在发送请求前完成，并发的相同请求等待预留者创建任务，而不是各自创建
+	var dedupKey string
+	var reservation *approvalEntry
+	if c.kmsConfig.ApprovalDedupWindow > 0 {
+		dedupKey = approvalDedupKey(keyID, message, encoding, summary)
+	}
+	for dedupKey != "" {
+		entry, reserved := c.reserveApprovalTask(dedupKey)
+		if reserved {
+			reservation = entry
+			break
+		}
+		select {
+		case <-entry.ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry.taskID == "" {
+			// 预留者未创建任务，重新预留
+			continue
+		}
+		c.logger.WithFields(logrus.Fields{
+			"key_id":   keyID,
+			"task_id":  entry.taskID,
+			"endpoint": entry.endpoint,
+			"status":   "pending_approval",
+		}).Info("Attaching to pending approval task of identical sign request")
+		c.taskEndpoints.store(entry.taskID, entry.endpoint, keyID, c.clock.Now())
+		return c.awaitApproval(ctx, keyID, entry.taskID, startTime)
+	}
+	// 未创建审批任务（请求失败或直接返回签名）时释放预留
+	defer func() {
+		if reservation != nil {
+			c.finishApprovalTask(dedupKey, reservation, "", "")
+		}
+	}()
+
 	// 构建签名请求
 	signReq := NewSignRequest(message, encoding)
 	if summary != nil {
@@ -203,23 +440,13 @@ func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []by
 		"request_body": string(reqBody),
 	}).Debug("Sign request body")
 
-	url := c.getSignURL(keyID)
-
-	// 创建HTTP请求
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// 设置Content-Type
-	req.Header.Set("Content-Type", "application/json")
-
-	// 执行请求
-	resp, err := c.httpClient.Do(req)
+	// 执行请求（连接失败时切换到备用端点）
+	resp, endpoint, err := c.doWithFailover(ctx, "POST", reqBody, func(endpoint string) string {
+		return c.signURLFor(endpoint, keyID)
+	})
 	if err != nil {
 		c.logger.WithFields(logrus.Fields{
 			"key_id": keyID,
-			"url":    url,
 			"error":  err.Error(),
 		}).Error("Failed to execute sign request")
 		return nil, fmt.Errorf("failed to execute sign request: %w", err)
@@ -237,7 +464,7 @@ func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []by
 	// 统一响应日志格式 - 使用 has_signature 布尔值
 	c.logger.WithFields(logrus.Fields{
 		"key_id":        keyID,
-		"endpoint":      c.kmsConfig.Endpoint,
+		"endpoint":      endpoint,
 		"status_code":   resp.StatusCode,
 		"has_signature": resp.StatusCode == http.StatusOK,
 	}).Debug("Sign response received")
@@ -246,6 +473,7 @@ func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []by
 	if c.logger.IsLevelEnabled(logrus.DebugLevel) {
 		c.logger.WithFields(logrus.Fields{
 			"key_id":        keyID,
+			"status_code":   resp.StatusCode,
 			"response_body": string(respBody),
 		}).Debug("Sign response body")
 	}
@@ -253,7 +481,7 @@ func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []by
 	// 检查HTTP状态码
 	switch resp.StatusCode {
 	case http.StatusOK:
-		duration := time.Since(startTime).Milliseconds()
+		duration := c.clock.Now().Sub(startTime).Milliseconds()
 		// 直接返回签名结果
 		signResp, err := UnmarshalSignResponse(respBody)
 		if err != nil {
@@ -275,38 +503,24 @@ func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []by
 		}
 
 		c.logger.WithFields(logrus.Fields{
-			"key_id":  keyID,
-			"task_id": taskResp.TaskID,
-			"status":  "pending_approval",
-		}).Info("Sign request requires approval, starting task polling")
-
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
-		defer cancel()
-
-		result, err := c.WaitForTaskCompletion(ctx, taskResp.TaskID, 5*time.Second)
-		if err != nil {
-			c.logger.WithFields(logrus.Fields{
-				"task_id": taskResp.TaskID,
-				"error":   err.Error(),
-			}).Error("Task polling failed")
-			return nil, fmt.Errorf("task polling failed for task %s: %w", taskResp.TaskID, err)
+			"key_id":   keyID,
+			"task_id":  taskResp.TaskID,
+			"endpoint": endpoint,
+			"status":   "pending_approval",
+		}).Info("Sign request requires approval")
+
+		// 任务只能在创建它的端点上轮询
+		c.taskEndpoints.store(taskResp.TaskID, endpoint, keyID, c.clock.Now())
+		if reservation != nil {
+			// 先结束预留，让等待中的重复请求在本请求轮询期间附加到该任务
+			c.finishApprovalTask(dedupKey, reservation, taskResp.TaskID, endpoint)
+			reservation = nil
 		}
 
-		// 返回签名结果
-		var signResp SignResponse
-		if err := json.Unmarshal([]byte(result.Response), &signResp); err != nil {
-			return nil, fmt.Errorf("failed to parse signature from task: %w", err)
-		}
+		return c.awaitApproval(ctx, keyID, taskResp.TaskID, startTime)
 
-		duration := time.Since(startTime).Milliseconds()
-		c.logger.WithFields(logrus.Fields{
-			"key_id":      keyID,
-			"task_id":     taskResp.TaskID,
-			"duration_ms": duration,
-			"status":      "approved_and_completed",
-		}).Info("Sign request completed after approval")
-
-		return []byte(signResp.Signature), nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, c.authError(kmsOperationSign, endpoint, resp.StatusCode, respBody)
 
 	default:
 		// 处理错误响应
@@ -328,6 +542,96 @@ func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []by
 	}
 }
 
+// authError 记录 KMS 认证失败（401/403）并返回 ErrorTypeKMSAuth 类型的 AppError
+//
+// 认证失败通常是凭证错误或本机时钟偏差导致签名过期，重试无法恢复，因此不可重试
+func (c *Client) authError(operation, endpoint string, statusCode int, respBody []byte) error {
+	authFailures.Inc(operation)
+
+	details := fmt.Sprintf("MPC-KMS rejected the request with status %d", statusCode)
+	if errResp, _ := UnmarshalErrorResponse(respBody); errResp != nil {
+		details = fmt.Sprintf("%s (code: %d): %s", details, errResp.Code, errResp.Message)
+	}
+	c.logger.WithFields(logrus.Fields{
+		"operation":   operation,
+		"endpoint":    endpoint,
+		"status_code": statusCode,
+		"details":     details,
+	}).Error("MPC-KMS authentication failed: check the access key ID and secret key, and that the system clock is in sync (request signatures are time-stamped)")
+
+	return apperrors.New(apperrors.ErrorTypeKMSAuth, apperrors.ErrKMSAuth.Code, apperrors.ErrKMSAuth.Message).
+		WithDetails(details).
+		WithContext("operation", operation).
+		WithContext("status_code", statusCode)
+}
+
+type syncApprovalContextKey struct{}
+
+// WithSyncApproval returns a context whose sign requests wait for approval
+// even when KMSConfig.AsyncApproval is enabled.
+//
+// Async approval hands the client only a task ID. That is enough for message
+// signatures, but a transaction signature must be assembled into the signed
+// transaction (and, for eth_sendTransaction, broadcast) by the server, so
+// transaction signing uses this context.
+//
+// Parameters:
+//   - ctx: The parent context
+//
+// Returns:
+//   - context.Context: A context requiring synchronous approval
+func WithSyncApproval(ctx context.Context) context.Context {
+	return context.WithValue(ctx, syncApprovalContextKey{}, true)
+}
+
+// syncApprovalRequired 报告 ctx 是否要求同步等待审批
+func syncApprovalRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(syncApprovalContextKey{}).(bool)
+	return required
+}
+
+// awaitApproval 轮询审批任务直至完成并返回签名
+//
+// 异步审批模式下（WithSyncApproval 的请求除外）立即返回 ApprovalPendingError，端点与去重记录在任务结束后由 GetTaskResult 清理；
+// 同步模式下调用方放弃等待时任务仍待审批，保留记录供去重窗口内重试的相同请求复用
+func (c *Client) awaitApproval(ctx context.Context, keyID, taskID string, startTime time.Time) ([]byte, error) {
+	if c.kmsConfig.AsyncApproval && !syncApprovalRequired(ctx) {
+		return nil, &ApprovalPendingError{TaskID: taskID}
+	}
+
+	pollCtx, cancel := context.WithTimeout(ctx, taskPollingTimeout)
+	defer cancel()
+
+	result, err := c.waitForTask(pollCtx, taskID, keyID, config.KMSTaskPollInterval)
+	if ctx.Err() == nil || c.kmsConfig.ApprovalDedupWindow <= 0 {
+		c.taskEndpoints.delete(taskID)
+		c.forgetApprovalTask(taskID)
+	}
+	if err != nil {
+		c.logger.WithFields(logrus.Fields{
+			"task_id": taskID,
+			"error":   err.Error(),
+		}).Error("Task polling failed")
+		return nil, fmt.Errorf("task polling failed for task %s: %w", taskID, err)
+	}
+
+	// 返回签名结果
+	signature, err := SignatureFromTaskResponse(result.Response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signature from task: %w", err)
+	}
+
+	duration := c.clock.Now().Sub(startTime).Milliseconds()
+	c.logger.WithFields(logrus.Fields{
+		"key_id":      keyID,
+		"task_id":     taskID,
+		"duration_ms": duration,
+		"status":      "approved_and_completed",
+	}).Info("Sign request completed after approval")
+
+	return []byte(signature), nil
+}
+
 // GetTaskResult retrieves the result of an asynchronous signing task.
 //
 // This method is used to check the status of a task that requires approval.
@@ -340,7 +644,7 @@ func (c *Client) SignWithOptions(ctx context.Context, keyID string, message []by
 //   - *TaskResult: The task result with status and response data
 //   - error: An error if the task retrieval fails
 func (c *Client) GetTaskResult(ctx context.Context, taskID string) (*TaskResult, error) {
-	url := c.getTaskURL(taskID)
+	url := c.taskURLFor(taskID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -366,6 +670,9 @@ func (c *Client) GetTaskResult(ctx context.Context, taskID string) (*TaskResult,
 		"response_body": string(respBody),
 	}).Debug("Task result response")
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, c.authError(kmsOperationGetTask, req.URL.Host, resp.StatusCode, respBody)
+	}
 	if resp.StatusCode != http.StatusOK {
 		errResp, _ := UnmarshalErrorResponse(respBody)
 		if errResp != nil {
@@ -379,17 +686,162 @@ func (c *Client) GetTaskResult(ctx context.Context, taskID string) (*TaskResult,
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal task result: %w", err)
 	}
+	if c.kmsConfig.AsyncApproval && taskResult.Status.IsFinal() {
+		c.taskEndpoints.delete(taskID)
+		c.forgetApprovalTask(taskID)
+	}
 
 	return taskResult, nil
 }
 
+// CancelTask cancels a pending approval task.
+//
+// The request is sent to the endpoint that created the task. When the KMS
+// reports that the task can no longer be cancelled (HTTP 409), the task has
+// already reached a final state and that state is returned instead of an error.
+//
+// Parameters:
+//   - ctx: Context for the request (supports cancellation and timeout)
+//   - taskID: The task ID to cancel
+//
+// Returns:
+//   - *TaskResult: The task status after cancellation, or its final status if it already completed
+//   - error: An error if the cancellation request fails
+func (c *Client) CancelTask(ctx context.Context, taskID string) (*TaskResult, error) {
+	url := c.taskURLFor(taskID) + "/cancel"
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cancel request for task %s: %w", taskID, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute cancel request for task %s: %w", taskID, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cancel response body for task %s: %w", taskID, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"task_id":     taskID,
+		"status_code": resp.StatusCode,
+	}).Debug("Task cancel response")
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		result := &TaskResult{Status: TaskStatusCancelled}
+		if len(bytes.TrimSpace(respBody)) > 0 {
+			if result, err = UnmarshalTaskResult(respBody); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal cancel result: %w", err)
+			}
+		}
+		c.taskEndpoints.delete(taskID)
+		c.forgetApprovalTask(taskID)
+		c.logger.WithField("task_id", taskID).Info("Task cancelled")
+		return result, nil
+	case http.StatusConflict:
+		// 任务已结束（完成、拒绝或失败），返回其最终状态
+		result, err := c.GetTaskResult(ctx, taskID)
+		if err != nil {
+			return nil, fmt.Errorf("task %s can no longer be cancelled: %w", taskID, err)
+		}
+		c.taskEndpoints.delete(taskID)
+		c.forgetApprovalTask(taskID)
+		c.logger.WithFields(logrus.Fields{
+			"task_id": taskID,
+			"status":  result.Status,
+		}).Info("Task already completed, returning final status")
+		return result, nil
+	default:
+		errResp, _ := UnmarshalErrorResponse(respBody)
+		if errResp != nil {
+			return nil, fmt.Errorf("MPC-KMS error for task %s (code: %d): %s", taskID, errResp.Code, errResp.Message)
+		}
+		return nil, fmt.Errorf("MPC-KMS cancel request failed for task %s with status: %d", taskID, resp.StatusCode)
+	}
+}
+
+// ListKeys lists all keys accessible with the configured credentials.
+//
+// Parameters:
+//   - ctx: Context for the request (supports cancellation and timeout)
+//
+// Returns:
+//   - []KeyInfo: The accessible keys; Address may be empty if the endpoint omits it
+//   - error: An error if the request fails
+func (c *Client) ListKeys(ctx context.Context) ([]KeyInfo, error) {
+	var resp ListKeysResponse
+	if err := c.getJSON(ctx, "/api/v1/keys", "key list", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// GetKey retrieves details, including the Ethereum address and key algorithm, of a single key.
+//
+// Parameters:
+//   - ctx: Context for the request (supports cancellation and timeout)
+//   - keyID: The KMS key identifier to query
+//
+// Returns:
+//   - *KeyInfo: The key details
+//   - error: An error if the request fails
+func (c *Client) GetKey(ctx context.Context, keyID string) (*KeyInfo, error) {
+	var info KeyInfo
+	if err := c.getJSON(ctx, "/api/v1/keys/"+url.PathEscape(keyID), "key "+keyID, &info); err != nil {
+		return nil, err
+	}
+	if info.KeyID == "" {
+		info.KeyID = keyID
+	}
+	return &info, nil
+}
+
+// getJSON 向 path 发送 GET 请求（连接失败时切换端点）并将成功响应解析到 out
+func (c *Client) getJSON(ctx context.Context, path, what string, out interface{}) error {
+	resp, _, err := c.doWithFailover(ctx, "GET", nil, func(endpoint string) string {
+		return endpoint + path
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute request for %s: %w", what, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body for %s: %w", what, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errResp, _ := UnmarshalErrorResponse(respBody)
+		if errResp != nil {
+			return fmt.Errorf("MPC-KMS error for %s (code: %d): %s", what, errResp.Code, errResp.Message)
+		}
+		return fmt.Errorf("MPC-KMS request failed for %s with status: %d", what, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", what, err)
+	}
+	return nil
+}
+
 // WaitForTaskCompletion waits for an asynchronous signing task to complete.
 //
 // This method polls the task status at the specified interval until:
 //   - Task completes (TaskStatusDone)
 //   - Task fails (TaskStatusFailed)
 //   - Task is rejected (TaskStatusRejected)
-//   - Max attempts reached (5 minutes total)
+//   - KMSConfig.MaxPollAttempts status checks have been made
+//   - The 5 minute polling window elapses
 //   - Context is cancelled or times out
 //
 // Parameters:
@@ -401,14 +853,41 @@ func (c *Client) GetTaskResult(ctx context.Context, taskID string) (*TaskResult,
 //   - *TaskResult: The task result when complete
 //   - error: An error if task fails, is rejected, or context is cancelled
 func (c *Client) WaitForTaskCompletion(ctx context.Context, taskID string, interval time.Duration) (*TaskResult, error) {
-	startTime := time.Now()
-	maxAttempts := int(5 * time.Minute / interval)
+	return c.waitForTask(ctx, taskID, "", interval)
+}
+
+// waitForTask 轮询审批任务直至结束，轮询期间任务记录在 PendingTasks 中
+func (c *Client) waitForTask(ctx context.Context, taskID, keyID string, interval time.Duration) (*TaskResult, error) {
+	untrack := c.trackPendingTask(taskID, keyID)
+	defer untrack()
+
+	startTime := c.clock.Now()
+	deadline := startTime.Add(taskPollingTimeout)
+
+	maxAttempts := c.kmsConfig.MaxPollAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = config.DefaultKMSMaxPollAttempts
+	}
+
+	attempt := 0
+	for ; c.clock.Now().Before(deadline); attempt++ {
+		// 限制 GetTaskResult 调用次数，避免过短的轮询间隔压垮 KMS
+		if attempt >= maxAttempts {
+			c.logger.WithFields(logrus.Fields{
+				"task_id":      taskID,
+				"max_attempts": maxAttempts,
+			}).Error("Task polling reached max attempts")
+			c.observeApprovalWait(approvalOutcomeTimeout, startTime)
+			return nil, fmt.Errorf("task polling timeout: reached max poll attempts (%d)", maxAttempts)
+		}
 
-	for attempt := 0; attempt < maxAttempts; attempt++ {
 		select {
 		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				c.observeApprovalWait(approvalOutcomeTimeout, startTime)
+			}
 			return nil, ctx.Err()
-		case <-time.After(interval):
+		case <-c.clock.After(interval):
 			result, err := c.GetTaskResult(ctx, taskID)
 			if err != nil {
 				return nil, err
@@ -424,10 +903,10 @@ func (c *Client) WaitForTaskCompletion(ctx context.Context, taskID string, inter
 			switch result.Status {
 			case TaskStatusDone:
 				// 任务完成，解析签名结果
-				duration := time.Since(startTime).Milliseconds()
+				c.observeApprovalWait(approvalOutcomeApproved, startTime)
+				duration := c.clock.Now().Sub(startTime).Milliseconds()
 				if result.Response != "" {
-					var signResp SignResponse
-					if err := json.Unmarshal([]byte(result.Response), &signResp); err != nil {
+					if _, err := SignatureFromTaskResponse(result.Response); err != nil {
 						return nil, fmt.Errorf("failed to parse signature from task result: %w", err)
 					}
 					// 返回包含签名结果的任务结果
@@ -452,6 +931,7 @@ func (c *Client) WaitForTaskCompletion(ctx context.Context, taskID string, inter
 					"status":  "failed",
 					"message": result.Message,
 				}).Error("Task failed")
+				c.observeApprovalWait(approvalOutcomeFailed, startTime)
 				return nil, fmt.Errorf("task failed: %s", result.Message)
 			case TaskStatusRejected:
 				c.logger.WithFields(logrus.Fields{
@@ -459,7 +939,16 @@ func (c *Client) WaitForTaskCompletion(ctx context.Context, taskID string, inter
 					"status":  "rejected",
 					"message": result.Message,
 				}).Error("Task rejected")
+				c.observeApprovalWait(approvalOutcomeRejected, startTime)
 				return nil, fmt.Errorf("task rejected: %s", result.Message)
+			case TaskStatusCancelled:
+				c.logger.WithFields(logrus.Fields{
+					"task_id": taskID,
+					"status":  "cancelled",
+					"message": result.Message,
+				}).Warn("Task cancelled")
+				c.observeApprovalWait(approvalOutcomeCancelled, startTime)
+				return nil, fmt.Errorf("task cancelled: %s", result.Message)
 			case TaskStatusPendingApproval, TaskStatusApproved:
 				// 继续等待
 				continue
@@ -473,6 +962,7 @@ func (c *Client) WaitForTaskCompletion(ctx context.Context, taskID string, inter
 		}
 	}
 
-	// 达到最大尝试次数
-	return nil, fmt.Errorf("task polling timeout after %d attempts", maxAttempts)
+	// 超过轮询时限
+	c.observeApprovalWait(approvalOutcomeTimeout, startTime)
+	return nil, fmt.Errorf("task polling timeout after %d attempts", attempt)
 }
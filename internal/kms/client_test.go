@@ -7,16 +7,19 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/mowind/web3signer-go/internal/config"
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 )
 
 func defaultLogger() *logrus.Logger {
@@ -274,6 +277,104 @@ func TestClient_SignRequest(t *testing.T) {
 	}
 }
 
+func TestClient_SignRequest_StaticHeaders(t *testing.T) {
+	date := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	body := []byte(`{"data":"test"}`)
+	baseSigningString := BuildSigningString("POST", CalculateContentSHA256(body), "application/json", "Thu, 15 Jan 2026 10:30:00 GMT")
+
+	tests := []struct {
+		name          string
+		signedHeaders []string
+		signingString string
+	}{
+		{
+			name:          "headers sent but not signed",
+			signingString: baseSigningString,
+		},
+		{
+			name:          "signed headers appended in order",
+			signedHeaders: []string{"X-Tenant-Id", "x-project-id"},
+			signingString: baseSigningString + "\nx-tenant-id:acme\nx-project-id:payments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.KMSConfig{
+				AccessKeyID:   "AK1234567890",
+				SecretKey:     "test-secret-key",
+				Headers:       map[string]string{"x-tenant-id": "acme", "x-project-id": "payments"},
+				SignedHeaders: tt.signedHeaders,
+			}
+			httpClient := NewHTTPClient(cfg, defaultLogger()).WithClock(&fakeClock{now: date})
+
+			req, err := http.NewRequest("POST", "https://kms.example.com/api/v1/keys/test/sign", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if err := httpClient.SignRequest(req, body); err != nil {
+				t.Fatalf("SignRequest failed: %v", err)
+			}
+
+			if req.Header.Get("X-Tenant-Id") != "acme" || req.Header.Get("X-Project-Id") != "payments" {
+				t.Errorf("static headers not set: %v", req.Header)
+			}
+			want := BuildAuthorizationHeader(cfg.AccessKeyID, CalculateHMACSHA256(tt.signingString, cfg.SecretKey))
+			if got := req.Header.Get("Authorization"); got != want {
+				t.Errorf("Authorization = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_SignRequest_VerifyContentSHA256(t *testing.T) {
+	tests := []struct {
+		name     string
+		signed   []byte
+		sent     []byte
+		wantWarn bool
+	}{
+		{name: "body unchanged", signed: []byte(`{"data":"test"}`), sent: []byte(`{"data":"test"}`)},
+		{name: "body modified after signing", signed: []byte(`{"data":"test"}`), sent: []byte(`{"data":"test2"}`), wantWarn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			cfg := &config.KMSConfig{
+				AccessKeyID:         "AK1234567890",
+				SecretKey:           "test-secret-key",
+				VerifyContentSHA256: true,
+			}
+			httpClient := NewHTTPClient(cfg, logger)
+
+			req, err := http.NewRequest("POST", "https://kms.example.com/api/v1/keys/test/sign", io.NopCloser(bytes.NewReader(tt.sent)))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if err := httpClient.SignRequest(req, tt.signed); err != nil {
+				t.Fatalf("SignRequest failed: %v", err)
+			}
+
+			warned := false
+			for _, entry := range hook.AllEntries() {
+				if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "Content-SHA256 mismatch") {
+					warned = true
+				}
+			}
+			if warned != tt.wantWarn {
+				t.Errorf("mismatch warning = %v, want %v", warned, tt.wantWarn)
+			}
+
+			// 自检读取请求体后必须恢复原内容
+			sent, err := io.ReadAll(req.Body)
+			if err != nil || !bytes.Equal(sent, tt.sent) {
+				t.Errorf("request body after self-check = %q, want %q", sent, tt.sent)
+			}
+		})
+	}
+}
+
 func TestHTTPClient_Do(t *testing.T) {
 	cfg := &config.KMSConfig{
 		Endpoint:    "https://kms.example.com",
@@ -588,6 +689,177 @@ func TestClient_GetTaskResult(t *testing.T) {
 	})
 }
 
+func TestClient_CancelTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v1/tasks/task-pending/cancel":
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusCancelled})
+		case r.Method == "POST" && r.URL.Path == "/api/v1/tasks/task-done/cancel":
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{Code: 409, Message: "Task already completed"})
+		case r.Method == "GET" && r.URL.Path == "/api/v1/tasks/task-done":
+			_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusDone, Response: "completed-signature"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{Code: 404, Message: "Task not found"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.KMSConfig{
+		Endpoint:    server.URL,
+		AccessKeyID: "AK1234567890",
+		SecretKey:   "test-secret-key",
+	}, defaultLogger())
+
+	// 任务结束后不再需要其端点记录
+	for _, taskID := range []string{"task-pending", "task-done"} {
+		client.taskEndpoints.store(taskID, server.URL, "key-1", client.clock.Now())
+	}
+
+	t.Run("cancel pending task", func(t *testing.T) {
+		result, err := client.CancelTask(context.Background(), "task-pending")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Status != TaskStatusCancelled {
+			t.Errorf("Expected status CANCELLED, got %s", result.Status)
+		}
+		if _, ok := client.taskEndpoints.load("task-pending", client.clock.Now()); ok {
+			t.Error("Expected task endpoint to be forgotten once the task is cancelled")
+		}
+	})
+
+	t.Run("task already completed returns final status", func(t *testing.T) {
+		result, err := client.CancelTask(context.Background(), "task-done")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Status != TaskStatusDone || result.Response != "completed-signature" {
+			t.Errorf("Expected final DONE result, got %+v", result)
+		}
+		if _, ok := client.taskEndpoints.load("task-done", client.clock.Now()); ok {
+			t.Error("Expected task endpoint to be forgotten once the task is final")
+		}
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		if _, err := client.CancelTask(context.Background(), "task-missing"); err == nil {
+			t.Error("Expected error for unknown task")
+		}
+	})
+}
+
+func TestClient_SignWithOptions_AsyncApproval(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/keys/key-1/sign":
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(TaskResponse{TaskID: "task-42"})
+		case "/api/v1/tasks/task-42":
+			if atomic.AddInt32(&polls, 1) < 2 {
+				_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusPendingApproval})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusDone, Response: `{"signature":"approved-signature"}`})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.KMSConfig{
+		Endpoint:      server.URL,
+		AccessKeyID:   "AK1234567890",
+		SecretKey:     "test-secret-key",
+		AsyncApproval: true,
+	}, defaultLogger())
+
+	_, err := client.Sign(context.Background(), "key-1", []byte("message"))
+	var pending *ApprovalPendingError
+	if !errors.As(err, &pending) || pending.TaskID != "task-42" {
+		t.Fatalf("Expected ApprovalPendingError for task-42, got %v", err)
+	}
+	if atomic.LoadInt32(&polls) != 0 {
+		t.Fatalf("Expected no polling in async mode, got %d polls", polls)
+	}
+
+	// 客户端自行轮询
+	result, err := client.GetTaskResult(context.Background(), "task-42")
+	if err != nil || result.Status != TaskStatusPendingApproval {
+		t.Fatalf("GetTaskResult() = %+v, %v; want PENDING_APPROVAL", result, err)
+	}
+	if _, ok := client.taskEndpoints.load("task-42", client.clock.Now()); !ok {
+		t.Error("Expected task endpoint to be kept while the task is pending")
+	}
+	if keyID, ok := client.TaskKeyID("task-42"); !ok || keyID != "key-1" {
+		t.Errorf("TaskKeyID() = (%q, %v), want key-1", keyID, ok)
+	}
+
+	result, err = client.GetTaskResult(context.Background(), "task-42")
+	if err != nil || result.Status != TaskStatusDone {
+		t.Fatalf("GetTaskResult() = %+v, %v; want DONE", result, err)
+	}
+	if _, ok := client.taskEndpoints.load("task-42", client.clock.Now()); ok {
+		t.Error("Expected task endpoint to be forgotten once the task is final")
+	}
+}
+
+func TestClient_SignWithOptions_AsyncApprovalSyncContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/keys/key-1/sign":
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(TaskResponse{TaskID: "task-7"})
+		case "/api/v1/tasks/task-7":
+			_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusDone, Response: `{"signature":"approved-signature"}`})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.KMSConfig{
+		Endpoint:      server.URL,
+		AccessKeyID:   "AK1234567890",
+		SecretKey:     "test-secret-key",
+		AsyncApproval: true,
+	}, defaultLogger()).WithClock(&fakeClock{now: time.Unix(0, 0)})
+
+	// 交易签名需在服务端组装，异步审批模式下仍等待审批完成
+	signature, err := client.Sign(WithSyncApproval(context.Background()), "key-1", []byte("message"))
+	if err != nil || string(signature) != "approved-signature" {
+		t.Fatalf("Sign() = %q, %v; want approved signature", signature, err)
+	}
+	if _, ok := client.taskEndpoints.load("task-7", client.clock.Now()); ok {
+		t.Error("Expected task endpoint to be forgotten after synchronous approval")
+	}
+}
+
+func TestTaskEndpointMap_TTL(t *testing.T) {
+	var m taskEndpointMap
+	now := time.Unix(0, 0)
+	m.store("stale", "http://kms-b", "key-1", now)
+
+	if endpoint, ok := m.load("stale", now.Add(taskEndpointTTL-time.Second)); !ok || endpoint != "http://kms-b" {
+		t.Fatalf("load() = %q, %v; want kms-b before the TTL", endpoint, ok)
+	}
+	if _, ok := m.load("stale", now.Add(taskEndpointTTL)); ok {
+		t.Error("Expected entry to expire after the TTL")
+	}
+
+	// 新记录写入时清理过期记录
+	m.store("fresh", "http://kms-a", "key-1", now.Add(taskEndpointTTL))
+	if len(m.entries) != 1 {
+		t.Errorf("Expected expired entry to be pruned, got %d entries", len(m.entries))
+	}
+}
+
 func TestClient_WaitForTaskCompletion(t *testing.T) {
 	cfg := &config.KMSConfig{
 		Endpoint:    "https://kms.example.com",
@@ -854,6 +1126,75 @@ func TestUnmarshalTaskResult(t *testing.T) {
 	}
 }
 
+func TestSignatureFromTaskResponse(t *testing.T) {
+	hexSig := strings.Repeat("ab", 65)
+	b64Sig := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0xab}, 65))
+
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  bool
+	}{
+		{name: "json-wrapped", response: `{"signature":"` + hexSig + `"}`, want: hexSig},
+		{name: "bare hex", response: hexSig, want: hexSig},
+		{name: "bare 0x hex", response: "0x" + hexSig, want: hexSig},
+		{name: "bare base64", response: b64Sig, want: b64Sig},
+		{name: "json string", response: `"0x` + hexSig + `"`, want: hexSig},
+		{name: "empty", response: "", wantErr: true},
+		{name: "unrelated object", response: `{"status":"ok"}`, wantErr: true},
+		{name: "garbage", response: "not a signature!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SignatureFromTaskResponse(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignatureFromTaskResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SignatureFromTaskResponse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_SignWithOptions_TaskResponseForms(t *testing.T) {
+	hexSig := strings.Repeat("ab", 65)
+
+	for name, response := range map[string]string{
+		"json-wrapped": `{"signature":"` + hexSig + `"}`,
+		"bare string":  hexSig,
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/api/v1/keys/key-1/sign" {
+					w.WriteHeader(http.StatusCreated)
+					_ = json.NewEncoder(w).Encode(TaskResponse{TaskID: "task-1"})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusDone, Response: response})
+			}))
+			defer server.Close()
+
+			client := NewClient(&config.KMSConfig{
+				Endpoint:    server.URL,
+				AccessKeyID: "AK1234567890",
+				SecretKey:   "test-secret-key",
+			}, defaultLogger()).WithClock(&fakeClock{now: time.Unix(0, 0)})
+
+			signature, err := client.Sign(context.Background(), "key-1", []byte("message"))
+			if err != nil {
+				t.Fatalf("Sign() error: %v", err)
+			}
+			if string(signature) != hexSig {
+				t.Errorf("Sign() = %s, want %s", signature, hexSig)
+			}
+		})
+	}
+}
+
 func TestUnmarshalErrorResponse(t *testing.T) {
 	tests := []struct {
 		name     string
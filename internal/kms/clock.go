@@ -0,0 +1,24 @@
+package kms
+
+import "time"
+
+// Clock abstracts time so that Date header generation and task polling
+// timeouts can be controlled deterministically in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the time after duration d.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the default Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RealClock returns the Clock backed by the system time.
+func RealClock() Clock {
+	return realClock{}
+}
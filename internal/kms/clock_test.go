@@ -0,0 +1,151 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/config"
+)
+
+// fakeClock 是可控的测试时钟，After 立即触发并推进当前时间
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	now := f.now
+	f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	ch <- now
+	return ch
+}
+
+func TestHTTPClient_SignRequest_UsesClock(t *testing.T) {
+	cfg := &config.KMSConfig{AccessKeyID: "AK", SecretKey: "secret"}
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	httpClient := NewHTTPClient(cfg, defaultLogger()).WithClock(clock)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://kms.example.com", bytes.NewReader([]byte("{}")))
+	if err := httpClient.SignRequest(req, []byte("{}")); err != nil {
+		t.Fatalf("SignRequest failed: %v", err)
+	}
+
+	if got, want := req.Header.Get("Date"), "Tue, 02 Jan 2024 03:04:05 GMT"; got != want {
+		t.Errorf("Date header = %q, want %q", got, want)
+	}
+}
+
+func TestClient_WaitForTaskCompletion_ClockTimeout(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusPendingApproval})
+	}))
+	defer server.Close()
+
+	cfg := &config.KMSConfig{Endpoint: server.URL, AccessKeyID: "AK", SecretKey: "secret"}
+	client := NewClient(cfg, defaultLogger()).WithClock(&fakeClock{now: time.Unix(0, 0)})
+
+	timeoutsBefore := approvalWaitSeconds.Count(approvalOutcomeTimeout)
+	start := time.Now()
+	_, err := client.WaitForTaskCompletion(context.Background(), "task-1", 5*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "task polling timeout") {
+		t.Fatalf("Expected polling timeout, got %v", err)
+	}
+
+	wantCalls := int32(taskPollingTimeout / (5 * time.Second))
+	if got := atomic.LoadInt32(&calls); got != wantCalls {
+		t.Errorf("Expected %d polls, got %d", wantCalls, got)
+	}
+	if got := approvalWaitSeconds.Count(approvalOutcomeTimeout); got != timeoutsBefore+1 {
+		t.Errorf("Expected timeout to be recorded in approval histogram, count %d -> %d", timeoutsBefore, got)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected fake clock to avoid real waiting, took %v", elapsed)
+	}
+}
+
+func TestClient_WaitForTaskCompletion_MaxPollAttempts(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxPollAttempts int
+		wantCalls       int32
+	}{
+		{name: "configured cap", maxPollAttempts: 3, wantCalls: 3},
+		{name: "default cap", maxPollAttempts: 0, wantCalls: config.DefaultKMSMaxPollAttempts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusPendingApproval})
+			}))
+			defer server.Close()
+
+			cfg := &config.KMSConfig{Endpoint: server.URL, AccessKeyID: "AK", SecretKey: "secret", MaxPollAttempts: tt.maxPollAttempts}
+			client := NewClient(cfg, defaultLogger()).WithClock(&fakeClock{now: time.Unix(0, 0)})
+
+			// 极短的轮询间隔在 5 分钟窗口内会产生大量请求，应被最大轮询次数限制
+			_, err := client.WaitForTaskCompletion(context.Background(), "task-1", time.Millisecond)
+			if err == nil || !strings.Contains(err.Error(), "max poll attempts") {
+				t.Fatalf("Expected max poll attempts error, got %v", err)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("Expected %d polls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestClient_WaitForTaskCompletion_ApprovalMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  TaskStatus
+		outcome string
+	}{
+		{name: "approved", status: TaskStatusDone, outcome: approvalOutcomeApproved},
+		{name: "rejected", status: TaskStatusRejected, outcome: approvalOutcomeRejected},
+		{name: "failed", status: TaskStatusFailed, outcome: approvalOutcomeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(TaskResult{Status: tt.status})
+			}))
+			defer server.Close()
+
+			cfg := &config.KMSConfig{Endpoint: server.URL, AccessKeyID: "AK", SecretKey: "secret"}
+			client := NewClient(cfg, defaultLogger()).WithClock(&fakeClock{now: time.Unix(0, 0)})
+
+			before := approvalWaitSeconds.Count(tt.outcome)
+			_, _ = client.WaitForTaskCompletion(context.Background(), "task-1", 5*time.Second)
+			if got := approvalWaitSeconds.Count(tt.outcome); got != before+1 {
+				t.Errorf("Expected %s count %d, got %d", tt.outcome, before+1, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package kms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// approvalEntry 去重窗口内待审批的签名任务
+//
+// 条目在发送签名请求前预留，此时 taskID 为空；创建请求结束后关闭 ready，
+// 之后 taskID 与 endpoint 不再改变，等待者在 ready 关闭后读取
+type approvalEntry struct {
+	taskID    string
+	endpoint  string
+	createdAt time.Time
+	ready     chan struct{}
+}
+
+// approvalDedupKey 计算签名请求的去重键：keyID、编码、消息与交易摘要均相同的请求视为同一请求
+func approvalDedupKey(keyID string, message []byte, encoding DataEncoding, summary *SignSummary) string {
+	h := sha256.New()
+	h.Write([]byte(keyID))
+	h.Write([]byte{0})
+	h.Write([]byte(encoding))
+	h.Write([]byte{0})
+	msgHash := sha256.Sum256(message)
+	h.Write(msgHash[:])
+	if summary != nil {
+		summaryJSON, _ := json.Marshal(summary)
+		h.Write(summaryJSON)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// reserveApprovalTask 原子地查找或预留 key 对应的审批任务，并清理已过期的条目
+//
+// 返回 true 表示调用方取得预留，须发送签名请求并调用 finishApprovalTask；
+// 返回 false 时 entry 为已有的（可能仍在创建中的）任务。
+// 同步模式下过期任务若已无请求在轮询，一并删除其端点记录
+func (c *Client) reserveApprovalTask(key string) (*approvalEntry, bool) {
+	c.approvalMu.Lock()
+	defer c.approvalMu.Unlock()
+
+	now := c.clock.Now()
+	window := c.kmsConfig.ApprovalDedupWindow
+	for k, entry := range c.approvalTasks {
+		// 创建中的条目由预留者负责清理
+		if entry.taskID != "" && now.Sub(entry.createdAt) >= window {
+			delete(c.approvalTasks, k)
+			if _, polling := c.pendingTasks.Load(entry.taskID); !polling && !c.kmsConfig.AsyncApproval {
+				c.taskEndpoints.delete(entry.taskID)
+			}
+		}
+	}
+	if entry, ok := c.approvalTasks[key]; ok {
+		return entry, false
+	}
+
+	if c.approvalTasks == nil {
+		c.approvalTasks = make(map[string]*approvalEntry)
+	}
+	entry := &approvalEntry{createdAt: now, ready: make(chan struct{})}
+	c.approvalTasks[key] = entry
+	return entry, true
+}
+
+// finishApprovalTask 结束预留：taskID 非空时记录新创建的审批任务，供窗口内的重复请求复用；
+// 为空（请求失败或无需审批）时删除预留，等待中的重复请求将重新预留
+func (c *Client) finishApprovalTask(key string, entry *approvalEntry, taskID, endpoint string) {
+	c.approvalMu.Lock()
+	defer c.approvalMu.Unlock()
+
+	if taskID == "" {
+		if c.approvalTasks[key] == entry {
+			delete(c.approvalTasks, key)
+		}
+	} else {
+		entry.taskID = taskID
+		entry.endpoint = endpoint
+		entry.createdAt = c.clock.Now()
+	}
+	close(entry.ready)
+}
+
+// forgetApprovalTask 任务结束后删除其去重记录
+func (c *Client) forgetApprovalTask(taskID string) {
+	c.approvalMu.Lock()
+	defer c.approvalMu.Unlock()
+
+	for k, entry := range c.approvalTasks {
+		if entry.taskID == taskID {
+			delete(c.approvalTasks, k)
+		}
+	}
+}
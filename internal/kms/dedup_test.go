@@ -0,0 +1,201 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/config"
+)
+
+// newApprovalServer 返回每次签名请求都创建新审批任务（task-1、task-2…）的 KMS 服务器
+func newApprovalServer(t *testing.T, signs *int32, taskStatus func(taskID string) TaskStatus) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/sign") {
+			n := atomic.AddInt32(signs, 1)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(TaskResponse{TaskID: fmt.Sprintf("task-%d", n)})
+			return
+		}
+		taskID := strings.TrimPrefix(r.URL.Path, "/api/v1/tasks/")
+		status := taskStatus(taskID)
+		result := TaskResult{Status: status}
+		if status == TaskStatusDone {
+			result.Response = `{"signature":"approved-` + taskID + `"}`
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_ApprovalDedup_Async(t *testing.T) {
+	var signs int32
+	server := newApprovalServer(t, &signs, func(string) TaskStatus { return TaskStatusDone })
+
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	client := NewClient(&config.KMSConfig{
+		Endpoint:            server.URL,
+		AccessKeyID:         "AK",
+		SecretKey:           "secret",
+		AsyncApproval:       true,
+		ApprovalDedupWindow: time.Minute,
+	}, defaultLogger()).WithClock(clock)
+
+	sign := func(message string, summary *SignSummary) string {
+		t.Helper()
+		_, err := client.SignWithOptions(context.Background(), "key-1", []byte(message), DataEncodingHex, summary, "")
+		var pending *ApprovalPendingError
+		if !errors.As(err, &pending) {
+			t.Fatalf("Expected ApprovalPendingError, got %v", err)
+		}
+		return pending.TaskID
+	}
+
+	summary := &SignSummary{Type: string(SummaryTypeTransfer), From: "0x01", To: "0x02", Amount: "1", Token: "ETH"}
+	if first, retry := sign("message", summary), sign("message", summary); first != "task-1" || retry != "task-1" {
+		t.Fatalf("identical requests got tasks %s and %s, want task-1 for both", first, retry)
+	}
+	if other := sign("message", &SignSummary{Type: string(SummaryTypeTransfer), Remark: "other"}); other != "task-2" {
+		t.Errorf("request with a different summary got %s, want task-2", other)
+	}
+	if other := sign("other message", summary); other != "task-3" {
+		t.Errorf("request with a different message got %s, want task-3", other)
+	}
+
+	// 任务结束后清理去重记录
+	if result, err := client.GetTaskResult(context.Background(), "task-1"); err != nil || result.Status != TaskStatusDone {
+		t.Fatalf("GetTaskResult() = %+v, %v", result, err)
+	}
+	if again := sign("message", summary); again != "task-4" {
+		t.Errorf("request after task completion got %s, want task-4", again)
+	}
+
+	// 窗口过期后创建新任务
+	clock.After(time.Minute)
+	if expired := sign("message", summary); expired != "task-5" {
+		t.Errorf("request after the window got %s, want task-5", expired)
+	}
+	if got := atomic.LoadInt32(&signs); got != 5 {
+		t.Errorf("sign requests sent = %d, want 5", got)
+	}
+}
+
+func TestClient_ApprovalDedup_RetryAfterDisconnect(t *testing.T) {
+	var signs, polls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	server := newApprovalServer(t, &signs, func(string) TaskStatus {
+		// 第一次轮询时客户端断开，之后审批通过
+		if atomic.AddInt32(&polls, 1) == 1 {
+			cancel()
+			return TaskStatusPendingApproval
+		}
+		return TaskStatusDone
+	})
+
+	client := NewClient(&config.KMSConfig{
+		Endpoint:            server.URL,
+		AccessKeyID:         "AK",
+		SecretKey:           "secret",
+		ApprovalDedupWindow: time.Minute,
+	}, defaultLogger()).WithClock(&fakeClock{now: time.Unix(1000, 0)})
+
+	if _, err := client.Sign(ctx, "key-1", []byte("message")); err == nil {
+		t.Fatal("Expected error after the caller disconnected")
+	}
+
+	signature, err := client.Sign(context.Background(), "key-1", []byte("message"))
+	if err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if string(signature) != "approved-task-1" {
+		t.Errorf("retry signature = %s, want the result of task-1", signature)
+	}
+	if got := atomic.LoadInt32(&signs); got != 1 {
+		t.Errorf("sign requests sent = %d, want 1", got)
+	}
+
+	client.approvalMu.Lock()
+	remaining := len(client.approvalTasks)
+	client.approvalMu.Unlock()
+	if remaining != 0 {
+		t.Errorf("Expected dedup entry to be removed after approval, %d left", remaining)
+	}
+	if _, ok := client.taskEndpoints.load("task-1", client.clock.Now()); ok {
+		t.Error("Expected task endpoint to be removed after approval")
+	}
+}
+
+func TestClient_ApprovalDedup_Concurrent(t *testing.T) {
+	var signs int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasSuffix(r.URL.Path, "/sign") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		n := atomic.AddInt32(&signs, 1)
+		// 第一次签名请求失败，释放预留；第二次创建的任务阻塞到所有请求都已发出
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{Code: 500, Message: "unavailable"})
+			return
+		}
+		<-release
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(TaskResponse{TaskID: fmt.Sprintf("task-%d", n)})
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.KMSConfig{
+		Endpoint:            server.URL,
+		AccessKeyID:         "AK",
+		SecretKey:           "secret",
+		AsyncApproval:       true,
+		ApprovalDedupWindow: time.Minute,
+	}, defaultLogger()).WithClock(&fakeClock{now: time.Unix(1000, 0)})
+
+	// 创建任务失败时释放预留，后续相同请求重新创建
+	if _, err := client.Sign(context.Background(), "key-1", []byte("message")); err == nil || strings.Contains(err.Error(), "approval") {
+		t.Fatalf("Expected KMS error for the failed request, got %v", err)
+	}
+
+	// 并发的相同请求只创建一个任务
+	const callers = 8
+	taskIDs := make(chan string, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, err := client.Sign(context.Background(), "key-1", []byte("message"))
+			var pending *ApprovalPendingError
+			if !errors.As(err, &pending) {
+				taskIDs <- "error: " + fmt.Sprint(err)
+				return
+			}
+			taskIDs <- pending.TaskID
+		}()
+	}
+	for atomic.LoadInt32(&signs) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < callers; i++ {
+		if taskID := <-taskIDs; taskID != "task-2" {
+			t.Errorf("caller %d got %s, want task-2", i, taskID)
+		}
+	}
+	if got := atomic.LoadInt32(&signs); got != 2 {
+		t.Errorf("sign requests sent = %d, want 2", got)
+	}
+}
@@ -0,0 +1,92 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/config"
+)
+
+func TestClient_ListKeysAndGetKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "MPC-KMS") || r.Method != "GET" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		// 含 / 与 ? 的 key ID 须作为单个转义的路径段发送
+		if r.URL.EscapedPath() == "/api/v1/keys/team%2Fkey%3F3" && r.URL.RawQuery == "" {
+			_ = json.NewEncoder(w).Encode(KeyInfo{Address: "0x3333333333333333333333333333333333333333"})
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/keys":
+			_ = json.NewEncoder(w).Encode(ListKeysResponse{Keys: []KeyInfo{
+				{KeyID: "key-1", Address: "0x1111111111111111111111111111111111111111"},
+				{KeyID: "key-2"},
+			}})
+		case "/api/v1/keys/key-2":
+			_ = json.NewEncoder(w).Encode(KeyInfo{Address: "0x2222222222222222222222222222222222222222", Algorithm: "secp256k1"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{Code: 404, Message: "key not found"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.KMSConfig{
+		Endpoint:    server.URL,
+		AccessKeyID: "AK1234567890",
+		SecretKey:   "test-secret-key",
+	}, defaultLogger())
+
+	keys, err := client.ListKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListKeys() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0].KeyID != "key-1" || keys[1].Address != "" {
+		t.Fatalf("ListKeys() = %+v", keys)
+	}
+
+	info, err := client.GetKey(context.Background(), "key-2")
+	if err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	if info.KeyID != "key-2" || info.Address != "0x2222222222222222222222222222222222222222" || info.Algorithm != KeyAlgorithmSecp256k1 {
+		t.Errorf("GetKey() = %+v", info)
+	}
+
+	info, err = client.GetKey(context.Background(), "team/key?3")
+	if err != nil || info.KeyID != "team/key?3" || info.Address != "0x3333333333333333333333333333333333333333" {
+		t.Errorf("GetKey(team/key?3) = %+v, %v; want key ID escaped in the path", info, err)
+	}
+
+	_, err = client.GetKey(context.Background(), "missing")
+	if err == nil || !strings.Contains(err.Error(), "key not found") {
+		t.Errorf("GetKey(missing) error = %v, want KMS error message", err)
+	}
+}
+
+func TestKeyInfo_CheckSecp256k1(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		wantErr   bool
+	}{
+		{algorithm: "", wantErr: false},
+		{algorithm: "secp256k1", wantErr: false},
+		{algorithm: "SECP256K1", wantErr: false},
+		{algorithm: "ed25519", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		info := &KeyInfo{KeyID: "key-1", Algorithm: tt.algorithm}
+		if err := info.CheckSecp256k1(); (err != nil) != tt.wantErr {
+			t.Errorf("CheckSecp256k1(%q) error = %v, wantErr %v", tt.algorithm, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/config"
+)
+
+// unreachableEndpoint 返回一个已关闭服务器的地址，模拟主 KMS 集群宕机
+func unreachableEndpoint() string {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+	return url
+}
+
+func newFailoverClient(primary string, fallbacks ...string) *Client {
+	cfg := &config.KMSConfig{
+		Endpoint:          primary,
+		FallbackEndpoints: fallbacks,
+		AccessKeyID:       "AK",
+		SecretKey:         "secret",
+		MaxPollAttempts:   10,
+	}
+	return NewClient(cfg, defaultLogger()).WithClock(&fakeClock{now: time.Unix(0, 0)})
+}
+
+func TestClient_Failover_PrimaryUnreachable(t *testing.T) {
+	var calls int32
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path != "/api/v1/keys/key-1/sign" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SignResponse{Signature: "fallback-signature"})
+	}))
+	defer fallback.Close()
+
+	client := newFailoverClient(unreachableEndpoint(), fallback.URL)
+
+	signature, err := client.Sign(context.Background(), "key-1", []byte("message"))
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	if string(signature) != "fallback-signature" {
+		t.Errorf("Sign() = %s, want fallback-signature", signature)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Expected 1 request to fallback, got %d", calls)
+	}
+}
+
+func TestClient_Failover_AllEndpointsUnreachable(t *testing.T) {
+	client := newFailoverClient(unreachableEndpoint(), unreachableEndpoint())
+
+	if _, err := client.Sign(context.Background(), "key-1", []byte("message")); err == nil ||
+		!strings.Contains(err.Error(), "failed to execute sign request") {
+		t.Fatalf("Expected connection error, got %v", err)
+	}
+}
+
+func TestClient_Failover_NotOnErrorResponse(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Code: 403, Message: "key disabled"})
+	}))
+	defer primary.Close()
+
+	var fallbackCalls int32
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fallbackCalls, 1)
+	}))
+	defer fallback.Close()
+
+	client := newFailoverClient(primary.URL, fallback.URL)
+
+	_, err := client.Sign(context.Background(), "key-1", []byte("message"))
+	if err == nil || !strings.Contains(err.Error(), "key disabled") {
+		t.Fatalf("Expected KMS error response, got %v", err)
+	}
+	if atomic.LoadInt32(&fallbackCalls) != 0 {
+		t.Errorf("Expected no failover on a valid error response, fallback got %d requests", fallbackCalls)
+	}
+}
+
+func TestClient_Failover_NotAfterRequestSent(t *testing.T) {
+	// 主端点读取请求后断开连接：签名任务可能已创建，不能再提交到备用端点
+	var primaryCalls int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryCalls, 1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack() error: %v", err)
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer primary.Close()
+
+	var fallbackCalls int32
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fallbackCalls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(ListKeysResponse{Keys: []KeyInfo{{KeyID: "key-1"}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(SignResponse{Signature: "fallback-signature"})
+	}))
+	defer fallback.Close()
+
+	client := newFailoverClient(primary.URL, fallback.URL)
+
+	if _, err := client.Sign(context.Background(), "key-1", []byte("message")); err == nil {
+		t.Fatal("Expected error when the primary drops the connection after receiving the sign request")
+	}
+	if atomic.LoadInt32(&fallbackCalls) != 0 {
+		t.Errorf("Expected no failover after the sign request was sent, fallback got %d requests", fallbackCalls)
+	}
+
+	// GET 请求可安全重发，仍切换到备用端点
+	if _, err := client.ListKeys(context.Background()); err != nil {
+		t.Fatalf("ListKeys() error: %v", err)
+	}
+	if atomic.LoadInt32(&fallbackCalls) != 1 {
+		t.Errorf("Expected ListKeys to fail over, fallback got %d requests", fallbackCalls)
+	}
+}
+
+func TestClient_Failover_TaskPolledOnCreatingEndpoint(t *testing.T) {
+	var primaryCalls int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryCalls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	primaryURL := primary.URL
+	primary.Close()
+
+	var polls int32
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(TaskResponse{TaskID: "task-42"})
+		case r.URL.Path == "/api/v1/tasks/task-42":
+			if atomic.AddInt32(&polls, 1) < 2 {
+				_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusPendingApproval})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(TaskResult{Status: TaskStatusDone, Response: `{"signature":"approved-signature"}`})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer fallback.Close()
+
+	client := newFailoverClient(primaryURL, fallback.URL)
+
+	signature, err := client.Sign(context.Background(), "key-1", []byte("message"))
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	if string(signature) != "approved-signature" {
+		t.Errorf("Sign() = %s, want approved-signature", signature)
+	}
+	if atomic.LoadInt32(&polls) != 2 {
+		t.Errorf("Expected task to be polled twice on the fallback endpoint, got %d", polls)
+	}
+	if atomic.LoadInt32(&primaryCalls) != 0 {
+		t.Errorf("Expected no requests to the primary endpoint, got %d", primaryCalls)
+	}
+	if _, ok := client.taskEndpoints.load("task-42", client.clock.Now()); ok {
+		t.Error("Expected task endpoint to be forgotten after completion")
+	}
+}
+
+func TestClient_Failover_ListKeys(t *testing.T) {
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ListKeysResponse{Keys: []KeyInfo{{KeyID: "key-1"}}})
+	}))
+	defer fallback.Close()
+
+	client := newFailoverClient(unreachableEndpoint(), fallback.URL)
+
+	keys, err := client.ListKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListKeys() error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].KeyID != "key-1" {
+		t.Errorf("ListKeys() = %+v", keys)
+	}
+}
@@ -1,6 +1,7 @@
 package kms
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,6 +21,7 @@ type HTTPClient struct {
 	kmsConfig  *config.KMSConfig
 	httpClient *http.Client
 	logger     *logrus.Logger
+	clock      Clock
 }
 
 // NewHTTPClient creates a new MPC-KMS HTTP client.
@@ -37,25 +39,62 @@ type HTTPClient struct {
 // Returns:
 //   - *HTTPClient: A new HTTP client instance
 func NewHTTPClient(kmsCfg *config.KMSConfig, logger *logrus.Logger) *HTTPClient {
+	transport := utils.CreateTransport(100, 90*time.Second)
+	if err := utils.SetProxy(transport, kmsCfg.Proxy); err != nil {
+		// 配置校验已拒绝无效代理，这里只会在未校验的配置下发生
+		logger.WithError(err).Warn("Ignoring invalid KMS proxy, connecting directly")
+	}
+	if err := utils.SetTLS(transport, kmsCfg.TLSMinVersion, kmsCfg.TLSCAFile, kmsCfg.TLSInsecureSkipVerify); err != nil {
+		// 配置校验已检查版本和 CA 文件，这里只会在未校验的配置或文件内容无效时发生
+		logger.WithError(err).Error("Ignoring invalid KMS TLS settings, using the default TLS configuration")
+	}
+	if kmsCfg.TLSInsecureSkipVerify {
+		logger.Warn("KMS TLS certificate verification is DISABLED: connections can be intercepted. For development only, NEVER use in production")
+	}
+
 	return &HTTPClient{
 		kmsConfig: kmsCfg,
 		httpClient: &http.Client{
 			Timeout:   30 * time.Second,
-			Transport: utils.CreateTransport(100, 90*time.Second),
+			Transport: transport,
 		},
 		logger: logger,
+		clock:  RealClock(),
 	}
 }
 
+// WithClock replaces the clock used to generate Date headers.
+//
+// Parameters:
+//   - clock: The clock to use; nil restores the real clock
+//
+// Returns:
+//   - *HTTPClient: The same client for chaining
+func (c *HTTPClient) WithClock(clock Clock) *HTTPClient {
+	if clock == nil {
+		clock = RealClock()
+	}
+	c.clock = clock
+	return c
+}
+
 // SignRequest signs an HTTP request according to MPC-KMS specification.
 //
 // This method performs HMAC-SHA256 authentication:
 //  1. Generate GMT timestamp
 //  2. Calculate Content-SHA256 (base64 encoded)
-//  3. Build signing string: VERB\nContent-SHA256\nContent-Type\nDate
+//  3. Build signing string: VERB\nContent-SHA256\nContent-Type\nDate,
+//     followed by one "\nname:value" line per KMSConfig.SignedHeaders entry
 //  4. Calculate HMAC-SHA256 signature
 //  5. Set Authorization header: "MPC-KMS AK:Signature"
 //
+// The static headers in KMSConfig.Headers are added to every request; only
+// those listed in KMSConfig.SignedHeaders are covered by the signature.
+//
+// With KMSConfig.VerifyContentSHA256 set, the Content-SHA256 is recomputed
+// from the body the request will actually send and a warning is logged if it
+// differs from the signed one, catching bodies modified after signing.
+//
 // Parameters:
 //   - req: The HTTP request to sign (will be modified in place)
 //   - body: The request body bytes for Content-SHA256 calculation
@@ -64,7 +103,7 @@ func NewHTTPClient(kmsCfg *config.KMSConfig, logger *logrus.Logger) *HTTPClient
 //   - error: An error if signing fails
 func (c *HTTPClient) SignRequest(req *http.Request, body []byte) error {
 	// 1. 生成 GMT 格式的时间戳
-	date := time.Now().UTC().Format("Mon, 02 Jan 2006 15:04:05 GMT")
+	date := c.clock.Now().UTC().Format("Mon, 02 Jan 2006 15:04:05 GMT")
 
 	// 2. 计算 Content-SHA256
 	contentSHA256 := CalculateContentSHA256(body)
@@ -75,8 +114,12 @@ func (c *HTTPClient) SignRequest(req *http.Request, body []byte) error {
 		contentType = "application/json"
 	}
 
-	// 4. 构建签名字符串（根据文档规范）
-	signingString := BuildSigningString(req.Method, contentSHA256, contentType, date)
+	// 4. 构建签名字符串（根据文档规范），配置的静态请求头仅在 signed-headers 中列出时参与签名
+	for name, value := range c.kmsConfig.Headers {
+		req.Header.Set(name, value)
+	}
+	signingString := BuildSigningString(req.Method, contentSHA256, contentType, date) +
+		BuildSignedHeaders(c.kmsConfig.SignedHeaders, req.Header)
 
 	// 5. 计算 HMAC-SHA256 签名
 	signature := CalculateHMACSHA256(signingString, c.kmsConfig.SecretKey)
@@ -89,9 +132,53 @@ func (c *HTTPClient) SignRequest(req *http.Request, body []byte) error {
 	req.Header.Set("Date", date)
 	req.Header.Set("Content-Type", contentType)
 
+	if c.kmsConfig.VerifyContentSHA256 {
+		c.verifyContentSHA256(req, body, contentSHA256)
+	}
+
 	return nil
 }
 
+// verifyContentSHA256 按请求实际发送的请求体重新计算 Content-SHA256，与签名时使用的值不一致时记录告警
+func (c *HTTPClient) verifyContentSHA256(req *http.Request, signedBody []byte, signedSHA256 string) {
+	logger := c.logger.WithFields(logrus.Fields{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	})
+
+	var sent []byte
+	switch {
+	case req.GetBody != nil:
+		rc, err := req.GetBody()
+		if err != nil {
+			logger.WithError(err).Warn("Content-SHA256 self-check could not read the request body")
+			return
+		}
+		defer rc.Close()
+		if sent, err = io.ReadAll(rc); err != nil {
+			logger.WithError(err).Warn("Content-SHA256 self-check could not read the request body")
+			return
+		}
+	case req.Body != nil && req.Body != http.NoBody:
+		var err error
+		if sent, err = io.ReadAll(req.Body); err != nil {
+			logger.WithError(err).Warn("Content-SHA256 self-check could not read the request body")
+			return
+		}
+		// 读取后恢复请求体，保证实际发送的内容不变
+		req.Body = io.NopCloser(bytes.NewReader(sent))
+	}
+
+	if actual := CalculateContentSHA256(sent); actual != signedSHA256 {
+		logger.WithFields(logrus.Fields{
+			"signed_content_sha256": signedSHA256,
+			"actual_content_sha256": actual,
+			"signed_body_bytes":     len(signedBody),
+			"actual_body_bytes":     len(sent),
+		}).Warn("Content-SHA256 mismatch: the request body sent differs from the body that was signed, KMS will reject the signature")
+	}
+}
+
 // Do executes an HTTP request with automatic signing.
 //
 // This method:
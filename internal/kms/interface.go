@@ -20,6 +20,33 @@ type ClientInterface interface {
 	WaitForTaskCompletion(ctx context.Context, taskID string, interval time.Duration) (*TaskResult, error)
 }
 
+// KeyDiscoverer 定义密钥发现接口
+type KeyDiscoverer interface {
+	// ListKeys 列出凭证可访问的所有密钥
+	ListKeys(ctx context.Context) ([]KeyInfo, error)
+
+	// GetKey 获取单个密钥的详情（包括地址）
+	GetKey(ctx context.Context, keyID string) (*KeyInfo, error)
+}
+
+// TaskManager 定义审批任务管理接口
+type TaskManager interface {
+	// GetTaskResult 获取任务结果
+	GetTaskResult(ctx context.Context, taskID string) (*TaskResult, error)
+
+	// CancelTask 取消待审批的任务；任务已结束时返回其最终状态
+	CancelTask(ctx context.Context, taskID string) (*TaskResult, error)
+
+	// PendingTasks 返回正在轮询等待审批的任务
+	PendingTasks() []PendingTask
+
+	// TaskKeyID 返回本客户端创建的任务的签名密钥 ID，未知任务返回 false
+	TaskKeyID(taskID string) (string, bool)
+
+	// Clock 返回任务计时使用的时钟，PendingTask 的开始时间取自该时钟
+	Clock() Clock
+}
+
 // Signer 定义签名器接口
 type Signer interface {
 	// SignMessage 对消息进行签名
@@ -61,5 +88,7 @@ func (s *MPCKMSSigner) SignTransaction(ctx context.Context, transactionData []by
 // VerifyInterfaceImplementation 验证接口实现
 var (
 	_ ClientInterface = (*Client)(nil)
+	_ KeyDiscoverer   = (*Client)(nil)
+	_ TaskManager     = (*Client)(nil)
 	_ Signer          = (*MPCKMSSigner)(nil)
 )
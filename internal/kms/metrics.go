@@ -0,0 +1,47 @@
+package kms
+
+import (
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/metrics"
+)
+
+// Approval outcomes recorded by approvalWaitSeconds.
+const (
+	approvalOutcomeApproved  = "approved"
+	approvalOutcomeRejected  = "rejected"
+	approvalOutcomeFailed    = "failed"
+	approvalOutcomeTimeout   = "timeout"
+	approvalOutcomeCancelled = "cancelled"
+)
+
+// KMS operations recorded by authFailures.
+const (
+	kmsOperationSign    = "sign"
+	kmsOperationGetTask = "get_task"
+)
+
+// authFailures counts requests the KMS rejected with 401 or 403.
+var authFailures = metrics.NewCounterVec(
+	"web3signer_kms_auth_failures_total",
+	"KMS requests rejected with HTTP 401 or 403, usually caused by wrong credentials or clock skew.",
+	"operation",
+)
+
+// approvalWaitSeconds tracks how long approval tasks take to reach a final state.
+var approvalWaitSeconds = metrics.NewHistogramVec(
+	"web3signer_kms_approval_wait_seconds",
+	"Time from approval task creation until it is approved, rejected, failed, cancelled or times out.",
+	"outcome",
+	[]float64{1, 5, 15, 30, 60, 120, 300, 600},
+)
+
+func init() {
+	metrics.DefaultRegistry.MustRegister(approvalWaitSeconds)
+	metrics.DefaultRegistry.MustRegister(authFailures)
+}
+
+// observeApprovalWait records the elapsed approval time for outcome.
+func (c *Client) observeApprovalWait(outcome string, startTime time.Time) {
+	approvalWaitSeconds.Observe(outcome, c.clock.Now().Sub(startTime).Seconds())
+}
@@ -0,0 +1,44 @@
+package kms
+
+import (
+	"sort"
+	"time"
+)
+
+// PendingTask describes an approval task the client is currently polling.
+type PendingTask struct {
+	TaskID    string    `json:"taskId"`
+	KeyID     string    `json:"keyId,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// PendingTasks returns the approval tasks currently being polled by WaitForTaskCompletion.
+//
+// Tasks are ordered by start time, oldest first. Entries are removed as soon as
+// polling ends, whether the task completed, failed, timed out or was cancelled.
+//
+// Returns:
+//   - []PendingTask: Snapshot of the tasks awaiting approval
+func (c *Client) PendingTasks() []PendingTask {
+	tasks := make([]PendingTask, 0)
+	c.pendingTasks.Range(func(_, value any) bool {
+		tasks = append(tasks, value.(PendingTask))
+		return true
+	})
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].StartedAt.Before(tasks[j].StartedAt)
+	})
+	return tasks
+}
+
+// trackPendingTask 记录正在轮询的任务，返回的函数在轮询结束时删除记录
+func (c *Client) trackPendingTask(taskID, keyID string) func() {
+	c.pendingTasks.Store(taskID, PendingTask{
+		TaskID:    taskID,
+		KeyID:     keyID,
+		StartedAt: c.clock.Now(),
+	})
+	return func() {
+		c.pendingTasks.Delete(taskID)
+	}
+}
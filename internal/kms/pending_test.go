@@ -0,0 +1,69 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/config"
+)
+
+func TestClient_PendingTasks(t *testing.T) {
+	var client *Client
+	var seen []PendingTask
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 轮询期间任务应出现在待审批列表中
+		seen = client.PendingTasks()
+		w.Header().Set("Content-Type", "application/json")
+		status := TaskStatusPendingApproval
+		if r.URL.Path == "/api/v1/tasks/task-done" {
+			status = TaskStatusDone
+		}
+		_ = json.NewEncoder(w).Encode(TaskResult{Status: status})
+	}))
+	defer server.Close()
+
+	cfg := &config.KMSConfig{Endpoint: server.URL, AccessKeyID: "AK", SecretKey: "secret", MaxPollAttempts: 2}
+	start := time.Unix(1000, 0)
+	client = NewClient(cfg, defaultLogger()).WithClock(&fakeClock{now: start})
+
+	tests := []struct {
+		name    string
+		taskID  string
+		wantErr bool
+	}{
+		{name: "completed task", taskID: "task-done"},
+		{name: "timed out task", taskID: "task-slow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			_, err := client.waitForTask(context.Background(), tt.taskID, "key-1", time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("waitForTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(seen) != 1 || seen[0].TaskID != tt.taskID || seen[0].KeyID != "key-1" || seen[0].StartedAt.IsZero() {
+				t.Errorf("PendingTasks() during polling = %+v", seen)
+			}
+			if pending := client.PendingTasks(); len(pending) != 0 {
+				t.Errorf("Expected task to be removed after polling, got %+v", pending)
+			}
+		})
+	}
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := client.waitForTask(ctx, "task-cancelled", "key-1", time.Second); err == nil {
+			t.Fatal("Expected error for cancelled context")
+		}
+		if pending := client.PendingTasks(); len(pending) != 0 {
+			t.Errorf("Expected task to be removed after cancellation, got %+v", pending)
+		}
+	})
+}
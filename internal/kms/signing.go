@@ -5,6 +5,8 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"net/http"
+	"strings"
 )
 
 // CalculateContentSHA256 计算内容的 SHA256 哈希（base64编码）
@@ -29,6 +31,21 @@ func BuildSigningString(verb, contentSHA256, contentType, date string) string {
 	)
 }
 
+// BuildSignedHeaders 构建追加到签名字符串末尾的请求头部分
+//
+// 每个名称按给定顺序追加一行 "\n" + 小写名称 + ":" + 去除首尾空白的值；names 为空时返回空字符串，
+// 此时签名字符串与文档规范的四行格式一致
+func BuildSignedHeaders(names []string, header http.Header) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString("\n")
+		b.WriteString(strings.ToLower(name))
+		b.WriteString(":")
+		b.WriteString(strings.TrimSpace(header.Get(name)))
+	}
+	return b.String()
+}
+
 // CalculateHMACSHA256 计算 HMAC-SHA256 签名（base64编码）
 func CalculateHMACSHA256(message, secretKey string) string {
 	mac := hmac.New(sha256.New, []byte(secretKey))
@@ -0,0 +1,83 @@
+package kms
+
+import (
+	"math/big"
+	"strings"
+)
+
+// FormatAmount converts an integer amount in a token's smallest unit (e.g. wei)
+// to a human-readable decimal string (e.g. "1.5" for 1.5e18 wei at 18 decimals).
+//
+// The conversion is exact integer arithmetic, so arbitrarily large amounts
+// never lose precision. With precision > 0 the fraction is rounded half-up to
+// at most that many digits; a non-zero amount that rounds to zero is shown as
+// "<0.0…1" rather than "0" so approvers never see a transfer as empty.
+// Trailing fractional zeros are removed.
+//
+// Parameters:
+//   - raw: The amount in the smallest unit
+//   - decimals: Number of decimals of the token (18 for ETH)
+//   - precision: Maximum fractional digits to show; 0 or less shows all
+//
+// Returns:
+//   - string: The formatted amount
+func FormatAmount(raw *big.Int, decimals, precision int) string {
+	if raw.Sign() < 0 {
+		return "-" + FormatAmount(new(big.Int).Neg(raw), decimals, precision)
+	}
+	if decimals <= 0 {
+		return raw.String()
+	}
+
+	digits := decimals
+	scaled := new(big.Int).Set(raw)
+	if precision > 0 && precision < decimals {
+		// 按显示精度缩放后四舍五入：scaled = round(raw / 10^(decimals-precision))
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-precision)), nil)
+		remainder := new(big.Int)
+		scaled.QuoRem(scaled, divisor, remainder)
+		if remainder.Lsh(remainder, 1).Cmp(divisor) >= 0 {
+			scaled.Add(scaled, big.NewInt(1))
+		}
+		digits = precision
+		if scaled.Sign() == 0 && raw.Sign() != 0 {
+			return "<0." + strings.Repeat("0", precision-1) + "1"
+		}
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	whole, fraction := new(big.Int).QuoRem(scaled, unit, new(big.Int))
+	if fraction.Sign() == 0 {
+		return whole.String()
+	}
+	fractionDigits := fraction.String()
+	fractionDigits = strings.Repeat("0", digits-len(fractionDigits)) + fractionDigits
+	return whole.String() + "." + strings.TrimRight(fractionDigits, "0")
+}
+
+// WithDisplayAmount returns a copy of the summary whose Amount is converted
+// from the smallest unit to a human-readable value, keeping the original
+// integer in AmountRaw.
+//
+// Summaries whose Amount is not a non-negative decimal integer (for example
+// one already formatted by the caller) are returned unchanged.
+//
+// Parameters:
+//   - decimals: Number of decimals of the summary's token
+//   - precision: Maximum fractional digits to show; 0 shows all
+//
+// Returns:
+//   - *SignSummary: The converted copy, the original summary if Amount is not an integer, or nil for a nil summary
+func (s *SignSummary) WithDisplayAmount(decimals, precision int) *SignSummary {
+	if s == nil {
+		return nil
+	}
+	raw, ok := new(big.Int).SetString(s.Amount, 10)
+	if !ok || raw.Sign() < 0 {
+		return s
+	}
+	formatted := *s
+	formatted.Amount = FormatAmount(raw, decimals, precision)
+	formatted.AmountRaw = s.Amount
+	return &formatted
+}
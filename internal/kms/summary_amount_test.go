@@ -0,0 +1,111 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/config"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		decimals  int
+		precision int
+		want      string
+	}{
+		{name: "zero", raw: "0", decimals: 18, want: "0"},
+		{name: "whole ether", raw: "2000000000000000000", decimals: 18, want: "2"},
+		{name: "fractional ether", raw: "1500000000000000000", decimals: 18, want: "1.5"},
+		{name: "one wei", raw: "1", decimals: 18, want: "0.000000000000000001"},
+		{name: "token decimals", raw: "1234567", decimals: 6, want: "1.234567"},
+		{name: "no decimals", raw: "42", decimals: 0, want: "42"},
+		{name: "uint256 max", raw: "115792089237316195423570985008687907853269984665640564039457584007913129639935", decimals: 18,
+			want: "115792089237316195423570985008687907853269984665640564039457.584007913129639935"},
+		{name: "rounded down", raw: "1234499999999999999", decimals: 18, precision: 3, want: "1.234"},
+		{name: "rounded half up", raw: "1234500000000000000", decimals: 18, precision: 3, want: "1.235"},
+		{name: "rounding carries into whole", raw: "1999999999999999999", decimals: 18, precision: 6, want: "2"},
+		{name: "tiny amount not shown as zero", raw: "1", decimals: 18, precision: 6, want: "<0.000001"},
+		{name: "precision above decimals", raw: "1234567", decimals: 6, precision: 8, want: "1.234567"},
+		{name: "negative", raw: "-1500000000000000000", decimals: 18, want: "-1.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, ok := new(big.Int).SetString(tt.raw, 10)
+			if !ok {
+				t.Fatalf("invalid test amount %q", tt.raw)
+			}
+			if got := FormatAmount(raw, tt.decimals, tt.precision); got != tt.want {
+				t.Errorf("FormatAmount(%s, %d, %d) = %q, want %q", tt.raw, tt.decimals, tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignSummary_WithDisplayAmount(t *testing.T) {
+	summary := NewTransferSummary("0xfrom", "0xto", "2500000", "USDT", "")
+	converted := summary.WithDisplayAmount(6, 0)
+	if converted.Amount != "2.5" || converted.AmountRaw != "2500000" {
+		t.Errorf("WithDisplayAmount() = %+v, want amount 2.5 and raw 2500000", converted)
+	}
+	if summary.Amount != "2500000" || summary.AmountRaw != "" {
+		t.Error("WithDisplayAmount() must not modify the original summary")
+	}
+
+	formatted := NewTransferSummary("0xfrom", "0xto", "2.5", "USDT", "")
+	if got := formatted.WithDisplayAmount(6, 0); got != formatted {
+		t.Errorf("Expected non-integer amount to be left unchanged, got %+v", got)
+	}
+	if (*SignSummary)(nil).WithDisplayAmount(18, 0) != nil {
+		t.Error("Expected nil summary to stay nil")
+	}
+}
+
+func TestClient_SignWithOptions_ConvertsSummaryAmount(t *testing.T) {
+	var received SignRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SignResponse{Signature: "sig"})
+	}))
+	defer server.Close()
+
+	cfg := &config.KMSConfig{
+		Endpoint: server.URL, AccessKeyID: "AK1234567890", SecretKey: "test-secret-key",
+		SummaryAmountPrecision: 4,
+		SummaryTokenDecimals:   map[string]string{"USDT": "6"},
+	}
+	client := NewClient(cfg, defaultLogger())
+
+	tests := []struct {
+		name    string
+		amount  string
+		token   string
+		wantAmt string
+		wantRaw string
+	}{
+		{name: "ether", amount: "1234567890000000000", token: "ETH", wantAmt: "1.2346", wantRaw: "1234567890000000000"},
+		{name: "registered token", amount: "2500000", token: "usdt", wantAmt: "2.5", wantRaw: "2500000"},
+		{name: "unregistered token", amount: "2500000", token: "DAI", wantAmt: "2500000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = SignRequest{}
+			summary := NewTransferSummary("0xfrom", "0xto", tt.amount, tt.token, "")
+			if _, err := client.SignWithOptions(context.Background(), "key-1", []byte("msg"), DataEncodingHex, summary, ""); err != nil {
+				t.Fatalf("SignWithOptions() error = %v", err)
+			}
+			if received.Summary == nil || received.Summary.Amount != tt.wantAmt || received.Summary.AmountRaw != tt.wantRaw {
+				t.Errorf("summary sent = %+v, want amount %q raw %q", received.Summary, tt.wantAmt, tt.wantRaw)
+			}
+			if summary.Amount != tt.amount {
+				t.Error("SignWithOptions() must not modify the caller's summary")
+			}
+		})
+	}
+}
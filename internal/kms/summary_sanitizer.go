@@ -0,0 +1,70 @@
+package kms
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SummarySanitizer cleans one string field of a SignSummary before it is
+// sent to the KMS and shown to approvers.
+//
+// field is the JSON name of the summary field (e.g. "remark"), so a custom
+// sanitizer can treat free-text fields differently from addresses.
+type SummarySanitizer func(field, value string) string
+
+// NewSummarySanitizer returns the default SignSummary sanitizer.
+//
+// It removes control and invisible formatting characters (including bidi
+// overrides such as U+202E that can visually reorder an address), collapses
+// the result to at most maxLength characters and HTML-escapes it, so approval
+// UIs that render HTML cannot be made to display spoofed details.
+//
+// Parameters:
+//   - maxLength: Maximum characters per field before escaping; 0 or less disables the limit
+//
+// Returns:
+//   - SummarySanitizer: The sanitizer
+func NewSummarySanitizer(maxLength int) SummarySanitizer {
+	return func(_, value string) string {
+		cleaned := strings.Map(func(r rune) rune {
+			if r == utf8.RuneError || unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+				return -1
+			}
+			return r
+		}, value)
+		if maxLength > 0 && utf8.RuneCountInString(cleaned) > maxLength {
+			runes := []rune(cleaned)
+			cleaned = string(runes[:maxLength-1]) + "…"
+		}
+		return html.EscapeString(cleaned)
+	}
+}
+
+// Sanitize returns a copy of the summary with every string field passed
+// through sanitizer. The access list holds only addresses and hashes and is
+// copied unchanged.
+//
+// Parameters:
+//   - sanitizer: The sanitizer to apply
+//
+// Returns:
+//   - *SignSummary: The sanitized copy, or nil for a nil summary
+func (s *SignSummary) Sanitize(sanitizer SummarySanitizer) *SignSummary {
+	if s == nil {
+		return nil
+	}
+	sanitized := &SignSummary{
+		Type:   sanitizer("type", s.Type),
+		From:   sanitizer("from", s.From),
+		To:     sanitizer("to", s.To),
+		Amount: sanitizer("amount", s.Amount),
+		Remark: sanitizer("remark", s.Remark),
+		Token:  sanitizer("token", s.Token),
+	}
+	if s.AmountRaw != "" {
+		sanitized.AmountRaw = sanitizer("amount_raw", s.AmountRaw)
+	}
+	return sanitized.WithAccessList(s.AccessList)
+}
@@ -0,0 +1,112 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/umbracle/ethgo"
+)
+
+func TestNewSummarySanitizer(t *testing.T) {
+	sanitize := NewSummarySanitizer(16)
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain value unchanged", value: "1.5 ETH", want: "1.5 ETH"},
+		{name: "html is escaped", value: `<img src=x onerror="alert(1)">`, want: "&lt;img src=x oner…"},
+		{name: "script tag", value: "<b>ok</b>", want: "&lt;b&gt;ok&lt;/b&gt;"},
+		{name: "newlines and control characters removed", value: "pay\r\nApproved: yes\x00\x1b[31m", want: "payApproved: ye…"},
+		{name: "bidi override removed", value: "0xabc‮fed", want: "0xabcfed"},
+		{name: "zero width characters removed", value: "US​DT", want: "USDT"},
+		{name: "invalid utf-8 removed", value: "ab\xffcd", want: "abcd"},
+		{name: "truncated to max length", value: strings.Repeat("a", 40), want: strings.Repeat("a", 15) + "…"},
+		{name: "multibyte characters counted as one", value: strings.Repeat("转", 16), want: strings.Repeat("转", 16)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitize("remark", tt.value); got != tt.want {
+				t.Errorf("sanitize(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if got := NewSummarySanitizer(0)("remark", strings.Repeat("a", 1000)); len(got) != 1000 {
+		t.Errorf("Expected no length limit with maxLength 0, got %d characters", len(got))
+	}
+}
+
+func TestSignSummary_Sanitize(t *testing.T) {
+	accessList := ethgo.AccessList{{Address: ethgo.HexToAddress("0x1"), Storage: []ethgo.Hash{{1}}}}
+	summary := NewTransferSummary("0xfrom", "0xto‮", "1<2", "ETH", "<script>").WithAccessList(accessList)
+
+	var fields []string
+	sanitized := summary.Sanitize(func(field, value string) string {
+		fields = append(fields, field)
+		return NewSummarySanitizer(0)(field, value)
+	})
+
+	if sanitized.To != "0xto" || sanitized.Amount != "1&lt;2" || sanitized.Remark != "&lt;script&gt;" {
+		t.Errorf("Unexpected sanitized summary: %+v", sanitized)
+	}
+	if summary.Remark != "<script>" {
+		t.Error("Sanitize() must not modify the original summary")
+	}
+	if len(sanitized.AccessList) != 1 || sanitized.AccessList[0].Address != accessList[0].Address {
+		t.Errorf("Expected access list to be copied, got %+v", sanitized.AccessList)
+	}
+	if want := "type,from,to,amount,remark,token"; strings.Join(fields, ",") != want {
+		t.Errorf("sanitized fields = %s, want %s", strings.Join(fields, ","), want)
+	}
+	if (*SignSummary)(nil).Sanitize(NewSummarySanitizer(0)) != nil {
+		t.Error("Expected nil summary to stay nil")
+	}
+}
+
+func TestClient_SignWithOptions_SanitizesSummary(t *testing.T) {
+	var received SignRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SignResponse{Signature: "sig"})
+	}))
+	defer server.Close()
+
+	cfg := &config.KMSConfig{Endpoint: server.URL, AccessKeyID: "AK1234567890", SecretKey: "test-secret-key", SummaryMaxFieldLength: 8}
+	summary := NewTransferSummary("0xfrom", "0xto", "1", "ETH", "<a href=evil>click me</a>\nApproved")
+
+	t.Run("default sanitizer", func(t *testing.T) {
+		client := NewClient(cfg, defaultLogger())
+		if _, err := client.SignWithOptions(context.Background(), "key-1", []byte("msg"), DataEncodingHex, summary, ""); err != nil {
+			t.Fatalf("SignWithOptions() error = %v", err)
+		}
+		if received.Summary == nil || received.Summary.Remark != "&lt;a href…" {
+			t.Errorf("remark sent = %+v, want sanitized and truncated", received.Summary)
+		}
+		if summary.Remark != "<a href=evil>click me</a>\nApproved" {
+			t.Error("SignWithOptions() must not modify the caller's summary")
+		}
+	})
+
+	t.Run("custom sanitizer", func(t *testing.T) {
+		client := NewClient(cfg, defaultLogger()).WithSummarySanitizer(func(field, value string) string {
+			if field == "remark" {
+				return "[redacted]"
+			}
+			return value
+		})
+		if _, err := client.SignWithOptions(context.Background(), "key-1", []byte("msg"), DataEncodingHex, summary, ""); err != nil {
+			t.Fatalf("SignWithOptions() error = %v", err)
+		}
+		if received.Summary == nil || received.Summary.Remark != "[redacted]" || received.Summary.From != "0xfrom" {
+			t.Errorf("summary sent = %+v, want custom sanitizer applied", received.Summary)
+		}
+	})
+}
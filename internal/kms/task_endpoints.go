@@ -0,0 +1,72 @@
+package kms
+
+import (
+	"sync"
+	"time"
+)
+
+// taskEndpointTTL 审批任务端点记录的保留时间
+//
+// 异步审批模式下端点记录在客户端查询到任务结束时删除；无人查询的任务在此之后清理，避免记录无限增长
+const taskEndpointTTL = 24 * time.Hour
+
+// taskEndpoint 创建审批任务的端点、签名密钥及记录时间
+type taskEndpoint struct {
+	endpoint string
+	keyID    string
+	storedAt time.Time
+}
+
+// taskEndpointMap 记录创建审批任务的端点与密钥（taskID -> endpoint），零值可直接使用
+type taskEndpointMap struct {
+	mu      sync.Mutex
+	entries map[string]taskEndpoint
+}
+
+// store 记录任务的端点与签名密钥，并清理超过 taskEndpointTTL 的记录
+func (m *taskEndpointMap) store(taskID, endpoint, keyID string, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries == nil {
+		m.entries = make(map[string]taskEndpoint)
+	}
+	for id, entry := range m.entries {
+		if now.Sub(entry.storedAt) >= taskEndpointTTL {
+			delete(m.entries, id)
+		}
+	}
+	m.entries[taskID] = taskEndpoint{endpoint: endpoint, keyID: keyID, storedAt: now}
+}
+
+// load 返回任务的端点，记录不存在或已过期时返回 false
+func (m *taskEndpointMap) load(taskID string, now time.Time) (string, bool) {
+	entry, ok := m.entry(taskID, now)
+	return entry.endpoint, ok
+}
+
+// loadKeyID 返回创建任务的签名密钥 ID，记录不存在或已过期时返回 false
+func (m *taskEndpointMap) loadKeyID(taskID string, now time.Time) (string, bool) {
+	entry, ok := m.entry(taskID, now)
+	return entry.keyID, ok
+}
+
+// entry 返回任务的记录，记录不存在或已过期时返回 false
+func (m *taskEndpointMap) entry(taskID string, now time.Time) (taskEndpoint, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[taskID]
+	if !ok || now.Sub(entry.storedAt) >= taskEndpointTTL {
+		return taskEndpoint{}, false
+	}
+	return entry, true
+}
+
+// delete 删除任务的端点记录
+func (m *taskEndpointMap) delete(taskID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, taskID)
+}
@@ -54,5 +54,5 @@ func ParseTransactionSummary(transactionData []byte) (*SignSummary, error) {
 	// 默认使用 ETH 作为代币符号
 	token := "ETH"
 
-	return NewTransferSummary(from, to, amount, token, ""), nil
+	return NewTransferSummary(from, to, amount, token, "").WithAccessList(tx.AccessList), nil
 }
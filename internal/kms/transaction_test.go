@@ -261,3 +261,36 @@ func TestParseTransactionSummary_TypeSupport(t *testing.T) {
 func addressPtr(addr ethgo.Address) *ethgo.Address {
 	return &addr
 }
+
+func TestParseTransactionSummary_AccessList(t *testing.T) {
+	accessList := ethgo.AccessList{
+		{
+			Address: ethgo.HexToAddress("0x2222222222222222222222222222222222222222"),
+			Storage: []ethgo.Hash{ethgo.HexToHash("0x01")},
+		},
+	}
+	tx := &ethgo.Transaction{
+		Type:                 ethgo.TransactionDynamicFee,
+		Gas:                  50000,
+		To:                   addressPtr(ethgo.HexToAddress("0x1111111111111111111111111111111111111111")),
+		Value:                big.NewInt(0),
+		MaxPriorityFeePerGas: big.NewInt(1500000000),
+		MaxFeePerGas:         big.NewInt(2000000000),
+		ChainID:              big.NewInt(1),
+		AccessList:           accessList,
+	}
+
+	encoded, err := tx.MarshalRLPTo(nil)
+	if err != nil {
+		t.Fatalf("Failed to encode transaction: %v", err)
+	}
+
+	summary, err := ParseTransactionSummary(encoded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(summary.AccessList) != 1 || summary.AccessList[0].Address != accessList[0].Address ||
+		len(summary.AccessList[0].Storage) != 1 || summary.AccessList[0].Storage[0] != accessList[0].Storage[0] {
+		t.Errorf("Expected access list %+v in summary, got %+v", accessList, summary.AccessList)
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/umbracle/ethgo"
 )
 
 // SignRequest 表示 MPC-KMS 签名请求
@@ -20,8 +24,13 @@ type SignSummary struct {
 	From   string `json:"from"`
 	To     string `json:"to"`
 	Amount string `json:"amount"`
-	Remark string `json:"remark,omitempty"`
-	Token  string `json:"token"`
+	// AmountRaw 以最小单位（如 wei）表示的原始金额；Amount 转换为可读单位后保留，供审批人核对
+	AmountRaw string `json:"amount_raw,omitempty"`
+	Remark    string `json:"remark,omitempty"`
+	Token     string `json:"token"`
+
+	// AccessList EIP-2930/1559 交易访问列表，供审批人查看交易涉及的地址和存储槽
+	AccessList ethgo.AccessList `json:"access_list,omitempty"`
 }
 
 // SignResponse 表示 MPC-KMS 签名响应
@@ -43,8 +52,31 @@ const (
 	TaskStatusRejected        TaskStatus = "REJECTED"
 	TaskStatusDone            TaskStatus = "DONE"
 	TaskStatusFailed          TaskStatus = "FAILED"
+	TaskStatusCancelled       TaskStatus = "CANCELLED"
 )
 
+// IsFinal 报告任务是否已结束（不再变化）
+func (s TaskStatus) IsFinal() bool {
+	switch s {
+	case TaskStatusDone, TaskStatusFailed, TaskStatusRejected, TaskStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApprovalPendingError 表示签名需要审批且客户端启用了异步审批模式
+//
+// 调用方应保存 TaskID，并通过 GetTaskResult 查询审批进度
+type ApprovalPendingError struct {
+	TaskID string
+}
+
+// Error 实现 error 接口
+func (e *ApprovalPendingError) Error() string {
+	return fmt.Sprintf("sign request pending approval (task %s)", e.TaskID)
+}
+
 // TaskResult 表示任务结果
 type TaskResult struct {
 	Status   TaskStatus `json:"status"`
@@ -52,6 +84,32 @@ type TaskResult struct {
 	Response string     `json:"response,omitempty"`
 }
 
+// KeyAlgorithmSecp256k1 以太坊签名要求的密钥曲线
+const KeyAlgorithmSecp256k1 = "secp256k1"
+
+// KeyInfo 表示凭证可访问的 MPC-KMS 密钥
+type KeyInfo struct {
+	KeyID     string `json:"key_id"`
+	Address   string `json:"address,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"` // 密钥曲线/算法（如 secp256k1、ed25519），旧版 KMS 可能不返回
+}
+
+// CheckSecp256k1 检查密钥是否可用于以太坊签名
+//
+// 其它曲线（如 ed25519）的签名无法按以太坊方式恢复地址，会返回错误；
+// KMS 未返回算法时无法判断，视为通过
+func (k *KeyInfo) CheckSecp256k1() error {
+	if k.Algorithm == "" || strings.EqualFold(k.Algorithm, KeyAlgorithmSecp256k1) {
+		return nil
+	}
+	return fmt.Errorf("key %s uses algorithm %s, Ethereum signing requires %s", k.KeyID, k.Algorithm, KeyAlgorithmSecp256k1)
+}
+
+// ListKeysResponse 表示密钥列表响应
+type ListKeysResponse struct {
+	Keys []KeyInfo `json:"keys"`
+}
+
 // ErrorResponse 表示 MPC-KMS 错误响应
 type ErrorResponse struct {
 	Code    int    `json:"code"`
@@ -116,6 +174,18 @@ func NewTransferSummary(from, to, amount, token, remark string) *SignSummary {
 	}
 }
 
+// WithAccessList 为摘要添加交易访问列表，空列表不会写入摘要
+func (s *SignSummary) WithAccessList(accessList ethgo.AccessList) *SignSummary {
+	s.AccessList = nil
+	for _, entry := range accessList {
+		s.AccessList = append(s.AccessList, ethgo.AccessEntry{
+			Address: entry.Address,
+			Storage: append([]ethgo.Hash(nil), entry.Storage...),
+		})
+	}
+	return s
+}
+
 // Marshal 序列化签名请求
 func (r *SignRequest) Marshal() ([]byte, error) {
 	return json.Marshal(r)
@@ -148,6 +218,36 @@ func UnmarshalTaskResult(data []byte) (*TaskResult, error) {
 	return &result, nil
 }
 
+// SignatureFromTaskResponse 从任务结果的 Response 字段中提取签名
+//
+// Response 通常是 JSON 编码的 SignResponse；部分 KMS 直接返回签名字符串，
+// 此时校验其为 hex（可带 0x 前缀，返回时去除）或 base64 编码后直接作为签名
+func SignatureFromTaskResponse(response string) (string, error) {
+	var signResp SignResponse
+	if err := json.Unmarshal([]byte(response), &signResp); err == nil && signResp.Signature != "" {
+		return signResp.Signature, nil
+	}
+
+	signature := strings.TrimSpace(response)
+	// 兼容 JSON 字符串形式的裸签名
+	var quoted string
+	if err := json.Unmarshal([]byte(signature), &quoted); err == nil {
+		signature = strings.TrimSpace(quoted)
+	}
+	if signature == "" {
+		return "", fmt.Errorf("task response contains no signature")
+	}
+
+	hexSignature := strings.TrimPrefix(strings.TrimPrefix(signature, "0x"), "0X")
+	if _, err := hex.DecodeString(hexSignature); err == nil {
+		return hexSignature, nil
+	}
+	if _, err := base64.StdEncoding.DecodeString(signature); err == nil {
+		return signature, nil
+	}
+	return "", fmt.Errorf("task response is neither a sign response nor a hex/base64 signature")
+}
+
 // UnmarshalErrorResponse 反序列化错误响应
 func UnmarshalErrorResponse(data []byte) (*ErrorResponse, error) {
 	var errResp ErrorResponse
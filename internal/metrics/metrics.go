@@ -0,0 +1,346 @@
+// Package metrics provides a minimal Prometheus-compatible metrics registry.
+//
+// Collectors render themselves in the Prometheus text exposition format so that
+// the /metrics endpoint can be scraped without pulling in the client library.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector is a metric that can write itself in the Prometheus text format.
+type Collector interface {
+	// Name returns the fully-qualified metric name.
+	Name() string
+	// Write writes the HELP, TYPE and sample lines of the metric.
+	Write(w io.Writer) error
+}
+
+// Registry holds a set of collectors exposed together.
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]Collector
+}
+
+// NewRegistry creates an empty registry.
+//
+// Returns:
+//   - *Registry: A new registry instance
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// DefaultRegistry is the registry served by the HTTP server's /metrics endpoint.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a collector to the registry.
+//
+// Parameters:
+//   - c: The collector to register
+//
+// Returns:
+//   - error: An error if a collector with the same name is already registered
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.collectors[c.Name()]; exists {
+		return fmt.Errorf("metric %s already registered", c.Name())
+	}
+	r.collectors[c.Name()] = c
+	return nil
+}
+
+// MustRegister adds a collector and panics if the name is already taken.
+//
+// Parameters:
+//   - c: The collector to register
+func (r *Registry) MustRegister(c Collector) {
+	if err := r.Register(c); err != nil {
+		panic(err)
+	}
+}
+
+// Write writes all collectors, sorted by name, in the Prometheus text format.
+//
+// Parameters:
+//   - w: Destination writer
+//
+// Returns:
+//   - error: The first write error encountered
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	collectors := make([]Collector, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mu.RUnlock()
+
+	for _, c := range collectors {
+		if err := c.Write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns an http.Handler serving the registry in the Prometheus text format.
+//
+// Returns:
+//   - http.Handler: Handler suitable for a /metrics endpoint
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var buf bytes.Buffer
+		if err := r.Write(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	})
+}
+
+// HistogramVec is a histogram partitioned by a single label.
+type HistogramVec struct {
+	name    string
+	help    string
+	label   string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64 // 每个桶的累计计数（非累加形式，输出时累加）
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec creates a histogram partitioned by label.
+//
+// Parameters:
+//   - name: Metric name
+//   - help: Help text
+//   - label: Label name used to partition observations
+//   - buckets: Upper bounds of the buckets, in ascending order
+//
+// Returns:
+//   - *HistogramVec: A new histogram
+func NewHistogramVec(name, help, label string, buckets []float64) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &HistogramVec{
+		name:    name,
+		help:    help,
+		label:   label,
+		buckets: sorted,
+		series:  make(map[string]*histogram),
+	}
+}
+
+// Name returns the metric name.
+func (h *HistogramVec) Name() string {
+	return h.name
+}
+
+// Observe records a value for the given label value.
+//
+// Parameters:
+//   - labelValue: Value of the partitioning label
+//   - v: The observed value
+func (h *HistogramVec) Observe(labelValue string, v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[labelValue]
+	if !ok {
+		s = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.series[labelValue] = s
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+// Count returns the number of observations for the given label value.
+func (h *HistogramVec) Count(labelValue string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if s, ok := h.series[labelValue]; ok {
+		return s.count
+	}
+	return 0
+}
+
+// Write writes the histogram in the Prometheus text format.
+func (h *HistogramVec) Write(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n", h.name, h.help)
+	fmt.Fprintf(&b, "# TYPE %s histogram\n", h.name)
+
+	values := make([]string, 0, len(h.series))
+	for v := range h.series {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+
+	for _, value := range values {
+		s := h.series[value]
+		labels := fmt.Sprintf("%s=%q", h.label, value)
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(&b, "%s_bucket{%s,le=%q} %d\n", h.name, labels, formatFloat(upper), cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{%s,le=\"+Inf\"} %d\n", h.name, labels, s.count)
+		fmt.Fprintf(&b, "%s_sum{%s} %s\n", h.name, labels, formatFloat(s.sum))
+		fmt.Fprintf(&b, "%s_count{%s} %d\n", h.name, labels, s.count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// CounterVec is a monotonically increasing counter partitioned by a single label.
+type CounterVec struct {
+	name  string
+	help  string
+	label string
+
+	mu     sync.Mutex
+	series map[string]uint64
+}
+
+// NewCounterVec creates a counter partitioned by label.
+//
+// Parameters:
+//   - name: Metric name
+//   - help: Help text
+//   - label: Label name used to partition increments
+//
+// Returns:
+//   - *CounterVec: A new counter
+func NewCounterVec(name, help, label string) *CounterVec {
+	return &CounterVec{name: name, help: help, label: label, series: make(map[string]uint64)}
+}
+
+// Name returns the metric name.
+func (c *CounterVec) Name() string {
+	return c.name
+}
+
+// Inc increments the counter for the given label value by one.
+func (c *CounterVec) Inc(labelValue string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.series[labelValue]++
+}
+
+// Value returns the counter value for the given label value.
+func (c *CounterVec) Value(labelValue string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.series[labelValue]
+}
+
+// Write writes the counter in the Prometheus text format.
+func (c *CounterVec) Write(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n", c.name, c.help)
+	fmt.Fprintf(&b, "# TYPE %s counter\n", c.name)
+
+	values := make([]string, 0, len(c.series))
+	for v := range c.series {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		fmt.Fprintf(&b, "%s{%s=%q} %d\n", c.name, c.label, value, c.series[value])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Gauge is a single value that can go up and down.
+type Gauge struct {
+	name string
+	help string
+
+	mu    sync.Mutex
+	value float64
+}
+
+// NewGauge creates a gauge.
+//
+// Parameters:
+//   - name: Metric name
+//   - help: Help text
+//
+// Returns:
+//   - *Gauge: A new gauge with value 0
+func NewGauge(name, help string) *Gauge {
+	return &Gauge{name: name, help: help}
+}
+
+// Name returns the metric name.
+func (g *Gauge) Name() string {
+	return g.name
+}
+
+// Set sets the gauge to v.
+func (g *Gauge) Set(v float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value = v
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+// Write writes the gauge in the Prometheus text format.
+func (g *Gauge) Write(w io.Writer) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", g.name, g.help, g.name, g.name, formatFloat(g.value))
+	return err
+}
+
+// formatFloat formats a sample value the way Prometheus expects.
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
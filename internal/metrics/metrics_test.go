@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHistogramVec_Write(t *testing.T) {
+	h := NewHistogramVec("test_wait_seconds", "Test histogram.", "outcome", []float64{5, 1})
+	h.Observe("approved", 0.5)
+	h.Observe("approved", 3)
+	h.Observe("approved", 10)
+	h.Observe("rejected", 1)
+
+	var buf bytes.Buffer
+	if err := h.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := []string{
+		"# HELP test_wait_seconds Test histogram.",
+		"# TYPE test_wait_seconds histogram",
+		`test_wait_seconds_bucket{outcome="approved",le="1"} 1`,
+		`test_wait_seconds_bucket{outcome="approved",le="5"} 2`,
+		`test_wait_seconds_bucket{outcome="approved",le="+Inf"} 3`,
+		`test_wait_seconds_sum{outcome="approved"} 13.5`,
+		`test_wait_seconds_count{outcome="approved"} 3`,
+		`test_wait_seconds_bucket{outcome="rejected",le="1"} 1`,
+		`test_wait_seconds_count{outcome="rejected"} 1`,
+	}
+	out := buf.String()
+	for _, line := range want {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing line %q in output:\n%s", line, out)
+		}
+	}
+
+	if got := h.Count("approved"); got != 3 {
+		t.Errorf("Count(approved) = %d, want 3", got)
+	}
+	if got := h.Count("missing"); got != 0 {
+		t.Errorf("Count(missing) = %d, want 0", got)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	h := NewHistogramVec("b_metric", "B.", "label", []float64{1})
+	h.Observe("x", 1)
+	if err := r.Register(h); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := r.Register(NewHistogramVec("b_metric", "dup", "label", nil)); err == nil {
+		t.Error("expected error for duplicate registration")
+	}
+	r.MustRegister(NewHistogramVec("a_metric", "A.", "label", []float64{1}))
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+	body := w.Body.String()
+	if strings.Index(body, "a_metric") > strings.Index(body, "b_metric") {
+		t.Errorf("expected metrics sorted by name, got:\n%s", body)
+	}
+}
+
+func TestGauge_Write(t *testing.T) {
+	g := NewGauge("test_gap", "Test gauge.")
+	g.Set(3)
+	g.Set(1.5)
+
+	var buf bytes.Buffer
+	if err := g.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := "# HELP test_gap Test gauge.\n# TYPE test_gap gauge\ntest_gap 1.5\n"
+	if buf.String() != want {
+		t.Errorf("Write() = %q, want %q", buf.String(), want)
+	}
+	if g.Value() != 1.5 {
+		t.Errorf("Value() = %v, want 1.5", g.Value())
+	}
+}
+
+func TestCounterVec_Write(t *testing.T) {
+	c := NewCounterVec("test_failures_total", "Test counter.", "operation")
+	c.Inc("sign")
+	c.Inc("sign")
+	c.Inc("get_task")
+
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := "# HELP test_failures_total Test counter.\n# TYPE test_failures_total counter\n" +
+		"test_failures_total{operation=\"get_task\"} 1\ntest_failures_total{operation=\"sign\"} 2\n"
+	if buf.String() != want {
+		t.Errorf("Write() = %q, want %q", buf.String(), want)
+	}
+	if c.Value("sign") != 2 || c.Value("missing") != 0 {
+		t.Errorf("Value() = %d/%d, want 2/0", c.Value("sign"), c.Value("missing"))
+	}
+}
@@ -0,0 +1,145 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+// maxVerdictSize bounds the policy endpoint response body.
+const maxVerdictSize = 1 << 20
+
+// HTTPHook asks an external policy engine over HTTP whether a transaction may be signed.
+//
+// The hook POSTs {"input": TransactionSummary} to the endpoint, which matches
+// the Open Policy Agent data API. The verdict is read from either
+//   - {"result": true|false}
+//   - {"result": {"allow": true|false, "reason": "..."}}
+//   - {"allow": true|false, "reason": "..."}
+//
+// Any transport error, non-200 status or undecidable verdict denies signing.
+type HTTPHook struct {
+	endpoint   string
+	httpClient *http.Client
+	logger     *logrus.Entry
+}
+
+// NewHTTPHook creates an HTTP policy hook.
+//
+// Parameters:
+//   - endpoint: The policy decision URL, e.g. http://localhost:8181/v1/data/web3signer/allow
+//   - timeout: Maximum time to wait for a verdict
+//   - logger: Logger for verdict tracking
+//
+// Returns:
+//   - *HTTPHook: A new HTTP policy hook
+func NewHTTPHook(endpoint string, timeout time.Duration, logger *logrus.Logger) *HTTPHook {
+	return &HTTPHook{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger.WithField("component", "policy_hook"),
+	}
+}
+
+// policyRequest is the request body sent to the policy engine.
+type policyRequest struct {
+	Input *TransactionSummary `json:"input"`
+}
+
+// verdict is the allow/deny decision returned by the policy engine.
+type verdict struct {
+	Allow  *bool  `json:"allow"`
+	Reason string `json:"reason"`
+}
+
+// Evaluate posts the transaction summary to the policy endpoint and honors its verdict.
+//
+// Parameters:
+//   - ctx: Context of the JSON-RPC request
+//   - tx: The transaction about to be signed
+//   - method: The JSON-RPC method
+//
+// Returns:
+//   - error: nil if allowed, *DeniedError if denied, or an error if no verdict could be obtained
+func (h *HTTPHook) Evaluate(ctx context.Context, tx *ethgo.Transaction, method string) error {
+	summary := NewTransactionSummary(tx, method)
+	body, err := json.Marshal(policyRequest{Input: summary})
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy input: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create policy request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("policy endpoint unreachable: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxVerdictSize))
+	if err != nil {
+		return fmt.Errorf("failed to read policy response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("policy endpoint returned status %d", resp.StatusCode)
+	}
+
+	v, err := parseVerdict(respBody)
+	if err != nil {
+		return err
+	}
+
+	fields := logrus.Fields{
+		"method": method,
+		"from":   summary.From,
+		"to":     summary.To,
+		"allow":  *v.Allow,
+	}
+	if !*v.Allow {
+		h.logger.WithFields(fields).WithField("reason", v.Reason).Warn("Transaction denied by policy")
+		return &DeniedError{Reason: v.Reason}
+	}
+	h.logger.WithFields(fields).Debug("Transaction allowed by policy")
+	return nil
+}
+
+// parseVerdict decodes the policy engine response.
+func parseVerdict(body []byte) (*verdict, error) {
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		verdict
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid policy response: %w", err)
+	}
+
+	if len(envelope.Result) > 0 && string(envelope.Result) != "null" {
+		var allow bool
+		if err := json.Unmarshal(envelope.Result, &allow); err == nil {
+			return &verdict{Allow: &allow}, nil
+		}
+		var v verdict
+		if err := json.Unmarshal(envelope.Result, &v); err != nil {
+			return nil, fmt.Errorf("invalid policy result: %w", err)
+		}
+		envelope.verdict = v
+	}
+
+	if envelope.Allow == nil {
+		return nil, fmt.Errorf("policy response has no allow decision")
+	}
+	return &envelope.verdict, nil
+}
@@ -0,0 +1,91 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+func TestHTTPHook_Evaluate(t *testing.T) {
+	to := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+	tx := &ethgo.Transaction{
+		Type:     ethgo.TransactionLegacy,
+		From:     ethgo.HexToAddress("0x1234567890123456789012345678901234567890"),
+		To:       &to,
+		Value:    big.NewInt(1000),
+		Gas:      21000,
+		GasPrice: 20,
+		Input:    []byte{0xab},
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantErr    bool
+		wantDenied bool
+		wantReason string
+	}{
+		{name: "opa boolean allow", status: http.StatusOK, body: `{"result": true}`},
+		{name: "opa boolean deny", status: http.StatusOK, body: `{"result": false}`, wantErr: true, wantDenied: true},
+		{name: "opa object deny", status: http.StatusOK, body: `{"result": {"allow": false, "reason": "value too high"}}`, wantErr: true, wantDenied: true, wantReason: "value too high"},
+		{name: "top-level allow", status: http.StatusOK, body: `{"allow": true}`},
+		{name: "undefined decision", status: http.StatusOK, body: `{}`, wantErr: true},
+		{name: "error status", status: http.StatusInternalServerError, body: `{"result": true}`, wantErr: true},
+		{name: "invalid json", status: http.StatusOK, body: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input TransactionSummary
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req policyRequest
+				req.Input = &input
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("Failed to decode policy request: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			hook := NewHTTPHook(server.URL, time.Second, logrus.New())
+			err := hook.Evaluate(context.Background(), tx, "eth_sendTransaction")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var denied *DeniedError
+			if errors.As(err, &denied) != tt.wantDenied {
+				t.Errorf("Evaluate() error = %v, want denied %v", err, tt.wantDenied)
+			}
+			if tt.wantDenied && denied.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", denied.Reason, tt.wantReason)
+			}
+
+			if input.Method != "eth_sendTransaction" || input.Value != "1000" || input.To != to.String() ||
+				input.GasPrice != "20" || input.Data != "0xab" || input.Type != "legacy" {
+				t.Errorf("Unexpected policy input: %+v", input)
+			}
+		})
+	}
+}
+
+func TestHTTPHook_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	hook := NewHTTPHook(url, time.Second, logrus.New())
+	if err := hook.Evaluate(context.Background(), &ethgo.Transaction{}, "eth_signTransaction"); err == nil {
+		t.Fatal("Expected unreachable policy endpoint to deny signing")
+	}
+}
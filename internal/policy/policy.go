@@ -0,0 +1,122 @@
+// Package policy provides pre-sign hooks that let external policy engines
+// authorize transactions before they are signed.
+package policy
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+)
+
+// PreSignHook evaluates a transaction before it is signed.
+//
+// Implementations return nil to allow signing. Any non-nil error aborts the
+// request with a policy-denied JSON-RPC error.
+type PreSignHook interface {
+	// Evaluate authorizes tx for the given JSON-RPC method.
+	//
+	// Parameters:
+	//   - ctx: Context of the JSON-RPC request
+	//   - tx: The transaction about to be signed
+	//   - method: The JSON-RPC method, e.g. "eth_sendTransaction"
+	//
+	// Returns:
+	//   - error: nil to allow signing, otherwise the reason for denial
+	Evaluate(ctx context.Context, tx *ethgo.Transaction, method string) error
+}
+
+// NoopHook allows every transaction.
+type NoopHook struct{}
+
+// Evaluate always allows signing.
+func (NoopHook) Evaluate(context.Context, *ethgo.Transaction, string) error {
+	return nil
+}
+
+// DeniedError is returned when the policy engine denies a transaction.
+type DeniedError struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *DeniedError) Error() string {
+	if e.Reason == "" {
+		return "transaction denied by policy"
+	}
+	return fmt.Sprintf("transaction denied by policy: %s", e.Reason)
+}
+
+// TransactionSummary is the policy input describing a transaction.
+//
+// Amounts are decimal strings in wei so that policy rules can compare them
+// without losing precision.
+type TransactionSummary struct {
+	Method               string `json:"method"`
+	Type                 string `json:"type"`
+	ChainID              string `json:"chainId,omitempty"`
+	From                 string `json:"from"`
+	To                   string `json:"to,omitempty"`
+	Value                string `json:"value"`
+	Nonce                uint64 `json:"nonce"`
+	Gas                  uint64 `json:"gas"`
+	GasPrice             string `json:"gasPrice,omitempty"`
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+	Data                 string `json:"data,omitempty"`
+}
+
+// NewTransactionSummary builds the policy input for tx.
+//
+// Parameters:
+//   - tx: The transaction about to be signed
+//   - method: The JSON-RPC method
+//
+// Returns:
+//   - *TransactionSummary: The policy input
+func NewTransactionSummary(tx *ethgo.Transaction, method string) *TransactionSummary {
+	summary := &TransactionSummary{
+		Method:               method,
+		Type:                 transactionTypeName(tx.Type),
+		ChainID:              bigString(tx.ChainID),
+		From:                 tx.From.String(),
+		Value:                "0",
+		Nonce:                tx.Nonce,
+		Gas:                  tx.Gas,
+		MaxFeePerGas:         bigString(tx.MaxFeePerGas),
+		MaxPriorityFeePerGas: bigString(tx.MaxPriorityFeePerGas),
+	}
+	if tx.To != nil {
+		summary.To = tx.To.String()
+	}
+	if tx.Value != nil {
+		summary.Value = tx.Value.String()
+	}
+	if tx.GasPrice != 0 {
+		summary.GasPrice = new(big.Int).SetUint64(tx.GasPrice).String()
+	}
+	if len(tx.Input) > 0 {
+		summary.Data = "0x" + hex.EncodeToString(tx.Input)
+	}
+	return summary
+}
+
+func transactionTypeName(t ethgo.TransactionType) string {
+	switch t {
+	case ethgo.TransactionAccessList:
+		return "accessList"
+	case ethgo.TransactionDynamicFee:
+		return "dynamicFee"
+	default:
+		return "legacy"
+	}
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
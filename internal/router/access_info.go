@@ -0,0 +1,52 @@
+package router
+
+import (
+	"context"
+	"sync"
+)
+
+// AccessInfo 记录一次 HTTP 请求中路由的 JSON-RPC 方法与错误响应数，供访问日志使用
+type AccessInfo struct {
+	mu        sync.Mutex
+	methods   []string
+	rpcErrors int
+}
+
+type accessInfoContextKey struct{}
+
+// WithAccessInfo 返回携带 AccessInfo 的 context，路由器处理请求时向其中写入方法与错误数
+func WithAccessInfo(ctx context.Context) (context.Context, *AccessInfo) {
+	info := &AccessInfo{}
+	return context.WithValue(ctx, accessInfoContextKey{}, info), info
+}
+
+// accessInfoFromContext 从 context 获取 AccessInfo，未设置时返回 nil
+func accessInfoFromContext(ctx context.Context) *AccessInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(accessInfoContextKey{}).(*AccessInfo)
+	return info
+}
+
+// record 记录路由的方法与错误响应数
+func (a *AccessInfo) record(methods []string, rpcErrors int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.methods = append(a.methods, methods...)
+	a.rpcErrors += rpcErrors
+}
+
+// Methods 返回请求中的 JSON-RPC 方法，批量请求按请求顺序返回
+func (a *AccessInfo) Methods() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.methods...)
+}
+
+// RPCErrors 返回带 error 字段的 JSON-RPC 响应数
+func (a *AccessInfo) RPCErrors() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rpcErrors
+}
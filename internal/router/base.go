@@ -2,12 +2,56 @@ package router
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	apperrors "github.com/mowind/web3signer-go/internal/errors"
 	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/signer"
 	"github.com/sirupsen/logrus"
 )
 
+// 错误响应 data 中的稳定错误标识
+//
+// 错误消息文本可能调整，客户端应依据 errorId 判断错误类型
+const (
+	ErrorIDInvalidParams       = "invalid_params"        // 参数无法解析或字段无效
+	ErrorIDInvalidAddress      = "invalid_address"       // 地址格式无效
+	ErrorIDAddressMismatch     = "address_mismatch"      // 地址不是签名地址
+	ErrorIDInvalidTypedData    = "invalid_typed_data"    // EIP-712 数据或 domain 无效
+	ErrorIDInvalidSummary      = "invalid_summary"       // 审批摘要无效
+	ErrorIDInsufficientFunds   = "insufficient_funds"    // 余额不足以支付交易最大花费
+	ErrorIDMethodNotSupported  = "method_not_supported"  // 处理器不支持该方法
+	ErrorIDSignFailed          = "sign_failed"           // 签名后端签名失败
+	ErrorIDApprovalPending     = "approval_pending"      // 异步审批模式下等待审批
+	ErrorIDEncodeFailed        = "encode_failed"         // 结果编码失败
+	ErrorIDNonceUnavailable    = "nonce_unavailable"     // 无法从下游获取 nonce
+	ErrorIDNonceGapExceeded    = "nonce_gap_exceeded"    // 交易 nonce 领先已确认 nonce 过多
+	ErrorIDGasPriceUnavailable = "gas_price_unavailable" // 无法从下游获取 gas 价格
+	ErrorIDGasEstimateFailed   = "gas_estimate_failed"   // 下游 gas 估算失败
+	ErrorIDBalanceUnavailable  = "balance_unavailable"   // 无法从下游获取余额
+	ErrorIDForwardFailed       = "forward_failed"        // 转发到下游失败
+	ErrorIDDuplicateRequest    = "duplicate_request"     // 相同幂等键的请求正在处理
+	ErrorIDPolicyDenied        = "policy_denied"         // 预签名策略拒绝
+	ErrorIDKeyNotAllowed       = "key_not_allowed"       // 签名密钥不在 JSON-RPC 允许列表中
+	ErrorIDChainIDUnavailable  = "chain_id_unavailable"  // 无法解析签名使用的链 ID
+	ErrorIDTaskQueryFailed     = "task_query_failed"     // 查询 KMS 审批任务失败
+	ErrorIDTaskCancelFailed    = "task_cancel_failed"    // 取消 KMS 审批任务失败
+)
+
+// ErrorData 错误响应 data 字段的结构化约定
+//
+// 客户端可依据 ErrorID 与 Retryable 程序化地处理错误，Reason 与 Field 用于定位问题
+type ErrorData struct {
+	ErrorID   string `json:"errorId"`          // 稳定的错误标识（ErrorID* 常量）
+	Method    string `json:"method"`           // 出错请求的 JSON-RPC 方法
+	Reason    string `json:"reason,omitempty"` // 具体原因
+	Field     string `json:"field,omitempty"`  // 导致错误的参数字段
+	Retryable bool   `json:"retryable"`        // 原样重试是否可能成功
+	TaskID    string `json:"taskId,omitempty"` // 待审批的 KMS 任务 ID
+}
+
 // BaseHandler 提供处理器的基础功能
 type BaseHandler struct {
 	method string
@@ -86,6 +130,69 @@ func (h *BaseHandler) CreateInvalidParamsResponse(id interface{}, message string
 	return h.CreateErrorResponse(id, jsonrpc.CodeInvalidParams, message, nil)
 }
 
+// CreateStructuredErrorResponse 创建 data 为 ErrorData 的错误响应，Method 取自请求
+func (h *BaseHandler) CreateStructuredErrorResponse(request *jsonrpc.Request, code int, message string, data ErrorData) *jsonrpc.Response {
+	data.Method = request.Method
+	return h.CreateErrorResponse(request.ID, code, message, data)
+}
+
+// invalidParamsError 创建无效参数错误响应，err 为原因，并从中提取出错字段
+func (h *BaseHandler) invalidParamsError(request *jsonrpc.Request, errorID, message string, err error) *jsonrpc.Response {
+	data := ErrorData{ErrorID: errorID}
+	if err != nil {
+		data.Reason = err.Error()
+		data.Field = errorField(err)
+		message = fmt.Sprintf("%s: %v", message, err)
+	}
+	return h.CreateStructuredErrorResponse(request, jsonrpc.CodeInvalidParams, message, data)
+}
+
+// internalError 创建内部错误响应；retryable 表示错误来自下游等暂时性故障，原样重试可能成功
+func (h *BaseHandler) internalError(request *jsonrpc.Request, errorID, message string, err error, retryable bool) *jsonrpc.Response {
+	return h.CreateStructuredErrorResponse(request, jsonrpc.CodeInternalError, message, ErrorData{
+		ErrorID:   errorID,
+		Reason:    err.Error(),
+		Retryable: retryable,
+	})
+}
+
+// paramError 标记导致参数错误的字段，错误消息保持不变
+type paramError struct {
+	field string
+	err   error
+}
+
+func (e *paramError) Error() string { return e.err.Error() }
+
+func (e *paramError) Unwrap() error { return e.err }
+
+// withField 为参数错误标记出错字段
+func withField(field string, err error) error {
+	return &paramError{field: field, err: err}
+}
+
+// errorField 返回导致参数错误的字段，无法确定时返回空字符串
+func errorField(err error) string {
+	var pe *paramError
+	if errors.As(err, &pe) {
+		return pe.field
+	}
+	var fe *signer.FieldError
+	if errors.As(err, &fe) {
+		return fe.Field
+	}
+	if errors.Is(err, errMissingFrom) || errors.Is(err, errFromMismatch) {
+		return "from"
+	}
+	return ""
+}
+
+// isRetryableError 判断签名后端错误在原样重试时是否可能成功（如 KMS 暂时不可用）
+func isRetryableError(err error) bool {
+	var appErr *apperrors.AppError
+	return errors.As(err, &appErr) && apperrors.IsRetryable(appErr)
+}
+
 // LogRequest 记录请求日志
 func (h *BaseHandler) LogRequest(request *jsonrpc.Request) {
 	fields := logrus.Fields{
@@ -125,3 +232,22 @@ func (h *BaseHandler) LogResponse(request *jsonrpc.Request, response *jsonrpc.Re
 		h.logger.WithFields(fields).Info("Request completed")
 	}
 }
+
+// signErrorResponse 创建签名失败响应
+//
+// KMS 异步审批模式下签名请求不会等待审批，此时返回待审批错误，data 中携带任务 ID，
+// 客户端可通过 web3signer_getTaskResult 查询审批进度
+func (h *BaseHandler) signErrorResponse(request *jsonrpc.Request, message string, err error) *jsonrpc.Response {
+	var pending *kms.ApprovalPendingError
+	if errors.As(err, &pending) {
+		h.logger.WithField("task_id", pending.TaskID).Info("Sign request pending approval")
+		return h.CreateStructuredErrorResponse(request, jsonrpc.CodeApprovalPending, "Approval pending", ErrorData{
+			ErrorID:   ErrorIDApprovalPending,
+			Retryable: true,
+			TaskID:    pending.TaskID,
+		})
+	}
+
+	h.logger.WithError(err).Error(message)
+	return h.internalError(request, ErrorIDSignFailed, message, err, isRetryableError(err))
+}
@@ -0,0 +1,137 @@
+package router
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+)
+
+// cosmosPubKeyType Amino JSON 中 secp256k1 公钥的类型名
+const cosmosPubKeyType = "tendermint/PubKeySecp256k1"
+
+// CosmosHandler 使用以太坊签名密钥签名 Cosmos SDK 交易
+//
+// Cosmos SDK 链与以太坊使用相同的 secp256k1 曲线，但签名的是 sign bytes 的 SHA-256 哈希，
+// 且签名为不含恢复 ID 的 64 字节 r || s
+type CosmosHandler struct {
+	*BaseHandler
+	signer        signer.Client
+	allowedKeyIDs keyAllowlist // 允许签名的密钥 ID，nil 表示不限制
+}
+
+// NewCosmosHandler 创建 Cosmos 签名处理器
+func NewCosmosHandler(s signer.Client, logger *logrus.Logger) *CosmosHandler {
+	return &CosmosHandler{
+		BaseHandler: NewBaseHandler("cosmos", logger),
+		signer:      s,
+	}
+}
+
+// Method 返回处理器支持的方法名
+func (h *CosmosHandler) Method() string {
+	return "cosmos_handler"
+}
+
+// cosmosPubKey Amino JSON 编码的公钥
+type cosmosPubKey struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// cosmosStdSignature 与 Cosmos SDK StdSignature 一致的签名结果
+type cosmosStdSignature struct {
+	PubKey    cosmosPubKey `json:"pub_key"`
+	Signature string       `json:"signature"`
+}
+
+// cosmosSignResponse cosmos_signAmino / cosmos_signDirect 的返回结果
+type cosmosSignResponse struct {
+	Signed    json.RawMessage    `json:"signed"`
+	Signature cosmosStdSignature `json:"signature"`
+}
+
+// cosmosDirectSignDoc cosmos_signDirect 的参数，字节字段为 base64 编码
+type cosmosDirectSignDoc struct {
+	BodyBytes     []byte      `json:"bodyBytes"`
+	AuthInfoBytes []byte      `json:"authInfoBytes"`
+	ChainID       string      `json:"chainId"`
+	AccountNumber json.Number `json:"accountNumber"`
+}
+
+// Handle 处理 JSON-RPC 请求
+func (h *CosmosHandler) Handle(_ context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	h.LogRequest(request)
+
+	// 整个请求使用同一默认密钥签名并报告其公钥
+	s := pinDefaultKey(h.signer)
+	if resp := h.allowedKeyIDs.checkDefaultKey(h.BaseHandler, s, request); resp != nil {
+		return resp, nil
+	}
+
+	var params []json.RawMessage
+	if err := json.Unmarshal(request.Params, &params); err != nil || len(params) != 1 {
+		return h.CreateInvalidParamsResponse(request.ID, "Invalid parameters: expected [signDoc]"), nil
+	}
+
+	var signBytes []byte
+	switch request.Method {
+	case "cosmos_signAmino":
+		b, err := signer.CosmosAminoSignBytes(params[0])
+		if err != nil {
+			return h.CreateInvalidParamsResponse(request.ID, fmt.Sprintf("Invalid parameters: %v", err)), nil
+		}
+		signBytes = b
+	case "cosmos_signDirect":
+		var doc cosmosDirectSignDoc
+		if err := json.Unmarshal(params[0], &doc); err != nil {
+			return h.CreateInvalidParamsResponse(request.ID, fmt.Sprintf("Invalid parameters: %v", err)), nil
+		}
+		if len(doc.BodyBytes) == 0 || len(doc.AuthInfoBytes) == 0 || doc.ChainID == "" {
+			return h.CreateInvalidParamsResponse(request.ID, "Invalid parameters: bodyBytes, authInfoBytes and chainId are required"), nil
+		}
+		var accountNumber uint64
+		if doc.AccountNumber != "" {
+			n, err := strconv.ParseUint(doc.AccountNumber.String(), 10, 64)
+			if err != nil {
+				return h.CreateInvalidParamsResponse(request.ID, fmt.Sprintf("Invalid parameters: invalid accountNumber: %s", doc.AccountNumber)), nil
+			}
+			accountNumber = n
+		}
+		signBytes = signer.CosmosDirectSignBytes(doc.BodyBytes, doc.AuthInfoBytes, doc.ChainID, accountNumber)
+	default:
+		return h.CreateErrorResponse(request.ID, jsonrpc.CodeMethodNotFound,
+			"Method not supported by cosmos handler", nil), nil
+	}
+
+	signature, err := signer.SignCosmos(s, signBytes)
+	if err != nil {
+		return h.signErrorResponse(request, "Failed to sign Cosmos document", err), nil
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"method":  request.Method,
+		"address": s.Address().String(),
+	}).Info("Cosmos document signed successfully")
+
+	return h.CreateSuccessResponse(request.ID, cosmosSignResponse{
+		Signed: params[0],
+		Signature: cosmosStdSignature{
+			PubKey: cosmosPubKey{
+				Type:  cosmosPubKeyType,
+				Value: base64.StdEncoding.EncodeToString(signature.PubKey),
+			},
+			Signature: base64.StdEncoding.EncodeToString(signature.Signature),
+		},
+	})
+}
+
+// IsCosmosSignMethod 检查是否为 Cosmos 签名方法
+func IsCosmosSignMethod(method string) bool {
+	return method == "cosmos_signAmino" || method == "cosmos_signDirect"
+}
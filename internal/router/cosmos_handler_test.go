@@ -0,0 +1,141 @@
+package router
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+func TestCosmosHandler(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	localSigner := signer.NewLocalKeystoreSigner(key, big.NewInt(1))
+
+	downstream := newMockDownstreamClient()
+	defer func() { _ = downstream.Close() }()
+	router := NewRouterFactory(logger).WithCosmosSigning(true).CreateRouter(localSigner, downstream)
+
+	tests := []struct {
+		name     string
+		method   string
+		params   string
+		wantCode int
+	}{
+		{name: "amino", method: "cosmos_signAmino", params: `[{"chain_id":"cosmoshub-4","account_number":"7","sequence":"0","fee":{"gas":"200000","amount":[]},"msgs":[],"memo":""}]`},
+		{name: "direct", method: "cosmos_signDirect", params: `[{"bodyBytes":"CgE=","authInfoBytes":"EgE=","chainId":"cosmoshub-4","accountNumber":"7"}]`},
+		{name: "direct numeric account", method: "cosmos_signDirect", params: `[{"bodyBytes":"CgE=","authInfoBytes":"EgE=","chainId":"cosmoshub-4","accountNumber":7}]`},
+		{name: "direct missing chain id", method: "cosmos_signDirect", params: `[{"bodyBytes":"CgE=","authInfoBytes":"EgE="}]`, wantCode: jsonrpc.CodeInvalidParams},
+		{name: "direct invalid account", method: "cosmos_signDirect", params: `[{"bodyBytes":"CgE=","authInfoBytes":"EgE=","chainId":"c","accountNumber":"-1"}]`, wantCode: jsonrpc.CodeInvalidParams},
+		{name: "amino not an object", method: "cosmos_signAmino", params: `["doc"]`, wantCode: jsonrpc.CodeInvalidParams},
+		{name: "missing params", method: "cosmos_signAmino", params: `[]`, wantCode: jsonrpc.CodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := router.Route(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0",
+				Method:  tt.method,
+				ID:      1,
+				Params:  json.RawMessage(tt.params),
+			})
+			if tt.wantCode != 0 {
+				if resp.Error == nil || resp.Error.Code != tt.wantCode {
+					t.Errorf("expected error code %d, got %+v", tt.wantCode, resp.Error)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %+v", resp.Error)
+			}
+
+			var result cosmosSignResponse
+			if err := json.Unmarshal(resp.Result, &result); err != nil {
+				t.Fatalf("failed to unmarshal result: %v", err)
+			}
+			sig, err := base64.StdEncoding.DecodeString(result.Signature.Signature)
+			if err != nil || len(sig) != 64 {
+				t.Errorf("signature should be 64 bytes of base64, got %q", result.Signature.Signature)
+			}
+			pub, err := base64.StdEncoding.DecodeString(result.Signature.PubKey.Value)
+			if err != nil || len(pub) != 33 || result.Signature.PubKey.Type != cosmosPubKeyType {
+				t.Errorf("unexpected pub_key: %+v", result.Signature.PubKey)
+			}
+			if len(result.Signed) == 0 {
+				t.Error("expected signed document in result")
+			}
+		})
+	}
+}
+
+func TestCosmosHandler_DisabledByDefault(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	downstream := newMockDownstreamClient()
+	defer func() { _ = downstream.Close() }()
+	router := NewRouterFactory(logger).CreateRouter(createSimpleTestHandler(t).signer, downstream)
+
+	if router.HasHandler("cosmos_signAmino") || router.HasHandler("cosmos_signDirect") {
+		t.Error("Cosmos signing methods must not be registered unless enabled")
+	}
+}
+
+func TestCosmosHandler_AllowedKeyIDs(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	multiKeySigner := signer.NewMultiKeySigner("internal-key", big.NewInt(1), logger)
+	if err := multiKeySigner.AddClient("internal-key", signer.NewLocalKeystoreSigner(key, big.NewInt(1))); err != nil {
+		t.Fatalf("AddClient() error: %v", err)
+	}
+
+	downstream := newMockDownstreamClient()
+	defer func() { _ = downstream.Close() }()
+
+	params := map[string]string{
+		"cosmos_signAmino":  `[{"chain_id":"cosmoshub-4","account_number":"7","sequence":"0","fee":{"gas":"200000","amount":[]},"msgs":[],"memo":""}]`,
+		"cosmos_signDirect": `[{"bodyBytes":"CgE=","authInfoBytes":"EgE=","chainId":"cosmoshub-4","accountNumber":"7"}]`,
+	}
+	tests := []struct {
+		name    string
+		allowed []string
+		wantErr bool
+	}{
+		{name: "default key not allowed", allowed: []string{"public-key"}, wantErr: true},
+		{name: "default key allowed", allowed: []string{"internal-key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouterFactory(logger).WithCosmosSigning(true).WithAllowedKeyIDs(tt.allowed).CreateRouter(multiKeySigner, downstream)
+			for method, p := range params {
+				resp := router.Route(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: method, ID: 1, Params: json.RawMessage(p)})
+				if !tt.wantErr {
+					if resp.Error != nil {
+						t.Errorf("%s: unexpected error: %+v", method, resp.Error)
+					}
+					continue
+				}
+				if resp.Error == nil || resp.Error.Code != jsonrpc.CodeKeyNotAllowed {
+					t.Errorf("%s: expected key not allowed error, got %+v", method, resp.Error)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/downstream"
+	internaljsonrpc "github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/sirupsen/logrus"
+)
+
+// downstreamRPCFailureThreshold 连续多少次传输错误后重新创建下游 RPC 客户端
+const downstreamRPCFailureThreshold = 3
+
+// downstreamRPCState 查询下游（nonce、gas 等）的 RPC 客户端及其故障计数
+//
+// 以指针形式由 SignHandler 持有，按请求复制的处理器视图共享同一客户端
+type downstreamRPCState struct {
+	mu       sync.RWMutex
+	client   *downstream.RPCClient
+	failures atomic.Int32 // 连续传输错误次数
+}
+
+// newDownstreamRPCState 创建经由 client 发送查询的下游 RPC 状态
+func newDownstreamRPCState(client *downstream.RPCClient) *downstreamRPCState {
+	return &downstreamRPCState{client: client}
+}
+
+// rpc 返回当前的下游 RPC 客户端
+func (h *SignHandler) rpc() *downstream.RPCClient {
+	h.downstreamRPC.mu.RLock()
+	defer h.downstreamRPC.mu.RUnlock()
+	return h.downstreamRPC.client
+}
+
+// ReconnectDownstream 丢弃与下游节点的空闲连接，并重新创建查询下游（nonce、gas 等）的 RPC 客户端
+//
+// 查询经由下游客户端发送，下游地址、代理、TLS 与请求头均取自下游客户端的配置。
+// 正在进行的调用不受影响，之后的调用重新建立连接
+func (h *SignHandler) ReconnectDownstream() {
+	client := downstream.NewRPCClient(h.client)
+	client.CloseIdleConnections()
+
+	h.downstreamRPC.mu.Lock()
+	h.downstreamRPC.client = client
+	h.downstreamRPC.mu.Unlock()
+	h.downstreamRPC.failures.Store(0)
+
+	h.logger.WithField("endpoint", h.client.GetEndpoint()).Warn("Recreated downstream RPC client")
+}
+
+// isTransportError 判断下游调用错误是否为连接层故障；下游返回的 JSON-RPC 错误说明连接正常
+func isTransportError(err error) bool {
+	var rpcErr *internaljsonrpc.Error
+	return err != nil && !errors.As(err, &rpcErr)
+}
+
+// observeRPCResult 记录下游调用结果，连续传输错误达到阈值时重新创建客户端
+func (h *SignHandler) observeRPCResult(err error) {
+	if !isTransportError(err) {
+		h.downstreamRPC.failures.Store(0)
+		return
+	}
+	// 仅达到阈值的那次调用负责重建，避免并发请求重复重建
+	if h.downstreamRPC.failures.Add(1) != downstreamRPCFailureThreshold {
+		return
+	}
+
+	h.logger.WithError(err).Warn("Downstream RPC client failing persistently, reconnecting")
+	h.ReconnectDownstream()
+}
+
+// WithDownstreamHealthCheck 设置定期检查下游 RPC 客户端的间隔与使用的方法，interval 为 0 表示不检查
+func (h *SignHandler) WithDownstreamHealthCheck(interval time.Duration, method string) *SignHandler {
+	h.rpcHealthInterval = interval
+	h.rpcHealthMethod = method
+	return h
+}
+
+// runDownstreamHealthCheck 按间隔检查下游 RPC 客户端，连接故障时立即重建，直到 ctx 取消
+func (h *SignHandler) runDownstreamHealthCheck(ctx context.Context) {
+	ticker := time.NewTicker(h.rpcHealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.checkDownstreamRPC()
+		}
+	}
+}
+
+// checkDownstreamRPC 调用健康检查方法验证下游 RPC 客户端，传输错误时重建客户端
+func (h *SignHandler) checkDownstreamRPC() {
+	var out json.RawMessage
+	err := h.rpc().Call(h.rpcHealthMethod, &out)
+	if !isTransportError(err) {
+		h.downstreamRPC.failures.Store(0)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"method": h.rpcHealthMethod,
+		"error":  err.Error(),
+	}).Warn("Downstream RPC health check failed, reconnecting")
+	h.ReconnectDownstream()
+}
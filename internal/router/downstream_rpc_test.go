@@ -0,0 +1,89 @@
+package router
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+// newNonceServer 返回 eth_getTransactionCount 为 0x7 的下游；rpcError 为 true 时返回 JSON-RPC 错误
+func newNonceServer(rpcError bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if rpcError {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":-32005,"message":"rate limited"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":"0x7"}`))
+	}))
+}
+
+func TestSignHandler_DownstreamRPCRecovery(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	healthy := newNonceServer(false)
+	defer healthy.Close()
+	limited := newNonceServer(true)
+	defer limited.Close()
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+
+	mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", ethgo.HexToAddress("0x1234567890123456789012345678901234567890"), big.NewInt(1))
+
+	// 下游返回 JSON-RPC 错误说明连接正常，不重建客户端
+	handler := NewSignHandler(mpcSigner, &testDownstreamClient{mockServer: limited}, logger)
+	client := handler.rpc()
+	for i := 0; i < downstreamRPCFailureThreshold+1; i++ {
+		if _, err := handler.fetchNonce(&signer.JSONRPCTransaction{}); err == nil {
+			t.Fatal("Expected JSON-RPC error")
+		}
+	}
+	if handler.rpc() != client {
+		t.Error("Expected client to be kept after JSON-RPC errors")
+	}
+
+	// 连续传输错误达到阈值时重建客户端
+	handler = NewSignHandler(mpcSigner, &testDownstreamClient{mockServer: dead}, logger)
+	client = handler.rpc()
+	for i := 0; i < downstreamRPCFailureThreshold-1; i++ {
+		_, _ = handler.fetchNonce(&signer.JSONRPCTransaction{})
+	}
+	if handler.rpc() != client {
+		t.Fatal("Expected client to be kept below the failure threshold")
+	}
+	_, _ = handler.fetchNonce(&signer.JSONRPCTransaction{})
+	if handler.rpc() == client {
+		t.Fatal("Expected client to be recreated after persistent transport errors")
+	}
+
+	// 健康检查失败时立即重建
+	handler.WithDownstreamHealthCheck(0, "web3_clientVersion")
+	client = handler.rpc()
+	handler.checkDownstreamRPC()
+	if handler.rpc() == client {
+		t.Error("Expected health check failure to recreate client")
+	}
+
+	handler = NewSignHandler(mpcSigner, &testDownstreamClient{mockServer: healthy}, logger)
+	handler.WithDownstreamHealthCheck(0, "web3_clientVersion")
+	nonce, err := handler.fetchNonce(&signer.JSONRPCTransaction{})
+	if err != nil || nonce != 7 {
+		t.Errorf("fetchNonce() = %d, %v, want 7", nonce, err)
+	}
+	client = handler.rpc()
+	handler.checkDownstreamRPC()
+	if handler.rpc() != client {
+		t.Error("Expected healthy client to be kept")
+	}
+}
@@ -2,17 +2,67 @@ package router
 
 import (
 	"context"
+	"time"
 
+	"github.com/mowind/web3signer-go/internal/config"
 	"github.com/mowind/web3signer-go/internal/downstream"
 	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/policy"
 	"github.com/mowind/web3signer-go/internal/signer"
 	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
 )
 
 // RouterFactory 路由器工厂，简化路由器的创建和配置
 type RouterFactory struct {
 	logger         *logrus.Entry
 	maxRequestSize int64
+	txConfig       config.TransactionConfig
+
+	compressionThreshold int64
+	streamBatchThreshold int
+
+	allowMissingJSONRPCVersion bool
+
+	requestTimeout     time.Duration
+	signRequestTimeout time.Duration
+	methodTimeouts     map[string]time.Duration
+
+	taskManager kms.TaskManager
+	taskCancel  bool
+
+	batchWorkers    int
+	batchQueueSize  int
+	maxBatchWorkers int
+
+	preSignHook      policy.PreSignHook
+	maxCalldataBytes int
+
+	maxAccessListEntries     int
+	maxAccessListStorageKeys int
+
+	responseCacheTTL  time.Duration
+	responseCacheSize int
+
+	feeHistoryInterval    time.Duration
+	feeHistoryMaxAge      time.Duration
+	feeHistoryBlocks      int
+	feeHistoryPercentiles []float64
+
+	cosmosEnabled bool
+
+	keyRotationEnabled bool
+
+	signBatchMaxItems    int
+	signBatchConcurrency int
+
+	allowedKeyIDs []string
+
+	rpcHealthCheckInterval time.Duration
+	rpcHealthCheckMethod   string
+
+	slowRequestThreshold time.Duration
 }
 
 // NewRouterFactory 创建路由器工厂
@@ -28,14 +78,184 @@ func NewRouterFactoryWithMaxSize(logger *logrus.Logger, maxRequestSize int64) *R
 	}
 }
 
+// WithTransactionConfig 设置签名处理器填充交易字段时使用的配置
+func (f *RouterFactory) WithTransactionConfig(cfg config.TransactionConfig) *RouterFactory {
+	f.txConfig = cfg
+	return f
+}
+
+// WithCompressionThreshold 设置响应 gzip 压缩阈值（字节），0 表示不压缩
+func (f *RouterFactory) WithCompressionThreshold(threshold int64) *RouterFactory {
+	f.compressionThreshold = threshold
+	return f
+}
+
+// WithStreamBatchThreshold 设置批量响应流式写出的最小响应条数，0 表示不流式写出
+func (f *RouterFactory) WithStreamBatchThreshold(threshold int) *RouterFactory {
+	f.streamBatchThreshold = threshold
+	return f
+}
+
+// WithAllowMissingJSONRPCVersion 设置是否接受缺少 jsonrpc 字段的请求（视为 2.0）
+func (f *RouterFactory) WithAllowMissingJSONRPCVersion(allow bool) *RouterFactory {
+	f.allowMissingJSONRPCVersion = allow
+	return f
+}
+
+// WithRequestTimeouts 设置请求处理超时：request 用于非签名方法，sign 用于签名方法，0 表示不限制
+func (f *RouterFactory) WithRequestTimeouts(request, sign time.Duration) *RouterFactory {
+	f.requestTimeout = request
+	f.signRequestTimeout = sign
+	return f
+}
+
+// WithMethodTimeouts 设置按方法覆盖的请求处理超时，0 表示该方法不限制
+func (f *RouterFactory) WithMethodTimeouts(timeouts map[string]time.Duration) *RouterFactory {
+	f.methodTimeouts = timeouts
+	return f
+}
+
+// WithBatchWorkers 设置批量请求 worker 池：workers 为单个批量的 worker 数，queueSize 为任务通道缓冲，
+// maxTotal 为所有并发批量共享的 worker 上限（0 表示不限制）
+func (f *RouterFactory) WithBatchWorkers(workers, queueSize, maxTotal int) *RouterFactory {
+	f.batchWorkers = workers
+	f.batchQueueSize = queueSize
+	f.maxBatchWorkers = maxTotal
+	return f
+}
+
+// WithSlowRequestThreshold 设置慢请求日志阈值，0 表示不记录
+func (f *RouterFactory) WithSlowRequestThreshold(threshold time.Duration) *RouterFactory {
+	f.slowRequestThreshold = threshold
+	return f
+}
+
+// WithPreSignHook 设置签名交易前调用的策略钩子
+func (f *RouterFactory) WithPreSignHook(hook policy.PreSignHook) *RouterFactory {
+	f.preSignHook = hook
+	return f
+}
+
+// WithMaxCalldataBytes 设置交易 data 的最大字节数，0 表示不限制
+func (f *RouterFactory) WithMaxCalldataBytes(limit int) *RouterFactory {
+	f.maxCalldataBytes = limit
+	return f
+}
+
+// WithMaxAccessList 设置访问列表的最大条目数与每个条目的最大存储键数，0 表示不限制
+func (f *RouterFactory) WithMaxAccessList(entries, storageKeys int) *RouterFactory {
+	f.maxAccessListEntries = entries
+	f.maxAccessListStorageKeys = storageKeys
+	return f
+}
+
+// WithResponseCache 设置不可变查询方法的下游响应缓存，ttl 为 0 表示不缓存
+func (f *RouterFactory) WithResponseCache(ttl time.Duration, maxSize int) *RouterFactory {
+	f.responseCacheTTL = ttl
+	f.responseCacheSize = maxSize
+	return f
+}
+
+// WithFeeHistoryCache 设置 eth_feeHistory 本地缓存，interval 为 0 表示不缓存
+//
+// 缓存每隔 interval 从下游拉取最近 blocks 个区块（按 percentiles 采样奖励）的费用历史，
+// 超过 maxAge 未成功刷新的缓存不再使用，查询回退为实时转发
+func (f *RouterFactory) WithFeeHistoryCache(interval, maxAge time.Duration, blocks int, percentiles []float64) *RouterFactory {
+	f.feeHistoryInterval = interval
+	f.feeHistoryMaxAge = maxAge
+	f.feeHistoryBlocks = blocks
+	f.feeHistoryPercentiles = percentiles
+	return f
+}
+
+// WithCosmosSigning 设置是否注册 cosmos_signAmino 和 cosmos_signDirect 方法
+func (f *RouterFactory) WithCosmosSigning(enabled bool) *RouterFactory {
+	f.cosmosEnabled = enabled
+	return f
+}
+
+// WithTaskCancel 设置是否注册 web3signer_cancelTask 方法，须同时设置审批任务管理器
+func (f *RouterFactory) WithTaskCancel(enabled bool) *RouterFactory {
+	f.taskCancel = enabled
+	return f
+}
+
+// WithKeyRotation 设置是否注册 web3signer_setDefaultKey 方法，签名器须支持切换默认密钥
+func (f *RouterFactory) WithKeyRotation(enabled bool) *RouterFactory {
+	f.keyRotationEnabled = enabled
+	return f
+}
+
+// WithSignBatchLimits 设置 web3signer_signBatch 单次请求的最大消息数（0 表示不限制）与并发签名数
+func (f *RouterFactory) WithSignBatchLimits(maxItems, concurrency int) *RouterFactory {
+	f.signBatchMaxItems = maxItems
+	f.signBatchConcurrency = concurrency
+	return f
+}
+
+// WithAllowedKeyIDs 设置客户端请求可用于签名的密钥 ID，为空表示允许所有已注册的密钥
+func (f *RouterFactory) WithAllowedKeyIDs(keyIDs []string) *RouterFactory {
+	f.allowedKeyIDs = keyIDs
+	return f
+}
+
+// WithDownstreamHealthCheck 设置签名处理器定期检查下游 RPC 连接的间隔与方法，连接故障时重建，interval 为 0 表示不检查
+func (f *RouterFactory) WithDownstreamHealthCheck(interval time.Duration, method string) *RouterFactory {
+	f.rpcHealthCheckInterval = interval
+	f.rpcHealthCheckMethod = method
+	return f
+}
+
+// WithTaskManager 设置审批任务管理器，设置后注册 web3signer_getTaskResult 和 web3signer_cancelTask 方法
+func (f *RouterFactory) WithTaskManager(tasks kms.TaskManager) *RouterFactory {
+	f.taskManager = tasks
+	return f
+}
+
 // CreateRouter 创建完整配置的路由器
 func (f *RouterFactory) CreateRouter(mpcSigner signer.Client, downstreamClient downstream.ClientInterface) *Router { //nolint:staticcheck // SA1019: backward compatibility
 	router := NewRouterWithMaxSize(f.logger.Logger, f.maxRequestSize)
+	router.SetCompressionThreshold(f.compressionThreshold)
+	router.SetStreamBatchThreshold(f.streamBatchThreshold)
+	router.SetAllowMissingJSONRPCVersion(f.allowMissingJSONRPCVersion)
+	router.SetRequestTimeouts(f.requestTimeout, f.signRequestTimeout)
+	router.SetMethodTimeouts(f.methodTimeouts)
+	router.SetBatchWorkers(f.batchWorkers, f.batchQueueSize)
+	router.SetMaxBatchWorkers(f.maxBatchWorkers)
+	router.SetSlowRequestThreshold(f.slowRequestThreshold)
 
 	// 注册签名处理器
-	signHandler, err := NewSignHandler(mpcSigner, downstreamClient, downstreamClient.GetEndpoint(), f.logger.Logger)
-	if err != nil {
-		f.logger.WithError(err).Fatal("Failed to create sign handler")
+	signHandler := NewSignHandler(mpcSigner, downstreamClient, f.logger.Logger)
+	signHandler.WithTransactionConfig(f.txConfig)
+
+	// 签名处理器与转发处理器共享已发送交易缓存，使 eth_getTransactionByHash 能看到两条路径发送的交易
+	var sentTxs *sentTxCache
+	if f.txConfig.PendingTxTTLSeconds > 0 && f.txConfig.PendingTxCacheSize > 0 {
+		sentTxs = newSentTxCache(time.Duration(f.txConfig.PendingTxTTLSeconds)*time.Second, f.txConfig.PendingTxCacheSize)
+	}
+	signHandler.sentTxs = sentTxs
+
+	// 记录发送交易的 gas limit，转发处理器看到回执时统计 gasUsed 占比
+	gasLimits := newGasLimitTracker(gasLimitTrackerSize)
+	signHandler.gasLimits = gasLimits
+
+	if f.txConfig.NonceGapCheckInterval > 0 {
+		monitor := newNonceMonitor(func() (uint64, error) {
+			return signHandler.rpc().GetNonce(mpcSigner.Address(), ethgo.Pending)
+		}, f.txConfig.NonceGapCheckInterval, f.txConfig.NonceGapThreshold, f.logger.Logger)
+		signHandler.nonceMonitor = monitor
+		router.AddBackgroundTask(monitor.run)
+	}
+	signHandler.WithMaxCalldataBytes(f.maxCalldataBytes)
+	signHandler.WithMaxAccessList(f.maxAccessListEntries, f.maxAccessListStorageKeys)
+	signHandler.WithSignBatchLimits(f.signBatchMaxItems, f.signBatchConcurrency)
+	signHandler.WithAllowedKeyIDs(f.allowedKeyIDs)
+	if f.rpcHealthCheckInterval > 0 && f.rpcHealthCheckMethod != "" {
+		signHandler.WithDownstreamHealthCheck(f.rpcHealthCheckInterval, f.rpcHealthCheckMethod)
+		router.AddBackgroundTask(signHandler.runDownstreamHealthCheck)
+	}
+	if f.preSignHook != nil {
+		signHandler.WithPreSignHook(f.preSignHook)
 	}
 
 	// 注意：SignHandler 处理多个方法，所以我们需要为每个方法注册同一个处理器
@@ -54,6 +274,13 @@ func (f *RouterFactory) CreateRouter(mpcSigner signer.Client, downstreamClient d
 		f.logger.WithError(err).Error("Failed to register eth_sign handler")
 	}
 
+	if err := router.Register(&MethodHandler{
+		handler: signHandler,
+		method:  "eth_signTypedData_v4",
+	}); err != nil {
+		f.logger.WithError(err).Error("Failed to register eth_signTypedData_v4 handler")
+	}
+
 	if err := router.Register(&MethodHandler{
 		handler: signHandler,
 		method:  "eth_signTransaction",
@@ -68,8 +295,89 @@ func (f *RouterFactory) CreateRouter(mpcSigner signer.Client, downstreamClient d
 		f.logger.WithError(err).Error("Failed to register eth_sendTransaction handler")
 	}
 
+	if err := router.Register(&MethodHandler{
+		handler: signHandler,
+		method:  "web3signer_signTransactionWithSummary",
+	}); err != nil {
+		f.logger.WithError(err).Error("Failed to register web3signer_signTransactionWithSummary handler")
+	}
+
+	if err := router.Register(&MethodHandler{
+		handler: signHandler,
+		method:  "web3signer_signRawTransaction",
+	}); err != nil {
+		f.logger.WithError(err).Error("Failed to register web3signer_signRawTransaction handler")
+	}
+
+	if err := router.Register(&MethodHandler{
+		handler: signHandler,
+		method:  "web3signer_signBatch",
+	}); err != nil {
+		f.logger.WithError(err).Error("Failed to register web3signer_signBatch handler")
+	}
+
+	// eth_call 需要注入默认 from 时由签名处理器改写后转发
+	if f.txConfig.InjectCallFrom {
+		if err := router.Register(&MethodHandler{
+			handler: signHandler,
+			method:  "eth_call",
+		}); err != nil {
+			f.logger.WithError(err).Error("Failed to register eth_call handler")
+		}
+	}
+
+	if f.cosmosEnabled {
+		cosmosHandler := NewCosmosHandler(mpcSigner, f.logger.Logger).WithAllowedKeyIDs(f.allowedKeyIDs)
+		for _, method := range []string{"cosmos_signAmino", "cosmos_signDirect"} {
+			if err := router.Register(&MethodHandler{
+				handler: cosmosHandler,
+				method:  method,
+			}); err != nil {
+				f.logger.WithError(err).Errorf("Failed to register %s handler", method)
+			}
+		}
+	}
+
+	if f.keyRotationEnabled {
+		if setter, ok := mpcSigner.(defaultKeySetter); ok {
+			if err := router.Register(&MethodHandler{
+				handler: NewKeyHandler(setter, f.logger.Logger),
+				method:  "web3signer_setDefaultKey",
+			}); err != nil {
+				f.logger.WithError(err).Error("Failed to register web3signer_setDefaultKey handler")
+			}
+		} else {
+			f.logger.Warn("Signer does not support switching the default key, web3signer_setDefaultKey not registered")
+		}
+	}
+
+	if f.taskManager != nil {
+		taskHandler := NewTaskHandler(f.taskManager, f.logger.Logger).WithAllowedKeyIDs(f.allowedKeyIDs)
+		methods := []string{"web3signer_getTaskResult", "web3signer_pendingTasks"}
+		if f.taskCancel {
+			methods = append(methods, "web3signer_cancelTask")
+		}
+		for _, method := range methods {
+			if err := router.Register(&MethodHandler{
+				handler: taskHandler,
+				method:  method,
+			}); err != nil {
+				f.logger.WithError(err).Errorf("Failed to register %s handler", method)
+			}
+		}
+	}
+
 	// 注册转发处理器（处理所有其他方法）
-	forwardHandler := NewForwardHandler(downstreamClient, f.logger.Logger)
+	forwardHandler := NewForwardHandler(downstreamClient, f.logger.Logger).
+		WithResponseCache(f.responseCacheTTL, f.responseCacheSize)
+	forwardHandler.sentTxs = sentTxs
+	forwardHandler.gasLimits = gasLimits
+	if f.feeHistoryInterval > 0 && f.feeHistoryBlocks > 0 {
+		cache := newFeeHistoryCache(downstreamClient, f.feeHistoryBlocks, f.feeHistoryPercentiles,
+			f.feeHistoryInterval, f.feeHistoryMaxAge, f.logger.Logger)
+		forwardHandler.feeHistory = cache
+		router.AddBackgroundTask(cache.run)
+	}
 	router.SetDefaultHandler(&MethodHandler{
 		handler: forwardHandler,
 		method:  "forward_handler", // 这个会处理所有非签名方法
@@ -0,0 +1,225 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/downstream"
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/sirupsen/logrus"
+)
+
+// feeHistory eth_feeHistory 的结果，数值保留下游返回的原始 JSON 编码
+type feeHistory struct {
+	OldestBlock       string              `json:"oldestBlock"`
+	BaseFeePerGas     []json.RawMessage   `json:"baseFeePerGas"`
+	GasUsedRatio      []json.RawMessage   `json:"gasUsedRatio"`
+	Reward            [][]json.RawMessage `json:"reward,omitempty"`
+	BaseFeePerBlobGas []json.RawMessage   `json:"baseFeePerBlobGas,omitempty"`
+	BlobGasUsedRatio  []json.RawMessage   `json:"blobGasUsedRatio,omitempty"`
+}
+
+// feeHistoryCache 缓存最近区块的 eth_feeHistory 结果
+//
+// 后台按间隔以 "latest" 为最新区块从下游拉取固定数量区块的费用历史，
+// 区间被缓存覆盖且缓存未超过新鲜度上限的查询直接从缓存切片返回，
+// 避免钱包密集查询费用时反复打到下游节点；其余查询仍实时转发
+type feeHistoryCache struct {
+	client      downstream.ClientInterface
+	blocks      int
+	percentiles []float64
+	interval    time.Duration
+	maxAge      time.Duration
+	logger      *logrus.Entry
+	now         func() time.Time
+
+	mu        sync.RWMutex
+	history   *feeHistory
+	oldest    uint64
+	fetchedAt time.Time
+}
+
+// newFeeHistoryCache 创建 eth_feeHistory 缓存
+func newFeeHistoryCache(client downstream.ClientInterface, blocks int, percentiles []float64, interval, maxAge time.Duration, logger *logrus.Logger) *feeHistoryCache {
+	return &feeHistoryCache{
+		client:      client,
+		blocks:      blocks,
+		percentiles: percentiles,
+		interval:    interval,
+		maxAge:      maxAge,
+		logger:      logger.WithField("component", "fee_history_cache"),
+		now:         time.Now,
+	}
+}
+
+// run 立即拉取一次，随后按间隔刷新，直到 ctx 取消
+func (c *feeHistoryCache) run(ctx context.Context) {
+	c.refresh(ctx)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.refresh(ctx)
+		}
+	}
+}
+
+// refresh 从下游拉取费用历史，失败时保留旧数据，由新鲜度上限决定何时不再使用
+func (c *feeHistoryCache) refresh(ctx context.Context) {
+	params, err := json.Marshal([]interface{}{fmt.Sprintf("0x%x", c.blocks), "latest", c.percentiles})
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to encode eth_feeHistory params")
+		return
+	}
+	response, err := c.client.ForwardRequest(ctx, &jsonrpc.Request{
+		JSONRPC: jsonrpc.JSONRPCVersion,
+		Method:  "eth_feeHistory",
+		Params:  params,
+		ID:      1,
+	})
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to refresh fee history cache")
+		return
+	}
+	if response.Error != nil {
+		c.logger.WithField("error", response.Error.Message).Warn("Downstream rejected eth_feeHistory, fee history cache not refreshed")
+		return
+	}
+
+	var history feeHistory
+	if err := json.Unmarshal(response.Result, &history); err != nil {
+		c.logger.WithError(err).Warn("Invalid eth_feeHistory result from downstream")
+		return
+	}
+	oldest, err := parseQuantity(history.OldestBlock)
+	if err != nil || len(history.BaseFeePerGas) != len(history.GasUsedRatio)+1 {
+		c.logger.Warn("Malformed eth_feeHistory result from downstream, fee history cache not refreshed")
+		return
+	}
+
+	c.mu.Lock()
+	c.history = &history
+	c.oldest = oldest
+	c.fetchedAt = c.now()
+	c.mu.Unlock()
+}
+
+// lookup 返回请求参数对应的缓存结果，缓存过期或未覆盖请求区间时返回 false
+func (c *feeHistoryCache) lookup(params json.RawMessage) (json.RawMessage, bool) {
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil || len(args) < 2 || len(args) > 3 {
+		return nil, false
+	}
+	count, ok := parseBlockCount(args[0])
+	if !ok || count == 0 {
+		return nil, false
+	}
+	var newestTag string
+	if err := json.Unmarshal(args[1], &newestTag); err != nil {
+		return nil, false
+	}
+	var percentiles []float64
+	if len(args) == 3 && string(args[2]) != "null" {
+		if err := json.Unmarshal(args[2], &percentiles); err != nil {
+			return nil, false
+		}
+	}
+	withReward := len(percentiles) > 0
+	if withReward && !equalPercentiles(percentiles, c.percentiles) {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.history == nil || c.now().Sub(c.fetchedAt) > c.maxAge {
+		return nil, false
+	}
+
+	cached := uint64(len(c.history.GasUsedRatio))
+	if cached == 0 {
+		return nil, false
+	}
+	cachedNewest := c.oldest + cached - 1
+	newest := cachedNewest
+	if newestTag != "latest" {
+		n, err := parseQuantity(newestTag)
+		if err != nil || n > cachedNewest {
+			return nil, false
+		}
+		newest = n
+	}
+	if count > newest+1 || newest-count+1 < c.oldest {
+		return nil, false
+	}
+
+	start := newest - count + 1 - c.oldest
+	end := start + count
+	result := &feeHistory{
+		OldestBlock:   fmt.Sprintf("0x%x", newest-count+1),
+		BaseFeePerGas: c.history.BaseFeePerGas[start : end+1],
+		GasUsedRatio:  c.history.GasUsedRatio[start:end],
+	}
+	if withReward {
+		if uint64(len(c.history.Reward)) != cached {
+			return nil, false
+		}
+		result.Reward = c.history.Reward[start:end]
+	}
+	if uint64(len(c.history.BaseFeePerBlobGas)) == cached+1 && uint64(len(c.history.BlobGasUsedRatio)) == cached {
+		result.BaseFeePerBlobGas = c.history.BaseFeePerBlobGas[start : end+1]
+		result.BlobGasUsedRatio = c.history.BlobGasUsedRatio[start:end]
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, false
+	}
+	return encoded, true
+}
+
+// parseBlockCount 解析 eth_feeHistory 的 blockCount 参数，兼容十六进制字符串、十进制字符串与 JSON 数字
+func parseBlockCount(raw json.RawMessage) (uint64, bool) {
+	var n uint64
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n, true
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, false
+	}
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		n, err := parseQuantity(s)
+		return n, err == nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	return n, err == nil
+}
+
+// parseQuantity 解析 0x 前缀的十六进制数量
+func parseQuantity(s string) (uint64, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return 0, fmt.Errorf("quantity %q must be 0x-prefixed", s)
+	}
+	return strconv.ParseUint(s[2:], 16, 64)
+}
+
+// equalPercentiles 判断两组奖励百分位是否完全相同
+func equalPercentiles(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/sirupsen/logrus"
+)
+
+// feeHistoryDownstreamClient 返回区块 0x64-0x67 的费用历史并统计 eth_feeHistory 请求次数
+type feeHistoryDownstreamClient struct {
+	*testDownstreamClient
+	calls int32
+}
+
+func (c *feeHistoryDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	if req.Method == "eth_feeHistory" {
+		atomic.AddInt32(&c.calls, 1)
+		return &jsonrpc.Response{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{
+			"oldestBlock":"0x64",
+			"baseFeePerGas":["0x1","0x2","0x3","0x4","0x5"],
+			"gasUsedRatio":[0.1,0.2,0.3,0.4],
+			"reward":[["0xa"],["0xb"],["0xc"],["0xd"]]
+		}`)}, nil
+	}
+	return c.testDownstreamClient.ForwardRequest(ctx, req)
+}
+
+func TestForwardHandler_FeeHistoryCache(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	downstream := &feeHistoryDownstreamClient{testDownstreamClient: &testDownstreamClient{}}
+	cache := newFeeHistoryCache(downstream, 4, []float64{50}, time.Second, time.Minute, logger)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	cache.refresh(context.Background())
+
+	handler := NewForwardHandler(downstream, logger)
+	handler.feeHistory = cache
+
+	tests := []struct {
+		name   string
+		params string
+		want   string
+		cached bool
+	}{
+		{
+			name:   "latest without rewards",
+			params: `["0x2","latest"]`,
+			want:   `{"oldestBlock":"0x66","baseFeePerGas":["0x3","0x4","0x5"],"gasUsedRatio":[0.3,0.4]}`,
+			cached: true,
+		},
+		{
+			name:   "numeric newest block with matching percentiles",
+			params: `[2,"0x65",[50]]`,
+			want:   `{"oldestBlock":"0x64","baseFeePerGas":["0x1","0x2","0x3"],"gasUsedRatio":[0.1,0.2],"reward":[["0xa"],["0xb"]]}`,
+			cached: true,
+		},
+		{name: "range before cache", params: `["0x3","0x65"]`},
+		{name: "block count exceeds cache", params: `["0x5","latest"]`},
+		{name: "different percentiles", params: `["0x2","latest",[25]]`},
+		{name: "pending tag", params: `["0x2","pending"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := atomic.LoadInt32(&downstream.calls)
+			resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0", Method: "eth_feeHistory", ID: 1, Params: json.RawMessage(tt.params),
+			})
+			if err != nil || resp.Error != nil {
+				t.Fatalf("eth_feeHistory failed: %v %+v", err, resp)
+			}
+			forwarded := atomic.LoadInt32(&downstream.calls) - before
+			if tt.cached {
+				if forwarded != 0 {
+					t.Errorf("Expected cached response, downstream called %d times", forwarded)
+				}
+				if string(resp.Result) != tt.want {
+					t.Errorf("result = %s, want %s", resp.Result, tt.want)
+				}
+			} else if forwarded != 1 {
+				t.Errorf("Expected live forward, downstream called %d times", forwarded)
+			}
+		})
+	}
+
+	// 超过新鲜度上限后回退为实时转发
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.lookup(json.RawMessage(`["0x1","latest"]`)); ok {
+		t.Error("Expected stale cache to miss")
+	}
+}
@@ -2,11 +2,17 @@ package router
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mowind/web3signer-go/internal/downstream"
 	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
 	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
 )
 
 // ForwardHandler 处理转发到下游服务的 JSON-RPC 方法
@@ -15,7 +21,11 @@ import (
 // 它特殊处理 eth_accounts 方法（返回空数组），并支持批量请求转发以优化性能。
 type ForwardHandler struct {
 	*BaseHandler
-	client downstream.ClientInterface
+	client     downstream.ClientInterface
+	sentTxs    *sentTxCache
+	gasLimits  *gasLimitTracker
+	responses  *responseCache
+	feeHistory *feeHistoryCache
 }
 
 // NewForwardHandler 创建转发处理器
@@ -26,6 +36,16 @@ func NewForwardHandler(client downstream.ClientInterface, logger *logrus.Logger)
 	}
 }
 
+// WithResponseCache 启用不可变查询方法的下游响应缓存，ttl 或 maxSize 为 0 表示不缓存
+func (h *ForwardHandler) WithResponseCache(ttl time.Duration, maxSize int) *ForwardHandler {
+	if ttl > 0 && maxSize > 0 {
+		h.responses = newResponseCache(ttl, maxSize)
+	} else {
+		h.responses = nil
+	}
+	return h
+}
+
 // Client returns the downstream client used by this handler.
 // This method is used for batch forwarding optimizations.
 func (h *ForwardHandler) Client() downstream.ClientInterface {
@@ -41,9 +61,8 @@ func (h *ForwardHandler) Method() string {
 func (h *ForwardHandler) Handle(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
 	h.LogRequest(request)
 
-	// 特殊处理 eth_accounts - 返回空数组
-	if request.Method == "eth_accounts" {
-		return h.handleEthAccounts(ctx, request)
+	if response, ok := h.localResponse(ctx, request); ok {
+		return response, nil
 	}
 
 	// 转发到下游服务
@@ -54,10 +73,124 @@ func (h *ForwardHandler) Handle(ctx context.Context, request *jsonrpc.Request) (
 			"Failed to forward request", err.Error()), nil
 	}
 
+	response = h.finishForwarded(request, response)
 	h.LogResponse(request, response, nil)
 	return response, nil
 }
 
+// localResponse 返回无需访问下游的响应：eth_accounts、缓存的 fee history 与缓存的不可变结果
+//
+// 单个请求与批量转发的请求都先经过此处
+func (h *ForwardHandler) localResponse(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, bool) {
+	// 特殊处理 eth_accounts - 返回空数组
+	if request.Method == "eth_accounts" {
+		response, err := h.handleEthAccounts(ctx, request)
+		if err != nil {
+			return h.CreateErrorResponse(request.ID, jsonrpc.CodeInternalError, "Internal error", err.Error()), true
+		}
+		return response, true
+	}
+
+	if request.Method == "eth_feeHistory" && h.feeHistory != nil {
+		if result, ok := h.feeHistory.lookup(request.Params); ok {
+			h.logger.Debug("Returning cached fee history")
+			return &jsonrpc.Response{
+				JSONRPC: jsonrpc.JSONRPCVersion,
+				Result:  result,
+				ID:      request.ID,
+			}, true
+		}
+	}
+
+	if h.responses != nil {
+		if cacheKey := responseCacheKey(request.Method, request.Params); cacheKey != "" {
+			if result, ok := h.responses.lookup(cacheKey); ok {
+				h.logger.WithField("method", request.Method).Debug("Returning cached downstream response")
+				return &jsonrpc.Response{
+					JSONRPC: jsonrpc.JSONRPCVersion,
+					Result:  result,
+					ID:      request.ID,
+				}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// finishForwarded 缓存下游返回的不可变结果并关联已发送交易
+func (h *ForwardHandler) finishForwarded(request *jsonrpc.Request, response *jsonrpc.Response) *jsonrpc.Response {
+	if h.responses != nil && response != nil && response.Error == nil {
+		if cacheKey := responseCacheKey(request.Method, request.Params); cacheKey != "" && isImmutableResult(request.Method, response.Result) {
+			h.responses.add(cacheKey, response.Result)
+		}
+	}
+	return h.correlate(request, response)
+}
+
+// correlate 关联已发送交易与后续查询
+//
+// 成功转发的 eth_sendRawTransaction 记录到已发送交易缓存；eth_getTransactionByHash
+// 在下游返回 null 时，对缓存中的交易返回 pending 状态的合成结果，弥补交易提交到进入节点交易池之间的空窗；
+// eth_getTransactionReceipt 的回执用于统计 gasUsed 占比。单个请求与批量转发的请求都经过此处
+func (h *ForwardHandler) correlate(request *jsonrpc.Request, response *jsonrpc.Response) *jsonrpc.Response {
+	if response == nil || response.Error != nil {
+		return response
+	}
+	if request.Method == "eth_getTransactionReceipt" && h.gasLimits != nil {
+		h.gasLimits.observeReceipt(response.Result)
+	}
+	if h.sentTxs == nil {
+		return response
+	}
+
+	switch request.Method {
+	case "eth_sendRawTransaction":
+		var params []string
+		if err := json.Unmarshal(request.Params, &params); err != nil || len(params) == 0 {
+			return response
+		}
+		raw, err := hex.DecodeString(strings.TrimPrefix(params[0], "0x"))
+		if err != nil {
+			return response
+		}
+		tx, err := signer.DecodeRawTransaction(raw)
+		if err != nil {
+			h.logger.WithError(err).Debug("Not tracking undecodable raw transaction")
+			return response
+		}
+		h.sentTxs.add(tx.Hash, tx)
+
+	case "eth_getTransactionByHash":
+		if len(response.Result) != 0 && string(response.Result) != "null" {
+			return response
+		}
+		var params []string
+		if err := json.Unmarshal(request.Params, &params); err != nil || len(params) == 0 {
+			return response
+		}
+		var hash ethgo.Hash
+		if err := hash.UnmarshalText([]byte(params[0])); err != nil {
+			return response
+		}
+		tx, ok := h.sentTxs.lookup(hash)
+		if !ok {
+			return response
+		}
+		result, err := pendingTransactionResult(hash, tx)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to encode pending transaction")
+			return response
+		}
+		h.logger.WithField("tx_hash", hash.String()).Debug("Returning locally tracked pending transaction")
+		return &jsonrpc.Response{
+			JSONRPC: jsonrpc.JSONRPCVersion,
+			Result:  result,
+			ID:      request.ID,
+		}
+	}
+	return response
+}
+
 // handleEthAccounts 处理 eth_accounts 方法
 func (h *ForwardHandler) handleEthAccounts(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
 	h.logger.Info("Returning empty accounts array")
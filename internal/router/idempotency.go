@@ -0,0 +1,155 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader 客户端传递幂等键的 HTTP 请求头
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKeyParam eth_sendTransaction 交易对象中携带幂等键的字段名
+const idempotencyKeyParam = "idempotencyKey"
+
+// errIdempotencyInProgress 相同幂等键的请求正在处理中
+var errIdempotencyInProgress = errors.New("a request with the same idempotency key is already in progress")
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey 返回携带幂等键的 context
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext 从 context 获取幂等键
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// idempotencyKeyFromParams 从交易参数中提取幂等键，支持数组和对象两种格式
+func idempotencyKeyFromParams(params json.RawMessage) string {
+	var paramsArray []json.RawMessage
+	if err := json.Unmarshal(params, &paramsArray); err == nil {
+		if len(paramsArray) == 0 {
+			return ""
+		}
+		params = paramsArray[0]
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(params, &fields); err != nil {
+		return ""
+	}
+	var key string
+	if err := json.Unmarshal(fields[idempotencyKeyParam], &key); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(key)
+}
+
+// idempotencyEntry 幂等缓存条目；result 为 nil 表示请求仍在处理中
+//
+// 处理中的条目不会过期也不会被淘汰，expiresAt 只在记录结果时设置
+type idempotencyEntry struct {
+	result    json.RawMessage
+	expiresAt time.Time
+}
+
+// idempotencyCache 缓存幂等键到交易结果（交易哈希）的映射
+type idempotencyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]*idempotencyEntry
+	now     func() time.Time
+}
+
+// newIdempotencyCache 创建幂等缓存
+func newIdempotencyCache(ttl time.Duration, maxSize int) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*idempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// reserve 占用幂等键
+//
+// 已有完成结果时返回该结果；已有处理中的请求时返回 errIdempotencyInProgress；
+// 否则将该键标记为处理中并返回 (nil, nil)，调用方随后必须调用 complete 或 release。
+func (c *idempotencyCache) reserve(key string) (json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if entry, ok := c.entries[key]; ok {
+		if entry.result == nil {
+			return nil, errIdempotencyInProgress
+		}
+		if now.Before(entry.expiresAt) {
+			return entry.result, nil
+		}
+	}
+
+	c.evictLocked(now)
+	c.entries[key] = &idempotencyEntry{}
+	return nil, nil
+}
+
+// complete 记录幂等键对应的成功结果
+func (c *idempotencyCache) complete(key string, result json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = &idempotencyEntry{result: result, expiresAt: c.now().Add(c.ttl)}
+}
+
+// release 释放处理失败的幂等键，允许客户端重试
+func (c *idempotencyCache) release(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok && entry.result == nil {
+		delete(c.entries, key)
+	}
+}
+
+// evictLocked 清理过期条目；缓存仍满时淘汰最早过期的已完成条目
+//
+// 处理中的条目不参与淘汰，否则重试会在原请求完成前再次发送交易；
+// 全部条目都在处理中时缓存可暂时超过 maxSize
+func (c *idempotencyCache) evictLocked(now time.Time) {
+	if len(c.entries) < c.maxSize {
+		return
+	}
+	for key, entry := range c.entries {
+		if entry.result != nil && !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	for len(c.entries) >= c.maxSize {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range c.entries {
+			if entry.result == nil {
+				continue
+			}
+			if oldestKey == "" || entry.expiresAt.Before(oldest) {
+				oldestKey, oldest = key, entry.expiresAt
+			}
+		}
+		if oldestKey == "" {
+			return
+		}
+		delete(c.entries, oldestKey)
+	}
+}
@@ -0,0 +1,222 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newIdempotencyCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	if result, err := cache.reserve("a"); result != nil || err != nil {
+		t.Fatalf("first reserve: got (%s, %v), want (nil, nil)", result, err)
+	}
+	if _, err := cache.reserve("a"); err != errIdempotencyInProgress {
+		t.Errorf("reserve while pending: got %v, want errIdempotencyInProgress", err)
+	}
+
+	cache.complete("a", json.RawMessage(`"0xabc"`))
+	if result, err := cache.reserve("a"); err != nil || string(result) != `"0xabc"` {
+		t.Errorf("reserve after complete: got (%s, %v)", result, err)
+	}
+
+	// 失败后释放，允许重试
+	if _, err := cache.reserve("b"); err != nil {
+		t.Fatalf("reserve b: %v", err)
+	}
+	cache.release("b")
+	if result, err := cache.reserve("b"); result != nil || err != nil {
+		t.Errorf("reserve after release: got (%s, %v), want (nil, nil)", result, err)
+	}
+
+	// 完成的结果不会被 release 清除
+	cache.release("a")
+	if result, _ := cache.reserve("a"); string(result) != `"0xabc"` {
+		t.Errorf("release removed completed entry, got %s", result)
+	}
+
+	// 过期后重新占用
+	now = now.Add(2 * time.Minute)
+	if result, err := cache.reserve("a"); result != nil || err != nil {
+		t.Errorf("reserve after expiry: got (%s, %v), want (nil, nil)", result, err)
+	}
+
+	// 容量上限
+	cache.release("a")
+	cache.release("b")
+	for _, key := range []string{"c", "d", "e"} {
+		if _, err := cache.reserve(key); err != nil {
+			t.Fatalf("reserve %s: %v", key, err)
+		}
+		cache.complete(key, json.RawMessage(`"0x`+key+`"`))
+	}
+	if len(cache.entries) > 2 {
+		t.Errorf("cache size = %d, want <= 2", len(cache.entries))
+	}
+}
+
+func TestIdempotencyCache_PendingNeverEvicted(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newIdempotencyCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	if _, err := cache.reserve("pending"); err != nil {
+		t.Fatalf("reserve pending: %v", err)
+	}
+
+	// 填满缓存并超过 TTL，处理中的条目既不淘汰也不过期
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := cache.reserve(key); err != nil {
+			t.Fatalf("reserve %s: %v", key, err)
+		}
+		cache.complete(key, json.RawMessage(`"0x`+key+`"`))
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.reserve("d"); err != nil {
+		t.Fatalf("reserve d: %v", err)
+	}
+
+	if _, err := cache.reserve("pending"); err != errIdempotencyInProgress {
+		t.Errorf("retry while pending: got %v, want errIdempotencyInProgress", err)
+	}
+
+	// TTL 从记录结果时开始计算
+	cache.complete("pending", json.RawMessage(`"0xabc"`))
+	now = now.Add(30 * time.Second)
+	if result, err := cache.reserve("pending"); err != nil || string(result) != `"0xabc"` {
+		t.Errorf("reserve after complete: got (%s, %v), want 0xabc", result, err)
+	}
+}
+
+func TestIdempotencyKeyFromParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{name: "array format", params: `[{"from":"0x1","idempotencyKey":"key-1"}]`, want: "key-1"},
+		{name: "object format", params: `{"from":"0x1","idempotencyKey":" key-2 "}`, want: "key-2"},
+		{name: "missing", params: `[{"from":"0x1"}]`, want: ""},
+		{name: "non-string", params: `[{"idempotencyKey":123}]`, want: ""},
+		{name: "empty array", params: `[]`, want: ""},
+		{name: "invalid json", params: `{`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idempotencyKeyFromParams(json.RawMessage(tt.params)); got != tt.want {
+				t.Errorf("idempotencyKeyFromParams() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignHandler_IdempotencyKeyScopedByFrom(t *testing.T) {
+	handler := createSimpleTestHandler(t)
+	handler.WithTransactionConfig(config.TransactionConfig{IdempotencyTTLSeconds: 60, IdempotencyCacheSize: 100})
+
+	request := &jsonrpc.Request{Params: json.RawMessage(`[{"idempotencyKey":"shared"}]`)}
+	a := &signer.JSONRPCTransaction{Transaction: ethgo.Transaction{From: ethgo.HexToAddress("0x1111111111111111111111111111111111111111")}}
+	b := &signer.JSONRPCTransaction{Transaction: ethgo.Transaction{From: ethgo.HexToAddress("0x2222222222222222222222222222222222222222")}}
+
+	// 不同账户使用相同的幂等键时互不影响
+	keyA := handler.idempotencyKey(context.Background(), request, a)
+	keyB := handler.idempotencyKey(context.Background(), request, b)
+	if keyA == "" || keyA == keyB {
+		t.Errorf("idempotency keys for different senders = %q, %q; want distinct non-empty keys", keyA, keyB)
+	}
+	if got := handler.idempotencyKey(context.Background(), &jsonrpc.Request{Params: json.RawMessage(`[{}]`)}, a); got != "" {
+		t.Errorf("idempotencyKey() without key = %q, want empty", got)
+	}
+
+	handler.WithTransactionConfig(config.TransactionConfig{})
+	if got := handler.idempotencyKey(context.Background(), request, a); got != "" {
+		t.Errorf("idempotencyKey() with idempotency disabled = %q, want empty", got)
+	}
+}
+
+// countingDownstreamClient 统计 eth_sendRawTransaction 调用次数
+type countingDownstreamClient struct {
+	*testDownstreamClient
+	sends int32
+}
+
+func (c *countingDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	if req.Method == "eth_sendRawTransaction" {
+		atomic.AddInt32(&c.sends, 1)
+	}
+	return c.testDownstreamClient.ForwardRequest(ctx, req)
+}
+
+func TestSignHandler_IdempotentSendTransaction(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	testAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
+	mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", testAddress, big.NewInt(1))
+
+	downstream := &countingDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+	defer func() { _ = downstream.Close() }()
+
+	router := NewRouterFactory(logger).
+		WithTransactionConfig(config.TransactionConfig{IdempotencyTTLSeconds: 60, IdempotencyCacheSize: 100}).
+		CreateRouter(mpcSigner, downstream)
+
+	send := func(params, headerKey string) *jsonrpc.Response {
+		t.Helper()
+		body := `{"jsonrpc":"2.0","id":1,"method":"eth_sendTransaction","params":[` + params + `]}`
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		if headerKey != "" {
+			req.Header.Set(IdempotencyKeyHeader, headerKey)
+		}
+		w := httptest.NewRecorder()
+		router.HandleHTTPRequest(w, req)
+
+		var resp jsonrpc.Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v, body: %s", err, w.Body.String())
+		}
+		if resp.Error != nil {
+			t.Fatalf("Unexpected error: %+v", resp.Error)
+		}
+		return &resp
+	}
+
+	tx := `{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x4a817c800"}`
+	txWithKey := `{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x4a817c800","idempotencyKey":"param-key"}`
+
+	first := send(tx, "header-key")
+	second := send(tx, "header-key")
+	if string(first.Result) != string(second.Result) {
+		t.Errorf("Expected cached result %s, got %s", first.Result, second.Result)
+	}
+	if got := atomic.LoadInt32(&downstream.sends); got != 1 {
+		t.Errorf("Expected 1 broadcast for repeated header key, got %d", got)
+	}
+
+	send(txWithKey, "")
+	send(txWithKey, "")
+	if got := atomic.LoadInt32(&downstream.sends); got != 2 {
+		t.Errorf("Expected 2 broadcasts after param key retries, got %d", got)
+	}
+
+	send(tx, "")
+	send(tx, "")
+	if got := atomic.LoadInt32(&downstream.sends); got != 4 {
+		t.Errorf("Expected requests without key to always broadcast, got %d", got)
+	}
+}
@@ -1,15 +1,22 @@
 package router
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/downstream"
 	"github.com/mowind/web3signer-go/internal/jsonrpc"
 	"github.com/mowind/web3signer-go/internal/kms"
 	"github.com/mowind/web3signer-go/internal/signer"
@@ -26,6 +33,7 @@ func (c *testKMSClient) Sign(ctx context.Context, keyID string, message []byte)
 	for i := 0; i < 65; i++ {
 		signature[i] = byte(i + 1) // 避免 0 值
 	}
+	signature[64] = 1 // 恢复 ID 须为 0 或 1
 	// 返回十六进制编码的签名
 	hexSignature := hex.EncodeToString(signature)
 	return []byte(hexSignature), nil
@@ -52,7 +60,29 @@ type testDownstreamClient struct {
 	mockServer *httptest.Server
 }
 
+// ForwardRequest 有 mockServer 时将请求发送给它，否则返回固定结果
 func (c *testDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	if c.mockServer != nil {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.mockServer.URL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var response jsonrpc.Response
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return nil, err
+		}
+		return &response, nil
+	}
 	if req.Method == "eth_sendRawTransaction" {
 		return jsonrpc.NewResponse(req.ID, "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
 	}
@@ -365,6 +395,80 @@ func TestIntegration_BatchRequests(t *testing.T) {
 	}
 }
 
+// batchCountingDownstreamClient 记录每次批量转发的方法，并统计单个转发的次数
+type batchCountingDownstreamClient struct {
+	*testDownstreamClient
+	mu       sync.Mutex
+	batches  [][]string
+	forwards int
+}
+
+func (c *batchCountingDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	c.mu.Lock()
+	c.forwards++
+	c.mu.Unlock()
+	return c.testDownstreamClient.ForwardRequest(ctx, req)
+}
+
+func (c *batchCountingDownstreamClient) ForwardBatchRequest(ctx context.Context, requests []jsonrpc.Request) ([]jsonrpc.Response, error) {
+	methods := make([]string, len(requests))
+	for i := range requests {
+		methods[i] = requests[i].Method
+	}
+	c.mu.Lock()
+	c.batches = append(c.batches, methods)
+	c.mu.Unlock()
+	return c.testDownstreamClient.ForwardBatchRequest(ctx, requests)
+}
+
+func TestIntegration_BatchForwardedInSingleDownstreamRequest(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	testAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
+	mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", testAddress, big.NewInt(1))
+
+	downstream := &batchCountingDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+	defer func() { _ = downstream.Close() }()
+
+	router := NewRouterFactory(logger).CreateRouter(mpcSigner, downstream)
+
+	body := `[
+		{"jsonrpc":"2.0","id":1,"method":"eth_sign","params":["0x1234567890123456789012345678901234567890","0x000000000000000000000000000000000000000000000000000000000000dead"]},
+		{"jsonrpc":"2.0","id":2,"method":"eth_getBalance","params":["0x1234567890123456789012345678901234567890","latest"]},
+		{"jsonrpc":"2.0","id":3,"method":"eth_accounts","params":[]},
+		{"jsonrpc":"2.0","id":4,"method":"eth_blockNumber","params":[]}
+	]`
+	w := httptest.NewRecorder()
+	router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+	var responses []jsonrpc.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v, body: %s", err, w.Body.String())
+	}
+	if len(responses) != 4 {
+		t.Fatalf("Expected 4 responses, got %d", len(responses))
+	}
+	for i, resp := range responses {
+		if resp.Error != nil {
+			t.Errorf("Response %d has unexpected error: %+v", i, resp.Error)
+		}
+	}
+	for _, i := range []int{1, 3} {
+		if string(responses[i].Result) != `"batch_result"` {
+			t.Errorf("Response %d: expected batch_result, got %s", i, responses[i].Result)
+		}
+	}
+
+	// 非签名方法合并为一次下游批量请求，签名方法与 eth_accounts 在本地处理
+	if !reflect.DeepEqual(downstream.batches, [][]string{{"eth_getBalance", "eth_blockNumber"}}) {
+		t.Errorf("Expected one downstream batch [eth_getBalance eth_blockNumber], got %v", downstream.batches)
+	}
+	if downstream.forwards != 0 {
+		t.Errorf("Expected no single downstream forwards, got %d", downstream.forwards)
+	}
+}
+
 func TestIntegration_HandlerRegistration(t *testing.T) {
 	logger := logrus.New()
 	router := NewRouter(logger)
@@ -404,3 +508,112 @@ func TestIntegration_HandlerRegistration(t *testing.T) {
 		t.Error("Handler eth_sign should be unregistered")
 	}
 }
+
+func TestIntegration_BatchWithNotifications(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// 模拟节点：通知不返回响应，且响应顺序与请求相反
+	var forwarded atomic.Int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []jsonrpc.Request
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		forwarded.Add(int32(len(requests)))
+		responses := make([]jsonrpc.Response, 0, len(requests))
+		for i := len(requests) - 1; i >= 0; i-- {
+			if requests[i].ID == nil {
+				continue
+			}
+			result, _ := json.Marshal(requests[i].Method)
+			responses = append(responses, jsonrpc.Response{JSONRPC: "2.0", Result: result, ID: requests[i].ID})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if len(responses) == 0 {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(responses)
+	}))
+	defer node.Close()
+
+	cfg := &config.DownstreamConfig{HTTPHost: node.URL, HTTPPath: "/"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	downstreamClient := downstream.NewClient(cfg, logger)
+	defer func() { _ = downstreamClient.Close() }()
+
+	// 默认处理器为 ForwardHandler 时，非本地方法合并为一个下游批量请求
+	router := NewRouter(logger)
+	router.SetDefaultHandler(NewForwardHandler(downstreamClient, logger))
+	if err := router.Register(&mockHandler{method: "eth_accounts"}); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		forwarded   int32
+		expectedIDs []interface{}
+		results     []string
+	}{
+		{
+			name: "mixed batch",
+			body: `[
+				{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"},
+				{"jsonrpc":"2.0","method":"eth_getBalance","params":["0x1234567890123456789012345678901234567890","latest"]},
+				{"jsonrpc":"2.0","id":3,"method":"eth_accounts"},
+				{"jsonrpc":"2.0","id":"four","method":"net_version"}
+			]`,
+			forwarded:   3,
+			expectedIDs: []interface{}{float64(1), float64(3), "four"},
+			results:     []string{`"eth_blockNumber"`, "", `"net_version"`},
+		},
+		{
+			name:      "only forwarded notifications",
+			body:      `[{"jsonrpc":"2.0","method":"eth_blockNumber"},{"jsonrpc":"2.0","method":"net_version"}]`,
+			forwarded: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forwarded.Store(0)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.HandleHTTPRequest(w, req)
+
+			if got := forwarded.Load(); got != tt.forwarded {
+				t.Errorf("node received %d requests, want %d", got, tt.forwarded)
+			}
+			if len(tt.expectedIDs) == 0 {
+				if w.Code != http.StatusNoContent {
+					t.Errorf("status = %d, want 204, body: %s", w.Code, w.Body.String())
+				}
+				return
+			}
+
+			var responses []jsonrpc.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+				t.Fatalf("Failed to unmarshal batch response: %v, body: %s", err, w.Body.String())
+			}
+			if len(responses) != len(tt.expectedIDs) {
+				t.Fatalf("Expected %d responses, got %d: %s", len(tt.expectedIDs), len(responses), w.Body.String())
+			}
+			for i, resp := range responses {
+				if resp.Error != nil {
+					t.Errorf("Response %d has unexpected error: %v", i, resp.Error)
+				}
+				if resp.ID != tt.expectedIDs[i] {
+					t.Errorf("Response %d: ID = %v, want %v", i, resp.ID, tt.expectedIDs[i])
+				}
+				if tt.results[i] != "" && string(resp.Result) != tt.results[i] {
+					t.Errorf("Response %d: result = %s, want %s", i, resp.Result, tt.results[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+
+	internaljsonrpc "github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+)
+
+// errKeyNotAllowed 签名密钥不在 JSON-RPC 允许列表中
+var errKeyNotAllowed = errors.New("key is not allowed for JSON-RPC signing")
+
+// keyAllowlist 允许通过 JSON-RPC 签名的密钥 ID，nil 表示不限制
+//
+// 所有使用签名器密钥签名的处理器（SignHandler、CosmosHandler）共用这一检查
+type keyAllowlist map[string]struct{}
+
+// newKeyAllowlist 创建密钥允许列表，keyIDs 为空时返回 nil（不限制）
+func newKeyAllowlist(keyIDs []string) keyAllowlist {
+	if len(keyIDs) == 0 {
+		return nil
+	}
+	allowed := make(keyAllowlist, len(keyIDs))
+	for _, keyID := range keyIDs {
+		allowed[keyID] = struct{}{}
+	}
+	return allowed
+}
+
+// check 检查密钥是否允许通过 JSON-RPC 签名
+func (l keyAllowlist) check(keyID string) error {
+	if l == nil {
+		return nil
+	}
+	if _, ok := l[keyID]; ok {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errKeyNotAllowed, keyID)
+}
+
+// checkDefaultKey 检查签名器当前默认密钥是否允许使用，不允许时返回授权错误响应
+func (l keyAllowlist) checkDefaultKey(h *BaseHandler, s signer.Client, request *internaljsonrpc.Request) *internaljsonrpc.Response {
+	keyID := signerDefaultKeyID(s)
+	if err := l.check(keyID); err != nil {
+		return keyNotAllowedResponse(h, request, keyID, err)
+	}
+	return nil
+}
+
+// signerDefaultKeyID 返回签名器当前的默认密钥 ID，签名器不支持密钥 ID 时返回空字符串
+func signerDefaultKeyID(s signer.Client) string {
+	if p, ok := s.(defaultKeyIDProvider); ok {
+		return p.DefaultKeyID()
+	}
+	return ""
+}
+
+// keyNotAllowedResponse 创建密钥不允许使用的授权错误响应
+func keyNotAllowedResponse(h *BaseHandler, request *internaljsonrpc.Request, keyID string, err error) *internaljsonrpc.Response {
+	h.logger.WithField("method", request.Method).WithField("key_id", keyID).Warn("Rejected signing with key not allowed for JSON-RPC")
+	return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodeKeyNotAllowed, "Key not allowed",
+		ErrorData{ErrorID: ErrorIDKeyNotAllowed, Reason: err.Error()})
+}
+
+// WithAllowedKeyIDs 设置客户端请求可用于签名的密钥 ID，为空表示允许所有已注册的密钥
+//
+// 列表之外的密钥（如仅供内部使用的密钥）仍可注册在签名器中，但不能通过 JSON-RPC 签名
+func (h *SignHandler) WithAllowedKeyIDs(keyIDs []string) *SignHandler {
+	h.allowedKeyIDs = newKeyAllowlist(keyIDs)
+	return h
+}
+
+// defaultKeyID 返回签名器当前的默认密钥 ID，签名器不支持密钥 ID 时返回空字符串
+func (h *SignHandler) defaultKeyID() string {
+	return signerDefaultKeyID(h.signer)
+}
+
+// checkKeyAllowed 检查密钥是否允许通过 JSON-RPC 签名
+func (h *SignHandler) checkKeyAllowed(keyID string) error {
+	return h.allowedKeyIDs.check(keyID)
+}
+
+// keyNotAllowedError 创建密钥不允许使用的授权错误响应
+func (h *SignHandler) keyNotAllowedError(request *internaljsonrpc.Request, keyID string, err error) *internaljsonrpc.Response {
+	return keyNotAllowedResponse(h.BaseHandler, request, keyID, err)
+}
+
+// WithAllowedKeyIDs 设置允许用于 Cosmos 签名的密钥 ID，与 SignHandler 使用同一列表
+func (h *CosmosHandler) WithAllowedKeyIDs(keyIDs []string) *CosmosHandler {
+	h.allowedKeyIDs = newKeyAllowlist(keyIDs)
+	return h
+}
@@ -0,0 +1,76 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+func TestSignHandler_AllowedKeyIDs(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	multiKeySigner := signer.NewMultiKeySigner("internal-key", big.NewInt(1), logger)
+	for keyID, address := range map[string]string{
+		"internal-key": "0x1234567890123456789012345678901234567890",
+		"public-key":   "0x2222222222222222222222222222222222222222",
+	} {
+		if err := multiKeySigner.AddClient(keyID, signer.NewMPCKMSSigner(&testKMSClient{}, keyID, ethgo.HexToAddress(address), big.NewInt(1))); err != nil {
+			t.Fatalf("Failed to add client: %v", err)
+		}
+	}
+
+	handler := createSimpleTestHandler(t)
+	handler.signer = multiKeySigner
+	handler.WithAllowedKeyIDs([]string{"public-key"})
+
+	handle := func(method, params string) *jsonrpc.Response {
+		t.Helper()
+		resp, err := handler.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: method, Params: json.RawMessage(params), ID: 1})
+		if err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+		return resp
+	}
+
+	// 默认密钥不在允许列表中
+	resp := handle("eth_sign", `["0x1234567890123456789012345678901234567890","0xdeadbeef"]`)
+	if resp.Error == nil || resp.Error.Code != jsonrpc.CodeKeyNotAllowed {
+		t.Fatalf("Expected key not allowed error, got %+v", resp.Error)
+	}
+	if data, ok := resp.Error.Data.(ErrorData); !ok || data.ErrorID != ErrorIDKeyNotAllowed {
+		t.Errorf("Expected errorId %s, got %+v", ErrorIDKeyNotAllowed, resp.Error.Data)
+	}
+
+	// eth_accounts 不签名，不受限制
+	if resp := handle("eth_accounts", `[]`); resp.Error != nil {
+		t.Errorf("eth_accounts failed: %+v", resp.Error)
+	}
+
+	resp = handle("web3signer_signBatch", `[[{"keyId":"public-key","message":"0x01"},{"message":"0x02"}]]`)
+	if resp.Error != nil {
+		t.Fatalf("web3signer_signBatch failed: %+v", resp.Error)
+	}
+	var results []signBatchResult
+	if err := json.Unmarshal(resp.Result, &results); err != nil {
+		t.Fatalf("Failed to decode results: %v", err)
+	}
+	if len(results) != 2 || results[0].Error != nil {
+		t.Fatalf("Expected allowed key to sign, got %+v", results)
+	}
+	if results[1].Error == nil || results[1].Error.Code != jsonrpc.CodeKeyNotAllowed || results[1].KeyID != "internal-key" {
+		t.Errorf("Expected default key rejected, got %+v", results[1])
+	}
+
+	// 允许默认密钥后可正常签名
+	handler.WithAllowedKeyIDs(nil)
+	if resp := handle("eth_sign", `["0x1234567890123456789012345678901234567890","0xdeadbeef"]`); resp.Error != nil {
+		t.Errorf("eth_sign failed without allowlist: %+v", resp.Error)
+	}
+}
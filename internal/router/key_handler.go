@@ -0,0 +1,104 @@
+package router
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+// defaultKeySetter 支持运行时切换默认密钥的签名器（如 signer.MultiKeySigner）
+type defaultKeySetter interface {
+	Address() ethgo.Address
+	DefaultKeyID() string
+	SetDefaultKeyID(keyID string) error
+}
+
+// keySnapshotter 能够固定当前默认密钥的签名器（如 signer.MultiKeySigner）
+type keySnapshotter interface {
+	Snapshot() (*signer.KeySnapshot, error)
+}
+
+// pinDefaultKey 返回固定了当前默认密钥的签名器，签名器不支持切换默认密钥时原样返回
+//
+// 处理请求前调用，使发送方校验、nonce 查询与签名在整个请求中使用同一密钥，
+// 不受处理期间 web3signer_setDefaultKey 轮换的影响
+func pinDefaultKey(s signer.Client) signer.Client {
+	snapshotter, ok := s.(keySnapshotter)
+	if !ok {
+		return s
+	}
+	snapshot, err := snapshotter.Snapshot()
+	if err != nil {
+		// 默认密钥不存在时交由签名器在签名时报告错误
+		return s
+	}
+	return snapshot
+}
+
+// KeyHandler 处理签名密钥管理相关的 JSON-RPC 方法
+type KeyHandler struct {
+	*BaseHandler
+	signer defaultKeySetter
+}
+
+// NewKeyHandler 创建密钥管理处理器
+func NewKeyHandler(s defaultKeySetter, logger *logrus.Logger) *KeyHandler {
+	return &KeyHandler{
+		BaseHandler: NewBaseHandler("key", logger),
+		signer:      s,
+	}
+}
+
+// Method 返回处理器支持的方法名
+func (h *KeyHandler) Method() string {
+	return "key_handler"
+}
+
+// setDefaultKeyResult web3signer_setDefaultKey 的返回结果
+type setDefaultKeyResult struct {
+	KeyID         string        `json:"keyId"`
+	PreviousKeyID string        `json:"previousKeyId"`
+	Address       ethgo.Address `json:"address"`
+}
+
+// Handle 处理 JSON-RPC 请求
+func (h *KeyHandler) Handle(_ context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	h.LogRequest(request)
+
+	switch request.Method {
+	case "web3signer_setDefaultKey":
+		return h.handleSetDefaultKey(request)
+	default:
+		return h.CreateErrorResponse(request.ID, jsonrpc.CodeMethodNotFound,
+			"Method not supported by key handler", nil), nil
+	}
+}
+
+// handleSetDefaultKey 处理 web3signer_setDefaultKey 方法
+//
+// 参数为 [keyId]，keyId 须为已注册的密钥；返回新旧默认密钥及新的签名地址
+func (h *KeyHandler) handleSetDefaultKey(request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	params, err := h.ValidateParams(request.Params, 1)
+	if err != nil {
+		return h.CreateInvalidParamsResponse(request.ID, fmt.Sprintf("Invalid parameters: %v", err)), nil
+	}
+	keyID, ok := params[0].(string)
+	if !ok || keyID == "" {
+		return h.CreateInvalidParamsResponse(request.ID, "Invalid parameters: key ID must be a non-empty string"), nil
+	}
+
+	previous := h.signer.DefaultKeyID()
+	if err := h.signer.SetDefaultKeyID(keyID); err != nil {
+		return h.CreateInvalidParamsResponse(request.ID, fmt.Sprintf("Invalid parameters: %v", err)), nil
+	}
+
+	return h.CreateSuccessResponse(request.ID, setDefaultKeyResult{
+		KeyID:         keyID,
+		PreviousKeyID: previous,
+		Address:       h.signer.Address(),
+	})
+}
@@ -0,0 +1,154 @@
+package router
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+func TestKeyHandler_SetDefaultKey(t *testing.T) {
+	multiKeySigner := signer.NewMultiKeySigner("old-key", big.NewInt(1), logrus.New())
+	newAddress := ethgo.HexToAddress("0x2222222222222222222222222222222222222222")
+	for keyID, address := range map[string]string{
+		"old-key": "0x1111111111111111111111111111111111111111",
+		"new-key": newAddress.String(),
+	} {
+		mpcSigner := signer.NewMPCKMSSigner(nil, keyID, ethgo.HexToAddress(address), big.NewInt(1))
+		if err := multiKeySigner.AddClient(keyID, mpcSigner); err != nil {
+			t.Fatalf("Failed to add client: %v", err)
+		}
+	}
+	handler := NewKeyHandler(multiKeySigner, logrus.New())
+
+	tests := []struct {
+		name     string
+		params   string
+		wantCode int
+	}{
+		{name: "unknown key", params: `["missing-key"]`, wantCode: jsonrpc.CodeInvalidParams},
+		{name: "missing key ID", params: `[]`, wantCode: jsonrpc.CodeInvalidParams},
+		{name: "non-string key ID", params: `[1]`, wantCode: jsonrpc.CodeInvalidParams},
+		{name: "registered key", params: `["new-key"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := handler.Handle(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0",
+				Method:  "web3signer_setDefaultKey",
+				Params:  json.RawMessage(tt.params),
+				ID:      1,
+			})
+			if err != nil {
+				t.Fatalf("Handle() error: %v", err)
+			}
+
+			if tt.wantCode != 0 {
+				if response.Error == nil || response.Error.Code != tt.wantCode {
+					t.Fatalf("Expected error code %d, got %+v", tt.wantCode, response.Error)
+				}
+				if multiKeySigner.DefaultKeyID() != "old-key" {
+					t.Errorf("DefaultKeyID() = %s, want old-key", multiKeySigner.DefaultKeyID())
+				}
+				return
+			}
+
+			if response.Error != nil {
+				t.Fatalf("Unexpected error: %+v", response.Error)
+			}
+			var result setDefaultKeyResult
+			if err := json.Unmarshal(response.Result, &result); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if result.KeyID != "new-key" || result.PreviousKeyID != "old-key" || result.Address != newAddress {
+				t.Errorf("result = %+v, want new-key (%s) replacing old-key", result, newAddress)
+			}
+			if multiKeySigner.Address() != newAddress {
+				t.Errorf("Address() = %s, want %s", multiKeySigner.Address(), newAddress)
+			}
+		})
+	}
+}
+
+func TestSignHandler_PinsDefaultKeyPerRequest(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	multiKeySigner := signer.NewMultiKeySigner("key-a", big.NewInt(1), logger)
+	keys := map[string]*wallet.Key{}
+	for _, keyID := range []string{"key-a", "key-b"} {
+		key, err := wallet.GenerateKey()
+		if err != nil {
+			t.Fatalf("GenerateKey() error: %v", err)
+		}
+		keys[keyID] = key
+		if err := multiKeySigner.AddClient(keyID, signer.NewLocalKeystoreSigner(key, big.NewInt(1))); err != nil {
+			t.Fatalf("AddClient() error: %v", err)
+		}
+	}
+
+	// 查询 nonce 时轮换默认密钥，模拟请求处理期间调用 web3signer_setDefaultKey
+	var sentRaw string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpc.Request
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		result := `"0x1"`
+		switch req.Method {
+		case "eth_getTransactionCount":
+			if err := multiKeySigner.SetDefaultKeyID("key-b"); err != nil {
+				t.Errorf("SetDefaultKeyID() error: %v", err)
+			}
+			result = `"0x5"`
+		case "eth_sendRawTransaction":
+			var params []string
+			_ = json.Unmarshal(req.Params, &params)
+			if len(params) == 1 {
+				sentRaw = params[0]
+			}
+			result = `"0x` + strings.Repeat("ab", 32) + `"`
+		}
+		id, _ := json.Marshal(req.ID)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":` + result + `}`))
+	}))
+	defer server.Close()
+
+	handler := NewSignHandler(multiKeySigner, &testDownstreamClient{mockServer: server}, logger)
+	from := keys["key-a"].Address()
+	params := `[{"from":"` + from.String() + `","to":"0x2222222222222222222222222222222222222222","value":"0x1","gas":"0x5208","gasPrice":"0x1"}]`
+	resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0", Method: "eth_sendTransaction", ID: 1, Params: json.RawMessage(params),
+	})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("eth_sendTransaction failed: %+v", resp.Error)
+	}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(sentRaw, "0x"))
+	if err != nil || len(raw) == 0 {
+		t.Fatalf("Failed to decode sent raw transaction %q: %v", sentRaw, err)
+	}
+	signed, err := signer.DecodeRawTransaction(raw)
+	if err != nil {
+		t.Fatalf("DecodeRawTransaction() error: %v", err)
+	}
+	if signed.From != from {
+		t.Errorf("transaction signed by %s, want the key that was default when the request started (%s)", signed.From, from)
+	}
+	if multiKeySigner.DefaultKeyID() != "key-b" {
+		t.Errorf("DefaultKeyID() = %s, want key-b after rotation", multiKeySigner.DefaultKeyID())
+	}
+}
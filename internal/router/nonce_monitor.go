@@ -0,0 +1,131 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// nonceGap 本地已发送的下一个 nonce 与下游 pending nonce 的差值
+	nonceGap = metrics.NewGauge(
+		"web3signer_nonce_gap",
+		"Number of nonces sent by this signer that the downstream node does not account for in its pending nonce.",
+	)
+	// nonceGapSeconds nonce 缺口持续的时间
+	nonceGapSeconds = metrics.NewGauge(
+		"web3signer_nonce_gap_seconds",
+		"How long the current nonce gap has persisted, 0 when there is no gap.",
+	)
+)
+
+func init() {
+	metrics.DefaultRegistry.MustRegister(nonceGap)
+	metrics.DefaultRegistry.MustRegister(nonceGapSeconds)
+}
+
+// nonceMonitor 检测签名地址的 nonce 缺口
+//
+// 记录本地成功广播的最高 nonce，并定期与下游的 pending nonce 比较。
+// 下游 pending nonce 落后于本地说明有已广播的交易不在下游交易池中（被丢弃或从未到达），
+// 后续交易会因此卡住；缺口持续超过阈值时记录告警
+//
+// 检测器只告警，不修改任何 nonce：签名器不在本地分配 nonce，eth_sendTransaction
+// 总是从下游 pending nonce 填充，因此下一笔交易会自动复用缺失的 nonce，无需重置
+type nonceMonitor struct {
+	pendingNonce func() (uint64, error) // 查询下游 pending nonce
+	interval     time.Duration
+	threshold    time.Duration
+	logger       *logrus.Entry
+	now          func() time.Time
+
+	mu        sync.Mutex
+	localNext uint64    // 本地已广播的最高 nonce + 1，0 表示尚未广播
+	gapSince  time.Time // 缺口首次出现的时间，零值表示没有缺口
+	alerted   bool      // 当前缺口是否已告警
+}
+
+// newNonceMonitor 创建 nonce 缺口检测器
+func newNonceMonitor(pendingNonce func() (uint64, error), interval, threshold time.Duration, logger *logrus.Logger) *nonceMonitor {
+	return &nonceMonitor{
+		pendingNonce: pendingNonce,
+		interval:     interval,
+		threshold:    threshold,
+		logger:       logger.WithField("component", "nonce_monitor"),
+		now:          time.Now,
+	}
+}
+
+// observeSent 记录成功广播的交易 nonce
+func (m *nonceMonitor) observeSent(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if nonce+1 > m.localNext {
+		m.localNext = nonce + 1
+	}
+}
+
+// run 按间隔检测缺口，直到 ctx 取消
+func (m *nonceMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check 比较本地与下游 nonce 并更新缺口状态
+func (m *nonceMonitor) check() {
+	m.mu.Lock()
+	localNext := m.localNext
+	m.mu.Unlock()
+	if localNext == 0 {
+		return
+	}
+
+	pending, err := m.pendingNonce()
+	if err != nil {
+		m.logger.WithError(err).Debug("Failed to get pending nonce from downstream")
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if pending >= m.localNext {
+		if !m.gapSince.IsZero() {
+			m.logger.WithField("pending_nonce", pending).Info("Nonce gap resolved")
+		}
+		m.gapSince, m.alerted = time.Time{}, false
+		nonceGap.Set(0)
+		nonceGapSeconds.Set(0)
+		return
+	}
+
+	gap := m.localNext - pending
+	if m.gapSince.IsZero() {
+		m.gapSince = now
+	}
+	age := now.Sub(m.gapSince)
+	nonceGap.Set(float64(gap))
+	nonceGapSeconds.Set(age.Seconds())
+
+	if age >= m.threshold && !m.alerted {
+		m.alerted = true
+		m.logger.WithFields(logrus.Fields{
+			"local_next_nonce": m.localNext,
+			"pending_nonce":    pending,
+			"gap":              gap,
+			"duration":         age.String(),
+		}).Warn("Nonce gap persists: transactions sent by this signer are missing from the downstream mempool")
+	}
+}
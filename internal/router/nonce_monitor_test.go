@@ -0,0 +1,81 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestNonceMonitor_Check(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.InfoLevel)
+
+	var pending uint64
+	var pendingErr error
+	now := time.Unix(1000, 0)
+	m := newNonceMonitor(func() (uint64, error) { return pending, pendingErr }, time.Second, time.Minute, logger)
+	m.now = func() time.Time { return now }
+
+	// 尚未广播任何交易时不检测
+	m.check()
+	if nonceGap.Value() != 0 {
+		t.Fatalf("nonce gap = %v before any send, want 0", nonceGap.Value())
+	}
+
+	m.observeSent(5)
+	m.observeSent(3)
+	pending = 4
+	m.check()
+	if nonceGap.Value() != 2 {
+		t.Errorf("nonce gap = %v, want 2", nonceGap.Value())
+	}
+	if m.alerted {
+		t.Error("Expected no alert before the threshold")
+	}
+
+	// 下游查询失败时保持当前状态
+	pendingErr = errors.New("connection refused")
+	now = now.Add(2 * time.Minute)
+	m.check()
+	if m.alerted {
+		t.Error("Expected no alert when downstream is unreachable")
+	}
+
+	pendingErr = nil
+	m.check()
+	if !m.alerted || nonceGapSeconds.Value() != 120 {
+		t.Errorf("alerted = %v, gap seconds = %v, want alert after 120s", m.alerted, nonceGapSeconds.Value())
+	}
+	if entry := hook.LastEntry(); entry == nil || entry.Level != logrus.WarnLevel {
+		t.Errorf("Expected a warning for a persistent gap, got %+v", entry)
+	}
+
+	pending = 6
+	m.check()
+	if nonceGap.Value() != 0 || nonceGapSeconds.Value() != 0 || m.alerted {
+		t.Errorf("Expected gap to be resolved, gap = %v, seconds = %v", nonceGap.Value(), nonceGapSeconds.Value())
+	}
+}
+
+func TestRouter_BackgroundTasks(t *testing.T) {
+	router := NewRouter(logrus.New())
+	stopped := make(chan struct{})
+	router.AddBackgroundTask(func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	router.StartBackgroundTasks(ctx)
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("background task did not stop after cancellation")
+	}
+}
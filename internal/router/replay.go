@@ -0,0 +1,31 @@
+package router
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/umbracle/ethgo"
+)
+
+// replayCache 最近转发的交易的 LRU 缓存，按签名地址与签名前的交易哈希索引
+//
+// 相同的交易在缓存窗口内再次提交时直接返回原交易哈希，不再签名和转发到下游。
+// KMS 签名不是确定性的，因此缓存键不能使用已签名交易的哈希
+type replayCache struct {
+	lru *ttlLRU[ethgo.Hash, json.RawMessage]
+}
+
+// newReplayCache 创建重放缓存
+func newReplayCache(ttl time.Duration, maxSize int) *replayCache {
+	return &replayCache{lru: newTTLLRU[ethgo.Hash, json.RawMessage](ttl, maxSize)}
+}
+
+// lookup 返回缓存窗口内已转发交易的结果
+func (c *replayCache) lookup(hash ethgo.Hash) (json.RawMessage, bool) {
+	return c.lru.get(hash)
+}
+
+// add 记录成功转发的交易；缓存已满时淘汰最久未使用的条目
+func (c *replayCache) add(hash ethgo.Hash, result json.RawMessage) {
+	c.lru.add(hash, result)
+}
@@ -0,0 +1,87 @@
+package router
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+// varyingKMSClient 每次调用返回不同签名的 KMS 客户端，模拟非确定性的 KMS 签名
+type varyingKMSClient struct {
+	testKMSClient
+	calls atomic.Int32
+}
+
+func (c *varyingKMSClient) Sign(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+	signature := make([]byte, 65)
+	for i := 0; i < 64; i++ {
+		signature[i] = byte(i + 1)
+	}
+	signature[0] = byte(c.calls.Add(1))
+	return []byte(hex.EncodeToString(signature)), nil
+}
+
+func (c *varyingKMSClient) SignWithOptions(ctx context.Context, keyID string, message []byte, encoding kms.DataEncoding, summary *kms.SignSummary, callbackURL string) ([]byte, error) {
+	return c.Sign(ctx, keyID, message)
+}
+
+func TestSignHandler_ReplayedSendTransaction(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	testAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	send := func(t *testing.T, cfg config.TransactionConfig) int32 {
+		t.Helper()
+		// 同一交易每次签名得到不同的已签名交易，重放检测须基于签名前的哈希
+		mpcSigner := signer.NewMPCKMSSigner(&varyingKMSClient{}, "test-key-id", testAddress, big.NewInt(1))
+		downstream := &countingDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+		defer func() { _ = downstream.Close() }()
+
+		router := NewRouterFactory(logger).WithTransactionConfig(cfg).CreateRouter(mpcSigner, downstream)
+
+		var results []string
+		for i := 0; i < 2; i++ {
+			body := `{"jsonrpc":"2.0","id":1,"method":"eth_sendTransaction","params":[{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x4a817c800"}]}`
+			w := httptest.NewRecorder()
+			router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+			var resp jsonrpc.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v, body: %s", err, w.Body.String())
+			}
+			if resp.Error != nil {
+				t.Fatalf("Unexpected error: %+v", resp.Error)
+			}
+			results = append(results, string(resp.Result))
+		}
+		if results[0] != results[1] {
+			t.Errorf("Expected the original hash %s, got %s", results[0], results[1])
+		}
+		return atomic.LoadInt32(&downstream.sends)
+	}
+
+	t.Run("replay detection enabled", func(t *testing.T) {
+		if got := send(t, config.TransactionConfig{ReplayTTLSeconds: 60, ReplayCacheSize: 100}); got != 1 {
+			t.Errorf("Expected 1 broadcast for replayed transaction, got %d", got)
+		}
+	})
+
+	t.Run("replay detection disabled", func(t *testing.T) {
+		if got := send(t, config.TransactionConfig{}); got != 2 {
+			t.Errorf("Expected every submission to be broadcast, got %d", got)
+		}
+	})
+}
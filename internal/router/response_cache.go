@@ -0,0 +1,87 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// cacheableMethods 返回不可变数据、可以缓存下游响应的方法
+//
+// 列表中的方法都按哈希或固定值查询，不接受 latest/pending 等区块标签
+var cacheableMethods = map[string]bool{
+	"eth_chainId":               true,
+	"eth_getBlockByHash":        true,
+	"eth_getTransactionReceipt": true,
+}
+
+// responseCache 不可变查询方法下游结果（按方法和参数）的 LRU 缓存
+type responseCache struct {
+	lru *ttlLRU[string, json.RawMessage]
+}
+
+// newResponseCache 创建下游响应缓存
+func newResponseCache(ttl time.Duration, maxSize int) *responseCache {
+	return &responseCache{lru: newTTLLRU[string, json.RawMessage](ttl, maxSize)}
+}
+
+// responseCacheKey 返回请求的缓存键，请求不可缓存时返回空字符串
+func responseCacheKey(method string, params json.RawMessage) string {
+	if !cacheableMethods[method] {
+		return ""
+	}
+	// 防御性检查：参数中出现区块标签说明查询结果可变，不缓存
+	var args []interface{}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &args); err != nil {
+			return ""
+		}
+	}
+	for _, arg := range args {
+		switch arg {
+		case "latest", "pending", "safe", "finalized", "earliest":
+			return ""
+		}
+	}
+
+	var compact bytes.Buffer
+	if len(params) > 0 {
+		if err := json.Compact(&compact, params); err != nil {
+			return ""
+		}
+	}
+	return method + ":" + compact.String()
+}
+
+// isImmutableResult 判断下游结果是否可以缓存
+//
+// null 结果（未找到或尚未打包）不缓存；未打包的交易收据 blockHash 为空，也不缓存
+func isImmutableResult(method string, result json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(result)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return false
+	}
+	if method == "eth_getTransactionReceipt" {
+		var receipt struct {
+			BlockHash *string `json:"blockHash"`
+		}
+		if err := json.Unmarshal(trimmed, &receipt); err != nil || receipt.BlockHash == nil || *receipt.BlockHash == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup 返回缓存窗口内的结果副本
+func (c *responseCache) lookup(key string) (json.RawMessage, bool) {
+	result, ok := c.lru.get(key)
+	if !ok {
+		return nil, false
+	}
+	return append(json.RawMessage(nil), result...), true
+}
+
+// add 记录下游结果；缓存已满时淘汰最久未使用的条目
+func (c *responseCache) add(key string, result json.RawMessage) {
+	c.lru.add(key, append(json.RawMessage(nil), result...))
+}
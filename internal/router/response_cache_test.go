@@ -0,0 +1,194 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/sirupsen/logrus"
+)
+
+func TestResponseCacheKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		params    string
+		cacheable bool
+	}{
+		{name: "chain id", method: "eth_chainId", params: `[]`, cacheable: true},
+		{name: "block by hash", method: "eth_getBlockByHash", params: `["0xabc", false]`, cacheable: true},
+		{name: "receipt", method: "eth_getTransactionReceipt", params: `["0xabc"]`, cacheable: true},
+		{name: "block by number", method: "eth_getBlockByNumber", params: `["0x1", false]`},
+		{name: "latest balance", method: "eth_getBalance", params: `["0xabc", "latest"]`},
+		{name: "block tag param", method: "eth_getBlockByHash", params: `["pending", false]`},
+		{name: "safe tag param", method: "eth_getTransactionReceipt", params: `["safe"]`},
+		{name: "finalized tag param", method: "eth_getBlockByHash", params: `["finalized", true]`},
+		{name: "invalid params", method: "eth_getBlockByHash", params: `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := responseCacheKey(tt.method, json.RawMessage(tt.params))
+			if (key != "") != tt.cacheable {
+				t.Errorf("responseCacheKey() = %q, want cacheable %v", key, tt.cacheable)
+			}
+		})
+	}
+
+	// 参数格式不同但语义相同的请求命中同一条目
+	if responseCacheKey("eth_getBlockByHash", json.RawMessage(`["0xabc",false]`)) !=
+		responseCacheKey("eth_getBlockByHash", json.RawMessage(`[ "0xabc", false ]`)) {
+		t.Error("Expected whitespace-insensitive cache keys")
+	}
+}
+
+func TestIsImmutableResult(t *testing.T) {
+	tests := []struct {
+		method string
+		result string
+		want   bool
+	}{
+		{method: "eth_chainId", result: `"0x1"`, want: true},
+		{method: "eth_getBlockByHash", result: `null`},
+		{method: "eth_getTransactionReceipt", result: `{"blockHash":"0xabc","status":"0x1"}`, want: true},
+		{method: "eth_getTransactionReceipt", result: `{"blockHash":null,"status":"0x1"}`},
+		{method: "eth_getTransactionReceipt", result: `null`},
+	}
+
+	for _, tt := range tests {
+		if got := isImmutableResult(tt.method, json.RawMessage(tt.result)); got != tt.want {
+			t.Errorf("isImmutableResult(%s, %s) = %v, want %v", tt.method, tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestResponseCache_ReturnsCopy(t *testing.T) {
+	cache := newResponseCache(time.Minute, 1)
+
+	cache.add("a", json.RawMessage(`"0x1"`))
+	result, ok := cache.lookup("a")
+	if !ok || string(result) != `"0x1"` {
+		t.Fatalf("lookup a: got (%s, %v)", result, ok)
+	}
+
+	// 返回副本，调用方修改不影响缓存
+	result[1] = 'x'
+	if again, _ := cache.lookup("a"); string(again) != `"0x1"` {
+		t.Errorf("cached result was mutated: %s", again)
+	}
+}
+
+// receiptDownstreamClient 统计下游请求次数，收据在第一次查询时尚未打包
+type receiptDownstreamClient struct {
+	*testDownstreamClient
+	calls int32
+}
+
+func (c *receiptDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	n := atomic.AddInt32(&c.calls, 1)
+	switch req.Method {
+	case "eth_getTransactionReceipt":
+		if n == 1 {
+			return &jsonrpc.Response{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`null`)}, nil
+		}
+		return &jsonrpc.Response{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"blockHash":"0xabc","status":"0x1"}`)}, nil
+	case "eth_chainId":
+		return &jsonrpc.Response{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`"0x1"`)}, nil
+	}
+	return c.testDownstreamClient.ForwardRequest(ctx, req)
+}
+
+func TestForwardHandler_ResponseCache(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	downstream := &receiptDownstreamClient{testDownstreamClient: &testDownstreamClient{}}
+	handler := NewForwardHandler(downstream, logger).WithResponseCache(time.Minute, 10)
+
+	call := func(method, params string, id int) *jsonrpc.Response {
+		t.Helper()
+		resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+			JSONRPC: "2.0", Method: method, ID: id, Params: json.RawMessage(params),
+		})
+		if err != nil || resp.Error != nil {
+			t.Fatalf("%s failed: %v %+v", method, err, resp)
+		}
+		return resp
+	}
+
+	// 未打包的收据（null）不缓存，打包后缓存
+	for i := 1; i <= 3; i++ {
+		call("eth_getTransactionReceipt", `["0x01"]`, i)
+	}
+	if calls := atomic.LoadInt32(&downstream.calls); calls != 2 {
+		t.Errorf("downstream calls = %d, want 2", calls)
+	}
+
+	resp := call("eth_chainId", `[]`, 7)
+	resp = call("eth_chainId", `[]`, 8)
+	if string(resp.Result) != `"0x1"` || resp.ID != 8 {
+		t.Errorf("cached response = %s (id %v), want \"0x1\" with request id", resp.Result, resp.ID)
+	}
+	if calls := atomic.LoadInt32(&downstream.calls); calls != 3 {
+		t.Errorf("downstream calls = %d, want 3", calls)
+	}
+
+	// 非白名单方法每次都转发
+	call("eth_blockNumber", `[]`, 9)
+	call("eth_blockNumber", `[]`, 10)
+	if calls := atomic.LoadInt32(&downstream.calls); calls != 5 {
+		t.Errorf("downstream calls = %d, want 5", calls)
+	}
+}
+
+// paramsCapturingDownstreamClient 记录转发请求的原始参数
+type paramsCapturingDownstreamClient struct {
+	*testDownstreamClient
+	params []string
+}
+
+func (c *paramsCapturingDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	c.params = append(c.params, string(req.Params))
+	return c.testDownstreamClient.ForwardRequest(ctx, req)
+}
+
+func TestForwardHandler_BlockTagsForwardedVerbatim(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	downstream := &paramsCapturingDownstreamClient{testDownstreamClient: &testDownstreamClient{}}
+	handler := NewForwardHandler(downstream, logger).WithResponseCache(time.Minute, 10)
+
+	requests := []struct {
+		method string
+		params string
+	}{
+		{method: "eth_getBalance", params: `["0x0000000000000000000000000000000000000001","safe"]`},
+		{method: "eth_getBlockByNumber", params: `["finalized",false]`},
+		{method: "eth_getTransactionCount", params: `["0x0000000000000000000000000000000000000001", "earliest"]`},
+		{method: "eth_getBlockByHash", params: `["finalized",false]`},
+		{method: "eth_getBlockByHash", params: `["finalized",false]`},
+	}
+
+	for i, req := range requests {
+		resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+			JSONRPC: "2.0", Method: req.method, ID: i, Params: json.RawMessage(req.params),
+		})
+		if err != nil || resp.Error != nil {
+			t.Fatalf("%s failed: %v %+v", req.method, err, resp)
+		}
+	}
+
+	// 参数逐字节转发；带区块标签的请求不命中缓存
+	if len(downstream.params) != len(requests) {
+		t.Fatalf("downstream calls = %d, want %d", len(downstream.params), len(requests))
+	}
+	for i, req := range requests {
+		if downstream.params[i] != req.params {
+			t.Errorf("forwarded params = %s, want %s", downstream.params[i], req.params)
+		}
+	}
+}
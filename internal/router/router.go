@@ -1,11 +1,21 @@
 package router
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/mowind/web3signer-go/internal/jsonrpc"
 	"github.com/sirupsen/logrus"
@@ -47,6 +57,24 @@ type Router struct {
 	mu             sync.RWMutex
 	logger         *logrus.Logger
 	maxRequestSize int64 // 最大请求体大小（字节）
+
+	parseOptions jsonrpc.ParseOptions // 请求解析选项（如是否允许缺省 jsonrpc 版本）
+
+	compressionThreshold int64 // 响应超过该大小（字节）且客户端支持时使用 gzip 压缩，0 表示不压缩
+	streamBatchThreshold int   // 批量响应条数达到该值时逐条流式写出，0 表示不流式写出
+
+	requestTimeout     time.Duration            // 非签名方法的处理超时，0 表示不限制
+	signRequestTimeout time.Duration            // 签名方法（可能等待审批）的处理超时，0 表示不限制
+	methodTimeouts     map[string]time.Duration // 按方法（小写）覆盖的处理超时
+
+	batchWorkerCount int           // 单个批量请求的最大 worker 数，0 表示使用 DefaultBatchWorkerCount
+	batchQueueSize   int           // 批量任务通道缓冲大小，0 表示与批量大小相同
+	batchSlots       chan struct{} // 所有并发批量请求共享的 worker 信号量，nil 表示不限制
+
+	slowRequestThreshold time.Duration  // 处理耗时超过该值时记录慢请求日志，0 表示不记录
+	slowLogger           *logrus.Logger // 慢请求日志记录器，不受全局日志级别限制
+
+	backgroundTasks []func(ctx context.Context) // 随服务器启动的后台任务
 }
 
 // NewRouter creates a new JSON-RPC router with default settings.
@@ -109,6 +137,177 @@ func NewRouterWithContextAndMaxSize(logger *logrus.Entry, maxRequestSize int64)
 	}
 }
 
+// SetCompressionThreshold enables gzip compression for large responses.
+//
+// Parameters:
+//   - threshold: Minimum response size in bytes to compress; 0 disables compression
+func (r *Router) SetCompressionThreshold(threshold int64) {
+	r.compressionThreshold = threshold
+}
+
+// SetAllowMissingJSONRPCVersion accepts requests that omit the jsonrpc field.
+//
+// Such requests, typically from legacy clients, are treated as JSON-RPC 2.0.
+// Requests that explicitly send another version are still rejected.
+//
+// Parameters:
+//   - allow: Whether to accept requests without a jsonrpc field
+func (r *Router) SetAllowMissingJSONRPCVersion(allow bool) {
+	r.parseOptions.AllowMissingVersion = allow
+}
+
+// SetStreamBatchThreshold enables streaming of large batch responses.
+//
+// Batches producing at least threshold responses are routed on the batch
+// worker pool, and responses are written and flushed to the connection in
+// request order as they complete instead of waiting for the whole batch.
+// Streamed batches are not forwarded to downstream as a single batch request.
+//
+// Parameters:
+//   - threshold: Minimum number of responses to stream; 0 disables streaming
+func (r *Router) SetStreamBatchThreshold(threshold int) {
+	r.streamBatchThreshold = threshold
+}
+
+// SetSlowRequestThreshold enables warn-level logging of slow requests.
+//
+// Slow requests are logged even when the router's log level is above warn,
+// so latency outliers surface without enabling verbose logging.
+//
+// Parameters:
+//   - threshold: Minimum handling duration to log; 0 disables slow request logging
+func (r *Router) SetSlowRequestThreshold(threshold time.Duration) {
+	r.slowRequestThreshold = threshold
+	if threshold <= 0 {
+		r.slowLogger = nil
+		return
+	}
+
+	// 共享输出、格式和 hook，但使用独立的级别
+	slowLogger := logrus.New()
+	slowLogger.SetOutput(r.logger.Out)
+	slowLogger.SetFormatter(r.logger.Formatter)
+	slowLogger.ReplaceHooks(r.logger.Hooks)
+	slowLogger.SetLevel(logrus.WarnLevel)
+	r.slowLogger = slowLogger
+}
+
+// logIfSlow logs the request at warn level when it took longer than the slow request threshold.
+func (r *Router) logIfSlow(request *jsonrpc.Request, logger *logrus.Entry, start time.Time) {
+	if r.slowLogger == nil {
+		return
+	}
+	duration := time.Since(start)
+	if duration < r.slowRequestThreshold {
+		return
+	}
+	r.slowLogger.WithFields(logger.Data).WithFields(logrus.Fields{
+		"method":       request.Method,
+		"id":           request.ID,
+		"duration_ms":  duration.Milliseconds(),
+		"threshold_ms": r.slowRequestThreshold.Milliseconds(),
+	}).Warn("Slow request")
+}
+
+// SetBatchWorkers configures the worker pool used by RouteBatch for a single batch.
+//
+// Parameters:
+//   - workers: Maximum workers per batch; 0 uses DefaultBatchWorkerCount
+//   - queueSize: Task channel buffer size; 0 buffers the whole batch
+func (r *Router) SetBatchWorkers(workers, queueSize int) {
+	r.batchWorkerCount = workers
+	r.batchQueueSize = queueSize
+}
+
+// SetMaxBatchWorkers caps the total number of batch workers across all
+// concurrent RouteBatch calls.
+//
+// Workers beyond the cap wait for a slot, so concurrent batches share the
+// limit instead of each spawning a full worker pool.
+//
+// Parameters:
+//   - max: Maximum concurrent batch workers; 0 disables the cap
+func (r *Router) SetMaxBatchWorkers(max int) {
+	if max <= 0 {
+		r.batchSlots = nil
+		return
+	}
+	r.batchSlots = make(chan struct{}, max)
+}
+
+// acquireBatchSlot waits for a shared batch worker slot.
+//
+// It returns false if ctx is done before a slot becomes available.
+func (r *Router) acquireBatchSlot(ctx context.Context) bool {
+	if r.batchSlots == nil {
+		return true
+	}
+	select {
+	case r.batchSlots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// releaseBatchSlot returns a slot taken by acquireBatchSlot.
+func (r *Router) releaseBatchSlot() {
+	if r.batchSlots != nil {
+		<-r.batchSlots
+	}
+}
+
+// SetRequestTimeouts bounds how long a handler may run for a single request.
+//
+// Sign methods may wait for KMS approval, so they use a separate timeout.
+// When a timeout elapses the client receives a RequestTimeoutError.
+//
+// Parameters:
+//   - request: Timeout for non-sign methods; 0 disables it
+//   - sign: Timeout for sign methods; 0 disables it
+func (r *Router) SetRequestTimeouts(request, sign time.Duration) {
+	r.requestTimeout = request
+	r.signRequestTimeout = sign
+}
+
+// SetMethodTimeouts overrides the request timeout for individual methods.
+//
+// Methods not listed keep the timeouts set by SetRequestTimeouts. Method names
+// are matched case-insensitively, since configuration keys may be lowercased.
+//
+// Parameters:
+//   - timeouts: Timeout per method name; 0 disables the timeout for that method
+func (r *Router) SetMethodTimeouts(timeouts map[string]time.Duration) {
+	r.methodTimeouts = make(map[string]time.Duration, len(timeouts))
+	for method, timeout := range timeouts {
+		r.methodTimeouts[strings.ToLower(method)] = timeout
+	}
+}
+
+// AddBackgroundTask registers a task started by StartBackgroundTasks.
+//
+// Parameters:
+//   - task: Long-running function that must return when ctx is cancelled
+func (r *Router) AddBackgroundTask(task func(ctx context.Context)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.backgroundTasks = append(r.backgroundTasks, task)
+}
+
+// StartBackgroundTasks starts all registered background tasks in their own goroutines.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the tasks
+func (r *Router) StartBackgroundTasks(ctx context.Context) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, task := range r.backgroundTasks {
+		go task(ctx)
+	}
+}
+
 // SetDefaultHandler sets the default handler for unregistered methods.
 //
 // This handler is called when a method is not registered.
@@ -150,6 +349,38 @@ func (r *Router) Register(handler Handler) error {
 	return nil
 }
 
+// RegisterOrReplace registers a handler, replacing any handler already
+// registered for the same method.
+//
+// The swap happens under the router lock, so concurrent requests see either
+// the old or the new handler and never a missing one, unlike Unregister
+// followed by Register.
+//
+// Parameters:
+//   - handler: The handler to register
+//
+// Returns:
+//   - Handler: The replaced handler, or nil if the method was not registered
+//   - error: An error if handler method is empty
+func (r *Router) RegisterOrReplace(handler Handler) (Handler, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	method := handler.Method()
+	if method == "" {
+		return nil, fmt.Errorf("handler method name cannot be empty")
+	}
+
+	previous, exists := r.handlers[method]
+	r.handlers[method] = handler
+	if exists {
+		r.logger.WithField("method", method).Info("Replaced JSON-RPC handler")
+	} else {
+		r.logger.WithField("method", method).Info("Registered JSON-RPC handler")
+	}
+	return previous, nil
+}
+
 // Unregister removes a handler for the specified method.
 //
 // Parameters:
@@ -194,7 +425,13 @@ func (r *Router) routeRequest(ctx context.Context, request *jsonrpc.Request, log
 		}
 	}
 
-	response, err := handler.Handle(ctx, request)
+	start := time.Now()
+	response, err := r.handleWithTimeout(ctx, handler, request)
+	r.logIfSlow(request, logger, start)
+	if errors.Is(err, context.DeadlineExceeded) {
+		logger.Warn("Request handling timed out")
+		return jsonrpc.NewErrorResponse(request.ID, jsonrpc.RequestTimeoutError)
+	}
 	if err != nil {
 		logger.WithError(err).Error("Handler execution failed")
 
@@ -221,6 +458,85 @@ func (r *Router) routeRequest(ctx context.Context, request *jsonrpc.Request, log
 	return response
 }
 
+// untimedMethods 签名后会广播或返回可广播交易的方法（小写）
+//
+// 处理超时后后台仍会继续签名和发送，客户端收到失败后重试会重复发送交易，因此这些方法不受处理超时限制
+var untimedMethods = map[string]bool{
+	"eth_sendtransaction":           true,
+	"web3signer_signrawtransaction": true,
+}
+
+// timeoutFor 返回方法的处理超时，0 表示不限制
+func (r *Router) timeoutFor(method string) time.Duration {
+	if untimedMethods[strings.ToLower(method)] {
+		return 0
+	}
+	if t, ok := r.methodTimeouts[strings.ToLower(method)]; ok {
+		return t
+	}
+	if IsSignMethod(method) || IsCosmosSignMethod(method) {
+		return r.signRequestTimeout
+	}
+	return r.requestTimeout
+}
+
+// handleWithTimeout runs the handler bounded by the router's request timeout.
+//
+// The handler runs in its own goroutine so that a handler ignoring its context
+// cannot hold the request past the deadline. Panics in that goroutine are
+// converted to errors, since they cannot be recovered by the HTTP handler.
+//
+// Parameters:
+//   - ctx: Request context
+//   - handler: The handler to run
+//   - request: The JSON-RPC request
+//
+// Returns:
+//   - *jsonrpc.Response: The handler response
+//   - error: The handler error, or context.DeadlineExceeded on timeout
+func (r *Router) handleWithTimeout(ctx context.Context, handler Handler, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	timeout := r.timeoutFor(request.Method)
+	if timeout <= 0 {
+		return handler.Handle(ctx, request)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		response *jsonrpc.Response
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				r.logger.WithFields(logrus.Fields{
+					"method": request.Method,
+					"panic":  p,
+					"stack":  string(debug.Stack()),
+				}).Error("Handler panic recovered")
+				done <- result{err: fmt.Errorf("handler panic: %v", p)}
+			}
+		}()
+		response, err := handler.Handle(ctx, request)
+		done <- result{response: response, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, context.DeadlineExceeded
+		}
+		return res.response, res.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, context.DeadlineExceeded
+		}
+		return nil, ctx.Err()
+	}
+}
+
 // RouteWithContext routes a single request using the provided logger entry.
 //
 // This is useful for maintaining log context across request lifecycle.
@@ -295,13 +611,47 @@ func (r *Router) RouteBatch(ctx context.Context, requests []jsonrpc.Request) []*
 		"count": len(requests),
 	}).Info("Routing batch requests")
 
-	// Create responses array
+	// Create responses array; workers write distinct indices
 	responses := make([]*jsonrpc.Response, len(requests))
+	r.routeConcurrently(ctx, requests, r.Route, func(idx int, resp *jsonrpc.Response) {
+		responses[idx] = resp
+	})
+
+	// 上下文取消时未处理的请求返回错误，而不是空响应
+	for idx := range responses {
+		if responses[idx] == nil {
+			responses[idx] = jsonrpc.NewErrorResponse(
+				requests[idx].ID,
+				jsonrpc.NewServerError(-32603, "Internal error", "Request cancelled"),
+			)
+		}
+	}
 
+	r.logger.WithFields(logrus.Fields{
+		"request_count":  len(requests),
+		"response_count": len(responses),
+	}).Info("Batch routing completed")
+	return responses
+}
+
+// routeConcurrently routes requests on the batch worker pool.
+//
+// done is called from the worker goroutines once for each routed request, as
+// soon as its response is ready. Requests left unrouted because ctx was
+// cancelled are not reported. routeConcurrently returns after all workers
+// have exited.
+func (r *Router) routeConcurrently(ctx context.Context, requests []jsonrpc.Request, route func(context.Context, *jsonrpc.Request) *jsonrpc.Response, done func(idx int, resp *jsonrpc.Response)) {
 	taskCount := len(requests)
-	taskCh := make(chan int, taskCount)
+	queueSize := taskCount
+	if r.batchQueueSize > 0 && r.batchQueueSize < queueSize {
+		queueSize = r.batchQueueSize
+	}
+	taskCh := make(chan int, queueSize)
 
 	workerCount := DefaultBatchWorkerCount
+	if r.batchWorkerCount > 0 {
+		workerCount = r.batchWorkerCount
+	}
 	if taskCount < workerCount {
 		workerCount = taskCount
 	}
@@ -314,52 +664,56 @@ func (r *Router) RouteBatch(ctx context.Context, requests []jsonrpc.Request) []*
 		defer wg.Done()
 		defer close(taskCh)
 		for i := 0; i < taskCount; i++ {
-			taskCh <- i
+			select {
+			case taskCh <- i:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 
-	// Start workers (fan-out)
+	// Start workers (fan-out), each holding a shared slot while it runs
 	for i := 0; i < workerCount; i++ {
+		if !r.acquireBatchSlot(ctx) {
+			break
+		}
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
+			defer r.releaseBatchSlot()
 
 			for idx := range taskCh {
 				if ctx.Err() != nil {
 					break
 				}
 
+				var resp *jsonrpc.Response
 				func() {
 					defer func() {
 						if p := recover(); p != nil {
 							r.logger.WithField("worker_id", workerID).WithField("panic", p).Error("Worker panic recovered")
-							responses[idx] = jsonrpc.NewErrorResponse(
+							resp = jsonrpc.NewErrorResponse(
 								requests[idx].ID,
 								jsonrpc.NewServerError(-32603, "Internal error", "Processing failed"),
 							)
 						}
 					}()
 
-					responses[idx] = r.Route(ctx, &requests[idx])
-					if responses[idx] == nil {
+					resp = route(ctx, &requests[idx])
+					if resp == nil {
 						r.logger.WithField("worker_id", workerID).WithField("idx", idx).Warn("Route returned nil response, setting error")
-						responses[idx] = jsonrpc.NewErrorResponse(
+						resp = jsonrpc.NewErrorResponse(
 							requests[idx].ID,
 							jsonrpc.NewServerError(-32603, "Internal error", "Processing failed"),
 						)
 					}
 				}()
+				done(idx, resp)
 			}
 		}(i)
 	}
 
 	wg.Wait()
-
-	r.logger.WithFields(logrus.Fields{
-		"request_count":  taskCount,
-		"response_count": len(responses),
-	}).Info("Batch routing completed")
-	return responses
 }
 
 // getHandler retrieves a registered handler for the given method name.
@@ -396,6 +750,66 @@ func (r *Router) GetRegisteredMethods() []string {
 	return methods
 }
 
+// LogRoutingTable logs the effective routing table at info level.
+//
+// One entry is logged per registered method with the handler serving it,
+// whether it is a sign method and its timeout, followed by an entry for the
+// default handler and, when it forwards, the downstream endpoint.
+func (r *Router) LogRoutingTable() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	methods := make([]string, 0, len(r.handlers))
+	for method := range r.handlers {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		r.logger.WithFields(logrus.Fields{
+			"method":      method,
+			"handler":     handlerName(r.handlers[method]),
+			"sign_method": IsSignMethod(method) || IsCosmosSignMethod(method),
+			"timeout":     r.timeoutFor(method).String(),
+		}).Info("Route: local handler")
+	}
+
+	fields := logrus.Fields{"registered_methods": len(methods), "handler": "none"}
+	if r.defaultHandler != nil {
+		fields["handler"] = handlerName(r.defaultHandler)
+		if endpoint := forwardEndpoint(r.defaultHandler); endpoint != "" {
+			fields["downstream_endpoint"] = endpoint
+		}
+	}
+	r.logger.WithFields(fields).Info("Route: default handler for all other methods")
+}
+
+// unwrapHandler 返回 MethodHandler 包装的处理器，其他处理器原样返回
+func unwrapHandler(handler Handler) Handler {
+	if m, ok := handler.(*MethodHandler); ok {
+		return m.handler
+	}
+	return handler
+}
+
+// handlerName 返回处理器的类型名，MethodHandler 返回被包装处理器的类型名
+func handlerName(handler Handler) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", unwrapHandler(handler)), "*router.")
+}
+
+// forwardEndpoint 返回转发处理器的下游端点（隐藏 URL 中的密码），非转发处理器返回空字符串
+func forwardEndpoint(handler Handler) string {
+	forward, ok := unwrapHandler(handler).(*ForwardHandler)
+	if !ok {
+		return ""
+	}
+	endpoint := forward.client.GetEndpoint()
+	if u, err := url.Parse(endpoint); err == nil {
+		return u.Redacted()
+	}
+	return endpoint
+}
+
 // HasHandler checks if a handler is registered for the given method.
 //
 // Parameters:
@@ -411,6 +825,10 @@ func (r *Router) HasHandler(method string) bool {
 // parseAndRoute parses the request body and routes requests to handlers.
 //
 // This is a helper method used by HandleHTTPRequestWithContext.
+// Malformed entries in a batch are answered with individual InvalidRequest
+// errors while the valid entries are still routed, preserving request order.
+// Notifications (requests without an id) are executed but produce no response;
+// if every entry is a notification the reply is 204 No Content.
 //
 // Parameters:
 //   - w: HTTP response writer
@@ -418,79 +836,367 @@ func (r *Router) HasHandler(method string) bool {
 //   - logger: Logger entry for tracing
 //   - body: The request body content
 func (r *Router) parseAndRoute(w http.ResponseWriter, req *http.Request, logger *logrus.Entry, body []byte) {
-	requests, err := jsonrpc.ParseRequest(body)
+	entries, err := jsonrpc.ParseBatchLenientWithOptions(body, r.parseOptions)
 	if err != nil {
 		logger.WithError(err).Warn("Failed to parse JSON-RPC request")
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		resp := jsonrpc.NewErrorResponse(nil, jsonrpc.ParseError)
-		data, _ := jsonrpc.MarshalResponse(resp)
-		if _, err := w.Write(data); err != nil {
-			logger.WithError(err).Error("Failed to write error response")
+		if info := accessInfoFromContext(req.Context()); info != nil {
+			info.record(nil, 1)
 		}
+		r.writeResponses(w, req, logger, []*jsonrpc.Response{jsonrpc.NewErrorResponse(nil, jsonrpc.ParseError)})
 		return
 	}
 
-	if len(requests) > MaxBatchSize {
-		logger.WithField("count", len(requests)).Warn("Batch size exceeds limit")
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		resp := jsonrpc.NewErrorResponse(nil, jsonrpc.NewServerError(
+	if len(entries) > MaxBatchSize {
+		logger.WithField("count", len(entries)).Warn("Batch size exceeds limit")
+		if info := accessInfoFromContext(req.Context()); info != nil {
+			info.record(nil, 1)
+		}
+		r.writeResponses(w, req, logger, []*jsonrpc.Response{jsonrpc.NewErrorResponse(nil, jsonrpc.NewServerError(
 			-32602, "Invalid params", fmt.Sprintf("Batch size exceeds maximum limit of %d", MaxBatchSize)),
-		)
-		data, _ := jsonrpc.MarshalResponse(resp)
-		if _, err := w.Write(data); err != nil {
-			logger.WithError(err).Error("Failed to write error response")
+		)})
+		return
+	}
+
+	ctx := req.Context()
+	// 幂等键请求头只作用于单个请求，批量请求需在各交易参数中单独指定
+	if key := strings.TrimSpace(req.Header.Get(IdempotencyKeyHeader)); key != "" && len(entries) == 1 {
+		ctx = WithIdempotencyKey(ctx, key)
+	}
+
+	// 无效条目与带 id 的请求各产生一条响应，通知不产生响应
+	expected := 0
+	for i := range entries {
+		if entries[i].Err != nil || entries[i].Request.ID != nil {
+			expected++
 		}
+	}
+	if r.streamBatchThreshold > 0 && expected > 1 && expected >= r.streamBatchThreshold {
+		r.streamBatch(ctx, w, req, logger, entries)
 		return
 	}
 
-	// If we have default handler and it supports batch forwarding, use optimized batch handling
-	if r.defaultHandler != nil {
-		// Check if default handler is ForwardHandler by inspecting its method
-		if fwdHandler, ok := r.defaultHandler.(*ForwardHandler); ok {
-			r.handleBatchWithForwarding(w, req, logger, requests, fwdHandler)
-			return
+	responses := make([]*jsonrpc.Response, len(entries))
+	requests := make([]jsonrpc.Request, 0, len(entries))
+	indices := make([]int, 0, len(entries))
+	for i := range entries {
+		if entries[i].Err != nil {
+			logger.WithField("index", i).WithField("error", entries[i].Err.Data).Warn("Invalid entry in JSON-RPC batch")
+			responses[i] = jsonrpc.NewErrorResponse(entries[i].Request.ID, entries[i].Err)
+			continue
+		}
+		indices = append(indices, i)
+		requests = append(requests, entries[i].Request)
+	}
+
+	if len(requests) > 0 {
+		routed := r.routeParsed(ctx, logger, requests)
+		for i, idx := range indices {
+			// 通知（无 id 的请求）照常执行，但按规范不返回响应
+			if requests[i].ID == nil {
+				continue
+			}
+			responses[idx] = routed[i]
+		}
+	}
+
+	filtered := responses[:0]
+	for _, resp := range responses {
+		if resp != nil {
+			filtered = append(filtered, resp)
+		}
+	}
+	if info := accessInfoFromContext(req.Context()); info != nil {
+		methods := make([]string, len(requests))
+		for i := range requests {
+			methods[i] = requests[i].Method
+		}
+		rpcErrors := 0
+		for _, resp := range filtered {
+			if resp.Error != nil {
+				rpcErrors++
+			}
+		}
+		info.record(methods, rpcErrors)
+	}
+	if len(filtered) == 0 {
+		logger.Debug("JSON-RPC request contained only notifications, no response body")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	r.writeResponses(w, req, logger, filtered)
+}
+
+// streamBatch routes batch entries on the batch worker pool and writes the
+// responses to the client in request order, each as soon as it and every
+// response before it have completed.
+//
+// Streamed batches are not forwarded to downstream as a single batch request,
+// since that would hold back every forwarded response until the whole batch
+// returns.
+func (r *Router) streamBatch(ctx context.Context, w http.ResponseWriter, req *http.Request, logger *logrus.Entry, entries []jsonrpc.BatchEntry) {
+	// responses[i] 为条目 i 的响应，ready[i] 表示条目 i 已处理完成（通知没有响应）
+	responses := make([]*jsonrpc.Response, len(entries))
+	ready := make([]bool, len(entries))
+	requests := make([]jsonrpc.Request, 0, len(entries))
+	indices := make([]int, 0, len(entries))
+	methods := make([]string, 0, len(entries))
+	for i := range entries {
+		if entries[i].Err != nil {
+			logger.WithField("index", i).WithField("error", entries[i].Err.Data).Warn("Invalid entry in JSON-RPC batch")
+			responses[i] = jsonrpc.NewErrorResponse(entries[i].Request.ID, entries[i].Err)
+			ready[i] = true
+			continue
+		}
+		methods = append(methods, entries[i].Request.Method)
+		indices = append(indices, i)
+		requests = append(requests, entries[i].Request)
+	}
+
+	type result struct {
+		idx  int
+		resp *jsonrpc.Response
+	}
+	results := make(chan result, len(requests))
+	go func() {
+		defer close(results)
+		route := func(ctx context.Context, request *jsonrpc.Request) *jsonrpc.Response {
+			return r.RouteWithContext(ctx, request, logger)
+		}
+		r.routeConcurrently(ctx, requests, route, func(idx int, resp *jsonrpc.Response) {
+			results <- result{idx: idx, resp: resp}
+		})
+	}()
+
+	stream := r.newResponseStream(w, req, logger)
+	rpcErrors := 0
+	next := 0
+	// writeReady 按条目顺序写出已完成的响应
+	writeReady := func() {
+		for ; next < len(entries) && ready[next]; next++ {
+			if responses[next] == nil {
+				continue
+			}
+			if responses[next].Error != nil {
+				rpcErrors++
+			}
+			stream.write(responses[next])
+		}
+	}
+	writeReady()
+	for res := range results {
+		idx := indices[res.idx]
+		// 通知（无 id 的请求）照常执行，但按规范不返回响应
+		if entries[idx].Request.ID != nil {
+			responses[idx] = res.resp
 		}
+		ready[idx] = true
+		writeReady()
+	}
+
+	// 上下文取消时未处理的请求返回错误，而不是缺失响应
+	for i := next; i < len(entries); i++ {
+		if !ready[i] && entries[i].Request.ID != nil {
+			responses[i] = jsonrpc.NewErrorResponse(
+				entries[i].Request.ID,
+				jsonrpc.NewServerError(-32603, "Internal error", "Request cancelled"),
+			)
+		}
+		ready[i] = true
+	}
+	writeReady()
+	stream.close()
+
+	if info := accessInfoFromContext(req.Context()); info != nil {
+		info.record(methods, rpcErrors)
+	}
+}
+
+// routeParsed routes already validated requests and returns responses in request order.
+//
+// If the default handler is a ForwardHandler (possibly wrapped in a
+// MethodHandler, as CreateRouter does), the non-sign requests of a batch are
+// forwarded to downstream in a single batch; otherwise requests are routed
+// sequentially.
+func (r *Router) routeParsed(ctx context.Context, logger *logrus.Entry, requests []jsonrpc.Request) []*jsonrpc.Response {
+	// If we have default handler and it supports batch forwarding, use optimized batch handling
+	if fwdHandler, ok := unwrapHandler(r.defaultHandler).(*ForwardHandler); ok && len(requests) > 1 {
+		return r.handleBatchWithForwarding(ctx, requests, fwdHandler)
 	}
 
 	// Fallback to sequential processing for single request or non-forward handlers
 	responses := make([]*jsonrpc.Response, 0, len(requests))
 	for i := range requests {
-		resp := r.RouteWithContext(req.Context(), &requests[i], logger)
+		resp := r.RouteWithContext(ctx, &requests[i], logger)
 		responses = append(responses, resp)
 	}
+	return responses
+}
 
+// writeResponses marshals responses and writes them as a JSON-RPC HTTP response.
+//
+// Responses larger than the compression threshold are gzip-compressed when
+// the client advertises gzip support in Accept-Encoding.
+func (r *Router) writeResponses(w http.ResponseWriter, req *http.Request, logger *logrus.Entry, responses []*jsonrpc.Response) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
 	data, err := jsonrpc.MarshalResponses(responses)
 	if err != nil {
 		logger.WithError(err).Error("Failed to marshal JSON-RPC responses")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+
+	if r.compressionThreshold > 0 {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if int64(len(data)) > r.compressionThreshold && acceptsGzip(req.Header.Get("Accept-Encoding")) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			if _, err := gz.Write(data); err == nil && gz.Close() == nil {
+				logger.WithFields(logrus.Fields{
+					"size":            len(data),
+					"compressed_size": buf.Len(),
+				}).Debug("Compressed JSON-RPC response")
+				w.Header().Set("Content-Encoding", "gzip")
+				data = buf.Bytes()
+			} else {
+				logger.Warn("Failed to gzip JSON-RPC response, sending uncompressed")
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(data); err != nil {
 		logger.WithError(err).Error("Failed to write response")
 	}
 }
 
-// handleBatchWithForwarding processes batch requests by separating sign and forward requests
-// for optimized batch forwarding to downstream services.
+// responseStream writes a batch of responses as a JSON array, encoding and
+// flushing each response to the connection as soon as it is written.
 //
-// It routes sign requests through registered handlers and forwards other requests
-// in bulk to the downstream service, preserving request order in responses.
-func (r *Router) handleBatchWithForwarding(w http.ResponseWriter, req *http.Request, logger *logrus.Entry, requests []jsonrpc.Request, fwdHandler *ForwardHandler) {
-	if len(requests) == 0 {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		resp := jsonrpc.NewErrorResponse(nil, jsonrpc.InvalidRequestError)
-		data, _ := jsonrpc.MarshalResponse(resp)
-		if _, err := w.Write(data); err != nil {
-			logger.WithError(err).Error("Failed to write response")
+// The status line is sent before the first response is encoded, so encoding
+// failures can only be logged; the array is still closed so clients see valid
+// JSON. When compression is enabled and the client accepts gzip the stream is
+// always compressed, since the total size is not known up front.
+type responseStream struct {
+	out     io.Writer
+	gz      *gzip.Writer
+	enc     *json.Encoder
+	logger  *logrus.Entry
+	count   int
+	failed  bool
+	flusher http.Flusher
+}
+
+// newResponseStream 写出响应头与数组起始符，返回逐条写出响应的流
+func (r *Router) newResponseStream(w http.ResponseWriter, req *http.Request, logger *logrus.Entry) *responseStream {
+	w.Header().Set("Content-Type", "application/json")
+
+	s := &responseStream{out: w, logger: logger}
+	s.flusher, _ = w.(http.Flusher)
+	if r.compressionThreshold > 0 {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if acceptsGzip(req.Header.Get("Accept-Encoding")) {
+			w.Header().Set("Content-Encoding", "gzip")
+			s.gz = gzip.NewWriter(w)
+			s.out = s.gz
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+
+	s.enc = json.NewEncoder(s.out)
+	s.writeString("[")
+	return s
+}
+
+// write 写出一条响应并立即刷新到客户端
+func (s *responseStream) write(resp *jsonrpc.Response) {
+	if s.count > 0 {
+		s.writeString(",")
+	}
+	if s.failed {
+		return
+	}
+	if err := s.enc.Encode(resp); err != nil {
+		s.logger.WithError(err).WithField("index", s.count).Error("Failed to encode streamed JSON-RPC response")
+		// 保持数组结构完整，用错误响应占位
+		if err := s.enc.Encode(jsonrpc.NewErrorResponse(resp.ID, jsonrpc.InternalError)); err != nil {
+			s.failed = true
+			return
+		}
+	}
+	s.count++
+	s.flush()
+}
+
+// close 写出数组结束符并结束 gzip 流
+func (s *responseStream) close() {
+	s.writeString("]")
+	if s.gz != nil {
+		if err := s.gz.Close(); err != nil {
+			s.logger.WithError(err).Error("Failed to finish gzip response stream")
 		}
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	s.logger.WithField("count", s.count).Debug("Streamed JSON-RPC batch response")
+}
+
+// writeString 写出分隔符，连接写入失败后不再写出
+func (s *responseStream) writeString(str string) {
+	if s.failed {
 		return
 	}
+	if _, err := io.WriteString(s.out, str); err != nil {
+		s.logger.WithError(err).Error("Failed to write response")
+		s.failed = true
+	}
+}
+
+// flush 将已编码的响应推送到客户端
+func (s *responseStream) flush() {
+	if s.gz != nil {
+		if err := s.gz.Flush(); err != nil {
+			s.logger.WithError(err).Error("Failed to flush gzip response stream")
+			s.failed = true
+			return
+		}
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+// acceptsGzip 判断 Accept-Encoding 是否接受 gzip（q=0 表示明确拒绝）
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		rejected := false
+		for _, param := range fields[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if q, ok := strings.CutPrefix(param, "q="); ok {
+				if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+					rejected = true
+				}
+			}
+		}
+		if !rejected {
+			return true
+		}
+	}
+	return false
+}
 
+// handleBatchWithForwarding processes batch requests by separating sign and forward requests
+// for optimized batch forwarding to downstream services.
+//
+// It routes sign requests through registered handlers and forwards other requests
+// in bulk to the downstream service, preserving request order in responses.
+func (r *Router) handleBatchWithForwarding(ctx context.Context, requests []jsonrpc.Request, fwdHandler *ForwardHandler) []*jsonrpc.Response {
 	// Create response array to maintain order
 	responses := make([]*jsonrpc.Response, len(requests))
 
@@ -500,8 +1206,11 @@ func (r *Router) handleBatchWithForwarding(w http.ResponseWriter, req *http.Requ
 	forwardRequests := make([]jsonrpc.Request, 0)
 
 	for i, request := range requests {
-		if IsSignMethod(request.Method) {
+		// 签名方法以及显式注册的方法（如注入 from 的 eth_call）由本地处理器处理
+		if IsSignMethod(request.Method) || r.HasHandler(request.Method) {
 			signIndices = append(signIndices, i)
+		} else if response, ok := fwdHandler.localResponse(ctx, &requests[i]); ok {
+			responses[i] = response
 		} else {
 			forwardIndices = append(forwardIndices, i)
 			forwardRequests = append(forwardRequests, request)
@@ -509,7 +1218,6 @@ func (r *Router) handleBatchWithForwarding(w http.ResponseWriter, req *http.Requ
 	}
 
 	// Process sign requests sequentially
-	ctx := req.Context()
 	for _, idx := range signIndices {
 		handler, found := r.getHandler(requests[idx].Method)
 		if !found {
@@ -517,8 +1225,10 @@ func (r *Router) handleBatchWithForwarding(w http.ResponseWriter, req *http.Requ
 			continue
 		}
 
-		response, err := handler.Handle(ctx, &requests[idx])
+		response, err := r.handleWithTimeout(ctx, handler, &requests[idx])
 		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			responses[idx] = jsonrpc.NewErrorResponse(requests[idx].ID, jsonrpc.RequestTimeoutError)
 		case err != nil:
 			if jsonErr, ok := err.(*jsonrpc.Error); ok {
 				responses[idx] = jsonrpc.NewErrorResponse(requests[idx].ID, jsonErr)
@@ -544,7 +1254,7 @@ func (r *Router) handleBatchWithForwarding(w http.ResponseWriter, req *http.Requ
 		if batchResponses, err := downstreamClient.ForwardBatchRequest(ctx, forwardRequests); err == nil {
 			for i, idx := range forwardIndices {
 				if i < len(batchResponses) {
-					responses[idx] = &batchResponses[i]
+					responses[idx] = fwdHandler.finishForwarded(&requests[idx], &batchResponses[i])
 				} else {
 					responses[idx] = jsonrpc.NewErrorResponse(requests[idx].ID, jsonrpc.InternalError)
 				}
@@ -560,18 +1270,7 @@ func (r *Router) handleBatchWithForwarding(w http.ResponseWriter, req *http.Requ
 		}
 	}
 
-	// Write response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	data, err := jsonrpc.MarshalResponses(responses)
-	if err != nil {
-		logger.WithError(err).Error("Failed to marshal JSON-RPC responses")
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	if _, err := w.Write(data); err != nil {
-		logger.WithError(err).Error("Failed to write response")
-	}
+	return responses
 }
 
 // HandleHTTPRequestWithContext handles HTTP requests with context-aware logging.
@@ -584,6 +1283,8 @@ func (r *Router) handleBatchWithForwarding(w http.ResponseWriter, req *http.Requ
 //   - req: HTTP request
 //   - logger: Logger entry with context fields for tracing
 func (r *Router) HandleHTTPRequestWithContext(w http.ResponseWriter, req *http.Request, logger *logrus.Entry) {
+	defer r.recoverHTTPPanic(w, req, logger)
+
 	if req.Method == "OPTIONS" {
 		maxBody := r.maxRequestSize
 		limitedBody := http.MaxBytesReader(w, req.Body, maxBody)
@@ -625,6 +1326,8 @@ func (r *Router) HandleHTTPRequestWithContext(w http.ResponseWriter, req *http.R
 //   - w: HTTP response writer
 //   - req: HTTP request
 func (r *Router) HandleHTTPRequest(w http.ResponseWriter, req *http.Request) {
+	defer r.recoverHTTPPanic(w, req, logrus.NewEntry(r.logger))
+
 	if req.Method == "OPTIONS" {
 		maxBody := r.maxRequestSize
 		limitedBody := http.MaxBytesReader(w, req.Body, maxBody)
@@ -658,6 +1361,33 @@ func (r *Router) HandleHTTPRequest(w http.ResponseWriter, req *http.Request) {
 	r.parseAndRouteSimple(w, req, body)
 }
 
+// recoverHTTPPanic recovers a panic raised while handling an HTTP request.
+//
+// The panic is logged together with its stack trace and the client receives a
+// JSON-RPC InternalError response instead of a dropped connection.
+// It must be called directly via defer.
+//
+// Parameters:
+//   - w: HTTP response writer
+//   - req: HTTP request
+//   - logger: Logger entry for tracing
+func (r *Router) recoverHTTPPanic(w http.ResponseWriter, req *http.Request, logger *logrus.Entry) {
+	p := recover()
+	if p == nil {
+		return
+	}
+	if p == http.ErrAbortHandler {
+		panic(p)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"panic": p,
+		"stack": string(debug.Stack()),
+	}).Error("Panic recovered in HTTP handler")
+
+	r.writeResponses(w, req, logger, []*jsonrpc.Response{jsonrpc.NewErrorResponse(nil, jsonrpc.InternalError)})
+}
+
 // parseAndRouteSimple parses and routes requests using the router's default logger.
 //
 // This is a helper method used by HandleHTTPRequest.
@@ -669,7 +1399,7 @@ func (r *Router) HandleHTTPRequest(w http.ResponseWriter, req *http.Request) {
 //   - body: The request body content
 func (r *Router) parseAndRouteSimple(w http.ResponseWriter, req *http.Request, body []byte) {
 	// Parse request to extract fields for logger context
-	requests, err := jsonrpc.ParseRequest(body)
+	requests, err := jsonrpc.ParseRequestWithOptions(body, r.parseOptions)
 	if err != nil {
 		// Create logger entry without request fields for error case
 		logger := r.logger.WithError(err)
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,10 +10,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/mowind/web3signer-go/internal/jsonrpc"
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 )
 
 // mockHandler 是 Handler 接口的 mock 实现
@@ -53,6 +59,82 @@ func TestRouter_Register(t *testing.T) {
 	}
 }
 
+func TestRouter_RegisterOrReplace(t *testing.T) {
+	router := NewRouter(logrus.New())
+
+	respondWith := func(result string) *mockHandler {
+		return &mockHandler{
+			method: "test_method",
+			handleFunc: func(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+				return jsonrpc.NewResponse(req.ID, result)
+			},
+		}
+	}
+	first, second := respondWith("first"), respondWith("second")
+
+	previous, err := router.RegisterOrReplace(first)
+	if err != nil || previous != nil {
+		t.Fatalf("RegisterOrReplace() = (%v, %v), want (nil, nil)", previous, err)
+	}
+	previous, err = router.RegisterOrReplace(second)
+	if err != nil || previous != first {
+		t.Fatalf("RegisterOrReplace() = (%v, %v), want the first handler", previous, err)
+	}
+
+	response := router.Route(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "test_method", ID: 1})
+	if string(response.Result) != `"second"` {
+		t.Errorf("Expected replaced handler to serve the request, got %s", response.Result)
+	}
+
+	// Register 仍拒绝重复注册
+	if err := router.Register(first); err == nil {
+		t.Error("Expected error for duplicate registration, got nil")
+	}
+	if _, err := router.RegisterOrReplace(&mockHandler{method: ""}); err == nil {
+		t.Error("Expected error for empty method name, got nil")
+	}
+}
+
+func TestRouter_LogRoutingTable(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	router := NewRouter(logger)
+	router.SetRequestTimeouts(time.Minute, 0)
+	router.SetMethodTimeouts(map[string]time.Duration{"custom_method": time.Second})
+
+	for _, method := range []string{"eth_sign", "custom_method"} {
+		if err := router.Register(&mockHandler{method: method}); err != nil {
+			t.Fatalf("Failed to register handler: %v", err)
+		}
+	}
+	downstream := newMockDownstreamClient()
+	defer func() { _ = downstream.Close() }()
+	router.SetDefaultHandler(&MethodHandler{handler: NewForwardHandler(downstream, logger), method: "forward_handler"})
+
+	hook.Reset()
+	router.LogRoutingTable()
+
+	entries := hook.AllEntries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 log entries, got %d", len(entries))
+	}
+	// 方法按名称排序
+	if entries[0].Data["method"] != "custom_method" || entries[0].Data["sign_method"] != false ||
+		entries[0].Data["timeout"] != "1s" || entries[0].Data["handler"] != "mockHandler" {
+		t.Errorf("unexpected custom_method route: %v", entries[0].Data)
+	}
+	if entries[1].Data["method"] != "eth_sign" || entries[1].Data["sign_method"] != true || entries[1].Data["timeout"] != "0s" {
+		t.Errorf("unexpected eth_sign route: %v", entries[1].Data)
+	}
+	if entries[2].Data["handler"] != "ForwardHandler" || entries[2].Data["downstream_endpoint"] != downstream.GetEndpoint() {
+		t.Errorf("unexpected default route: %v", entries[2].Data)
+	}
+	for _, entry := range entries {
+		if entry.Level != logrus.InfoLevel {
+			t.Errorf("Expected info level, got %s", entry.Level)
+		}
+	}
+}
+
 func TestRouter_Register_EmptyMethod(t *testing.T) {
 	logger := logrus.New()
 	router := NewRouter(logger)
@@ -197,6 +279,119 @@ func TestRouter_Route_HandlerError(t *testing.T) {
 	}
 }
 
+func TestRouter_Route_Timeout(t *testing.T) {
+	router := NewRouter(logrus.New())
+	router.SetRequestTimeouts(20*time.Millisecond, 0)
+	// 会广播交易的方法即使配置了超时也不受限制，避免超时后仍在后台发送
+	router.SetMethodTimeouts(map[string]time.Duration{"web3signer_signRawTransaction": time.Millisecond})
+
+	slow := func(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			return jsonrpc.NewResponse(request.ID, "done")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	for _, method := range []string{"slow_method", "eth_sendTransaction", "web3signer_signRawTransaction"} {
+		if err := router.Register(&mockHandler{method: method, handleFunc: slow}); err != nil {
+			t.Fatalf("Failed to register handler: %v", err)
+		}
+	}
+
+	response := router.Route(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "slow_method", ID: 1})
+	if response.Error == nil || response.Error.Code != jsonrpc.CodeRequestTimeout {
+		t.Fatalf("Expected request timeout error, got %+v", response)
+	}
+
+	// 签名方法的超时为 0，不受限制
+	response = router.Route(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_sendTransaction", ID: 2})
+	if response.Error != nil {
+		t.Fatalf("Expected sign method to be exempt from the timeout, got %+v", response.Error)
+	}
+
+	router.SetRequestTimeouts(20*time.Millisecond, 20*time.Millisecond)
+	for _, method := range []string{"eth_sendTransaction", "web3signer_signRawTransaction"} {
+		response = router.Route(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: method, ID: 3})
+		if response.Error != nil {
+			t.Errorf("Expected %s to be exempt from timeouts, got %+v", method, response.Error)
+		}
+	}
+}
+
+func TestRouter_Route_MethodTimeout(t *testing.T) {
+	router := NewRouter(logrus.New())
+	router.SetRequestTimeouts(20*time.Millisecond, 20*time.Millisecond)
+	// 配置文件中的键可能被转为小写
+	router.SetMethodTimeouts(map[string]time.Duration{"eth_getlogs": 0, "eth_sendTransaction": 0, "eth_chainId": time.Millisecond})
+
+	handleFunc := func(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return jsonrpc.NewResponse(request.ID, "done")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	for _, method := range []string{"eth_getLogs", "eth_sendTransaction", "eth_chainId", "eth_blockNumber"} {
+		if err := router.Register(&mockHandler{method: method, handleFunc: handleFunc}); err != nil {
+			t.Fatalf("Failed to register handler: %v", err)
+		}
+	}
+
+	tests := []struct {
+		method      string
+		wantTimeout bool
+	}{
+		{method: "eth_getLogs", wantTimeout: false},
+		{method: "eth_sendTransaction", wantTimeout: false},
+		{method: "eth_chainId", wantTimeout: true},
+		{method: "eth_blockNumber", wantTimeout: true}, // 未配置的方法使用全局超时
+	}
+	for _, tt := range tests {
+		response := router.Route(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: tt.method, ID: 1})
+		timedOut := response.Error != nil && response.Error.Code == jsonrpc.CodeRequestTimeout
+		if timedOut != tt.wantTimeout {
+			t.Errorf("%s: timed out = %v, want %v (response %+v)", tt.method, timedOut, tt.wantTimeout, response)
+		}
+	}
+}
+
+func TestRouter_Route_SlowRequestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetLevel(logrus.ErrorLevel)
+
+	router := NewRouter(logger)
+	router.SetSlowRequestThreshold(10 * time.Millisecond)
+
+	handleFunc := func(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+		if request.Method == "slow_method" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		return jsonrpc.NewResponse(request.ID, "done")
+	}
+	for _, method := range []string{"slow_method", "fast_method"} {
+		if err := router.Register(&mockHandler{method: method, handleFunc: handleFunc}); err != nil {
+			t.Fatalf("Failed to register handler: %v", err)
+		}
+	}
+
+	router.Route(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "fast_method", ID: 1})
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no log for fast request, got %s", buf.String())
+	}
+
+	// 日志级别为 error 时仍然记录慢请求
+	router.Route(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "slow_method", ID: 2})
+	output := buf.String()
+	if !strings.Contains(output, "Slow request") || !strings.Contains(output, "method=slow_method") ||
+		!strings.Contains(output, "duration_ms=") {
+		t.Errorf("Expected slow request warning, got %s", output)
+	}
+}
+
 func TestRouter_Route_JSONRPCError(t *testing.T) {
 	logger := logrus.New()
 	router := NewRouter(logger)
@@ -286,6 +481,57 @@ func TestRouter_RouteBatch(t *testing.T) {
 	}
 }
 
+func TestRouter_RouteBatch_MaxBatchWorkers(t *testing.T) {
+	router := NewRouter(logrus.New())
+	router.SetBatchWorkers(4, 2)
+	router.SetMaxBatchWorkers(3)
+
+	var active, peak int32
+	err := router.Register(&mockHandler{
+		method: "slow_method",
+		handleFunc: func(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+			n := atomic.AddInt32(&active, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			return jsonrpc.NewResponse(request.ID, "ok")
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+
+	requests := make([]jsonrpc.Request, 10)
+	for i := range requests {
+		requests[i] = jsonrpc.Request{JSONRPC: "2.0", Method: "slow_method", ID: i}
+	}
+
+	// 两个并发批量请求共享 3 个 worker
+	var wg sync.WaitGroup
+	for b := 0; b < 2; b++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses := router.RouteBatch(context.Background(), requests)
+			for i, resp := range responses {
+				if resp == nil || resp.Error != nil {
+					t.Errorf("Response %d: expected success, got %+v", i, resp)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&peak); got > 3 {
+		t.Errorf("Expected at most 3 concurrent batch workers, got %d", got)
+	}
+}
+
 func TestRouter_RouteBatch_Empty(t *testing.T) {
 	logger := logrus.New()
 	router := NewRouter(logger)
@@ -526,3 +772,477 @@ func TestRouter_RouteAndRouteWithContext(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_HandleHTTPRequest_BatchWithMalformedEntry(t *testing.T) {
+	logger := logrus.New()
+	router := NewRouter(logger)
+
+	if err := router.Register(&mockHandler{method: "test_method"}); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+
+	body := `[
+		{"jsonrpc":"2.0","id":1,"method":"test_method"},
+		{"jsonrpc":"1.0","id":2,"method":"test_method"},
+		"garbage",
+		{"jsonrpc":"2.0","id":4,"method":"test_method"}
+	]`
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	router.HandleHTTPRequest(w, req)
+
+	var responses []jsonrpc.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("Failed to unmarshal batch response: %v, body: %s", err, w.Body.String())
+	}
+	if len(responses) != 4 {
+		t.Fatalf("Expected 4 responses, got %d", len(responses))
+	}
+
+	if responses[0].Error != nil || responses[0].ID != float64(1) {
+		t.Errorf("Expected success for entry 0, got %+v", responses[0])
+	}
+	if responses[1].Error == nil || responses[1].Error.Code != jsonrpc.CodeInvalidRequest || responses[1].ID != float64(2) {
+		t.Errorf("Expected invalid request for entry 1, got %+v", responses[1])
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != jsonrpc.CodeInvalidRequest || responses[2].ID != nil {
+		t.Errorf("Expected invalid request with null id for entry 2, got %+v", responses[2])
+	}
+	if responses[3].Error != nil || responses[3].ID != float64(4) {
+		t.Errorf("Expected success for entry 3, got %+v", responses[3])
+	}
+}
+
+func TestRouter_HandleHTTPRequest_Notifications(t *testing.T) {
+	logger := logrus.New()
+	router := NewRouter(logger)
+
+	if err := router.Register(&mockHandler{method: "test_method"}); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		expectStatus int
+		expectedIDs  []interface{}
+		expectNoBody bool
+	}{
+		{
+			name:         "single notification",
+			body:         `{"jsonrpc":"2.0","method":"test_method"}`,
+			expectStatus: http.StatusNoContent,
+			expectNoBody: true,
+		},
+		{
+			name:         "batch of only notifications",
+			body:         `[{"jsonrpc":"2.0","method":"test_method"},{"jsonrpc":"2.0","method":"test_method"}]`,
+			expectStatus: http.StatusNoContent,
+			expectNoBody: true,
+		},
+		{
+			name:         "batch mixing notifications and requests",
+			body:         `[{"jsonrpc":"2.0","method":"test_method"},{"jsonrpc":"2.0","id":1,"method":"test_method"},{"jsonrpc":"2.0","method":"test_method"},{"jsonrpc":"2.0","id":2,"method":"test_method"}]`,
+			expectStatus: http.StatusOK,
+			expectedIDs:  []interface{}{float64(1), float64(2)},
+		},
+		{
+			name:         "invalid entry without id is still answered",
+			body:         `[{"jsonrpc":"2.0","method":"test_method"},"garbage",{"jsonrpc":"2.0","id":3,"method":"test_method"}]`,
+			expectStatus: http.StatusOK,
+			expectedIDs:  []interface{}{nil, float64(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router.HandleHTTPRequest(w, req)
+
+			if w.Code != tt.expectStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectStatus, w.Code)
+			}
+			if tt.expectNoBody {
+				if w.Body.Len() != 0 {
+					t.Errorf("Expected empty body, got %s", w.Body.String())
+				}
+				return
+			}
+
+			var responses []jsonrpc.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+				t.Fatalf("Failed to unmarshal batch response: %v, body: %s", err, w.Body.String())
+			}
+			if len(responses) != len(tt.expectedIDs) {
+				t.Fatalf("Expected %d responses, got %d", len(tt.expectedIDs), len(responses))
+			}
+			for i, id := range tt.expectedIDs {
+				if responses[i].ID != id {
+					t.Errorf("Response %d: expected id %v, got %v", i, id, responses[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestRouter_HandleHTTPRequest_ResponseCompression(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	large := strings.Repeat("a", 4096)
+	tests := []struct {
+		name           string
+		threshold      int64
+		acceptEncoding string
+		result         string
+		expectGzip     bool
+	}{
+		{name: "large response with gzip", threshold: 1024, acceptEncoding: "gzip, deflate", result: large, expectGzip: true},
+		{name: "client without gzip support", threshold: 1024, result: large},
+		{name: "gzip explicitly refused", threshold: 1024, acceptEncoding: "gzip;q=0, identity", result: large},
+		{name: "below threshold", threshold: 1024, acceptEncoding: "gzip", result: "small"},
+		{name: "compression disabled", threshold: 0, acceptEncoding: "gzip", result: large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(logger)
+			router.SetCompressionThreshold(tt.threshold)
+			if err := router.Register(&simpleMockHandler{method: "test_method", result: tt.result}); err != nil {
+				t.Fatalf("Failed to register handler: %v", err)
+			}
+
+			req := httptest.NewRequest("POST", "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"test_method"}`))
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			router.HandleHTTPRequest(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+
+			body := w.Body.Bytes()
+			if got := w.Header().Get("Content-Encoding"); (got == "gzip") != tt.expectGzip {
+				t.Fatalf("Content-Encoding = %q, expectGzip %v", got, tt.expectGzip)
+			}
+			if tt.expectGzip {
+				gz, err := gzip.NewReader(bytes.NewReader(body))
+				if err != nil {
+					t.Fatalf("Failed to open gzip body: %v", err)
+				}
+				if body, err = io.ReadAll(gz); err != nil {
+					t.Fatalf("Failed to decompress body: %v", err)
+				}
+			}
+
+			var resp jsonrpc.Response
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var result string
+			if err := json.Unmarshal(resp.Result, &result); err != nil || result != tt.result {
+				t.Errorf("Unexpected result %q (err %v)", result, err)
+			}
+		})
+	}
+}
+
+func TestRouter_HandleHTTPRequest_PanicRecovery(t *testing.T) {
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	router := NewRouter(logger)
+
+	if err := router.Register(&mockHandler{
+		method: "panic_method",
+		handleFunc: func(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+			panic("boom")
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+
+	body := `{"jsonrpc":"2.0","method":"panic_method","id":1}`
+	handlers := map[string]func(w http.ResponseWriter, req *http.Request){
+		"HandleHTTPRequest": router.HandleHTTPRequest,
+		"HandleHTTPRequestWithContext": func(w http.ResponseWriter, req *http.Request) {
+			router.HandleHTTPRequestWithContext(w, req, logrus.NewEntry(logger))
+		},
+	}
+
+	for name, handle := range handlers {
+		t.Run(name, func(t *testing.T) {
+			logs.Reset()
+			w := httptest.NewRecorder()
+			handle(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+			var resp jsonrpc.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Expected JSON-RPC response, got %q: %v", w.Body.String(), err)
+			}
+			if resp.Error == nil || resp.Error.Code != jsonrpc.CodeInternalError {
+				t.Errorf("Expected InternalError, got %+v", resp)
+			}
+			if !strings.Contains(logs.String(), "boom") || !strings.Contains(logs.String(), "goroutine") {
+				t.Errorf("Expected panic and stack trace in logs, got %q", logs.String())
+			}
+		})
+	}
+}
+
+func TestRouter_HandleHTTPRequest_StreamBatchResponses(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	tests := []struct {
+		name           string
+		threshold      int
+		acceptEncoding string
+		body           string
+		expectIDs      []float64
+		expectArray    bool
+		expectGzip     bool
+	}{
+		{
+			name:        "batch streamed in order",
+			threshold:   2,
+			body:        `[{"jsonrpc":"2.0","id":1,"method":"test_method"},{"jsonrpc":"2.0","id":2,"method":"missing"},{"jsonrpc":"2.0","method":"test_method"},{"jsonrpc":"2.0","id":3,"method":"test_method"}]`,
+			expectIDs:   []float64{1, 2, 3},
+			expectArray: true,
+		},
+		{
+			name:           "streamed batch with gzip",
+			threshold:      2,
+			acceptEncoding: "gzip",
+			body:           `[{"jsonrpc":"2.0","id":1,"method":"test_method"},{"jsonrpc":"2.0","id":2,"method":"test_method"}]`,
+			expectIDs:      []float64{1, 2},
+			expectArray:    true,
+			expectGzip:     true,
+		},
+		{
+			name:        "batch below threshold",
+			threshold:   5,
+			body:        `[{"jsonrpc":"2.0","id":1,"method":"test_method"},{"jsonrpc":"2.0","id":2,"method":"test_method"}]`,
+			expectIDs:   []float64{1, 2},
+			expectArray: true,
+		},
+		{
+			name:      "single response is never streamed as array",
+			threshold: 1,
+			body:      `[{"jsonrpc":"2.0","id":1,"method":"test_method"},{"jsonrpc":"2.0","method":"test_method"}]`,
+			expectIDs: []float64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(logger)
+			router.SetStreamBatchThreshold(tt.threshold)
+			router.SetCompressionThreshold(1024 * 1024)
+			if err := router.Register(&simpleMockHandler{method: "test_method", result: "ok"}); err != nil {
+				t.Fatalf("Failed to register handler: %v", err)
+			}
+
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			router.HandleHTTPRequest(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			body := w.Body.Bytes()
+			if got := w.Header().Get("Content-Encoding"); (got == "gzip") != tt.expectGzip {
+				t.Fatalf("Content-Encoding = %q, expectGzip %v", got, tt.expectGzip)
+			}
+			if tt.expectGzip {
+				gz, err := gzip.NewReader(bytes.NewReader(body))
+				if err != nil {
+					t.Fatalf("Failed to open gzip body: %v", err)
+				}
+				if body, err = io.ReadAll(gz); err != nil {
+					t.Fatalf("Failed to decompress body: %v", err)
+				}
+			}
+
+			var responses []jsonrpc.Response
+			if tt.expectArray {
+				if err := json.Unmarshal(body, &responses); err != nil {
+					t.Fatalf("Expected JSON array, got %q: %v", body, err)
+				}
+			} else {
+				var resp jsonrpc.Response
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("Expected single JSON object, got %q: %v", body, err)
+				}
+				responses = append(responses, resp)
+			}
+			if len(responses) != len(tt.expectIDs) {
+				t.Fatalf("Expected %d responses, got %d", len(tt.expectIDs), len(responses))
+			}
+			for i, id := range tt.expectIDs {
+				if responses[i].ID != id {
+					t.Errorf("Response %d: expected id %v, got %v", i, id, responses[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestRouter_HandleHTTPRequest_StreamBatchWritesEachResponse(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	router := NewRouter(logger)
+	router.SetStreamBatchThreshold(2)
+	release := make(chan struct{})
+	if err := router.Register(&mockHandler{method: "fast"}); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+	if err := router.Register(&mockHandler{method: "slow", handleFunc: func(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+		<-release
+		return jsonrpc.NewResponse(request.ID, "done")
+	}}); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(router.HandleHTTPRequest))
+	defer server.Close()
+	defer func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	}()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`[{"jsonrpc":"2.0","id":1,"method":"fast"},{"jsonrpc":"2.0","id":2,"method":"slow"}]`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// 第二个请求仍在处理时，第一个响应已写到客户端
+	first := make([]byte, 0, 128)
+	buf := make([]byte, 128)
+	for !bytes.Contains(first, []byte("\n")) {
+		n, err := resp.Body.Read(buf)
+		if err != nil {
+			t.Fatalf("Failed to read first response: %v, got %q", err, first)
+		}
+		first = append(first, buf[:n]...)
+	}
+	if !strings.Contains(string(first), `"id":1`) {
+		t.Fatalf("Expected first response before the slow request completes, got %q", first)
+	}
+
+	close(release)
+	rest, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read remaining responses: %v", err)
+	}
+	var responses []jsonrpc.Response
+	if err := json.Unmarshal(append(first, rest...), &responses); err != nil {
+		t.Fatalf("Expected JSON array, got %q: %v", append(first, rest...), err)
+	}
+	if len(responses) != 2 || responses[1].ID != float64(2) {
+		t.Errorf("Expected responses for ids 1 and 2, got %+v", responses)
+	}
+}
+
+func TestRouter_HandleHTTPRequest_StreamBatchRoutesConcurrently(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	router := NewRouter(logger)
+	router.SetStreamBatchThreshold(2)
+	secondStarted := make(chan struct{})
+	// first 等待 second 开始处理，顺序处理时会超时
+	if err := router.Register(&mockHandler{method: "first", handleFunc: func(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+		select {
+		case <-secondStarted:
+			return jsonrpc.NewResponse(request.ID, "concurrent")
+		case <-time.After(5 * time.Second):
+			return jsonrpc.NewResponse(request.ID, "sequential")
+		}
+	}}); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+	if err := router.Register(&mockHandler{method: "second", handleFunc: func(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+		close(secondStarted)
+		return jsonrpc.NewResponse(request.ID, "done")
+	}}); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`[{"jsonrpc":"2.0","id":1,"method":"first"},{"jsonrpc":"2.0","id":2,"method":"second"},{"jsonrpc":"2.0","id":3,"method":"missing"}]`))
+	w := httptest.NewRecorder()
+	router.HandleHTTPRequest(w, req)
+
+	var responses []jsonrpc.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("Expected JSON array, got %q: %v", w.Body.String(), err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(responses))
+	}
+	// 后完成的请求仍按请求顺序写出
+	for i, id := range []float64{1, 2, 3} {
+		if responses[i].ID != id {
+			t.Errorf("Response %d: expected id %v, got %v", i, id, responses[i].ID)
+		}
+	}
+	if string(responses[0].Result) != `"concurrent"` {
+		t.Errorf("Expected batch entries to be routed concurrently, got %s", responses[0].Result)
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != jsonrpc.CodeMethodNotFound {
+		t.Errorf("Expected method not found for id 3, got %+v", responses[2].Error)
+	}
+}
+
+func TestRouter_HandleHTTPRequest_AllowMissingJSONRPCVersion(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	tests := []struct {
+		name      string
+		allow     bool
+		body      string
+		wantError bool
+	}{
+		{name: "strict rejects missing version", body: `{"id":1,"method":"test_method"}`, wantError: true},
+		{name: "lenient accepts missing version", allow: true, body: `{"id":1,"method":"test_method"}`},
+		{name: "lenient rejects explicit 1.0", allow: true, body: `{"jsonrpc":"1.0","id":1,"method":"test_method"}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(logger)
+			router.SetAllowMissingJSONRPCVersion(tt.allow)
+			if err := router.Register(&simpleMockHandler{method: "test_method", result: "ok"}); err != nil {
+				t.Fatalf("Failed to register handler: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(tt.body)))
+
+			var resp jsonrpc.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response %q: %v", w.Body.String(), err)
+			}
+			if (resp.Error != nil) != tt.wantError {
+				t.Fatalf("Unexpected response %+v, wantError %v", resp, tt.wantError)
+			}
+			if !tt.wantError && resp.JSONRPC != jsonrpc.JSONRPCVersion {
+				t.Errorf("Expected response jsonrpc %s, got %q", jsonrpc.JSONRPCVersion, resp.JSONRPC)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package router
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/umbracle/ethgo"
+)
+
+// sentTxCache 最近发送的已签名交易（按交易哈希）的 LRU 缓存
+//
+// 交易提交后、下游节点尚未在交易池中看到它之前，eth_getTransactionByHash 会返回 null；
+// 此时使用缓存的交易合成一个 pending 状态的结果
+type sentTxCache struct {
+	lru *ttlLRU[ethgo.Hash, *ethgo.Transaction]
+}
+
+// newSentTxCache 创建已发送交易缓存
+func newSentTxCache(ttl time.Duration, maxSize int) *sentTxCache {
+	return &sentTxCache{lru: newTTLLRU[ethgo.Hash, *ethgo.Transaction](ttl, maxSize)}
+}
+
+// lookup 返回缓存窗口内已发送的交易
+func (c *sentTxCache) lookup(hash ethgo.Hash) (*ethgo.Transaction, bool) {
+	return c.lru.get(hash)
+}
+
+// add 记录成功发送的交易；缓存已满时淘汰最久未使用的条目
+func (c *sentTxCache) add(hash ethgo.Hash, tx *ethgo.Transaction) {
+	c.lru.add(hash, tx)
+}
+
+// pendingTransaction 与节点返回的 pending 交易格式一致的 eth_getTransactionByHash 结果
+//
+// blockHash、blockNumber 和 transactionIndex 为 null 表示交易尚未打包
+type pendingTransaction struct {
+	BlockHash            *string           `json:"blockHash"`
+	BlockNumber          *string           `json:"blockNumber"`
+	TransactionIndex     *string           `json:"transactionIndex"`
+	Hash                 string            `json:"hash"`
+	Type                 string            `json:"type"`
+	From                 string            `json:"from"`
+	To                   *string           `json:"to"`
+	Nonce                string            `json:"nonce"`
+	Gas                  string            `json:"gas"`
+	GasPrice             string            `json:"gasPrice"`
+	MaxFeePerGas         string            `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string            `json:"maxPriorityFeePerGas,omitempty"`
+	Value                string            `json:"value"`
+	Input                string            `json:"input"`
+	ChainID              string            `json:"chainId,omitempty"`
+	AccessList           []accessListEntry `json:"accessList,omitempty"`
+	V                    string            `json:"v"`
+	R                    string            `json:"r"`
+	S                    string            `json:"s"`
+}
+
+// accessListEntry 访问列表条目
+type accessListEntry struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+// pendingTransactionResult 将已发送的交易编码为 pending 状态的 eth_getTransactionByHash 结果
+func pendingTransactionResult(hash ethgo.Hash, tx *ethgo.Transaction) (json.RawMessage, error) {
+	result := pendingTransaction{
+		Hash:     hash.String(),
+		Type:     fmt.Sprintf("0x%x", uint8(tx.Type)),
+		From:     tx.From.String(),
+		Nonce:    fmt.Sprintf("0x%x", tx.Nonce),
+		Gas:      fmt.Sprintf("0x%x", tx.Gas),
+		GasPrice: fmt.Sprintf("0x%x", tx.GasPrice),
+		Value:    hexBig(tx.Value),
+		Input:    "0x" + hex.EncodeToString(tx.Input),
+		V:        hexBig(new(big.Int).SetBytes(tx.V)),
+		R:        hexBig(new(big.Int).SetBytes(tx.R)),
+		S:        hexBig(new(big.Int).SetBytes(tx.S)),
+	}
+	if tx.To != nil {
+		to := tx.To.String()
+		result.To = &to
+	}
+	if tx.Type == ethgo.TransactionDynamicFee {
+		// 与节点一致：未打包的 EIP-1559 交易 gasPrice 返回 maxFeePerGas
+		result.GasPrice = hexBig(tx.MaxFeePerGas)
+		result.MaxFeePerGas = hexBig(tx.MaxFeePerGas)
+		result.MaxPriorityFeePerGas = hexBig(tx.MaxPriorityFeePerGas)
+	}
+	if tx.Type != ethgo.TransactionLegacy {
+		result.ChainID = hexBig(tx.ChainID)
+		result.AccessList = make([]accessListEntry, 0, len(tx.AccessList))
+		for _, entry := range tx.AccessList {
+			keys := make([]string, 0, len(entry.Storage))
+			for _, key := range entry.Storage {
+				keys = append(keys, key.String())
+			}
+			result.AccessList = append(result.AccessList, accessListEntry{Address: entry.Address.String(), StorageKeys: keys})
+		}
+	}
+	return json.Marshal(result)
+}
+
+// hexBig 将数值编码为 0x 前缀的十六进制字符串，nil 视为 0
+func hexBig(v *big.Int) string {
+	if v == nil {
+		return "0x0"
+	}
+	return fmt.Sprintf("0x%x", v)
+}
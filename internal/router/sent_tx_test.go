@@ -0,0 +1,171 @@
+package router
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// nullTxDownstreamClient 对所有 eth_getTransactionByHash 返回 null，模拟交易尚未进入下游交易池
+type nullTxDownstreamClient struct {
+	*testDownstreamClient
+}
+
+func (c *nullTxDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	if req.Method == "eth_getTransactionByHash" {
+		return &jsonrpc.Response{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`null`)}, nil
+	}
+	return c.testDownstreamClient.ForwardRequest(ctx, req)
+}
+
+func (c *nullTxDownstreamClient) ForwardBatchRequest(ctx context.Context, requests []jsonrpc.Request) ([]jsonrpc.Response, error) {
+	responses := make([]jsonrpc.Response, len(requests))
+	for i := range requests {
+		resp, err := c.ForwardRequest(ctx, &requests[i])
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *resp
+	}
+	return responses, nil
+}
+
+func TestForwardHandler_PendingTransactionCorrelation(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	localSigner := signer.NewLocalKeystoreSigner(key, big.NewInt(1))
+
+	to := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+	signed, err := localSigner.SignTransaction(&ethgo.Transaction{
+		Type: ethgo.TransactionDynamicFee, ChainID: big.NewInt(1), Nonce: 7, Gas: 21000,
+		MaxFeePerGas: big.NewInt(2000), MaxPriorityFeePerGas: big.NewInt(100), To: &to, Value: big.NewInt(5),
+	})
+	if err != nil {
+		t.Fatalf("SignTransaction() error: %v", err)
+	}
+	rawHex, err := signer.EncodeRawTransactionHex(signed)
+	if err != nil {
+		t.Fatalf("EncodeRawTransactionHex() error: %v", err)
+	}
+	raw, err := hex.DecodeString(strings.TrimPrefix(rawHex, "0x"))
+	if err != nil {
+		t.Fatalf("invalid raw hex: %v", err)
+	}
+	txHash := ethgo.BytesToHash(ethgo.Keccak256(raw)).String()
+
+	post := func(t *testing.T, router *Router, body string) []byte {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		return w.Body.Bytes()
+	}
+	getTx := `{"jsonrpc":"2.0","id":2,"method":"eth_getTransactionByHash","params":["` + txHash + `"]}`
+
+	for _, ttl := range []int{0, 60} {
+		downstream := &nullTxDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+		router := NewRouterFactory(logger).
+			WithTransactionConfig(config.TransactionConfig{PendingTxTTLSeconds: ttl, PendingTxCacheSize: 10}).
+			CreateRouter(localSigner, downstream)
+
+		var resp jsonrpc.Response
+		if err := json.Unmarshal(post(t, router, getTx), &resp); err != nil || string(resp.Result) != "null" {
+			t.Fatalf("ttl=%d: expected null before sending, got %s (%v)", ttl, resp.Result, err)
+		}
+
+		post(t, router, `{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["`+rawHex+`"]}`)
+
+		// 单个请求与批量请求均应返回合成的 pending 交易
+		var batch []jsonrpc.Response
+		batchBody := "[" + getTx + `,{"jsonrpc":"2.0","id":3,"method":"eth_blockNumber","params":[]}]`
+		if err := json.Unmarshal(post(t, router, batchBody), &batch); err != nil || len(batch) != 2 {
+			t.Fatalf("ttl=%d: invalid batch response: %v", ttl, err)
+		}
+		if err := json.Unmarshal(post(t, router, getTx), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		_ = downstream.Close()
+
+		for _, r := range []jsonrpc.Response{resp, batch[0]} {
+			if ttl == 0 {
+				if string(r.Result) != "null" {
+					t.Errorf("disabled correlation should pass null through, got %s", r.Result)
+				}
+				continue
+			}
+
+			var pending map[string]interface{}
+			if err := json.Unmarshal(r.Result, &pending); err != nil {
+				t.Fatalf("expected pending transaction, got %s", r.Result)
+			}
+			if pending["hash"] != txHash || pending["nonce"] != "0x7" || pending["type"] != "0x2" ||
+				pending["blockHash"] != nil || pending["blockNumber"] != nil {
+				t.Errorf("unexpected pending transaction: %s", r.Result)
+			}
+			if !strings.EqualFold(pending["from"].(string), key.Address().String()) {
+				t.Errorf("from = %v, want %s", pending["from"], key.Address())
+			}
+		}
+	}
+}
+
+func TestSignHandler_SentTransactionCorrelation(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	testAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
+	mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", testAddress, big.NewInt(1))
+
+	downstream := &nullTxDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+	defer func() { _ = downstream.Close() }()
+
+	router := NewRouterFactory(logger).
+		WithTransactionConfig(config.TransactionConfig{PendingTxTTLSeconds: 60, PendingTxCacheSize: 10}).
+		CreateRouter(mpcSigner, downstream)
+	handler := router.handlers["eth_sendTransaction"].(*MethodHandler).handler.(*SignHandler)
+
+	resp := router.Route(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0",
+		Method:  "eth_sendTransaction",
+		ID:      1,
+		Params:  json.RawMessage(`[{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x4a817c800","nonce":"0x3"}]`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("eth_sendTransaction failed: %+v", resp.Error)
+	}
+
+	if got := handler.sentTxs.lru.len(); got != 1 {
+		t.Fatalf("expected sent transaction to be tracked, got %d entries", got)
+	}
+	var hash ethgo.Hash
+	for h := range handler.sentTxs.lru.entries {
+		hash = h
+	}
+
+	getResp := router.Route(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0",
+		Method:  "eth_getTransactionByHash",
+		ID:      2,
+		Params:  json.RawMessage(`["` + hash.String() + `"]`),
+	})
+	var pending map[string]interface{}
+	if err := json.Unmarshal(getResp.Result, &pending); err != nil || pending["nonce"] != "0x3" ||
+		!strings.EqualFold(pending["from"].(string), testAddress.String()) {
+		t.Errorf("unexpected pending transaction: %s", getResp.Result)
+	}
+}
@@ -0,0 +1,152 @@
+package router
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/mowind/web3signer-go/internal/config"
+	internaljsonrpc "github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+)
+
+// keyedSigner 由能够按密钥 ID 签名的签名器实现（如 signer.MultiKeySigner）
+type keyedSigner interface {
+	GetClient(keyID string) (signer.Client, error)
+	SignWithKeyID(hash []byte, keyID string) ([]byte, error)
+}
+
+// signBatchResult web3signer_signBatch 中单条消息的结果，成功时 Error 为空，失败时仅有 KeyID 与 Error
+type signBatchResult struct {
+	KeyID     string                 `json:"keyId,omitempty"`
+	Address   string                 `json:"address,omitempty"`
+	Signature interface{}            `json:"signature,omitempty"`
+	Error     *internaljsonrpc.Error `json:"error,omitempty"`
+}
+
+// WithSignBatchLimits 设置 web3signer_signBatch 单次请求的最大消息数（0 表示不限制）与并发签名数
+func (h *SignHandler) WithSignBatchLimits(maxItems, concurrency int) *SignHandler {
+	h.signBatchMaxItems = maxItems
+	h.signBatchConcurrency = concurrency
+	return h
+}
+
+// handleSignBatch 处理 web3signer_signBatch 方法
+//
+// 参数为 [[{keyId, message}, ...]]，每条消息与 eth_sign 一样签名其 EIP-191 消息哈希；
+// 消息并发签名，结果按请求顺序返回，单条失败只在对应条目中返回错误，不影响其余消息
+func (h *SignHandler) handleSignBatch(ctx context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
+	items, err := signer.ParseSignBatchParams(request.Params)
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to parse web3signer_signBatch params")
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid parameters", err), nil
+	}
+	if h.signBatchMaxItems > 0 && len(items) > h.signBatchMaxItems {
+		err := fmt.Errorf("batch of %d messages exceeds limit of %d", len(items), h.signBatchMaxItems)
+		h.logger.WithError(err).Warn("Sign batch too large")
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid parameters", err), nil
+	}
+
+	concurrency := h.signBatchConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"items":       len(items),
+		"concurrency": concurrency,
+	}).Info("Signing message batch")
+
+	results := make([]signBatchResult, len(items))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			// 请求已取消或超时，尚未开始的消息不再签名
+			results[i] = signBatchResult{
+				KeyID: item.KeyID,
+				Error: h.internalError(request, ErrorIDSignFailed, "Request cancelled", ctx.Err(), true).Error,
+			}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = h.signBatchItem(request, item)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+	h.logger.WithFields(logrus.Fields{
+		"items":  len(items),
+		"failed": failed,
+	}).Info("Message batch signed")
+
+	return h.CreateSuccessResponse(request.ID, results)
+}
+
+// signBatchItem 签名批量请求中的一条消息，错误以 JSON-RPC 错误对象返回在结果中
+func (h *SignHandler) signBatchItem(request *internaljsonrpc.Request, item signer.SignBatchItem) signBatchResult {
+	result := signBatchResult{KeyID: item.KeyID}
+
+	data, err := item.Data()
+	if err != nil {
+		result.Error = h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid message", withField("message", err)).Error
+		return result
+	}
+	hash := signer.TextHash(data)
+
+	if item.KeyID == "" {
+		// 未指定密钥时与 eth_sign 一样使用默认密钥
+		result.KeyID = h.defaultKeyID()
+	}
+	if err := h.checkKeyAllowed(result.KeyID); err != nil {
+		result.Error = h.keyNotAllowedError(request, result.KeyID, err).Error
+		return result
+	}
+
+	var signature []byte
+	if item.KeyID == "" {
+		result.Address = h.signer.Address().String()
+		signature, err = h.signer.Sign(hash)
+	} else {
+		keyed, ok := h.signer.(keyedSigner)
+		if !ok {
+			result.Error = h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid key",
+				withField("keyId", fmt.Errorf("signer does not support key selection"))).Error
+			return result
+		}
+		client, clientErr := keyed.GetClient(item.KeyID)
+		if clientErr != nil {
+			result.Error = h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid key", withField("keyId", clientErr)).Error
+			return result
+		}
+		result.Address = client.Address().String()
+		signature, err = keyed.SignWithKeyID(hash, item.KeyID)
+	}
+	if err != nil {
+		return signBatchResult{KeyID: result.KeyID, Error: h.signErrorResponse(request, "Failed to sign message", err).Error}
+	}
+
+	if h.txConfig.SignatureFormat == config.SignatureFormatRSV {
+		rsv, err := signer.NewSignatureRSV(signature)
+		if err != nil {
+			return signBatchResult{KeyID: result.KeyID, Error: h.internalError(request, ErrorIDEncodeFailed, "Failed to sign message", err, false).Error}
+		}
+		result.Signature = rsv
+		return result
+	}
+	result.Signature = hex.EncodeToString(signature)
+	return result
+}
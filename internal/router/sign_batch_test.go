@@ -0,0 +1,95 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+func TestSignHandler_SignBatch(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	addresses := map[string]ethgo.Address{
+		"default-key": ethgo.HexToAddress("0x1111111111111111111111111111111111111111"),
+		"other-key":   ethgo.HexToAddress("0x2222222222222222222222222222222222222222"),
+	}
+	multiKeySigner := signer.NewMultiKeySigner("default-key", big.NewInt(1), logger)
+	for keyID, address := range addresses {
+		if err := multiKeySigner.AddClient(keyID, signer.NewMPCKMSSigner(&testKMSClient{}, keyID, address, big.NewInt(1))); err != nil {
+			t.Fatalf("Failed to add client: %v", err)
+		}
+	}
+
+	handler := createSimpleTestHandler(t)
+	handler.signer = multiKeySigner
+	handler.WithSignBatchLimits(4, 2)
+
+	t.Run("results in request order", func(t *testing.T) {
+		response, err := handler.Handle(context.Background(), &jsonrpc.Request{
+			JSONRPC: "2.0",
+			Method:  "web3signer_signBatch",
+			Params: json.RawMessage(`[[
+				{"keyId":"other-key","message":"0xdeadbeef"},
+				{"keyId":"other-key","message":"0xzz"},
+				{"keyId":"missing-key","message":"0x01"},
+				{"message":"0x"}
+			]]`),
+			ID: 1,
+		})
+		if err != nil || response.Error != nil {
+			t.Fatalf("web3signer_signBatch failed: %v %+v", err, response)
+		}
+
+		var results []struct {
+			KeyID     string         `json:"keyId"`
+			Address   string         `json:"address"`
+			Signature string         `json:"signature"`
+			Error     *jsonrpc.Error `json:"error"`
+		}
+		if err := json.Unmarshal(response.Result, &results); err != nil {
+			t.Fatalf("Failed to decode results: %v", err)
+		}
+		if len(results) != 4 {
+			t.Fatalf("Expected 4 results, got %d", len(results))
+		}
+
+		if results[0].Error != nil || results[0].KeyID != "other-key" || !strings.EqualFold(results[0].Address, addresses["other-key"].String()) {
+			t.Errorf("results[0] = %+v, want signature from other-key", results[0])
+		}
+		if len(results[0].Signature) != 130 {
+			t.Errorf("Expected 65-byte hex signature, got %q", results[0].Signature)
+		}
+		if results[1].Error == nil || results[1].Error.Code != jsonrpc.CodeInvalidParams || results[1].Signature != "" {
+			t.Errorf("results[1] = %+v, want invalid message error", results[1])
+		}
+		if results[2].Error == nil || results[2].Error.Code != jsonrpc.CodeInvalidParams || results[2].KeyID != "missing-key" {
+			t.Errorf("results[2] = %+v, want unknown key error", results[2])
+		}
+		if results[3].Error != nil || results[3].KeyID != "default-key" || !strings.EqualFold(results[3].Address, addresses["default-key"].String()) {
+			t.Errorf("results[3] = %+v, want signature from default key", results[3])
+		}
+	})
+
+	t.Run("batch exceeds limit", func(t *testing.T) {
+		response, err := handler.Handle(context.Background(), &jsonrpc.Request{
+			JSONRPC: "2.0",
+			Method:  "web3signer_signBatch",
+			Params:  json.RawMessage(`[[{"message":"0x01"},{"message":"0x02"},{"message":"0x03"},{"message":"0x04"},{"message":"0x05"}]]`),
+			ID:      1,
+		})
+		if err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+		if response.Error == nil || response.Error.Code != jsonrpc.CodeInvalidParams {
+			t.Errorf("Expected invalid params error, got %+v", response.Error)
+		}
+	})
+}
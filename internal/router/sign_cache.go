@@ -0,0 +1,38 @@
+package router
+
+import (
+	"github.com/umbracle/ethgo"
+)
+
+// signCacheKey eth_sign 签名缓存键：签名密钥与待签名数据的哈希
+type signCacheKey struct {
+	keyID string
+	hash  ethgo.Hash
+}
+
+// signatureCache eth_sign 签名结果的 LRU 缓存
+//
+// 确定性签名（RFC 6979）对相同密钥和消息总是产生等价签名，因此可直接复用，
+// 无需再次请求 KMS。MPC 签名可能不确定，故仅在显式配置时启用
+type signatureCache struct {
+	lru *ttlLRU[signCacheKey, []byte]
+}
+
+// newSignatureCache 创建签名缓存，条目不过期
+func newSignatureCache(maxSize int) *signatureCache {
+	return &signatureCache{lru: newTTLLRU[signCacheKey, []byte](0, maxSize)}
+}
+
+// get 返回缓存的签名副本
+func (c *signatureCache) get(key signCacheKey) ([]byte, bool) {
+	signature, ok := c.lru.get(key)
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), signature...), true
+}
+
+// add 缓存签名；缓存已满时淘汰最久未使用的条目
+func (c *signatureCache) add(key signCacheKey, signature []byte) {
+	c.lru.add(key, append([]byte(nil), signature...))
+}
@@ -1,15 +1,24 @@
 package router
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"math/rand/v2"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/mowind/web3signer-go/internal/config"
 	"github.com/mowind/web3signer-go/internal/downstream"
 	internaljsonrpc "github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/policy"
 	"github.com/mowind/web3signer-go/internal/signer"
 	"github.com/mowind/web3signer-go/internal/utils"
 	"github.com/sirupsen/logrus"
@@ -26,35 +35,135 @@ import (
 //lint:ignore SA1019 // downstream.ClientInterface is used for backward compatibility
 type SignHandler struct {
 	*BaseHandler
-	signer        signer.Client
-	client        downstream.ClientInterface
-	downstreamRPC *ethgojsonrpc.Client
+	signer       signer.Client
+	client       downstream.ClientInterface
+	txConfig     config.TransactionConfig
+	idempotency  *idempotencyCache
+	replay       *replayCache
+	signCache    *signatureCache
+	sentTxs      *sentTxCache
+	nonceMonitor *nonceMonitor
+	gasLimits    *gasLimitTracker
+	preSignHook  policy.PreSignHook
+
+	maxCalldataBytes int
+
+	maxAccessListEntries     int
+	maxAccessListStorageKeys int
+
+	signBatchMaxItems    int
+	signBatchConcurrency int
+
+	allowedKeyIDs keyAllowlist // 允许通过 JSON-RPC 签名的密钥 ID，nil 表示不限制
+
+	// 查询下游（nonce、gas 等）的 RPC 客户端，经由 client 发送以使用相同的代理、TLS 与请求头；
+	// 连接失效时重建，须通过 rpc() 读取
+	downstreamRPC     *downstreamRPCState
+	rpcHealthInterval time.Duration
+	rpcHealthMethod   string
+}
+
+// SignTransactionResult eth_signTransaction 的返回结果
+//
+// 与 geth 一致：Raw 为 EIP-2718 编码的已签名交易，Tx 为交易对象；
+// Hash 为 keccak256(Raw)，即广播后节点返回的交易哈希
+type SignTransactionResult struct {
+	Raw  string             `json:"raw"`
+	Tx   *ethgo.Transaction `json:"tx"`
+	Hash ethgo.Hash         `json:"hash"`
 }
 
-// NewSignHandler 创建签名处理器
-func NewSignHandler(mpcSigner signer.Client, client downstream.ClientInterface, downstreamEndpoint string, logger *logrus.Logger) (*SignHandler, error) { //nolint:staticcheck // SA1019: backward compatibility
-	rpcClient, err := ethgojsonrpc.NewClient(downstreamEndpoint)
+// newSignTransactionResult 编码已签名交易并计算交易哈希，同时填充 Tx.Hash
+func newSignTransactionResult(signedTx *ethgo.Transaction) (*SignTransactionResult, error) {
+	rawTx, err := signer.EncodeRawTransaction(signedTx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create downstream RPC client: %v", err)
+		return nil, err
 	}
+	signedTx.Hash = ethgo.BytesToHash(ethgo.Keccak256(rawTx))
+	return &SignTransactionResult{
+		Raw:  "0x" + hex.EncodeToString(rawTx),
+		Tx:   signedTx,
+		Hash: signedTx.Hash,
+	}, nil
+}
 
+// NewSignHandler 创建签名处理器，查询下游（nonce、gas 等）的调用经由 client 发送
+func NewSignHandler(mpcSigner signer.Client, client downstream.ClientInterface, logger *logrus.Logger) *SignHandler { //nolint:staticcheck // SA1019: backward compatibility
 	return &SignHandler{
 		BaseHandler:   NewBaseHandler("sign", logger),
 		signer:        mpcSigner,
 		client:        client,
-		downstreamRPC: rpcClient,
-	}, nil
+		downstreamRPC: newDownstreamRPCState(downstream.NewRPCClient(client)),
+		preSignHook:   policy.NoopHook{},
+	}
+}
+
+// WithPreSignHook 设置签名交易前调用的策略钩子，nil 表示不检查
+func (h *SignHandler) WithPreSignHook(hook policy.PreSignHook) *SignHandler {
+	h.preSignHook = hook
+	return h
+}
+
+// WithMaxCalldataBytes 设置交易 data 的最大字节数，0 表示不限制
+func (h *SignHandler) WithMaxCalldataBytes(limit int) *SignHandler {
+	h.maxCalldataBytes = limit
+	return h
+}
+
+// WithMaxAccessList 设置访问列表的最大条目数与每个条目的最大存储键数，0 表示不限制
+func (h *SignHandler) WithMaxAccessList(entries, storageKeys int) *SignHandler {
+	h.maxAccessListEntries = entries
+	h.maxAccessListStorageKeys = storageKeys
+	return h
+}
+
+// WithTransactionConfig 设置填充交易字段时使用的配置
+func (h *SignHandler) WithTransactionConfig(cfg config.TransactionConfig) *SignHandler {
+	h.txConfig = cfg
+	if cfg.IdempotencyTTLSeconds > 0 && cfg.IdempotencyCacheSize > 0 {
+		h.idempotency = newIdempotencyCache(time.Duration(cfg.IdempotencyTTLSeconds)*time.Second, cfg.IdempotencyCacheSize)
+	} else {
+		h.idempotency = nil
+	}
+	if cfg.ReplayTTLSeconds > 0 && cfg.ReplayCacheSize > 0 {
+		h.replay = newReplayCache(time.Duration(cfg.ReplayTTLSeconds)*time.Second, cfg.ReplayCacheSize)
+	} else {
+		h.replay = nil
+	}
+	if cfg.SignCacheSize > 0 {
+		h.signCache = newSignatureCache(cfg.SignCacheSize)
+	} else {
+		h.signCache = nil
+	}
+	return h
 }
 
 // handleEthAccounts 处理 eth_accounts 方法
 func (h *SignHandler) handleEthAccounts(_ context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
-	kmsAddress := h.signer.Address().String()
+	kmsAddress, err := utils.ToChecksumAddress(h.signer.Address().String())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to checksum-encode KMS address")
+		return h.internalError(request, ErrorIDEncodeFailed, "Failed to encode address", err, false), nil
+	}
 
 	h.logger.WithField("address", kmsAddress).Debug("Returning KMS managed address for eth_accounts")
 
 	return h.CreateSuccessResponse(request.ID, []string{kmsAddress})
 }
 
+// withPinnedKey 返回固定了当前默认密钥的处理器副本，供单个请求使用
+//
+// 副本与原处理器共享缓存、下游客户端等状态，仅签名器替换为密钥快照
+func (h *SignHandler) withPinnedKey() *SignHandler {
+	snapshot, ok := pinDefaultKey(h.signer).(*signer.KeySnapshot)
+	if !ok {
+		return h
+	}
+	view := *h
+	view.signer = snapshot
+	return &view
+}
+
 // Method 返回处理器支持的方法名
 func (h *SignHandler) Method() string {
 	return "sign_handler"
@@ -63,19 +172,39 @@ func (h *SignHandler) Method() string {
 // Handle 处理 JSON-RPC 请求
 func (h *SignHandler) Handle(ctx context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
 	h.LogRequest(request)
+	h = h.withPinnedKey()
+
+	// 使用默认密钥签名的方法须先确认默认密钥允许通过 JSON-RPC 使用；web3signer_signBatch 按条目检查
+	switch request.Method {
+	case "eth_sign", "eth_signTypedData_v4", "eth_signTransaction", "eth_sendTransaction",
+		"web3signer_signRawTransaction", "web3signer_signTransactionWithSummary":
+		if resp := h.allowedKeyIDs.checkDefaultKey(h.BaseHandler, h.signer, request); resp != nil {
+			return resp, nil
+		}
+	}
 
 	switch request.Method {
 	case "eth_accounts":
 		return h.handleEthAccounts(ctx, request)
 	case "eth_sign":
 		return h.handleEthSign(ctx, request)
+	case "eth_signTypedData_v4":
+		return h.handleEthSignTypedData(ctx, request)
 	case "eth_signTransaction":
 		return h.handleEthSignTransaction(ctx, request)
 	case "eth_sendTransaction":
 		return h.handleEthSendTransaction(ctx, request)
+	case "web3signer_signRawTransaction":
+		return h.handleSignRawTransaction(ctx, request)
+	case "web3signer_signTransactionWithSummary":
+		return h.handleSignTransactionWithSummary(ctx, request)
+	case "web3signer_signBatch":
+		return h.handleSignBatch(ctx, request)
+	case "eth_call":
+		return h.handleEthCall(ctx, request)
 	default:
-		return h.CreateErrorResponse(request.ID, internaljsonrpc.CodeMethodNotFound,
-			"Method not supported by sign handler", nil), nil
+		return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodeMethodNotFound,
+			"Method not supported by sign handler", ErrorData{ErrorID: ErrorIDMethodNotSupported}), nil
 	}
 }
 
@@ -84,12 +213,13 @@ func (h *SignHandler) handleEthSign(_ context.Context, request *internaljsonrpc.
 	address, data, err := signer.ParseSignParams(request.Params)
 	if err != nil {
 		h.logger.WithError(err).Warn("Failed to parse eth_sign params")
-		return h.CreateInvalidParamsResponse(request.ID, fmt.Sprintf("Invalid parameters: %v", err)), nil
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid parameters", err), nil
 	}
 
 	if !utils.IsValidEthAddress(address) {
 		h.logger.WithField("address", address).Warn("Invalid Ethereum address format")
-		return h.CreateInvalidParamsResponse(request.ID, "Invalid Ethereum address format"), nil
+		return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodeInvalidParams, "Invalid Ethereum address format",
+			ErrorData{ErrorID: ErrorIDInvalidAddress, Field: "address", Reason: fmt.Sprintf("invalid address: %s", address)}), nil
 	}
 
 	expectedAddress := h.signer.Address().String()
@@ -98,156 +228,919 @@ func (h *SignHandler) handleEthSign(_ context.Context, request *internaljsonrpc.
 			"expected": expectedAddress,
 			"provided": address,
 		}).Warn("Address mismatch in eth_sign")
-		return h.CreateInvalidParamsResponse(request.ID, "Address mismatch"), nil
+		return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodeInvalidParams, "Address mismatch",
+			ErrorData{ErrorID: ErrorIDAddressMismatch, Field: "address", Reason: "address is not the signer address"}), nil
 	}
 
 	h.logger.WithFields(logrus.Fields{
 		"data_length": len(data),
 	}).Info("Signing data")
 
-	signatureHex, err := h.signer.Sign(data)
+	// 与 geth 一致，签名数据的 EIP-191 消息哈希而非原始数据
+	signatureHex, err := h.signWithCache(signer.TextHash(data))
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to sign data")
-		return h.CreateErrorResponse(request.ID, internaljsonrpc.CodeInternalError,
-			"Failed to sign data", err.Error()), nil
+		return h.signErrorResponse(request, "Failed to sign data", err), nil
 	}
 
-	signature := hex.EncodeToString(signatureHex)
-
 	h.logger.WithFields(logrus.Fields{
 		"address": h.signer.Address().String(),
 	}).Info("Data signed successfully")
-	return h.CreateSuccessResponse(request.ID, signature)
+
+	if h.txConfig.SignatureFormat == config.SignatureFormatRSV {
+		rsv, err := signer.NewSignatureRSV(signatureHex)
+		if err != nil {
+			return h.internalError(request, ErrorIDEncodeFailed, "Failed to sign data", err, false), nil
+		}
+		return h.CreateSuccessResponse(request.ID, rsv)
+	}
+
+	return h.CreateSuccessResponse(request.ID, hex.EncodeToString(signatureHex))
 }
 
-// handleEthSignTransaction 处理 eth_signTransaction 方法
-func (h *SignHandler) handleEthSignTransaction(_ context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
-	tx, err := signer.ParseJSONRPCTransaction(request.Params)
+// defaultKeyIDProvider 由能够报告默认密钥 ID 的签名器实现
+type defaultKeyIDProvider interface {
+	DefaultKeyID() string
+}
+
+// signWithCache 签名 eth_sign 消息哈希；启用签名缓存时相同密钥和消息直接返回已缓存的签名
+func (h *SignHandler) signWithCache(hash []byte) ([]byte, error) {
+	if h.signCache == nil {
+		return h.signer.Sign(hash)
+	}
+
+	keyID := h.signer.Address().String()
+	if p, ok := h.signer.(defaultKeyIDProvider); ok {
+		keyID = p.DefaultKeyID()
+	}
+	key := signCacheKey{keyID: keyID, hash: ethgo.BytesToHash(hash)}
+
+	if signature, ok := h.signCache.get(key); ok {
+		h.logger.WithField("key_id", keyID).Debug("Returning cached eth_sign signature")
+		return signature, nil
+	}
+
+	signature, err := h.signer.Sign(hash)
 	if err != nil {
-		h.logger.WithError(err).Warn("Failed to parse eth_signTransaction params")
-		return h.CreateInvalidParamsResponse(request.ID, fmt.Sprintf("Invalid transaction parameters: %v", err)), nil
+		return nil, err
 	}
+	h.signCache.add(key, signature)
+	return signature, nil
+}
 
-	h.logger.WithFields(logrus.Fields{
-		"from": tx.From.String(),
-		"to":   tx.To,
-	}).Info("Signing transaction")
+// chainIDProvider 由能够报告所配置链 ID 的签名器实现
+type chainIDProvider interface {
+	ChainID() *big.Int
+}
+
+// resolvedChainIDProvider 由按链 ID 策略解析签名链 ID 的签名器实现（MultiKeySigner）
+type resolvedChainIDProvider interface {
+	ResolvedChainID() (*big.Int, error)
+}
 
-	if tx.From.String() != "" && !utils.IsValidEthAddress(tx.From.String()) {
-		h.logger.WithField("from", tx.From.String()).Warn("Invalid From address format in eth_signTransaction")
-		return h.CreateInvalidParamsResponse(request.ID, "Invalid From address format"), nil
+// errChainIDUnresolved 签名器无法解析签名链 ID（如下游不可达或 fail 策略下链 ID 不一致）
+var errChainIDUnresolved = errors.New("failed to resolve signer chain ID")
+
+// signerChainID 返回签名器当前签名使用的链 ID
+//
+// 配置了链 ID 解析器时返回按 signer-chain-id-policy 解析出的链 ID，否则返回配置的链 ID；
+// 签名器不报告链 ID 时返回 nil。解析失败的错误包装 errChainIDUnresolved
+func (h *SignHandler) signerChainID() (*big.Int, error) {
+	if provider, ok := h.signer.(resolvedChainIDProvider); ok {
+		chainID, err := provider.ResolvedChainID()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errChainIDUnresolved, err)
+		}
+		return chainID, nil
+	}
+	if provider, ok := h.signer.(chainIDProvider); ok {
+		return provider.ChainID(), nil
+	}
+	return nil, nil
+}
+
+// handleEthSignTypedData 处理 eth_signTypedData_v4 方法
+func (h *SignHandler) handleEthSignTypedData(_ context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
+	address, typedData, err := signer.ParseSignTypedDataParams(request.Params)
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to parse eth_signTypedData_v4 params")
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid parameters", err), nil
+	}
+
+	if !utils.IsValidEthAddress(address) {
+		h.logger.WithField("address", address).Warn("Invalid Ethereum address format")
+		return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodeInvalidParams, "Invalid Ethereum address format",
+			ErrorData{ErrorID: ErrorIDInvalidAddress, Field: "address", Reason: fmt.Sprintf("invalid address: %s", address)}), nil
 	}
 
 	expectedAddress := h.signer.Address().String()
-	if tx.From.String() != "" && !strings.EqualFold(tx.From.String(), expectedAddress) {
+	if !strings.EqualFold(address, expectedAddress) {
 		h.logger.WithFields(logrus.Fields{
 			"expected": expectedAddress,
-			"provided": tx.From.String(),
-		}).Warn("From address mismatch in eth_signTransaction")
-		return h.CreateInvalidParamsResponse(request.ID, "From address mismatch"), nil
+			"provided": address,
+		}).Warn("Address mismatch in eth_signTypedData_v4")
+		return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodeInvalidParams, "Address mismatch",
+			ErrorData{ErrorID: ErrorIDAddressMismatch, Field: "address", Reason: "address is not the signer address"}), nil
+	}
+
+	if err := h.verifyTypedDataDomain(typedData); err != nil {
+		if errors.Is(err, errChainIDUnresolved) {
+			return h.internalError(request, ErrorIDChainIDUnavailable, "Failed to resolve chain ID", err, true), nil
+		}
+		return h.invalidParamsError(request, ErrorIDInvalidTypedData, "Invalid typed data domain", withField("domain", err)), nil
+	}
+
+	digest, err := typedData.Hash()
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to hash typed data")
+		return h.invalidParamsError(request, ErrorIDInvalidTypedData, "Invalid typed data", err), nil
 	}
 
-	signedTx, err := h.signer.SignTransaction(&tx.Transaction)
+	h.logger.WithField("primary_type", typedData.PrimaryType).Info("Signing typed data")
+
+	sig, err := h.signer.Sign(digest)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to sign transaction")
-		return h.CreateErrorResponse(request.ID, internaljsonrpc.CodeInternalError,
-			"Failed to sign transaction", err.Error()), nil
+		return h.signErrorResponse(request, "Failed to sign typed data", err), nil
+	}
+
+	if len(sig) != 65 {
+		return h.internalError(request, ErrorIDEncodeFailed, "Failed to sign typed data",
+			fmt.Errorf("invalid signature length: expected 65, got %d", len(sig)), false), nil
+	}
+	// 与钱包 eth_signTypedData_v4 的结果一致：0x 前缀，v 为 27/28
+	sig = append([]byte(nil), sig...)
+	if sig[64] < 27 {
+		sig[64] += 27
 	}
 
 	h.logger.WithFields(logrus.Fields{
+		"address": expectedAddress,
+	}).Info("Typed data signed successfully")
+	return h.CreateSuccessResponse(request.ID, "0x"+hex.EncodeToString(sig))
+}
+
+// verifyTypedDataDomain 校验 EIP-712 域的 chainId 与签名器的链 ID 一致
+//
+// 域未声明 chainId 时，严格模式下拒绝，否则仅记录警告
+func (h *SignHandler) verifyTypedDataDomain(typedData *signer.TypedData) error {
+	domainChainID, err := typedData.DomainChainID()
+	if err != nil {
+		h.logger.WithError(err).Warn("Invalid chainId in EIP-712 domain")
+		return err
+	}
+
+	if domainChainID == nil {
+		if h.txConfig.StrictEIP712Domain {
+			h.logger.Warn("Rejecting EIP-712 domain without chainId")
+			return fmt.Errorf("domain chainId is required")
+		}
+		h.logger.Warn("EIP-712 domain omits chainId; signature is not bound to a chain")
+		return nil
+	}
+
+	expected, err := h.signerChainID()
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to resolve signer chain ID for EIP-712 domain check")
+		return err
+	}
+	if expected == nil {
+		return nil
+	}
+	if domainChainID.Cmp(expected) != 0 {
+		h.logger.WithFields(logrus.Fields{
+			"expected": expected,
+			"provided": domainChainID,
+		}).Warn("EIP-712 domain chainId mismatch")
+		return fmt.Errorf("domain chainId %s does not match signer chainId %s", domainChainID, expected)
+	}
+	return nil
+}
+
+// handleEthSignTransaction 处理 eth_signTransaction 方法
+func (h *SignHandler) handleEthSignTransaction(ctx context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
+	tx, err := signer.ParseJSONRPCTransaction(request.Params)
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to parse eth_signTransaction params")
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err), nil
+	}
+
+	if err := h.resolveFrom(&tx, request.Method); err != nil {
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err), nil
+	}
+
+	if tx.Remark, err = sanitizeRemark(tx.Remark); err != nil {
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err), nil
+	}
+
+	h.logger.WithFields(withRemark(logrus.Fields{
 		"from": tx.From.String(),
 		"to":   tx.To,
-	}).Info("Transaction signed successfully")
-	return h.CreateSuccessResponse(request.ID, signedTx)
+	}, tx.Remark)).Info("Signing transaction")
+
+	if err := h.validateTransactionFields(&tx, true); err != nil {
+		h.logger.WithError(err).Warn("Invalid transaction fields in eth_signTransaction")
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err), nil
+	}
+	if err := h.checkChainID(tx.ChainID, true); err != nil {
+		return h.chainIDErrorResponse(request, err), nil
+	}
+
+	if resp := h.checkPolicy(ctx, request, &tx.Transaction); resp != nil {
+		return resp, nil
+	}
+
+	signedTx, err := h.signWithRemark(&tx)
+	if err != nil {
+		return h.signErrorResponse(request, "Failed to sign transaction", err), nil
+	}
+
+	result, err := newSignTransactionResult(signedTx)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to encode signed transaction")
+		return h.internalError(request, ErrorIDEncodeFailed, "Failed to encode signed transaction", err, false), nil
+	}
+
+	h.logger.WithFields(withRemark(logrus.Fields{
+		"from":    tx.From.String(),
+		"to":      tx.To,
+		"tx_hash": result.Hash.String(),
+	}, tx.Remark)).Info("Transaction signed successfully")
+	return h.CreateSuccessResponse(request.ID, result)
+}
+
+// maxSummaryRemarkLength 审批摘要备注的最大长度（字符数）
+const maxSummaryRemarkLength = 256
+
+// sanitizeRemark 清理客户端提供的审批备注：控制字符（含换行）替换为空格，合并连续空白并去除首尾空白，
+// 避免伪造多行日志或审批界面内容；超过 maxSummaryRemarkLength 个字符时返回错误
+func sanitizeRemark(remark string) (string, error) {
+	cleaned := strings.Join(strings.FieldsFunc(remark, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}), " ")
+	if utf8.RuneCountInString(cleaned) > maxSummaryRemarkLength {
+		return "", withField("remark", fmt.Errorf("remark exceeds %d characters", maxSummaryRemarkLength))
+	}
+	return cleaned, nil
+}
+
+// withRemark 备注非空时将其加入审计日志字段
+func withRemark(fields logrus.Fields, remark string) logrus.Fields {
+	if remark != "" {
+		fields["remark"] = remark
+	}
+	return fields
+}
+
+// signWithRemark 签名交易；交易带有备注且签名器支持审批摘要时，备注随转账摘要提交给 KMS 审批人
+//
+// 不支持审批摘要的签名器（PKCS#11、local）没有审批流程，备注仅记录在日志中
+func (h *SignHandler) signWithRemark(tx *signer.JSONRPCTransaction) (*ethgo.Transaction, error) {
+	if tx.Remark == "" || !h.supportsSummary() {
+		return h.signer.SignTransaction(&tx.Transaction)
+	}
+	summary := &kms.SignSummary{Remark: tx.Remark}
+	if err := h.completeSummary(summary, tx); err != nil {
+		return nil, err
+	}
+	return h.signTransactionWithSummary(&tx.Transaction, summary)
+}
+
+// handleSignTransactionWithSummary 处理 web3signer_signTransactionWithSummary 方法
+//
+// 与 eth_signTransaction 相同，但由客户端提供审批人看到的摘要
+func (h *SignHandler) handleSignTransactionWithSummary(ctx context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
+	tx, summary, err := signer.ParseTransactionWithSummaryParams(request.Params)
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to parse web3signer_signTransactionWithSummary params")
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid parameters", err), nil
+	}
+
+	if err := h.resolveFrom(&tx, request.Method); err != nil {
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err), nil
+	}
+
+	if err := h.validateTransactionFields(&tx, true); err != nil {
+		h.logger.WithError(err).Warn("Invalid transaction fields in web3signer_signTransactionWithSummary")
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err), nil
+	}
+	if err := h.checkChainID(tx.ChainID, false); err != nil {
+		return h.chainIDErrorResponse(request, err), nil
+	}
+
+	if err := h.completeSummary(summary, &tx); err != nil {
+		h.logger.WithError(err).Warn("Invalid approval summary")
+		return h.invalidParamsError(request, ErrorIDInvalidSummary, "Invalid summary", err), nil
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"from":         tx.From.String(),
+		"to":           tx.To,
+		"summary_type": summary.Type,
+	}).Info("Signing transaction with summary")
+
+	if resp := h.checkPolicy(ctx, request, &tx.Transaction); resp != nil {
+		return resp, nil
+	}
+
+	signedTx, err := h.signTransactionWithSummary(&tx.Transaction, summary)
+	if err != nil {
+		return h.signErrorResponse(request, "Failed to sign transaction", err), nil
+	}
+
+	result, err := newSignTransactionResult(signedTx)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to encode signed transaction")
+		return h.internalError(request, ErrorIDEncodeFailed, "Failed to encode signed transaction", err, false), nil
+	}
+
+	return h.CreateSuccessResponse(request.ID, result)
+}
+
+// erc20TransferSelector ERC-20 transfer(address,uint256) 的函数选择器
+var erc20TransferSelector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+
+// decodeERC20Transfer 解析 ERC-20 transfer 调用数据，返回收款地址与金额；不是 transfer 调用时返回 false
+func decodeERC20Transfer(input []byte) (ethgo.Address, *big.Int, bool) {
+	if len(input) != 4+32+32 || !bytes.Equal(input[:4], erc20TransferSelector) {
+		return ethgo.ZeroAddress, nil, false
+	}
+	// 地址参数高 12 字节必须为 0
+	for _, b := range input[4:16] {
+		if b != 0 {
+			return ethgo.ZeroAddress, nil, false
+		}
+	}
+	return ethgo.BytesToAddress(input[16:36]), new(big.Int).SetBytes(input[36:68]), true
+}
+
+// completeSummary 校验客户端提供的摘要，并用交易字段补全缺省值
+//
+// to、amount 始终取自交易本身（ERC-20 transfer 取自调用数据），客户端提供的值不一致时返回错误，
+// 避免审批人看到的转账与实际签名的交易不同；原生代币转账的 token 只能为 ETH
+func (h *SignHandler) completeSummary(summary *kms.SignSummary, tx *signer.JSONRPCTransaction) error {
+	if summary.Type == "" {
+		summary.Type = string(kms.SummaryTypeTransfer)
+	}
+	if summary.Type != string(kms.SummaryTypeTransfer) {
+		return fmt.Errorf("unsupported summary type %q", summary.Type)
+	}
+
+	expectedAddress := h.signer.Address().String()
+	if summary.From == "" {
+		summary.From = expectedAddress
+	} else if !strings.EqualFold(summary.From, expectedAddress) {
+		return fmt.Errorf("summary from address does not match signer address")
+	}
+
+	var to string
+	if tx.To != nil {
+		to = tx.To.String()
+	}
+	amount := new(big.Int)
+	if tx.Value != nil {
+		amount.Set(tx.Value)
+	}
+	if recipient, value, ok := decodeERC20Transfer(tx.Input); ok && tx.To != nil {
+		if amount.Sign() != 0 {
+			return withField("value", fmt.Errorf("token transfer must not carry a native value"))
+		}
+		if summary.Token == "" || strings.EqualFold(summary.Token, "ETH") {
+			return withField("token", fmt.Errorf("summary token must name the transferred token"))
+		}
+		to, amount = recipient.String(), value
+	} else if summary.Token == "" {
+		summary.Token = "ETH"
+	} else if !strings.EqualFold(summary.Token, "ETH") {
+		return withField("token", fmt.Errorf("summary token must be ETH for a native transfer"))
+	}
+
+	if summary.To != "" && !strings.EqualFold(summary.To, to) {
+		return withField("to", fmt.Errorf("summary to address does not match transaction"))
+	}
+	summary.To = to
+
+	if summary.Amount != "" {
+		if value, ok := new(big.Int).SetString(summary.Amount, 10); !ok || value.Cmp(amount) != 0 {
+			return withField("amount", fmt.Errorf("summary amount does not match transaction"))
+		}
+	}
+	summary.Amount = amount.String()
+
+	// 访问列表始终取自交易本身，确保审批人看到的与实际签名的一致
+	summary.WithAccessList(tx.AccessList)
+
+	remark, err := sanitizeRemark(summary.Remark)
+	if err != nil {
+		return fmt.Errorf("summary %w", err)
+	}
+	summary.Remark = remark
+	return nil
+}
+
+// supportsSummary 检查签名器（MultiKeySigner 时为其默认密钥）是否支持审批摘要
+func (h *SignHandler) supportsSummary() bool {
+	switch s := h.signer.(type) {
+	case *signer.KeySnapshot:
+		return s.SupportsSummary()
+	case *signer.MultiKeySigner:
+		client, err := s.GetClient(s.DefaultKeyID())
+		if err != nil {
+			return false
+		}
+		_, ok := client.(*signer.MPCKMSSigner)
+		return ok
+	case *signer.MPCKMSSigner:
+		return true
+	default:
+		return false
+	}
+}
+
+// signTransactionWithSummary 使用审批摘要签名交易，签名器需支持审批摘要
+func (h *SignHandler) signTransactionWithSummary(tx *ethgo.Transaction, summary *kms.SignSummary) (*ethgo.Transaction, error) {
+	switch s := h.signer.(type) {
+	case *signer.KeySnapshot:
+		return s.SignTransactionWithSummary(tx, summary)
+	case *signer.MultiKeySigner:
+		return s.SignTransactionWithSummary(tx, s.DefaultKeyID(), summary)
+	case *signer.MPCKMSSigner:
+		return s.SignTransactionWithSummary(tx, summary)
+	default:
+		return nil, fmt.Errorf("signer does not support approval summaries")
+	}
 }
 
 // handleEthSendTransaction 处理 eth_sendTransaction 方法
 func (h *SignHandler) handleEthSendTransaction(ctx context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
 	tx, err := h.validateRequest(request)
 	if err != nil {
-		return h.CreateInvalidParamsResponse(request.ID, fmt.Sprintf("Invalid transaction parameters: %v", err)), nil
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err), nil
 	}
 
-	nonce, err := h.fetchNonce(tx)
-	if err != nil {
-		return h.CreateErrorResponse(request.ID, internaljsonrpc.CodeInternalError,
-			"Failed to get nonce", err.Error()), nil
+	// 幂等键：重试的请求直接返回首次广播的交易哈希，避免重复签名和广播
+	idempotencyKey := h.idempotencyKey(ctx, request, tx)
+	if idempotencyKey != "" {
+		cached, err := h.idempotency.reserve(idempotencyKey)
+		if err != nil {
+			return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodeServerErrorStart, "Duplicate request in progress",
+				ErrorData{ErrorID: ErrorIDDuplicateRequest, Reason: err.Error(), Retryable: true}), nil
+		}
+		if cached != nil {
+			h.logger.WithField("idempotency_key", idempotencyKey).Info("Returning cached result for idempotent eth_sendTransaction")
+			return &internaljsonrpc.Response{
+				JSONRPC: internaljsonrpc.JSONRPCVersion,
+				Result:  cached,
+				ID:      request.ID,
+			}, nil
+		}
 	}
 
-	tx.Nonce = nonce
+	response, err := h.sendTransaction(ctx, request, tx)
+	if idempotencyKey != "" {
+		if err == nil && response.Error == nil {
+			h.idempotency.complete(idempotencyKey, response.Result)
+		} else {
+			h.idempotency.release(idempotencyKey)
+		}
+	}
+	return response, err
+}
 
-	if err := h.fetchGasPrice(tx); err != nil {
-		return h.CreateErrorResponse(request.ID, internaljsonrpc.CodeInternalError,
-			"Failed to get gasPrice", err.Error()), nil
+// idempotencyKey 返回请求的幂等键，交易参数中的 idempotencyKey 优先于请求头
+//
+// 幂等键按交易的 from 地址隔离，不同账户的请求使用相同的幂等键时互不影响
+func (h *SignHandler) idempotencyKey(ctx context.Context, request *internaljsonrpc.Request, tx *signer.JSONRPCTransaction) string {
+	if h.idempotency == nil {
+		return ""
 	}
+	key := idempotencyKeyFromParams(request.Params)
+	if key == "" {
+		key = IdempotencyKeyFromContext(ctx)
+	}
+	if key == "" {
+		return ""
+	}
+	return tx.From.String() + "/" + key
+}
 
-	if err := h.estimateGasIfNeeded(tx); err != nil {
-		return h.CreateErrorResponse(request.ID, internaljsonrpc.CodeInternalError,
-			"Failed to estimate gas", err.Error()), nil
+// sendTransaction 填充交易字段、签名并广播
+func (h *SignHandler) sendTransaction(ctx context.Context, request *internaljsonrpc.Request, tx *signer.JSONRPCTransaction) (*internaljsonrpc.Response, error) {
+	gasSource := gasSourceProvided
+	if tx.Gas == 0 {
+		gasSource = gasSourceEstimated
+	}
+
+	if resp := h.fillTransaction(ctx, request, tx); resp != nil {
+		return resp, nil
+	}
+
+	// 相同的交易（按签名前的哈希）在缓存窗口内重复提交时返回原交易哈希，不再签名和转发
+	replayKey, replayed := h.lookupReplay(tx)
+	if replayed != nil {
+		h.logger.WithField("signing_hash", replayKey.String()).Warn("Replayed transaction detected, returning original hash without signing")
+		return &internaljsonrpc.Response{
+			JSONRPC: internaljsonrpc.JSONRPCVersion,
+			Result:  replayed,
+			ID:      request.ID,
+		}, nil
 	}
 
 	signedTx, err := h.signTransaction(tx)
 	if err != nil {
-		return h.CreateErrorResponse(request.ID, internaljsonrpc.CodeInternalError,
-			"Failed to sign transaction", err.Error()), nil
+		resp := h.signErrorResponse(request, "Failed to sign transaction", err)
+		if data, ok := resp.Error.Data.(ErrorData); ok && data.ErrorID == ErrorIDSignFailed {
+			txOutcomes.Inc(txOutcomeSignFailed)
+		}
+		return resp, nil
 	}
+	txOutcomes.Inc(txOutcomeSigned)
 
 	forwardResponse, err := h.forwardTransaction(ctx, request, signedTx)
 	if err != nil {
-		return h.CreateErrorResponse(request.ID, internaljsonrpc.CodeInternalError,
-			"Failed to forward transaction", err.Error()), nil
+		txOutcomes.Inc(txOutcomeForwardFailed)
+		return h.internalError(request, ErrorIDForwardFailed, "Failed to forward transaction", err, true), nil
 	}
 
 	if forwardResponse.Error != nil {
+		observeDownstreamRejection(forwardResponse.Error)
 		return forwardResponse, nil
 	}
+	txOutcomes.Inc(txOutcomeForwarded)
+	if h.replay != nil && replayKey != (ethgo.Hash{}) {
+		h.replay.add(replayKey, forwardResponse.Result)
+	}
 
-	h.logger.WithFields(logrus.Fields{
+	if h.gasLimits != nil {
+		var txHash ethgo.Hash
+		if err := json.Unmarshal(forwardResponse.Result, &txHash); err == nil {
+			h.gasLimits.add(txHash, signedTx.Gas, gasSource)
+		}
+	}
+
+	h.logger.WithFields(withRemark(logrus.Fields{
 		"from": tx.From.String(),
 		"to":   tx.To,
-	}).Info("Transaction sent successfully")
+	}, tx.Remark)).Info("Transaction sent successfully")
 	return forwardResponse, nil
 }
 
+// handleSignRawTransaction 处理 web3signer_signRawTransaction 方法
+//
+// 与 eth_sendTransaction 相同地填充 nonce、gas 与费用并签名，但不转发到下游，
+// 而是返回可直接广播的已签名交易，供通过自有基础设施广播的客户端使用
+func (h *SignHandler) handleSignRawTransaction(ctx context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
+	tx, err := h.validateRequest(request)
+	if err != nil {
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err), nil
+	}
+
+	signedTx, resp := h.fillAndSignTransaction(ctx, request, tx)
+	if resp != nil {
+		return resp, nil
+	}
+
+	rawTx, err := signer.EncodeRawTransaction(signedTx)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to encode signed transaction")
+		return h.internalError(request, ErrorIDEncodeFailed, "Failed to encode signed transaction", err, false), nil
+	}
+
+	h.logger.WithFields(withRemark(logrus.Fields{
+		"from":    tx.From.String(),
+		"to":      tx.To,
+		"nonce":   signedTx.Nonce,
+		"tx_hash": ethgo.BytesToHash(ethgo.Keccak256(rawTx)).String(),
+	}, tx.Remark)).Info("Transaction signed for external broadcast")
+	return h.CreateSuccessResponse(request.ID, "0x"+hex.EncodeToString(rawTx))
+}
+
+// fillAndSignTransaction 填充 nonce、gas 与费用，执行余额与策略检查后签名交易
+//
+// 失败时返回应直接返回给客户端的错误响应
+func (h *SignHandler) fillAndSignTransaction(ctx context.Context, request *internaljsonrpc.Request, tx *signer.JSONRPCTransaction) (*ethgo.Transaction, *internaljsonrpc.Response) {
+	if resp := h.fillTransaction(ctx, request, tx); resp != nil {
+		return nil, resp
+	}
+
+	signedTx, err := h.signTransaction(tx)
+	if err != nil {
+		return nil, h.signErrorResponse(request, "Failed to sign transaction", err)
+	}
+	return signedTx, nil
+}
+
+// lookupReplay 计算交易签名前的重放缓存键并查询缓存，命中时返回原交易的下游结果
+//
+// 缓存键为签名地址与签名哈希的组合：KMS 签名不是确定性的，同一交易每次签名得到的
+// 已签名交易哈希不同，只有签名前的哈希能识别重复提交
+func (h *SignHandler) lookupReplay(tx *signer.JSONRPCTransaction) (ethgo.Hash, json.RawMessage) {
+	if h.replay == nil {
+		return ethgo.Hash{}, nil
+	}
+	chainID, err := h.signerChainID()
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to resolve signer chain ID, skipping replay detection")
+		return ethgo.Hash{}, nil
+	}
+	signingHash, err := signer.SigningHash(&tx.Transaction, chainID)
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to compute signing hash, skipping replay detection")
+		return ethgo.Hash{}, nil
+	}
+	key := ethgo.BytesToHash(ethgo.Keccak256(tx.From[:], signingHash[:]))
+	if result, ok := h.replay.lookup(key); ok {
+		return key, result
+	}
+	return key, nil
+}
+
+// fillTransaction 填充 nonce、gas 与费用并执行余额与策略检查
+//
+// 失败时返回应直接返回给客户端的错误响应
+func (h *SignHandler) fillTransaction(ctx context.Context, request *internaljsonrpc.Request, tx *signer.JSONRPCTransaction) *internaljsonrpc.Response {
+	nonceProvided := tx.Nonce != 0
+	nonce, err := h.fetchNonce(tx)
+	if err != nil {
+		return h.internalError(request, ErrorIDNonceUnavailable, "Failed to get nonce", err, true)
+	}
+
+	tx.Nonce = nonce
+
+	// 按 latest 自动填充的 nonce 即为已确认 nonce，无需再检查缺口
+	if nonceProvided || h.nonceBlockTag() != config.BlockTagLatest {
+		if err := h.checkNonceGap(nonce); err != nil {
+			if errors.Is(err, errNonceGapExceeded) {
+				return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodeInvalidParams, fmt.Sprintf("Invalid transaction parameters: %v", err),
+					ErrorData{ErrorID: ErrorIDNonceGapExceeded, Field: "nonce", Reason: err.Error(), Retryable: true})
+			}
+			return h.internalError(request, ErrorIDNonceUnavailable, "Failed to get confirmed nonce", err, true)
+		}
+	}
+
+	if err := h.fetchGasPrice(tx); err != nil {
+		return h.internalError(request, ErrorIDGasPriceUnavailable, "Failed to get gasPrice", err, true)
+	}
+
+	if err := h.estimateGasIfNeeded(tx); err != nil {
+		return h.internalError(request, ErrorIDGasEstimateFailed, "Failed to estimate gas", err, false)
+	}
+
+	if h.txConfig.PreflightBalanceCheck {
+		if err := h.checkBalance(tx); err != nil {
+			if errors.Is(err, errInsufficientFunds) {
+				return h.invalidParamsError(request, ErrorIDInsufficientFunds, "Invalid transaction parameters", withField("value", err))
+			}
+			return h.internalError(request, ErrorIDBalanceUnavailable, "Failed to get balance", err, true)
+		}
+	}
+
+	return h.checkPolicy(ctx, request, &tx.Transaction)
+}
+
+// errInsufficientFunds 签名地址余额不足以支付交易的最大花费
+var errInsufficientFunds = errors.New("insufficient funds")
+
+// checkBalance 检查签名地址余额是否足以支付 value + gas * 单价
+//
+// EIP-1559 交易按 maxFeePerGas 计算，即交易可能的最大花费
+func (h *SignHandler) checkBalance(tx *signer.JSONRPCTransaction) error {
+	feePerGas := new(big.Int).SetUint64(tx.GasPrice)
+	if tx.Type == ethgo.TransactionDynamicFee && tx.MaxFeePerGas != nil {
+		feePerGas = tx.MaxFeePerGas
+	}
+	required := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas), feePerGas)
+	if tx.Value != nil {
+		required.Add(required, tx.Value)
+	}
+
+	balance, err := h.rpc().GetBalance(tx.From, ethgo.Latest)
+	h.observeRPCResult(err)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get balance from downstream")
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	if balance.Cmp(required) < 0 {
+		h.logger.WithFields(logrus.Fields{
+			"from":     tx.From.String(),
+			"balance":  balance.String(),
+			"required": required.String(),
+		}).Warn("Rejected transaction with insufficient balance")
+		return fmt.Errorf("%w: balance %s wei, required %s wei (value + gas * fee)", errInsufficientFunds, balance, required)
+	}
+	return nil
+}
+
+var (
+	// errMissingFrom 交易未指定 from 且不允许省略
+	errMissingFrom = errors.New("from address is required")
+	// errFromMismatch 交易的 from 不是签名地址
+	errFromMismatch = errors.New("from address mismatch")
+)
+
+// resolveFrom 校验交易的 from 地址必须是签名地址
+//
+// 未指定 from 时使用签名地址；tx-require-from 开启时拒绝，
+// 避免客户端遗漏 from 时被意外地用本签名器的账户签名
+func (h *SignHandler) resolveFrom(tx *signer.JSONRPCTransaction, method string) error {
+	expected := h.signer.Address()
+	if !tx.FromSet {
+		if h.txConfig.RequireFrom {
+			h.logger.WithField("method", method).Warn("Rejected transaction without from address")
+			return errMissingFrom
+		}
+		tx.From = expected
+		return nil
+	}
+	if tx.From != expected {
+		h.logger.WithFields(logrus.Fields{
+			"method":   method,
+			"expected": expected.String(),
+			"provided": tx.From.String(),
+		}).Warn("From address mismatch")
+		return errFromMismatch
+	}
+	return nil
+}
+
 // validateRequest 验证交易请求参数
 // 解析交易参数并验证 from 地址是否匹配签名器地址
 func (h *SignHandler) validateRequest(request *internaljsonrpc.Request) (*signer.JSONRPCTransaction, error) {
 	tx, err := signer.ParseJSONRPCTransaction(request.Params)
 	if err != nil {
-		h.logger.WithError(err).Warn("Failed to parse eth_sendTransaction params")
-		return nil, fmt.Errorf("invalid transaction parameters: %w", err)
+		h.logger.WithError(err).WithField("method", request.Method).Warn("Failed to parse transaction params")
+		return nil, err
 	}
 
-	if tx.From.String() != "" && !utils.IsValidEthAddress(tx.From.String()) {
-		h.logger.WithField("from", tx.From.String()).Warn("Invalid From address format in eth_sendTransaction")
-		return nil, fmt.Errorf("invalid From address format")
+	if err := h.resolveFrom(&tx, request.Method); err != nil {
+		return nil, err
 	}
 
-	expectedAddress := h.signer.Address().String()
-	if tx.From.String() != "" && !strings.EqualFold(tx.From.String(), expectedAddress) {
-		h.logger.WithFields(logrus.Fields{
-			"expected": expectedAddress,
-			"provided": tx.From.String(),
-		}).Warn("From address mismatch in eth_sendTransaction")
-		return nil, fmt.Errorf("from address mismatch")
+	if tx.Remark, err = sanitizeRemark(tx.Remark); err != nil {
+		return nil, err
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	if err := h.validateTransactionFields(&tx, false); err != nil {
+		h.logger.WithError(err).WithField("method", request.Method).Warn("Invalid transaction fields")
+		return nil, err
+	}
+	if err := h.checkChainID(tx.ChainID, false); err != nil {
+		h.logger.WithError(err).WithField("method", request.Method).Warn("Invalid transaction chain ID")
+		return nil, err
+	}
+
+	h.logger.WithFields(withRemark(logrus.Fields{
 		"from": tx.From.String(),
 		"to":   tx.To,
-	}).Info("Transaction request validated")
+	}, tx.Remark)).Info("Transaction request validated")
 	return &tx, nil
 }
 
+// maxUint256BitLen 以太坊数值字段的最大位宽
+const maxUint256BitLen = 256
+
+// validateTransactionFields 在发起任何下游调用前拒绝明显无效的交易字段
+//
+// requireGas 为 true 时（eth_signTransaction 不会估算 gas）拒绝 gas 为 0 的交易
+func (h *SignHandler) validateTransactionFields(tx *signer.JSONRPCTransaction, requireGas bool) error {
+	if err := h.applyDefaultTransactionType(tx); err != nil {
+		return err
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"value", tx.Value},
+		{"maxFeePerGas", tx.MaxFeePerGas},
+		{"maxPriorityFeePerGas", tx.MaxPriorityFeePerGas},
+		{"chainId", tx.ChainID},
+	} {
+		if field.value == nil {
+			continue
+		}
+		if field.value.Sign() < 0 {
+			return withField(field.name, fmt.Errorf("%s must not be negative", field.name))
+		}
+		if field.value.BitLen() > maxUint256BitLen {
+			return withField(field.name, fmt.Errorf("%s exceeds 256 bits", field.name))
+		}
+	}
+
+	if tx.MaxFeePerGas != nil && tx.MaxPriorityFeePerGas != nil &&
+		tx.MaxFeePerGas.Sign() > 0 && tx.MaxPriorityFeePerGas.Cmp(tx.MaxFeePerGas) > 0 {
+		return withField("maxPriorityFeePerGas", fmt.Errorf("maxPriorityFeePerGas (%s) exceeds maxFeePerGas (%s)", tx.MaxPriorityFeePerGas, tx.MaxFeePerGas))
+	}
+
+	if requireGas && tx.Gas == 0 {
+		return withField("gas", fmt.Errorf("gas must be greater than zero"))
+	}
+	if limit := h.txConfig.MaxGasLimit; limit > 0 && tx.Gas > limit {
+		return withField("gas", fmt.Errorf("gas %d exceeds maximum gas limit %d", tx.Gas, limit))
+	}
+
+	if limit := h.maxCalldataBytes; limit > 0 && len(tx.Input) > limit {
+		return withField("data", fmt.Errorf("data size %d bytes exceeds maximum calldata size %d bytes", len(tx.Input), limit))
+	}
+
+	if limit := h.maxAccessListEntries; limit > 0 && len(tx.AccessList) > limit {
+		return withField("accessList", fmt.Errorf("accessList has %d entries, exceeds maximum of %d", len(tx.AccessList), limit))
+	}
+	if limit := h.maxAccessListStorageKeys; limit > 0 {
+		for i, entry := range tx.AccessList {
+			if len(entry.Storage) > limit {
+				return withField(fmt.Sprintf("accessList[%d].storageKeys", i),
+					fmt.Errorf("accessList[%d] has %d storage keys, exceeds maximum of %d", i, len(entry.Storage), limit))
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkChainID 校验请求指定的 chainId 是签名器自身的链 ID，allowOtherChains 为 true 时也接受 tx-allowed-chain-ids 中的链
+//
+// 只有 eth_signTransaction 允许其它链：其余交易方法从已配置的下游节点填充 nonce、gas 与费用，
+// 或把交易广播到该节点，对其它链的交易没有意义
+func (h *SignHandler) checkChainID(chainID *big.Int, allowOtherChains bool) error {
+	if chainID == nil || chainID.Sign() == 0 {
+		return nil
+	}
+	signerChainID, err := h.signerChainID()
+	if err != nil {
+		return err
+	}
+	if signerChainID != nil && signerChainID.Cmp(chainID) == 0 {
+		return nil
+	}
+	if !allowOtherChains {
+		return withField("chainId", fmt.Errorf("chainId %s does not match the signer chain ID; only eth_signTransaction may sign for other chains", chainID))
+	}
+	for _, allowed := range h.txConfig.AllowedChainIDs {
+		if chainID.IsUint64() && chainID.Uint64() == allowed {
+			return nil
+		}
+	}
+	return withField("chainId", fmt.Errorf("chainId %s is not allowed for this signer", chainID))
+}
+
+// chainIDErrorResponse 将 checkChainID 的错误转换为响应：无法解析签名链 ID 时返回可重试的内部错误，否则为参数错误
+func (h *SignHandler) chainIDErrorResponse(request *internaljsonrpc.Request, err error) *internaljsonrpc.Response {
+	if errors.Is(err, errChainIDUnresolved) {
+		return h.internalError(request, ErrorIDChainIDUnavailable, "Failed to resolve chain ID", err, true)
+	}
+	return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid transaction parameters", err)
+}
+
+// applyDefaultTransactionType 按 tx-default-type 设置交易类型
+//
+// 请求中与配置类型不一致的费用字段直接报错，避免混用 legacy 与 EIP-1559 字段
+func (h *SignHandler) applyDefaultTransactionType(tx *signer.JSONRPCTransaction) error {
+	switch h.txConfig.DefaultType {
+	case config.TxTypeEIP1559:
+		if tx.GasPrice != 0 {
+			return withField("gasPrice", fmt.Errorf("gasPrice is not allowed for EIP-1559 transactions (tx-default-type is eip1559), use maxFeePerGas and maxPriorityFeePerGas"))
+		}
+		tx.Type = ethgo.TransactionDynamicFee
+	case config.TxTypeLegacy:
+		if tx.Type == ethgo.TransactionDynamicFee {
+			return withField("maxFeePerGas", fmt.Errorf("maxFeePerGas and maxPriorityFeePerGas are not allowed for legacy transactions (tx-default-type is legacy), use gasPrice"))
+		}
+		if len(tx.AccessList) > 0 || tx.Type == ethgo.TransactionAccessList {
+			return withField("accessList", fmt.Errorf("accessList is not allowed for legacy transactions (tx-default-type is legacy)"))
+		}
+		tx.Type = ethgo.TransactionLegacy
+	}
+	return nil
+}
+
+// blockTag 区块标签（latest/pending/safe/finalized/earliest）
+//
+// ethgo.BlockNumber 仅支持 latest/pending/earliest，safe 与 finalized 需通过该类型原样传给下游
+type blockTag string
+
+// Location 实现 ethgo.BlockNumberOrHash
+func (t blockTag) Location() string {
+	return string(t)
+}
+
+// nonceBlockTag 返回自动填充 nonce 时使用的区块标签，未配置时为 latest
+func (h *SignHandler) nonceBlockTag() blockTag {
+	if h.txConfig.NonceBlockTag == "" {
+		return config.BlockTagLatest
+	}
+	return blockTag(h.txConfig.NonceBlockTag)
+}
+
 // fetchNonce 从下游获取账户 nonce
-// 如果交易已提供 nonce（非零），则直接使用；否则从下游获取最新 nonce
+// 如果交易已提供 nonce（非零），则直接使用；否则按配置的区块标签从下游获取 nonce
 func (h *SignHandler) fetchNonce(tx *signer.JSONRPCTransaction) (uint64, error) {
 	if tx.Nonce != 0 {
 		h.logger.WithField("nonce", tx.Nonce).Debug("Using provided nonce")
 		return tx.Nonce, nil
 	}
 
-	nonce, err := h.downstreamRPC.Eth().GetNonce(h.signer.Address(), ethgo.Latest)
+	nonce, err := h.rpc().GetNonce(h.signer.Address(), h.nonceBlockTag())
+	h.observeRPCResult(err)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get nonce from downstream")
 		return 0, fmt.Errorf("failed to get nonce: %w", err)
@@ -257,10 +1150,60 @@ func (h *SignHandler) fetchNonce(tx *signer.JSONRPCTransaction) (uint64, error)
 	return nonce, nil
 }
 
+// errNonceGapExceeded 交易 nonce 领先已确认 nonce 达到 NonceGapLimit
+var errNonceGapExceeded = errors.New("nonce gap limit exceeded")
+
+// checkNonceGap 检查交易 nonce 领先下游已确认（latest）nonce 的数量是否达到 NonceGapLimit
+//
+// 防止客户端故障时连续签发大量无法确认的交易堆积在交易池中；未配置上限时不检查
+func (h *SignHandler) checkNonceGap(nonce uint64) error {
+	limit := h.txConfig.NonceGapLimit
+	if limit <= 0 {
+		return nil
+	}
+
+	confirmed, err := h.rpc().GetNonce(h.signer.Address(), ethgo.Latest)
+	h.observeRPCResult(err)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get confirmed nonce from downstream")
+		return fmt.Errorf("failed to get confirmed nonce: %w", err)
+	}
+	if nonce < confirmed || nonce-confirmed < uint64(limit) {
+		return nil
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"nonce":           nonce,
+		"confirmed_nonce": confirmed,
+		"limit":           limit,
+	}).Warn("Rejected transaction exceeding nonce gap limit")
+	return fmt.Errorf("%w: nonce %d is %d ahead of confirmed nonce %d, limit is %d",
+		errNonceGapExceeded, nonce, nonce-confirmed, confirmed, limit)
+}
+
 // fetchGasPrice 获取并填充 gasPrice
 // 根据交易类型填充相应的 gas price 字段（Legacy/AccessList 使用 GasPrice，DynamicFee 使用 MaxFeePerGas/MaxPriorityFeePerGas）
 func (h *SignHandler) fetchGasPrice(tx *signer.JSONRPCTransaction) error {
-	gasPrice, err := h.downstreamRPC.Eth().GasPrice()
+	if tx.Type == ethgo.TransactionDynamicFee && h.txConfig.FeeHistoryEnabled {
+		maxFee, tip, err := h.suggestFeesFromHistory()
+		if err == nil {
+			if tx.MaxPriorityFeePerGas == nil || tx.MaxPriorityFeePerGas.Sign() == 0 {
+				tx.MaxPriorityFeePerGas = tip
+			}
+			if tx.MaxFeePerGas == nil || tx.MaxFeePerGas.Sign() == 0 {
+				tx.MaxFeePerGas = maxFee
+			}
+			// maxPriorityFeePerGas 不能超过 maxFeePerGas
+			if tx.MaxPriorityFeePerGas.Cmp(tx.MaxFeePerGas) > 0 {
+				tx.MaxPriorityFeePerGas = new(big.Int).Set(tx.MaxFeePerGas)
+			}
+			return nil
+		}
+		h.logger.WithError(err).Warn("eth_feeHistory unavailable, falling back to eth_gasPrice")
+	}
+
+	gasPrice, err := h.rpc().GasPrice()
+	h.observeRPCResult(err)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get gasPrice from downstream")
 		return fmt.Errorf("failed to get gasPrice: %w", err)
@@ -288,6 +1231,63 @@ func (h *SignHandler) fetchGasPrice(tx *signer.JSONRPCTransaction) error {
 	return nil
 }
 
+// suggestFeesFromHistory 基于 eth_feeHistory 计算 EIP-1559 费用
+//
+// maxPriorityFeePerGas 取最近区块指定百分位奖励的平均值，
+// maxFeePerGas = 下一区块 baseFee * multiplier + maxPriorityFeePerGas
+func (h *SignHandler) suggestFeesFromHistory() (maxFee, tip *big.Int, err error) {
+	cfg := h.txConfig
+	blocks := cfg.FeeHistoryBlocks
+	if blocks <= 0 {
+		blocks = config.DefaultFeeHistoryBlocks
+	}
+	multiplier := cfg.BaseFeeMultiplier
+	if multiplier < 1 {
+		multiplier = config.DefaultBaseFeeMultiplier
+	}
+	percentile := config.DefaultFeeHistoryPercentile
+	if cfg.FeeHistoryPercentile != nil {
+		percentile = *cfg.FeeHistoryPercentile
+	}
+
+	var history ethgojsonrpc.FeeHistory
+	err = h.rpc().Call("eth_feeHistory", &history,
+		fmt.Sprintf("0x%x", blocks), ethgo.Latest.String(), []float64{percentile})
+	h.observeRPCResult(err)
+	if err != nil {
+		return nil, nil, fmt.Errorf("eth_feeHistory failed: %w", err)
+	}
+	if len(history.BaseFee) == 0 || history.BaseFee[len(history.BaseFee)-1] == nil {
+		return nil, nil, fmt.Errorf("eth_feeHistory returned no base fee")
+	}
+	// baseFeePerGas 最后一个元素为下一区块的 baseFee
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	tip = new(big.Int)
+	samples := 0
+	for _, rewards := range history.Reward {
+		if len(rewards) == 0 || rewards[0] == nil {
+			continue
+		}
+		tip.Add(tip, rewards[0])
+		samples++
+	}
+	if samples > 0 {
+		tip.Div(tip, big.NewInt(int64(samples)))
+	}
+
+	scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee), big.NewFloat(multiplier)).Int(nil)
+	maxFee = scaled.Add(scaled, tip)
+
+	h.logger.WithFields(logrus.Fields{
+		"baseFee":              baseFee,
+		"maxFeePerGas":         maxFee,
+		"maxPriorityFeePerGas": tip,
+	}).Debug("Computed EIP-1559 fees from eth_feeHistory")
+
+	return maxFee, tip, nil
+}
+
 // estimateGasIfNeeded 估算 gas（如果需要）
 // 如果 gas 为 0，调用 eth_estimateGas 并增加 20% 作为安全边界
 func (h *SignHandler) estimateGasIfNeeded(tx *signer.JSONRPCTransaction) error {
@@ -314,7 +1314,7 @@ func (h *SignHandler) estimateGasIfNeeded(tx *signer.JSONRPCTransaction) error {
 		callMsg.Data = tx.Input
 	}
 
-	estimatedGas, err := h.downstreamRPC.Eth().EstimateGas(callMsg)
+	estimatedGas, err := h.estimateGas(callMsg)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to estimate gas")
 		return fmt.Errorf("failed to estimate gas: %w", err)
@@ -328,12 +1328,45 @@ func (h *SignHandler) estimateGasIfNeeded(tx *signer.JSONRPCTransaction) error {
 	return nil
 }
 
+// estimateGas 调用 eth_estimateGas，配置了区块标签时作为第二个参数传给下游
+func (h *SignHandler) estimateGas(callMsg *ethgo.CallMsg) (uint64, error) {
+	tag := h.txConfig.EstimateGasBlockTag
+	if tag == "" {
+		gas, err := h.rpc().EstimateGas(callMsg)
+		h.observeRPCResult(err)
+		return gas, err
+	}
+	var out ethgo.ArgUint64
+	err := h.rpc().Call("eth_estimateGas", &out, callMsg, tag)
+	h.observeRPCResult(err)
+	if err != nil {
+		return 0, err
+	}
+	return out.Uint64(), nil
+}
+
+// checkPolicy 签名前调用预签名钩子；钩子返回错误时返回策略拒绝响应，否则返回 nil
+func (h *SignHandler) checkPolicy(ctx context.Context, request *internaljsonrpc.Request, tx *ethgo.Transaction) *internaljsonrpc.Response {
+	if h.preSignHook == nil {
+		return nil
+	}
+
+	// 交易总是由签名地址签名，省略 from 时也以签名地址提交给策略引擎
+	txCopy := tx.Copy()
+	txCopy.From = h.signer.Address()
+	if err := h.preSignHook.Evaluate(ctx, txCopy, request.Method); err != nil {
+		h.logger.WithError(err).WithField("method", request.Method).Warn("Pre-sign policy check rejected transaction")
+		return h.CreateStructuredErrorResponse(request, internaljsonrpc.CodePolicyDenied, "Policy denied",
+			ErrorData{ErrorID: ErrorIDPolicyDenied, Reason: err.Error()})
+	}
+	return nil
+}
+
 // signTransaction 签名交易
 // 调用签名器对交易进行签名
 func (h *SignHandler) signTransaction(tx *signer.JSONRPCTransaction) (*ethgo.Transaction, error) {
-	signedTx, err := h.signer.SignTransaction(&tx.Transaction)
+	signedTx, err := h.signWithRemark(tx)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to sign transaction")
 		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
@@ -344,19 +1377,99 @@ func (h *SignHandler) signTransaction(tx *signer.JSONRPCTransaction) (*ethgo.Tra
 	return signedTx, nil
 }
 
+// handleEthCall 处理 eth_call 方法
+//
+// 调用对象未指定 from 时注入签名地址，然后原样转发到下游；
+// 参数无法解析时不做改写，由下游返回错误
+func (h *SignHandler) handleEthCall(ctx context.Context, request *internaljsonrpc.Request) (*internaljsonrpc.Response, error) {
+	forwardRequest := *request
+	if params, ok := injectCallFrom(request.Params, h.signer.Address().String()); ok {
+		forwardRequest.Params = params
+		h.logger.WithField("from", h.signer.Address().String()).Debug("Injected default from address into eth_call")
+	}
+
+	response, err := h.client.ForwardRequest(ctx, &forwardRequest)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to forward eth_call to downstream")
+		return h.internalError(request, ErrorIDForwardFailed, "Failed to forward request", err, true), nil
+	}
+
+	response.ID = request.ID
+	response.JSONRPC = internaljsonrpc.JSONRPCVersion
+	return response, nil
+}
+
+// injectCallFrom 在 eth_call 调用对象缺少 from（或为 null/空字符串）时写入 from
+//
+// 返回改写后的参数以及是否发生了改写
+func injectCallFrom(params json.RawMessage, from string) (json.RawMessage, bool) {
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil || len(args) == 0 {
+		return nil, false
+	}
+
+	var call map[string]json.RawMessage
+	if err := json.Unmarshal(args[0], &call); err != nil || call == nil {
+		return nil, false
+	}
+
+	if existing, ok := call["from"]; ok {
+		var value *string
+		if err := json.Unmarshal(existing, &value); err != nil || (value != nil && *value != "") {
+			return nil, false
+		}
+	}
+
+	fromJSON, err := json.Marshal(from)
+	if err != nil {
+		return nil, false
+	}
+	call["from"] = fromJSON
+
+	callJSON, err := json.Marshal(call)
+	if err != nil {
+		return nil, false
+	}
+	args[0] = callJSON
+
+	rewritten, err := json.Marshal(args)
+	if err != nil {
+		return nil, false
+	}
+	return rewritten, true
+}
+
+// fanoutDelay 转发前随机等待 [0, FanoutJitterMs] 毫秒，分散同时签名的交易到达下游的时间
+func (h *SignHandler) fanoutDelay(ctx context.Context) error {
+	if h.txConfig.FanoutJitterMs <= 0 {
+		return nil
+	}
+	delay := time.Duration(rand.Int64N(int64(h.txConfig.FanoutJitterMs)+1)) * time.Millisecond //nolint:gosec // jitter does not need a cryptographic source
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // forwardTransaction 转发签名交易到下游
 // RLP 编码签名交易并发送 eth_sendRawTransaction 请求
 func (h *SignHandler) forwardTransaction(ctx context.Context, request *internaljsonrpc.Request, signedTx *ethgo.Transaction) (*internaljsonrpc.Response, error) {
-	rlpBytes := make([]byte, 0)
-	rlpBytes, err := signedTx.MarshalRLPTo(rlpBytes)
+	rawTx, err := signer.EncodeRawTransaction(signedTx)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to marshal transaction to RLP")
 		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
 	}
 
-	rawTxHex := "0x" + hex.EncodeToString(rlpBytes)
+	txHash := ethgo.BytesToHash(ethgo.Keccak256(rawTx))
 
-	paramsBytes, err := json.Marshal([]interface{}{rawTxHex})
+	paramsBytes, err := json.Marshal([]interface{}{"0x" + hex.EncodeToString(rawTx)})
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to marshal eth_sendRawTransaction params")
 		return nil, fmt.Errorf("failed to create forward request: %w", err)
@@ -369,6 +1482,10 @@ func (h *SignHandler) forwardTransaction(ctx context.Context, request *internalj
 		ID:      request.ID,
 	}
 
+	if err := h.fanoutDelay(ctx); err != nil {
+		return nil, fmt.Errorf("failed to forward transaction: %w", err)
+	}
+
 	forwardResponse, err := h.client.ForwardRequest(ctx, forwardRequest)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to forward eth_sendRawTransaction to downstream")
@@ -381,6 +1498,12 @@ func (h *SignHandler) forwardTransaction(ctx context.Context, request *internalj
 	}
 
 	h.logger.Info("Transaction forwarded successfully")
+	if h.sentTxs != nil {
+		h.sentTxs.add(txHash, signedTx)
+	}
+	if h.nonceMonitor != nil {
+		h.nonceMonitor.observeSent(signedTx.Nonce)
+	}
 	forwardResponse.ID = request.ID
 	forwardResponse.JSONRPC = internaljsonrpc.JSONRPCVersion
 	return forwardResponse, nil
@@ -389,7 +1512,8 @@ func (h *SignHandler) forwardTransaction(ctx context.Context, request *internalj
 // IsSignMethod 检查是否为签名方法
 func IsSignMethod(method string) bool {
 	switch method {
-	case "eth_accounts", "eth_sign", "eth_signTransaction", "eth_sendTransaction":
+	case "eth_accounts", "eth_sign", "eth_signTypedData_v4", "eth_signTransaction", "eth_sendTransaction",
+		"web3signer_signTransactionWithSummary", "web3signer_signRawTransaction", "web3signer_signBatch":
 		return true
 	default:
 		return false
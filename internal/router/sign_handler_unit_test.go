@@ -1,14 +1,30 @@
 package router
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/downstream"
 	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/policy"
 	"github.com/mowind/web3signer-go/internal/signer"
 	"github.com/sirupsen/logrus"
 	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
 )
 
 // Test_validateRequest_Success 测试验证请求成功
@@ -68,6 +84,31 @@ func Test_validateRequest_WrongAddress(t *testing.T) {
 	}
 }
 
+// Test_validateRequest_OmittedFrom 测试 tx-require-from 对省略 from 的交易的处理
+func Test_validateRequest_OmittedFrom(t *testing.T) {
+	request := &jsonrpc.Request{
+		JSONRPC: "2.0",
+		Method:  "eth_sendTransaction",
+		ID:      1,
+		Params:  json.RawMessage(`[{"to":"0x0987654321098765432109876543210987654321","gas":"0x5208"}]`),
+	}
+
+	handler := createSimpleTestHandler(t)
+	handler.WithTransactionConfig(config.TransactionConfig{})
+	tx, err := handler.validateRequest(request)
+	if err != nil {
+		t.Fatalf("validateRequest() error = %v", err)
+	}
+	if tx.From != handler.signer.Address() {
+		t.Errorf("from = %s, want signer address %s", tx.From, handler.signer.Address())
+	}
+
+	handler.WithTransactionConfig(config.TransactionConfig{RequireFrom: true})
+	if _, err := handler.validateRequest(request); err == nil || err.Error() != "from address is required" {
+		t.Errorf("validateRequest() error = %v, want 'from address is required'", err)
+	}
+}
+
 // Test_validateRequest_InvalidParams 测试无效参数
 func Test_validateRequest_InvalidParams(t *testing.T) {
 	handler := createSimpleTestHandler(t)
@@ -98,6 +139,1391 @@ func createSimpleTestHandler(t *testing.T) *SignHandler {
 		BaseHandler:   NewBaseHandler("sign", logger),
 		signer:        mpcSigner,
 		client:        mockForward,
-		downstreamRPC: nil,
+		downstreamRPC: newDownstreamRPCState(downstream.NewRPCClient(mockForward)),
+	}
+}
+
+// Test_fetchGasPrice_FeeHistory 测试基于 eth_feeHistory 的 EIP-1559 费用填充
+func Test_fetchGasPrice_FeeHistory(t *testing.T) {
+	feeHistory := `{"oldestBlock":"0x10","baseFeePerGas":["0x64","0x64","0xc8"],"gasUsedRatio":[0.5,0.5],"reward":[["0xa"],["0x14"]]}`
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		historyErr  bool
+		tx          *signer.JSONRPCTransaction
+		wantMaxFee  int64
+		wantTip     int64
+		wantFeeHist bool
+	}{
+		{
+			name:        "fee history fills both fields",
+			enabled:     true,
+			tx:          &signer.JSONRPCTransaction{Transaction: ethgo.Transaction{Type: ethgo.TransactionDynamicFee}},
+			wantMaxFee:  200*3 + 15,
+			wantTip:     15,
+			wantFeeHist: true,
+		},
+		{
+			name:        "user supplied tip is kept",
+			enabled:     true,
+			tx:          &signer.JSONRPCTransaction{Transaction: ethgo.Transaction{Type: ethgo.TransactionDynamicFee, MaxPriorityFeePerGas: big.NewInt(7)}},
+			wantMaxFee:  200*3 + 15,
+			wantTip:     7,
+			wantFeeHist: true,
+		},
+		{
+			name:       "falls back to eth_gasPrice when fee history fails",
+			enabled:    true,
+			historyErr: true,
+			tx:         &signer.JSONRPCTransaction{Transaction: ethgo.Transaction{Type: ethgo.TransactionDynamicFee}},
+			wantMaxFee: 1000,
+			wantTip:    1000,
+		},
+		{
+			name:       "disabled uses eth_gasPrice",
+			tx:         &signer.JSONRPCTransaction{Transaction: ethgo.Transaction{Type: ethgo.TransactionDynamicFee}},
+			wantMaxFee: 1000,
+			wantTip:    1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calledFeeHistory := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					ID     interface{}   `json:"id"`
+					Method string        `json:"method"`
+					Params []interface{} `json:"params"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				id, _ := json.Marshal(req.ID)
+
+				w.Header().Set("Content-Type", "application/json")
+				switch req.Method {
+				case "eth_feeHistory":
+					calledFeeHistory = true
+					if tt.historyErr {
+						_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"error":{"code":-32601,"message":"method not found"}}`))
+						return
+					}
+					// 百分位 0 原样传给下游，不被替换为默认值
+					if len(req.Params) != 3 || req.Params[0] != "0x5" || !reflect.DeepEqual(req.Params[2], []interface{}{float64(0)}) {
+						t.Errorf("unexpected eth_feeHistory params: %v", req.Params)
+					}
+					_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":` + feeHistory + `}`))
+				case "eth_gasPrice":
+					_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":"0x3e8"}`))
+				default:
+					t.Errorf("unexpected method %s", req.Method)
+				}
+			}))
+			defer server.Close()
+
+			rpcClient := downstream.NewRPCClient(&testDownstreamClient{mockServer: server})
+
+			handler := createSimpleTestHandler(t)
+			handler.downstreamRPC = newDownstreamRPCState(rpcClient)
+			handler.WithTransactionConfig(config.TransactionConfig{
+				FeeHistoryEnabled:    tt.enabled,
+				FeeHistoryBlocks:     5,
+				FeeHistoryPercentile: new(float64),
+				BaseFeeMultiplier:    3,
+			})
+
+			if err := handler.fetchGasPrice(tt.tx); err != nil {
+				t.Fatalf("fetchGasPrice() error = %v", err)
+			}
+			if got := tt.tx.MaxFeePerGas.Int64(); got != tt.wantMaxFee {
+				t.Errorf("MaxFeePerGas = %d, want %d", got, tt.wantMaxFee)
+			}
+			if got := tt.tx.MaxPriorityFeePerGas.Int64(); got != tt.wantTip {
+				t.Errorf("MaxPriorityFeePerGas = %d, want %d", got, tt.wantTip)
+			}
+			if calledFeeHistory != tt.wantFeeHist && !tt.historyErr {
+				t.Errorf("eth_feeHistory called = %v, want %v", calledFeeHistory, tt.wantFeeHist)
+			}
+		})
+	}
+}
+
+// Test_checkBalance 测试签名前余额检查
+func Test_checkBalance(t *testing.T) {
+	tests := []struct {
+		name       string
+		balance    string
+		tx         ethgo.Transaction
+		wantErr    bool
+		wantFunds  bool
+		rpcFailure bool
+	}{
+		{
+			name:    "balance covers value and gas",
+			balance: "0x5208",
+			tx:      ethgo.Transaction{Gas: 21000, GasPrice: 1},
+		},
+		{
+			name:      "legacy value plus gas exceeds balance",
+			balance:   "0x5208",
+			tx:        ethgo.Transaction{Gas: 21000, GasPrice: 1, Value: big.NewInt(1)},
+			wantErr:   true,
+			wantFunds: true,
+		},
+		{
+			name:      "dynamic fee uses maxFeePerGas",
+			balance:   "0xa410",
+			tx:        ethgo.Transaction{Type: ethgo.TransactionDynamicFee, Gas: 21000, MaxFeePerGas: big.NewInt(3), MaxPriorityFeePerGas: big.NewInt(1)},
+			wantErr:   true,
+			wantFunds: true,
+		},
+		{
+			name:       "downstream failure",
+			tx:         ethgo.Transaction{Gas: 21000, GasPrice: 1},
+			wantErr:    true,
+			rpcFailure: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					ID     interface{} `json:"id"`
+					Method string      `json:"method"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				id, _ := json.Marshal(req.ID)
+
+				w.Header().Set("Content-Type", "application/json")
+				if req.Method != "eth_getBalance" || tt.rpcFailure {
+					_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"error":{"code":-32000,"message":"unavailable"}}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":"` + tt.balance + `"}`))
+			}))
+			defer server.Close()
+
+			rpcClient := downstream.NewRPCClient(&testDownstreamClient{mockServer: server})
+			handler := createSimpleTestHandler(t)
+			handler.downstreamRPC = newDownstreamRPCState(rpcClient)
+
+			tx := &signer.JSONRPCTransaction{Transaction: tt.tx}
+			err := handler.checkBalance(tx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkBalance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := errors.Is(err, errInsufficientFunds); got != tt.wantFunds {
+				t.Errorf("errors.Is(err, errInsufficientFunds) = %v, want %v (err = %v)", got, tt.wantFunds, err)
+			}
+		})
+	}
+}
+
+// Test_fillBlockTags 测试自动填充 nonce 与 gas 时使用配置的区块标签
+func Test_fillBlockTags(t *testing.T) {
+	tests := []struct {
+		name             string
+		txConfig         config.TransactionConfig
+		wantNonceParams  string
+		wantEstimateArgs int
+		wantEstimateTag  string
+	}{
+		{
+			name:             "defaults",
+			wantNonceParams:  `"latest"`,
+			wantEstimateArgs: 1,
+		},
+		{
+			name:             "finalized nonce and safe estimate",
+			txConfig:         config.TransactionConfig{NonceBlockTag: config.BlockTagFinalized, EstimateGasBlockTag: config.BlockTagSafe},
+			wantNonceParams:  `"finalized"`,
+			wantEstimateArgs: 2,
+			wantEstimateTag:  `"safe"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captured := make(map[string][]json.RawMessage)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					ID     interface{}       `json:"id"`
+					Method string            `json:"method"`
+					Params []json.RawMessage `json:"params"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				captured[req.Method] = req.Params
+				id, _ := json.Marshal(req.ID)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":"0x5208"}`))
+			}))
+			defer server.Close()
+
+			rpcClient := downstream.NewRPCClient(&testDownstreamClient{mockServer: server})
+			handler := createSimpleTestHandler(t)
+			handler.downstreamRPC = newDownstreamRPCState(rpcClient)
+			handler.WithTransactionConfig(tt.txConfig)
+
+			tx := &signer.JSONRPCTransaction{}
+			if nonce, err := handler.fetchNonce(tx); err != nil || nonce != 0x5208 {
+				t.Fatalf("fetchNonce() = %d, %v", nonce, err)
+			}
+			if err := handler.estimateGasIfNeeded(tx); err != nil || tx.Gas != 0x5208*120/100 {
+				t.Fatalf("estimateGasIfNeeded() gas = %d, %v", tx.Gas, err)
+			}
+
+			if params := captured["eth_getTransactionCount"]; len(params) != 2 || string(params[1]) != tt.wantNonceParams {
+				t.Errorf("eth_getTransactionCount params = %s, want block tag %s", params, tt.wantNonceParams)
+			}
+			params := captured["eth_estimateGas"]
+			if len(params) != tt.wantEstimateArgs {
+				t.Fatalf("eth_estimateGas params = %s, want %d arguments", params, tt.wantEstimateArgs)
+			}
+			if tt.wantEstimateTag != "" && string(params[1]) != tt.wantEstimateTag {
+				t.Errorf("eth_estimateGas block tag = %s, want %s", params[1], tt.wantEstimateTag)
+			}
+		})
+	}
+}
+
+// Test_handleEthSignTransaction_RawEnvelope 测试 eth_signTransaction 返回 EIP-2718 编码的 raw
+func Test_handleEthSignTransaction_RawEnvelope(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    string
+		wantType  ethgo.TransactionType
+		wantFirst string
+	}{
+		{
+			name:     "legacy",
+			params:   `[{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x4a817c800","nonce":"0x1"}]`,
+			wantType: ethgo.TransactionLegacy,
+		},
+		{
+			name:      "dynamic fee",
+			params:    `[{"type":"0x2","from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","maxFeePerGas":"0x4a817c800","maxPriorityFeePerGas":"0x3b9aca00","nonce":"0x1","chainId":"0x1"}]`,
+			wantType:  ethgo.TransactionDynamicFee,
+			wantFirst: "0x02",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createSimpleTestHandler(t)
+			resp, err := handler.handleEthSignTransaction(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0", Method: "eth_signTransaction", ID: 1, Params: json.RawMessage(tt.params),
+			})
+			if err != nil || resp.Error != nil {
+				t.Fatalf("unexpected error: %v %+v", err, resp.Error)
+			}
+
+			var result struct {
+				Raw string          `json:"raw"`
+				Tx  json.RawMessage `json:"tx"`
+			}
+			if err := json.Unmarshal(resp.Result, &result); err != nil {
+				t.Fatalf("failed to unmarshal result: %v", err)
+			}
+			if tt.wantFirst != "" && !strings.HasPrefix(result.Raw, tt.wantFirst) {
+				t.Errorf("raw %s should start with type byte %s", result.Raw, tt.wantFirst)
+			}
+
+			raw, err := hex.DecodeString(strings.TrimPrefix(result.Raw, "0x"))
+			if err != nil {
+				t.Fatalf("invalid raw hex: %v", err)
+			}
+			var decoded ethgo.Transaction
+			if err := decoded.UnmarshalRLP(raw); err != nil {
+				t.Fatalf("failed to decode raw: %v", err)
+			}
+			if decoded.Type != tt.wantType || decoded.Nonce != 1 {
+				t.Errorf("decoded type=%d nonce=%d, want type=%d nonce=1", decoded.Type, decoded.Nonce, tt.wantType)
+			}
+			if len(result.Tx) == 0 {
+				t.Error("expected tx object in result")
+			}
+		})
+	}
+}
+
+// Test_validateRequest_InvalidFields 测试明显无效的交易字段在下游调用前被拒绝
+func Test_validateRequest_InvalidFields(t *testing.T) {
+	accessListEntry := `{"address":"0x000000000000000000000000000000000000dead","storageKeys":["0x` +
+		strings.Repeat("00", 32) + `","0x` + strings.Repeat("11", 32) + `"]}`
+
+	tests := []struct {
+		name        string
+		fields      string
+		maxGasLimit uint64
+		maxCalldata int
+		maxAccess   [2]int
+		wantErr     string
+	}{
+		{name: "valid", fields: `"gas":"0x5208","value":"0x1"`},
+		{name: "negative value", fields: `"gas":"0x5208","value":"0x-1"`, wantErr: "invalid hex in field 'value': '0x-1'"},
+		{name: "value above 256 bits", fields: `"gas":"0x5208","value":"0x1` + strings.Repeat("0", 64) + `"`, wantErr: "value exceeds 256 bits"},
+		{name: "gas above limit", fields: `"gas":"0x1c9c381"`, maxGasLimit: 30000000, wantErr: "exceeds maximum gas limit"},
+		{name: "gas at limit", fields: `"gas":"0x1c9c380"`, maxGasLimit: 30000000},
+		{name: "priority fee above max fee", fields: `"gas":"0x5208","maxFeePerGas":"0x1","maxPriorityFeePerGas":"0x2"`, wantErr: "exceeds maxFeePerGas"},
+		{name: "short to address", fields: `"gas":"0x5208","to":"0x1234"`, wantErr: "invalid address format in field 'to'"},
+		{name: "invalid gasPrice hex", fields: `"gas":"0x5208","gasPrice":"0xzz"`, wantErr: "invalid hex in field 'gasPrice'"},
+		{name: "data above limit", fields: `"gas":"0x5208","data":"0x` + strings.Repeat("ab", 5) + `"`, maxCalldata: 4, wantErr: "exceeds maximum calldata size"},
+		{name: "data at limit", fields: `"gas":"0x5208","data":"0x` + strings.Repeat("ab", 4) + `"`, maxCalldata: 4},
+		{name: "access list entries above limit", fields: `"gas":"0x5208","accessList":[` + accessListEntry + `,` + accessListEntry + `]`, maxAccess: [2]int{1, 10}, wantErr: "accessList has 2 entries"},
+		{name: "access list storage keys above limit", fields: `"gas":"0x5208","accessList":[` + accessListEntry + `]`, maxAccess: [2]int{10, 1}, wantErr: "accessList[0] has 2 storage keys"},
+		{name: "access list at limit", fields: `"gas":"0x5208","accessList":[` + accessListEntry + `]`, maxAccess: [2]int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createSimpleTestHandler(t)
+			handler.WithTransactionConfig(config.TransactionConfig{MaxGasLimit: tt.maxGasLimit})
+			handler.WithMaxCalldataBytes(tt.maxCalldata)
+			handler.WithMaxAccessList(tt.maxAccess[0], tt.maxAccess[1])
+
+			_, err := handler.validateRequest(&jsonrpc.Request{
+				JSONRPC: "2.0",
+				Method:  "eth_sendTransaction",
+				ID:      1,
+				Params:  json.RawMessage(`[{"from":"0x1234567890123456789012345678901234567890",` + tt.fields + `}]`),
+			})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// Test_validateRequest_DefaultType 测试 tx-default-type 强制交易类型
+func Test_validateRequest_DefaultType(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultType string
+		fields      string
+		wantType    ethgo.TransactionType
+		wantErr     string
+	}{
+		{name: "auto infers legacy", defaultType: config.TxTypeAuto, fields: `"gas":"0x5208"`, wantType: ethgo.TransactionLegacy},
+		{name: "auto infers eip1559", defaultType: config.TxTypeAuto, fields: `"gas":"0x5208","maxFeePerGas":"0x2"`, wantType: ethgo.TransactionDynamicFee},
+		{name: "eip1559 upgrades ambiguous", defaultType: config.TxTypeEIP1559, fields: `"gas":"0x5208"`, wantType: ethgo.TransactionDynamicFee},
+		{name: "eip1559 keeps access list", defaultType: config.TxTypeEIP1559, fields: `"gas":"0x5208","accessList":[]`, wantType: ethgo.TransactionDynamicFee},
+		{name: "eip1559 rejects gasPrice", defaultType: config.TxTypeEIP1559, fields: `"gas":"0x5208","gasPrice":"0x1"`, wantErr: "gasPrice is not allowed"},
+		{name: "legacy keeps ambiguous", defaultType: config.TxTypeLegacy, fields: `"gas":"0x5208","gasPrice":"0x1"`, wantType: ethgo.TransactionLegacy},
+		{name: "legacy rejects eip1559 fees", defaultType: config.TxTypeLegacy, fields: `"gas":"0x5208","maxPriorityFeePerGas":"0x1"`, wantErr: "not allowed for legacy"},
+		{name: "legacy rejects access list", defaultType: config.TxTypeLegacy, fields: `"gas":"0x5208","accessList":[]`, wantErr: "accessList is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createSimpleTestHandler(t)
+			handler.WithTransactionConfig(config.TransactionConfig{DefaultType: tt.defaultType})
+
+			tx, err := handler.validateRequest(&jsonrpc.Request{
+				JSONRPC: "2.0",
+				Method:  "eth_sendTransaction",
+				ID:      1,
+				Params:  json.RawMessage(`[{"from":"0x1234567890123456789012345678901234567890",` + tt.fields + `}]`),
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tx.Type != tt.wantType {
+				t.Errorf("type = %v, want %v", tx.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+// Test_checkChainID_Methods 测试请求 chainId 的允许列表校验：只有 eth_signTransaction 可签名其它链的交易
+func Test_checkChainID_Methods(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		chainID string
+		allowed []uint64
+		wantErr bool
+	}{
+		{name: "sign: signer chain", method: "eth_signTransaction", chainID: "0x1"},
+		{name: "sign: allowed chain", method: "eth_signTransaction", chainID: "0x89", allowed: []uint64{137}},
+		{name: "sign: chain not allowed", method: "eth_signTransaction", chainID: "0x89", wantErr: true},
+		{name: "sign: chain not in allowlist", method: "eth_signTransaction", chainID: "0xa", allowed: []uint64{137}, wantErr: true},
+		{name: "send: signer chain", method: "eth_sendTransaction", chainID: "0x1"},
+		{name: "send: allowed chain", method: "eth_sendTransaction", chainID: "0x89", allowed: []uint64{137}, wantErr: true},
+		{name: "raw: allowed chain", method: "web3signer_signRawTransaction", chainID: "0x89", allowed: []uint64{137}, wantErr: true},
+		{name: "summary: allowed chain", method: "web3signer_signTransactionWithSummary", chainID: "0x89", allowed: []uint64{137}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createSimpleTestHandler(t)
+			handler.WithTransactionConfig(config.TransactionConfig{AllowedChainIDs: tt.allowed})
+
+			tx := `{"from":"0x1234567890123456789012345678901234567890","to":"0x2222222222222222222222222222222222222222","gas":"0x5208","gasPrice":"0x1","nonce":"0x1","chainId":"` + tt.chainID + `"}`
+			params := `[` + tx + `]`
+			if tt.method == "web3signer_signTransactionWithSummary" {
+				params = `[` + tx + `,{"type":"TRANSFER"}]`
+			}
+			resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0",
+				Method:  tt.method,
+				ID:      1,
+				Params:  json.RawMessage(params),
+			})
+			if err != nil {
+				t.Fatalf("Handle() error: %v", err)
+			}
+			var chainRejected bool
+			if resp.Error != nil {
+				data, ok := resp.Error.Data.(ErrorData)
+				chainRejected = ok && data.Field == "chainId"
+			}
+			if chainRejected != tt.wantErr {
+				t.Errorf("chainId rejected = %v, want %v (response error: %+v)", chainRejected, tt.wantErr, resp.Error)
+			}
+		})
+	}
+}
+
+// Test_fanoutDelay 测试转发前的随机延迟
+func Test_fanoutDelay(t *testing.T) {
+	handler := createSimpleTestHandler(t)
+	if err := handler.fanoutDelay(context.Background()); err != nil {
+		t.Errorf("fanoutDelay() disabled error = %v", err)
+	}
+
+	handler.WithTransactionConfig(config.TransactionConfig{FanoutJitterMs: 20})
+	start := time.Now()
+	if err := handler.fanoutDelay(context.Background()); err != nil {
+		t.Errorf("fanoutDelay() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("fanoutDelay() took %v, want at most 20ms", elapsed)
+	}
+
+	// 取消的上下文立即返回（延迟为 0 时也可能直接成功）
+	handler.WithTransactionConfig(config.TransactionConfig{FanoutJitterMs: 60000})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := handler.fanoutDelay(ctx); err != nil && err != context.Canceled {
+		t.Errorf("fanoutDelay() cancelled error = %v", err)
+	}
+}
+
+// Test_handleEthSignTransaction_FieldError 测试解析错误消息指明出错的字段
+func Test_handleEthSignTransaction_FieldError(t *testing.T) {
+	handler := createSimpleTestHandler(t)
+	resp, err := handler.handleEthSignTransaction(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0",
+		Method:  "eth_signTransaction",
+		ID:      1,
+		Params:  json.RawMessage(`[{"from":"0x1234567890123456789012345678901234567890","gas":"0x5208","nonce":"7"}]`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Invalid transaction parameters: missing 0x prefix in field 'nonce': '7'"
+	if resp.Error == nil || resp.Error.Code != jsonrpc.CodeInvalidParams || resp.Error.Message != want {
+		t.Errorf("error = %+v, want invalid params error %q", resp.Error, want)
+	}
+}
+
+// Test_handleEthSignTransaction_ZeroGas 测试 eth_signTransaction 拒绝 gas 为 0 的交易
+func Test_handleEthSignTransaction_ZeroGas(t *testing.T) {
+	handler := createSimpleTestHandler(t)
+	resp, err := handler.handleEthSignTransaction(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0",
+		Method:  "eth_signTransaction",
+		ID:      1,
+		Params:  json.RawMessage(`[{"from":"0x1234567890123456789012345678901234567890","gas":"0x0","gasPrice":"0x1"}]`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != jsonrpc.CodeInvalidParams {
+		t.Errorf("expected invalid params error, got %+v", resp.Error)
+	}
+}
+
+// Test_handleEthSignTypedData_DomainChainID 测试 EIP-712 域 chainId 校验
+func Test_handleEthSignTypedData_DomainChainID(t *testing.T) {
+	const from = "0x1234567890123456789012345678901234567890"
+	typedData := func(domain string) string {
+		return `["` + from + `",{"types":{"Greeting":[{"name":"text","type":"string"}]},` +
+			`"primaryType":"Greeting","domain":` + domain + `,"message":{"text":"hi"}}]`
+	}
+
+	tests := []struct {
+		name     string
+		params   string
+		strict   bool
+		wantCode int
+	}{
+		{name: "matching chainId", params: typedData(`{"name":"App","chainId":1}`)},
+		{name: "matching hex chainId", params: typedData(`{"name":"App","chainId":"0x1"}`)},
+		{name: "mismatched chainId", params: typedData(`{"name":"App","chainId":137}`), wantCode: jsonrpc.CodeInvalidParams},
+		{name: "omitted chainId", params: typedData(`{"name":"App"}`)},
+		{name: "omitted chainId strict", params: typedData(`{"name":"App"}`), strict: true, wantCode: jsonrpc.CodeInvalidParams},
+		{name: "address mismatch", params: strings.Replace(typedData(`{"chainId":1}`), from, "0x0987654321098765432109876543210987654321", 1), wantCode: jsonrpc.CodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createSimpleTestHandler(t)
+			handler.WithTransactionConfig(config.TransactionConfig{StrictEIP712Domain: tt.strict})
+
+			resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0", Method: "eth_signTypedData_v4", ID: 1, Params: json.RawMessage(tt.params),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantCode != 0 {
+				if resp.Error == nil || resp.Error.Code != tt.wantCode {
+					t.Fatalf("expected error code %d, got %+v", tt.wantCode, resp.Error)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error response: %+v", resp.Error)
+			}
+			var signature string
+			if err := json.Unmarshal(resp.Result, &signature); err != nil {
+				t.Fatalf("failed to unmarshal signature: %v", err)
+			}
+			if _, err := hex.DecodeString(strings.TrimPrefix(signature, "0x")); err != nil || !strings.HasPrefix(signature, "0x") || len(signature) != 132 {
+				t.Errorf("expected 0x-prefixed 65-byte hex signature, got %q", signature)
+			}
+		})
+	}
+}
+
+// Test_handleEthSignTypedData_Recoverable 测试 eth_signTypedData_v4 的签名可按钱包格式恢复出签名地址
+func Test_handleEthSignTypedData_Recoverable(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	handler := createSimpleTestHandler(t)
+	handler.signer = signer.NewLocalKeystoreSigner(key, big.NewInt(1))
+
+	typedDataJSON := `{"types":{"EIP712Domain":[{"name":"name","type":"string"},{"name":"chainId","type":"uint256"}],` +
+		`"Greeting":[{"name":"text","type":"string"}]},"primaryType":"Greeting","domain":{"name":"App","chainId":1},"message":{"text":"hi"}}`
+	resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0", Method: "eth_signTypedData_v4", ID: 1,
+		Params: json.RawMessage(`["` + key.Address().String() + `",` + typedDataJSON + `]`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("eth_signTypedData_v4 failed: %v %+v", err, resp.Error)
+	}
+
+	var signature string
+	if err := json.Unmarshal(resp.Result, &signature); err != nil {
+		t.Fatalf("failed to unmarshal signature: %v", err)
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || !strings.HasPrefix(signature, "0x") || len(sig) != 65 {
+		t.Fatalf("expected 0x-prefixed 65-byte signature, got %q", signature)
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		t.Fatalf("v = %d, want 27 or 28", sig[64])
+	}
+
+	var typedData signer.TypedData
+	if err := json.Unmarshal([]byte(typedDataJSON), &typedData); err != nil {
+		t.Fatalf("failed to parse typed data: %v", err)
+	}
+	digest, err := typedData.Hash()
+	if err != nil {
+		t.Fatalf("typedData.Hash() error: %v", err)
+	}
+
+	// Ecrecover 使用 0/1 的恢复 ID
+	sig[64] -= 27
+	pub, err := crypto.Ecrecover(digest, sig)
+	if err != nil {
+		t.Fatalf("Ecrecover() error: %v", err)
+	}
+	publicKey, err := crypto.UnmarshalPubkey(pub)
+	if err != nil {
+		t.Fatalf("UnmarshalPubkey() error: %v", err)
+	}
+	if recovered := crypto.PubkeyToAddress(*publicKey); !strings.EqualFold(recovered.Hex(), key.Address().String()) {
+		t.Errorf("recovered address %s, want %s", recovered.Hex(), key.Address())
+	}
+}
+
+// Test_signerChainID_Resolved 测试 chainId 校验使用解析器按策略解析出的链 ID 而非配置的链 ID
+func Test_signerChainID_Resolved(t *testing.T) {
+	const from = "0x1234567890123456789012345678901234567890"
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	var fetchErr error
+	multiKeySigner := signer.NewMultiKeySigner("default-key", big.NewInt(1), logger)
+	if err := multiKeySigner.AddClient("default-key", signer.NewMPCKMSSigner(&testKMSClient{}, "default-key", ethgo.HexToAddress(from), big.NewInt(1))); err != nil {
+		t.Fatalf("Failed to add client: %v", err)
+	}
+	resolver, err := signer.NewChainIDResolver(big.NewInt(1), signer.ChainIDPolicyDownstream, func() (*big.Int, error) {
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return big.NewInt(5), nil
+	}, 0, logger)
+	if err != nil {
+		t.Fatalf("NewChainIDResolver() error: %v", err)
+	}
+	multiKeySigner.SetChainIDResolver(resolver)
+
+	handler := createSimpleTestHandler(t)
+	handler.signer = multiKeySigner
+	handle := func(method, params string) *jsonrpc.Response {
+		t.Helper()
+		resp, err := handler.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: method, ID: 1, Params: json.RawMessage(params)})
+		if err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+		return resp
+	}
+	typedData := func(chainID int) string {
+		return fmt.Sprintf(`["%s",{"types":{"Greeting":[{"name":"text","type":"string"}]},"primaryType":"Greeting","domain":{"name":"App","chainId":%d},"message":{"text":"hi"}}]`, from, chainID)
+	}
+	signTx := `[{"from":"` + from + `","to":"0x2222222222222222222222222222222222222222","gas":"0x5208","gasPrice":"0x1","nonce":"0x1","chainId":"0x5"}]`
+
+	if resp := handle("eth_signTransaction", signTx); resp.Error != nil {
+		t.Errorf("eth_signTransaction with resolved chainId: unexpected error %+v", resp.Error)
+	}
+	if resp := handle("eth_signTypedData_v4", typedData(5)); resp.Error != nil {
+		t.Errorf("typed data with resolved domain chainId: unexpected error %+v", resp.Error)
+	}
+	if resp := handle("eth_signTypedData_v4", typedData(1)); resp.Error == nil || resp.Error.Code != jsonrpc.CodeInvalidParams {
+		t.Errorf("typed data with configured but unresolved chainId: got %+v, want invalid params", resp.Error)
+	}
+
+	// 无法解析链 ID 时返回可重试的内部错误
+	fetchErr = errors.New("downstream unavailable")
+	for _, call := range [][2]string{{"eth_signTypedData_v4", typedData(5)}, {"eth_signTransaction", signTx}} {
+		resp := handle(call[0], call[1])
+		if resp.Error == nil || resp.Error.Code != jsonrpc.CodeInternalError {
+			t.Errorf("%s: got %+v, want internal error", call[0], resp.Error)
+			continue
+		}
+		if data, ok := resp.Error.Data.(ErrorData); !ok || data.ErrorID != ErrorIDChainIDUnavailable || !data.Retryable {
+			t.Errorf("%s: error data = %+v, want retryable %s", call[0], resp.Error.Data, ErrorIDChainIDUnavailable)
+		}
+	}
+}
+
+// summaryCapturingKMSClient 记录签名请求中的审批摘要
+type summaryCapturingKMSClient struct {
+	testKMSClient
+	summary *kms.SignSummary
+}
+
+func (c *summaryCapturingKMSClient) SignWithOptions(ctx context.Context, keyID string, message []byte, encoding kms.DataEncoding, summary *kms.SignSummary, callbackURL string) ([]byte, error) {
+	c.summary = summary
+	return c.Sign(ctx, keyID, message)
+}
+
+// Test_handleSignTransactionWithSummary 测试客户端提供的审批摘要
+func Test_handleSignTransactionWithSummary(t *testing.T) {
+	const (
+		from = "0x1234567890123456789012345678901234567890"
+		tx   = `{"from":"` + from + `","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x4a817c800","value":"0x64","nonce":"0x1"}`
+		// transfer(0x1111…1111, 5000)
+		tokenTx = `{"from":"` + from + `","to":"0x0987654321098765432109876543210987654321","gas":"0xfde8","gasPrice":"0x1","nonce":"0x1",` +
+			`"data":"0xa9059cbb00000000000000000000000011111111111111111111111111111111111111110000000000000000000000000000000000000000000000000000000000001388"}`
+	)
+
+	tests := []struct {
+		name        string
+		params      string
+		wantErr     bool
+		wantSummary kms.SignSummary
+	}{
+		{
+			name:   "defaults filled from transaction",
+			params: `[` + tx + `,{"remark":"payroll"}]`,
+			wantSummary: kms.SignSummary{
+				Type: "TRANSFER", From: from, To: "0x0987654321098765432109876543210987654321",
+				Amount: "100", Token: "ETH", Remark: "payroll",
+			},
+		},
+		{
+			name:   "matching native transfer",
+			params: `[` + tx + `,{"to":"0x0987654321098765432109876543210987654321","amount":"100","token":"eth"}]`,
+			wantSummary: kms.SignSummary{
+				Type: "TRANSFER", From: from, To: "0x0987654321098765432109876543210987654321",
+				Amount: "100", Token: "eth",
+			},
+		},
+		{
+			name:   "token transfer derived from calldata",
+			params: `[` + tokenTx + `,{"token":"USDT"}]`,
+			wantSummary: kms.SignSummary{
+				Type: "TRANSFER", From: from, To: "0x1111111111111111111111111111111111111111",
+				Amount: "5000", Token: "USDT",
+			},
+		},
+		{name: "summary to mismatch", params: `[` + tx + `,{"to":"0x1111111111111111111111111111111111111111"}]`, wantErr: true},
+		{name: "summary amount mismatch", params: `[` + tx + `,{"amount":"1"}]`, wantErr: true},
+		{name: "native transfer with token", params: `[` + tx + `,{"token":"USDT"}]`, wantErr: true},
+		{name: "token transfer to mismatch", params: `[` + tokenTx + `,{"to":"0x0987654321098765432109876543210987654321","token":"USDT"}]`, wantErr: true},
+		{name: "token transfer without token", params: `[` + tokenTx + `,{}]`, wantErr: true},
+		{
+			name: "access list taken from transaction",
+			params: `[{"type":"0x1","from":"` + from + `","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x1","chainId":"0x1",` +
+				`"accessList":[{"address":"0x1111111111111111111111111111111111111111","storageKeys":["0x0000000000000000000000000000000000000000000000000000000000000001"]}]},` +
+				`{"to":"0x0987654321098765432109876543210987654321"}]`,
+			wantSummary: kms.SignSummary{
+				Type: "TRANSFER", From: from, To: "0x0987654321098765432109876543210987654321", Amount: "0", Token: "ETH",
+				AccessList: ethgo.AccessList{{
+					Address: ethgo.HexToAddress("0x1111111111111111111111111111111111111111"),
+					Storage: []ethgo.Hash{ethgo.HexToHash("0x01")},
+				}},
+			},
+		},
+		{name: "missing summary", params: `[` + tx + `]`, wantErr: true},
+		{name: "unknown type", params: `[` + tx + `,{"type":"CONTRACT"}]`, wantErr: true},
+		{name: "summary from mismatch", params: `[` + tx + `,{"from":"0x0987654321098765432109876543210987654321"}]`, wantErr: true},
+		{name: "invalid amount", params: `[` + tx + `,{"amount":"-1"}]`, wantErr: true},
+		{name: "remark too long", params: `[` + tx + `,{"remark":"` + strings.Repeat("x", maxSummaryRemarkLength+1) + `"}]`, wantErr: true},
+		{
+			name:    "transaction from mismatch",
+			params:  `[{"from":"0x0987654321098765432109876543210987654321","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x1"},{}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kmsClient := &summaryCapturingKMSClient{}
+			handler := createSimpleTestHandler(t)
+			handler.signer = signer.NewMPCKMSSigner(kmsClient, "test-key-id", ethgo.HexToAddress(from), big.NewInt(1))
+
+			resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0", Method: "web3signer_signTransactionWithSummary", ID: 1, Params: json.RawMessage(tt.params),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantErr {
+				if resp.Error == nil || resp.Error.Code != jsonrpc.CodeInvalidParams {
+					t.Fatalf("expected invalid params error, got %+v", resp.Error)
+				}
+				if kmsClient.summary != nil {
+					t.Error("KMS should not be called for invalid requests")
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error response: %+v", resp.Error)
+			}
+			if kmsClient.summary == nil {
+				t.Fatal("expected summary to be passed to KMS")
+			}
+			got := *kmsClient.summary
+			got.To = strings.ToLower(got.To)
+			got.From = strings.ToLower(got.From)
+			if !reflect.DeepEqual(got, tt.wantSummary) {
+				t.Errorf("summary = %+v, want %+v", got, tt.wantSummary)
+			}
+		})
+	}
+}
+
+// Test_handleEthSignTransaction_Remark 测试交易对象中的 remark 扩展字段进入 KMS 审批摘要
+func Test_handleEthSignTransaction_Remark(t *testing.T) {
+	const (
+		from = "0x1234567890123456789012345678901234567890"
+		to   = "0x0987654321098765432109876543210987654321"
+	)
+	txWithRemark := func(remark string) string {
+		return `[{"from":"` + from + `","to":"` + to + `","gas":"0x5208","gasPrice":"0x4a817c800","value":"0x64","nonce":"0x7","remark":` + remark + `}]`
+	}
+
+	tests := []struct {
+		name        string
+		params      string
+		wantErr     bool
+		wantSummary *kms.SignSummary
+	}{
+		{
+			name:   "remark sent as transfer summary",
+			params: txWithRemark(`"Payroll batch #42"`),
+			wantSummary: &kms.SignSummary{
+				Type: "TRANSFER", From: from, To: to, Amount: "100", Token: "ETH", Remark: "Payroll batch #42",
+			},
+		},
+		{
+			name:   "control characters replaced",
+			params: txWithRemark(`"  Payroll\nbatch\t #42\u0000 "`),
+			wantSummary: &kms.SignSummary{
+				Type: "TRANSFER", From: from, To: to, Amount: "100", Token: "ETH", Remark: "Payroll batch #42",
+			},
+		},
+		{name: "no remark signs without summary", params: txWithRemark(`null`)},
+		{name: "remark too long", params: txWithRemark(`"` + strings.Repeat("界", maxSummaryRemarkLength+1) + `"`), wantErr: true},
+		{name: "non-string remark", params: txWithRemark(`42`), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kmsClient := &summaryCapturingKMSClient{}
+			handler := createSimpleTestHandler(t)
+			handler.signer = signer.NewMPCKMSSigner(kmsClient, "test-key-id", ethgo.HexToAddress(from), big.NewInt(1))
+
+			resp, err := handler.Handle(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0", Method: "eth_signTransaction", ID: 1, Params: json.RawMessage(tt.params),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantErr {
+				if resp.Error == nil || resp.Error.Code != jsonrpc.CodeInvalidParams {
+					t.Fatalf("expected invalid params error, got %+v", resp.Error)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error response: %+v", resp.Error)
+			}
+
+			if tt.wantSummary == nil {
+				if kmsClient.summary != nil {
+					t.Errorf("expected no summary, got %+v", kmsClient.summary)
+				}
+				return
+			}
+			if kmsClient.summary == nil {
+				t.Fatal("expected summary to be passed to KMS")
+			}
+			got := *kmsClient.summary
+			got.To = strings.ToLower(got.To)
+			got.From = strings.ToLower(got.From)
+			if !reflect.DeepEqual(got, *tt.wantSummary) {
+				t.Errorf("summary = %+v, want %+v", got, *tt.wantSummary)
+			}
+
+			// 摘要签名路径须保留 nonce 与 gas
+			var result struct {
+				Tx struct {
+					Nonce string `json:"nonce"`
+					Gas   string `json:"gas"`
+				} `json:"tx"`
+			}
+			if err := json.Unmarshal(resp.Result, &result); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if result.Tx.Nonce != "0x7" || result.Tx.Gas != "0x5208" {
+				t.Errorf("signed tx nonce = %s, gas = %s, want 0x7 and 0x5208", result.Tx.Nonce, result.Tx.Gas)
+			}
+		})
+	}
+}
+
+func Test_handleEthAccounts_ChecksumAddress(t *testing.T) {
+	h := createSimpleTestHandler(t)
+	h.signer = signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id",
+		ethgo.HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"), big.NewInt(1))
+
+	resp, err := h.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_accounts", ID: 1})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error response: %+v", resp.Error)
+	}
+
+	var accounts []string
+	if err := json.Unmarshal(resp.Result, &accounts); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0] != "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" {
+		t.Errorf("Expected EIP-55 checksummed address, got %v", accounts)
+	}
+}
+
+// callCapturingDownstreamClient 记录转发的 eth_call 请求
+type callCapturingDownstreamClient struct {
+	*testDownstreamClient
+	calls []jsonrpc.Request
+}
+
+func (c *callCapturingDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	if req.Method == "eth_call" {
+		c.calls = append(c.calls, *req)
+		return &jsonrpc.Response{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`"0x"`)}, nil
+	}
+	return c.testDownstreamClient.ForwardRequest(ctx, req)
+}
+
+func Test_handleEthCall_InjectFrom(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	signerAddress := "0x1234567890123456789012345678901234567890"
+	mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", ethgo.HexToAddress(signerAddress), big.NewInt(1))
+
+	tests := []struct {
+		name     string
+		inject   bool
+		params   string
+		wantFrom interface{}
+	}{
+		{name: "missing from is injected", inject: true, params: `[{"to":"0x00000000000000000000000000000000000000aa","data":"0x"},"latest"]`, wantFrom: signerAddress},
+		{name: "safe block tag is kept", inject: true, params: `[{"to":"0x00000000000000000000000000000000000000aa"},"safe"]`, wantFrom: signerAddress},
+		{name: "finalized block tag is kept", inject: false, params: `[{"to":"0x00000000000000000000000000000000000000aa"},"finalized"]`, wantFrom: nil},
+		{name: "null from is injected", inject: true, params: `[{"to":"0x00000000000000000000000000000000000000aa","from":null}]`, wantFrom: signerAddress},
+		{name: "explicit from is kept", inject: true, params: `[{"to":"0x00000000000000000000000000000000000000aa","from":"0x00000000000000000000000000000000000000bb"}]`, wantFrom: "0x00000000000000000000000000000000000000bb"},
+		{name: "disabled leaves request untouched", inject: false, params: `[{"to":"0x00000000000000000000000000000000000000aa"}]`, wantFrom: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downstream := &callCapturingDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+			defer func() { _ = downstream.Close() }()
+
+			router := NewRouterFactory(logger).
+				WithTransactionConfig(config.TransactionConfig{InjectCallFrom: tt.inject}).
+				CreateRouter(mpcSigner, downstream)
+
+			body := `{"jsonrpc":"2.0","id":7,"method":"eth_call","params":` + tt.params + `}`
+			w := httptest.NewRecorder()
+			router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+			var resp jsonrpc.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if resp.Error != nil || resp.ID != float64(7) {
+				t.Fatalf("Unexpected response: %+v", resp)
+			}
+
+			if len(downstream.calls) != 1 {
+				t.Fatalf("Expected 1 forwarded eth_call, got %d", len(downstream.calls))
+			}
+			var params []json.RawMessage
+			var call map[string]interface{}
+			if err := json.Unmarshal(downstream.calls[0].Params, &params); err != nil || len(params) == 0 {
+				t.Fatalf("Failed to unmarshal forwarded params: %v", err)
+			}
+			if err := json.Unmarshal(params[0], &call); err != nil {
+				t.Fatalf("Failed to unmarshal forwarded call object: %v", err)
+			}
+			if got := call["from"]; got != tt.wantFrom {
+				t.Errorf("forwarded from = %v, want %v", got, tt.wantFrom)
+			}
+			var original []json.RawMessage
+			_ = json.Unmarshal([]byte(tt.params), &original)
+			if len(params) != len(original) || (len(original) > 1 && string(params[1]) != string(original[1])) {
+				t.Errorf("forwarded params = %s, want block tag from %s", downstream.calls[0].Params, tt.params)
+			}
+		})
+	}
+}
+
+func Test_handleEthSign_SignatureFormat(t *testing.T) {
+	params := json.RawMessage(`["0x1234567890123456789012345678901234567890","0x` + strings.Repeat("ab", 32) + `"]`)
+
+	signWith := func(format string) *jsonrpc.Response {
+		t.Helper()
+		h := createSimpleTestHandler(t)
+		h.WithTransactionConfig(config.TransactionConfig{SignatureFormat: format})
+		resp, err := h.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_sign", Params: params, ID: 1})
+		if err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+		if resp.Error != nil {
+			t.Fatalf("Unexpected error response: %+v", resp.Error)
+		}
+		return resp
+	}
+
+	var compact string
+	if err := json.Unmarshal(signWith(config.SignatureFormatCompact).Result, &compact); err != nil {
+		t.Fatalf("Expected compact hex string: %v", err)
+	}
+	compactBytes, err := hex.DecodeString(compact)
+	if err != nil || len(compactBytes) != 65 {
+		t.Fatalf("Expected 65-byte compact signature, got %q", compact)
+	}
+
+	var rsv signer.SignatureRSV
+	if err := json.Unmarshal(signWith(config.SignatureFormatRSV).Result, &rsv); err != nil {
+		t.Fatalf("Expected {r, s, v} object: %v", err)
+	}
+	rsvBytes, err := rsv.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error: %v", err)
+	}
+
+	if !reflect.DeepEqual(rsvBytes, compactBytes) {
+		t.Errorf("rsv %x does not round-trip to compact %x", rsvBytes, compactBytes)
+	}
+}
+
+// pendingKMSClient 模拟异步审批模式下需要审批的 KMS
+type pendingKMSClient struct {
+	testKMSClient
+}
+
+func (c *pendingKMSClient) Sign(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+	return nil, &kms.ApprovalPendingError{TaskID: "task-42"}
+}
+
+func (c *pendingKMSClient) SignWithOptions(ctx context.Context, keyID string, message []byte, encoding kms.DataEncoding, summary *kms.SignSummary, callbackURL string) ([]byte, error) {
+	return c.Sign(ctx, keyID, message)
+}
+
+func Test_signErrorResponse_ApprovalPending(t *testing.T) {
+	h := createSimpleTestHandler(t)
+	h.signer = signer.NewMPCKMSSigner(&pendingKMSClient{}, "test-key-id", h.signer.Address(), big.NewInt(1))
+
+	params := json.RawMessage(`["0x1234567890123456789012345678901234567890","0x` + strings.Repeat("ab", 32) + `"]`)
+	resp, err := h.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_sign", Params: params, ID: 1})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != jsonrpc.CodeApprovalPending {
+		t.Fatalf("Expected approval pending error, got %+v", resp.Error)
+	}
+	if data, ok := resp.Error.Data.(ErrorData); !ok || data.TaskID != "task-42" || data.ErrorID != ErrorIDApprovalPending || !data.Retryable {
+		t.Errorf("Expected taskId task-42 in error data, got %#v", resp.Error.Data)
+	}
+}
+
+// denyingPolicyHook 拒绝所有交易并记录评估的方法
+type denyingPolicyHook struct {
+	methods []string
+}
+
+func (h *denyingPolicyHook) Evaluate(_ context.Context, tx *ethgo.Transaction, method string) error {
+	h.methods = append(h.methods, method)
+	return &policy.DeniedError{Reason: "blocked recipient"}
+}
+
+func Test_checkPolicy_DeniesSigning(t *testing.T) {
+	hook := &denyingPolicyHook{}
+	h := createSimpleTestHandler(t)
+	h.WithPreSignHook(hook)
+
+	params := json.RawMessage(`[{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","gasPrice":"0x4a817c800","nonce":"0x0"}]`)
+	resp, err := h.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_signTransaction", Params: params, ID: 1})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != jsonrpc.CodePolicyDenied {
+		t.Fatalf("Expected policy denied error, got %+v", resp.Error)
+	}
+	if data, _ := resp.Error.Data.(ErrorData); !strings.Contains(data.Reason, "blocked recipient") || data.ErrorID != ErrorIDPolicyDenied {
+		t.Errorf("Expected denial reason in error data, got %v", resp.Error.Data)
+	}
+	if !reflect.DeepEqual(hook.methods, []string{"eth_signTransaction"}) {
+		t.Errorf("Expected hook to evaluate eth_signTransaction, got %v", hook.methods)
+	}
+
+	// 不设置钩子时正常签名
+	h.WithPreSignHook(nil)
+	resp, _ = h.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_signTransaction", Params: params, ID: 2})
+	if resp.Error != nil {
+		t.Errorf("Expected signing without hook to succeed, got %+v", resp.Error)
+	}
+}
+
+// countingKMSClient 统计 Sign 调用次数
+type countingKMSClient struct {
+	testKMSClient
+	signs int32
+}
+
+func (c *countingKMSClient) Sign(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+	atomic.AddInt32(&c.signs, 1)
+	return c.testKMSClient.Sign(ctx, keyID, message)
+}
+
+func Test_handleEthSign_SignatureCache(t *testing.T) {
+	sign := func(t *testing.T, cacheSize int, messages ...string) int32 {
+		t.Helper()
+		kmsClient := &countingKMSClient{}
+		h := createSimpleTestHandler(t)
+		h.signer = signer.NewMPCKMSSigner(kmsClient, "test-key-id", h.signer.Address(), big.NewInt(1))
+		h.WithTransactionConfig(config.TransactionConfig{SignCacheSize: cacheSize})
+
+		var first json.RawMessage
+		for i, message := range messages {
+			params := json.RawMessage(`["0x1234567890123456789012345678901234567890","0x` + strings.Repeat(message, 32) + `"]`)
+			resp, err := h.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_sign", Params: params, ID: i})
+			if err != nil || resp.Error != nil {
+				t.Fatalf("Unexpected eth_sign failure: %v %+v", err, resp.Error)
+			}
+			if i == 0 {
+				first = resp.Result
+			} else if message == messages[0] && string(resp.Result) != string(first) {
+				t.Errorf("Expected cached signature %s, got %s", first, resp.Result)
+			}
+		}
+		return atomic.LoadInt32(&kmsClient.signs)
+	}
+
+	if got := sign(t, 10, "ab", "ab", "ab"); got != 1 {
+		t.Errorf("Expected 1 KMS call for repeated message with cache, got %d", got)
+	}
+	if got := sign(t, 10, "ab", "cd", "ab"); got != 2 {
+		t.Errorf("Expected 1 KMS call per distinct message, got %d", got)
+	}
+	if got := sign(t, 0, "ab", "ab"); got != 2 {
+		t.Errorf("Expected every request to reach KMS without cache, got %d", got)
+	}
+}
+
+func TestSignatureCache_KeyedByKeyID(t *testing.T) {
+	cache := newSignatureCache(2)
+	a := signCacheKey{keyID: "k", hash: ethgo.Hash{1}}
+	c := signCacheKey{keyID: "other", hash: ethgo.Hash{1}}
+
+	cache.add(a, []byte{0xa})
+	cache.add(c, []byte{0xc})
+	if sig, ok := cache.get(c); !ok || sig[0] != 0xc {
+		t.Errorf("Expected same hash under another key ID to be cached separately, got (%x, %v)", sig, ok)
+	}
+
+	// 返回副本，调用方修改不影响缓存
+	sig, _ := cache.get(a)
+	sig[0] = 0xff
+	if again, _ := cache.get(a); again[0] != 0xa {
+		t.Errorf("cached signature was mutated: %x", again)
+	}
+}
+
+func Test_handleEthSign_DataEncoding(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	h := createSimpleTestHandler(t)
+	h.signer = signer.NewLocalKeystoreSigner(key, big.NewInt(1))
+
+	tests := []struct {
+		name     string
+		data     string
+		message  []byte
+		wantCode int
+	}{
+		{name: "short message", data: "0xdeadbeef", message: []byte{0xde, 0xad, 0xbe, 0xef}},
+		{name: "empty message", data: "0x", message: []byte{}},
+		{name: "invalid hex", data: "0xzz", wantCode: jsonrpc.CodeInvalidParams},
+		{name: "missing prefix", data: "deadbeef", wantCode: jsonrpc.CodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _ := json.Marshal([]string{key.Address().String(), tt.data})
+			resp, err := h.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "eth_sign", Params: params, ID: 1})
+			if err != nil {
+				t.Fatalf("Handle() error: %v", err)
+			}
+			if tt.wantCode != 0 {
+				if resp.Error == nil || resp.Error.Code != tt.wantCode {
+					t.Fatalf("Expected error code %d, got %+v", tt.wantCode, resp.Error)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("Unexpected error response: %+v", resp.Error)
+			}
+
+			var signatureHex string
+			if err := json.Unmarshal(resp.Result, &signatureHex); err != nil {
+				t.Fatalf("Failed to decode signature: %v", err)
+			}
+			signature, err := hex.DecodeString(signatureHex)
+			if err != nil {
+				t.Fatalf("Invalid signature hex: %v", err)
+			}
+
+			// 签名对象是解码后字节的 EIP-191 消息哈希
+			recovered, err := wallet.Ecrecover(signer.TextHash(tt.message), signature)
+			if err != nil || recovered != key.Address() {
+				t.Errorf("Recovered %s (%v), want %s", recovered, err, key.Address())
+			}
+		})
+	}
+}
+
+// Test_Handle_StructuredErrorData 测试签名处理器错误响应携带结构化 data
+func Test_Handle_StructuredErrorData(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		params    string
+		wantID    string
+		wantField string
+	}{
+		{
+			name:      "undecodable field",
+			method:    "eth_signTransaction",
+			params:    `[{"from":"0x1234567890123456789012345678901234567890","gas":"0x5208","gasPrice":"0xzz"}]`,
+			wantID:    ErrorIDInvalidParams,
+			wantField: "gasPrice",
+		},
+		{
+			name:      "invalid field value",
+			method:    "eth_signTransaction",
+			params:    `[{"from":"0x1234567890123456789012345678901234567890","gas":"0x5208","value":"0x-1"}]`,
+			wantID:    ErrorIDInvalidParams,
+			wantField: "value",
+		},
+		{
+			name:      "from mismatch",
+			method:    "eth_sendTransaction",
+			params:    `[{"from":"0x0987654321098765432109876543210987654321","gas":"0x5208"}]`,
+			wantID:    ErrorIDInvalidParams,
+			wantField: "from",
+		},
+		{
+			name:      "eth_sign address mismatch",
+			method:    "eth_sign",
+			params:    `["0x0987654321098765432109876543210987654321","0xabcd"]`,
+			wantID:    ErrorIDAddressMismatch,
+			wantField: "address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := createSimpleTestHandler(t)
+			resp, err := h.Handle(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0", Method: tt.method, Params: json.RawMessage(tt.params), ID: 1,
+			})
+			if err != nil || resp.Error == nil {
+				t.Fatalf("Expected error response, got %+v (err %v)", resp, err)
+			}
+			data, ok := resp.Error.Data.(ErrorData)
+			if !ok {
+				t.Fatalf("Expected ErrorData, got %#v", resp.Error.Data)
+			}
+			if data.ErrorID != tt.wantID || data.Field != tt.wantField || data.Method != tt.method || data.Retryable {
+				t.Errorf("error data = %+v, want errorId %s field %s method %s", data, tt.wantID, tt.wantField, tt.method)
+			}
+		})
+	}
+}
+
+// Test_handleSignRawTransaction 测试 web3signer_signRawTransaction 填充交易字段并签名，但不转发
+func Test_handleSignRawTransaction(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	testAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
+	mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", testAddress, big.NewInt(1))
+
+	downstream := &countingDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+	defer func() { _ = downstream.Close() }()
+
+	router := NewRouterFactory(logger).CreateRouter(mpcSigner, downstream)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"web3signer_signRawTransaction","params":[{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208","value":"0x64"}]}`
+	w := httptest.NewRecorder()
+	router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+	var resp jsonrpc.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v, body: %s", err, w.Body.String())
+	}
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error: %+v", resp.Error)
+	}
+
+	var rawHex string
+	if err := json.Unmarshal(resp.Result, &rawHex); err != nil {
+		t.Fatalf("Expected raw transaction hex string, got %s", resp.Result)
+	}
+	raw, err := hex.DecodeString(strings.TrimPrefix(rawHex, "0x"))
+	if err != nil {
+		t.Fatalf("Invalid raw transaction hex %q: %v", rawHex, err)
+	}
+	signedTx := &ethgo.Transaction{}
+	if err := signedTx.UnmarshalRLP(raw); err != nil {
+		t.Fatalf("Failed to decode raw transaction: %v", err)
+	}
+
+	// nonce 与 gasPrice 取自下游，与 eth_sendTransaction 相同
+	if signedTx.Nonce != 5 || signedTx.GasPrice != 20000000000 {
+		t.Errorf("Expected filled nonce 5 and gasPrice 20 gwei, got nonce %d gasPrice %d", signedTx.Nonce, signedTx.GasPrice)
+	}
+	if signedTx.Value == nil || signedTx.Value.Int64() != 100 {
+		t.Errorf("Expected value 100, got %v", signedTx.Value)
+	}
+	if got := atomic.LoadInt32(&downstream.sends); got != 0 {
+		t.Errorf("Expected no eth_sendRawTransaction, got %d", got)
+	}
+}
+
+// Test_fillAndSignTransaction_NonceGapLimit 测试 nonce 领先已确认 nonce 过多时拒绝签名
+func Test_fillAndSignTransaction_NonceGapLimit(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", ethgo.HexToAddress("0x1234567890123456789012345678901234567890"), big.NewInt(1))
+	downstream := &countingDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+	defer func() { _ = downstream.Close() }()
+
+	// 下游已确认 nonce 为 5，最多允许 nonce 5 与 6
+	router := NewRouterFactory(logger).
+		WithTransactionConfig(config.TransactionConfig{NonceGapLimit: 2}).
+		CreateRouter(mpcSigner, downstream)
+
+	tests := []struct {
+		name    string
+		nonce   string
+		wantErr bool
+	}{
+		{name: "filled nonce"},
+		{name: "within limit", nonce: `,"nonce":"0x6"`},
+		{name: "at limit", nonce: `,"nonce":"0x7"`, wantErr: true},
+		{name: "far ahead", nonce: `,"nonce":"0x3e8"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"jsonrpc":"2.0","id":1,"method":"web3signer_signRawTransaction","params":[{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208"` + tt.nonce + `}]}`
+			w := httptest.NewRecorder()
+			router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+			var resp jsonrpc.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v, body: %s", err, w.Body.String())
+			}
+			if !tt.wantErr {
+				if resp.Error != nil {
+					t.Fatalf("Unexpected error: %+v", resp.Error)
+				}
+				return
+			}
+			if resp.Error == nil || resp.Error.Code != jsonrpc.CodeInvalidParams {
+				t.Fatalf("Expected invalid params error, got %+v", resp.Error)
+			}
+			data, _ := json.Marshal(resp.Error.Data)
+			if !strings.Contains(string(data), `"errorId":"nonce_gap_exceeded"`) || !strings.Contains(resp.Error.Message, "confirmed nonce 5") {
+				t.Errorf("Expected nonce gap error, got %s %s", resp.Error.Message, data)
+			}
+		})
 	}
 }
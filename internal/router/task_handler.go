@@ -0,0 +1,160 @@
+package router
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/sirupsen/logrus"
+)
+
+// TaskHandler 处理 KMS 审批任务相关的 JSON-RPC 方法
+//
+// 配置了密钥允许列表时，只能查询和取消允许列表中密钥的任务，web3signer_pendingTasks 也只列出这些任务
+type TaskHandler struct {
+	*BaseHandler
+	tasks         kms.TaskManager
+	allowedKeyIDs keyAllowlist
+}
+
+// NewTaskHandler 创建审批任务处理器
+func NewTaskHandler(tasks kms.TaskManager, logger *logrus.Logger) *TaskHandler {
+	return &TaskHandler{
+		BaseHandler: NewBaseHandler("task", logger),
+		tasks:       tasks,
+	}
+}
+
+// WithAllowedKeyIDs 设置允许通过 JSON-RPC 访问其审批任务的密钥 ID，与 SignHandler 使用同一列表
+func (h *TaskHandler) WithAllowedKeyIDs(keyIDs []string) *TaskHandler {
+	h.allowedKeyIDs = newKeyAllowlist(keyIDs)
+	return h
+}
+
+// Method 返回处理器支持的方法名
+func (h *TaskHandler) Method() string {
+	return "task_handler"
+}
+
+// Handle 处理 JSON-RPC 请求
+func (h *TaskHandler) Handle(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	h.LogRequest(request)
+
+	switch request.Method {
+	case "web3signer_getTaskResult":
+		return h.handleGetTaskResult(ctx, request)
+	case "web3signer_cancelTask":
+		return h.handleCancelTask(ctx, request)
+	case "web3signer_pendingTasks":
+		return h.handlePendingTasks(request)
+	default:
+		return h.CreateStructuredErrorResponse(request, jsonrpc.CodeMethodNotFound,
+			"Method not supported by task handler", ErrorData{ErrorID: ErrorIDMethodNotSupported}), nil
+	}
+}
+
+// handleGetTaskResult 处理 web3signer_getTaskResult 方法
+//
+// 参数为 [taskId]，返回 KMS 任务结果；状态为 DONE 时 response 中包含签名
+func (h *TaskHandler) handleGetTaskResult(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	taskID, err := h.parseTaskID(request)
+	if err != nil {
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid parameters", withField("taskId", err)), nil
+	}
+	if resp := h.checkTaskKey(request, taskID); resp != nil {
+		return resp, nil
+	}
+
+	result, err := h.tasks.GetTaskResult(ctx, taskID)
+	if err != nil {
+		h.logger.WithError(err).WithField("task_id", taskID).Error("Failed to get task result")
+		return h.CreateStructuredErrorResponse(request, jsonrpc.CodeInternalError, "Failed to get task result",
+			ErrorData{ErrorID: ErrorIDTaskQueryFailed, Reason: err.Error(), Retryable: true, TaskID: taskID}), nil
+	}
+
+	return h.CreateSuccessResponse(request.ID, result)
+}
+
+// handleCancelTask 处理 web3signer_cancelTask 方法
+//
+// 参数为 [taskId]，返回取消后的任务状态；任务已结束时返回其最终状态
+func (h *TaskHandler) handleCancelTask(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	taskID, err := h.parseTaskID(request)
+	if err != nil {
+		return h.invalidParamsError(request, ErrorIDInvalidParams, "Invalid parameters", withField("taskId", err)), nil
+	}
+	if resp := h.checkTaskKey(request, taskID); resp != nil {
+		return resp, nil
+	}
+
+	result, err := h.tasks.CancelTask(ctx, taskID)
+	if err != nil {
+		h.logger.WithError(err).WithField("task_id", taskID).Error("Failed to cancel task")
+		return h.CreateStructuredErrorResponse(request, jsonrpc.CodeInternalError, "Failed to cancel task",
+			ErrorData{ErrorID: ErrorIDTaskCancelFailed, Reason: err.Error(), Retryable: true, TaskID: taskID}), nil
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"task_id": taskID,
+		"status":  result.Status,
+	}).Info("Task cancel requested")
+
+	return h.CreateSuccessResponse(request.ID, result)
+}
+
+// pendingTaskInfo web3signer_pendingTasks 返回的单个任务
+type pendingTaskInfo struct {
+	kms.PendingTask
+	WaitingSeconds int64 `json:"waitingSeconds"`
+}
+
+// handlePendingTasks 处理 web3signer_pendingTasks 方法
+//
+// 无参数，按开始时间返回服务端正在等待审批的任务及已等待的秒数；等待时间按任务管理器的时钟计算
+func (h *TaskHandler) handlePendingTasks(request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	pending := h.tasks.PendingTasks()
+	now := h.tasks.Clock().Now()
+
+	tasks := make([]pendingTaskInfo, 0, len(pending))
+	for _, task := range pending {
+		if h.allowedKeyIDs.check(task.KeyID) != nil {
+			continue
+		}
+		tasks = append(tasks, pendingTaskInfo{
+			PendingTask:    task,
+			WaitingSeconds: int64(now.Sub(task.StartedAt).Seconds()),
+		})
+	}
+	return h.CreateSuccessResponse(request.ID, tasks)
+}
+
+// checkTaskKey 检查任务的签名密钥是否在允许列表中，不允许时返回授权错误响应
+//
+// 未配置允许列表时不检查；配置了允许列表时，无法确定密钥的任务（非本服务创建或记录已过期）一律拒绝
+func (h *TaskHandler) checkTaskKey(request *jsonrpc.Request, taskID string) *jsonrpc.Response {
+	if h.allowedKeyIDs == nil {
+		return nil
+	}
+	keyID, ok := h.tasks.TaskKeyID(taskID)
+	if !ok {
+		return keyNotAllowedResponse(h.BaseHandler, request, "", fmt.Errorf("%w: key of task %s is unknown", errKeyNotAllowed, taskID))
+	}
+	if err := h.allowedKeyIDs.check(keyID); err != nil {
+		return keyNotAllowedResponse(h.BaseHandler, request, keyID, err)
+	}
+	return nil
+}
+
+// parseTaskID 解析 [taskId] 参数
+func (h *TaskHandler) parseTaskID(request *jsonrpc.Request) (string, error) {
+	params, err := h.ValidateParams(request.Params, 1)
+	if err != nil {
+		return "", err
+	}
+	taskID, ok := params[0].(string)
+	if !ok || taskID == "" {
+		return "", fmt.Errorf("task ID must be a non-empty string")
+	}
+	return taskID, nil
+}
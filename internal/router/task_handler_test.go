@@ -0,0 +1,277 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+// fakeTaskManager 模拟 KMS 审批任务：tasks 中的状态为任务当前状态，keys 为任务的签名密钥
+type fakeTaskManager struct {
+	tasks   map[string]kms.TaskStatus
+	keys    map[string]string
+	pending []kms.PendingTask
+	now     time.Time
+}
+
+func (m *fakeTaskManager) PendingTasks() []kms.PendingTask {
+	return m.pending
+}
+
+func (m *fakeTaskManager) TaskKeyID(taskID string) (string, bool) {
+	keyID, ok := m.keys[taskID]
+	return keyID, ok
+}
+
+func (m *fakeTaskManager) Clock() kms.Clock {
+	return fixedClock{now: m.now}
+}
+
+// fixedClock 始终返回固定时间的时钟
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+func (c fixedClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (m *fakeTaskManager) GetTaskResult(_ context.Context, taskID string) (*kms.TaskResult, error) {
+	status, ok := m.tasks[taskID]
+	if !ok {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+	return &kms.TaskResult{Status: status}, nil
+}
+
+func (m *fakeTaskManager) CancelTask(ctx context.Context, taskID string) (*kms.TaskResult, error) {
+	result, err := m.GetTaskResult(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status == kms.TaskStatusPendingApproval {
+		m.tasks[taskID] = kms.TaskStatusCancelled
+		result.Status = kms.TaskStatusCancelled
+	}
+	return result, nil
+}
+
+func TestTaskHandler_CancelTask(t *testing.T) {
+	handler := NewTaskHandler(&fakeTaskManager{tasks: map[string]kms.TaskStatus{
+		"task-pending": kms.TaskStatusPendingApproval,
+		"task-done":    kms.TaskStatusDone,
+	}}, logrus.New())
+
+	tests := []struct {
+		name       string
+		params     string
+		wantStatus kms.TaskStatus
+		wantCode   int
+	}{
+		{name: "pending task is cancelled", params: `["task-pending"]`, wantStatus: kms.TaskStatusCancelled},
+		{name: "completed task returns final status", params: `["task-done"]`, wantStatus: kms.TaskStatusDone},
+		{name: "unknown task", params: `["task-missing"]`, wantCode: jsonrpc.CodeInternalError},
+		{name: "missing task ID", params: `[]`, wantCode: jsonrpc.CodeInvalidParams},
+		{name: "non-string task ID", params: `[1]`, wantCode: jsonrpc.CodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := handler.Handle(context.Background(), &jsonrpc.Request{
+				JSONRPC: "2.0",
+				Method:  "web3signer_cancelTask",
+				Params:  json.RawMessage(tt.params),
+				ID:      1,
+			})
+			if err != nil {
+				t.Fatalf("Handle() error: %v", err)
+			}
+
+			if tt.wantCode != 0 {
+				if response.Error == nil || response.Error.Code != tt.wantCode {
+					t.Fatalf("Expected error code %d, got %+v", tt.wantCode, response.Error)
+				}
+				return
+			}
+
+			if response.Error != nil {
+				t.Fatalf("Unexpected error: %+v", response.Error)
+			}
+			var result kms.TaskResult
+			if err := json.Unmarshal(response.Result, &result); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", result.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetTaskResult(t *testing.T) {
+	handler := NewTaskHandler(&fakeTaskManager{tasks: map[string]kms.TaskStatus{
+		"task-pending": kms.TaskStatusPendingApproval,
+	}}, logrus.New())
+
+	response, err := handler.Handle(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0",
+		Method:  "web3signer_getTaskResult",
+		Params:  json.RawMessage(`["task-pending"]`),
+		ID:      1,
+	})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if response.Error != nil {
+		t.Fatalf("Unexpected error: %+v", response.Error)
+	}
+	var result kms.TaskResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if result.Status != kms.TaskStatusPendingApproval {
+		t.Errorf("Status = %s, want %s", result.Status, kms.TaskStatusPendingApproval)
+	}
+
+	response, _ = handler.Handle(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0",
+		Method:  "web3signer_getTaskResult",
+		Params:  json.RawMessage(`["task-missing"]`),
+		ID:      2,
+	})
+	if response.Error == nil || response.Error.Code != jsonrpc.CodeInternalError {
+		t.Fatalf("Expected internal error for unknown task, got %+v", response.Error)
+	}
+	if data, ok := response.Error.Data.(ErrorData); !ok || data.ErrorID != ErrorIDTaskQueryFailed || data.TaskID != "task-missing" {
+		t.Errorf("Unexpected error data: %+v", response.Error.Data)
+	}
+}
+
+func TestTaskHandler_PendingTasks(t *testing.T) {
+	startedAt := time.Unix(1000, 0)
+	handler := NewTaskHandler(&fakeTaskManager{pending: []kms.PendingTask{
+		{TaskID: "task-1", KeyID: "key-1", StartedAt: startedAt},
+	}, now: startedAt.Add(90 * time.Second)}, logrus.New())
+
+	response, err := handler.Handle(context.Background(), &jsonrpc.Request{
+		JSONRPC: "2.0",
+		Method:  "web3signer_pendingTasks",
+		ID:      1,
+	})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if response.Error != nil {
+		t.Fatalf("Unexpected error: %+v", response.Error)
+	}
+
+	var tasks []pendingTaskInfo
+	if err := json.Unmarshal(response.Result, &tasks); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].TaskID != "task-1" || tasks[0].KeyID != "key-1" || tasks[0].WaitingSeconds != 90 {
+		t.Errorf("Unexpected pending tasks: %+v", tasks)
+	}
+
+	// 无待审批任务时返回空数组而非 null
+	handler = NewTaskHandler(&fakeTaskManager{}, logrus.New())
+	response, _ = handler.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: "web3signer_pendingTasks", ID: 2})
+	if string(response.Result) != "[]" {
+		t.Errorf("Expected empty array, got %s", response.Result)
+	}
+}
+
+func TestTaskHandler_AllowedKeyIDs(t *testing.T) {
+	tasks := &fakeTaskManager{
+		tasks: map[string]kms.TaskStatus{
+			"task-allowed":  kms.TaskStatusPendingApproval,
+			"task-internal": kms.TaskStatusPendingApproval,
+			"task-unknown":  kms.TaskStatusPendingApproval,
+		},
+		keys: map[string]string{"task-allowed": "key-1", "task-internal": "internal-key"},
+		pending: []kms.PendingTask{
+			{TaskID: "task-allowed", KeyID: "key-1"},
+			{TaskID: "task-internal", KeyID: "internal-key"},
+		},
+	}
+	handler := NewTaskHandler(tasks, logrus.New()).WithAllowedKeyIDs([]string{"key-1"})
+
+	handle := func(method, params string) *jsonrpc.Response {
+		t.Helper()
+		response, err := handler.Handle(context.Background(), &jsonrpc.Request{JSONRPC: "2.0", Method: method, Params: json.RawMessage(params), ID: 1})
+		if err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+		return response
+	}
+
+	for _, method := range []string{"web3signer_getTaskResult", "web3signer_cancelTask"} {
+		if response := handle(method, `["task-allowed"]`); response.Error != nil {
+			t.Errorf("%s on allowed key: unexpected error %+v", method, response.Error)
+		}
+		for _, taskID := range []string{"task-internal", "task-unknown"} {
+			response := handle(method, `["`+taskID+`"]`)
+			if response.Error == nil || response.Error.Code != jsonrpc.CodeKeyNotAllowed {
+				t.Errorf("%s on %s: got %+v, want key not allowed", method, taskID, response.Error)
+				continue
+			}
+			if data, ok := response.Error.Data.(ErrorData); !ok || data.ErrorID != ErrorIDKeyNotAllowed {
+				t.Errorf("%s on %s: error data = %+v", method, taskID, response.Error.Data)
+			}
+		}
+	}
+	if tasks.tasks["task-internal"] != kms.TaskStatusPendingApproval {
+		t.Error("Expected task of a key outside the allowlist not to be cancelled")
+	}
+
+	var pending []pendingTaskInfo
+	if err := json.Unmarshal(handle("web3signer_pendingTasks", "").Result, &pending); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(pending) != 1 || pending[0].TaskID != "task-allowed" {
+		t.Errorf("Expected only tasks of allowed keys, got %+v", pending)
+	}
+}
+
+func TestRouterFactory_TaskCancelOptIn(t *testing.T) {
+	mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", ethgo.HexToAddress("0x1234567890123456789012345678901234567890"), big.NewInt(1))
+	downstreamClient := newMockDownstreamClient()
+	defer func() {
+		if err := downstreamClient.Close(); err != nil {
+			t.Errorf("Failed to close downstream client: %v", err)
+		}
+	}()
+
+	tests := []struct {
+		name       string
+		taskCancel bool
+	}{
+		{name: "disabled by default", taskCancel: false},
+		{name: "enabled explicitly", taskCancel: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewRouterFactory(logrus.New()).WithTaskManager(&fakeTaskManager{})
+			if tt.taskCancel {
+				factory = factory.WithTaskCancel(true)
+			}
+			router := factory.CreateRouter(mpcSigner, downstreamClient)
+
+			if !router.HasHandler("web3signer_getTaskResult") {
+				t.Error("Expected web3signer_getTaskResult to be registered")
+			}
+			if got := router.HasHandler("web3signer_cancelTask"); got != tt.taskCancel {
+				t.Errorf("web3signer_cancelTask registered = %v, want %v", got, tt.taskCancel)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package router
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ttlLRUEntry ttlLRU 缓存条目
+type ttlLRUEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// ttlLRU 带过期时间的并发安全 LRU 缓存，供签名、响应、重放、已发送交易等缓存共用
+//
+// ttl 为 0 时条目不过期，只按容量淘汰；缓存已满时淘汰最久未使用的条目。
+// 过期条目在下次访问时删除
+type ttlLRU[K comparable, V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	order   *list.List // 最近使用的条目在前
+	entries map[K]*list.Element
+	now     func() time.Time
+}
+
+// newTTLLRU 创建 ttlLRU 缓存
+func newTTLLRU[K comparable, V any](ttl time.Duration, maxSize int) *ttlLRU[K, V] {
+	return &ttlLRU[K, V]{
+		ttl:     ttl,
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// get 返回未过期的值并将其标记为最近使用
+func (c *ttlLRU[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.liveLocked(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(c.entries[key])
+	return entry.value, true
+}
+
+// take 取出并删除未过期的值
+func (c *ttlLRU[K, V]) take(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.liveLocked(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.removeLocked(c.entries[key])
+	return entry.value, true
+}
+
+// add 记录值并重置过期时间；缓存已满时淘汰最久未使用的条目
+func (c *ttlLRU[K, V]) add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = c.now().Add(c.ttl)
+	}
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*ttlLRUEntry[K, V])
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	for c.order.Len() > 0 && c.order.Len() >= c.maxSize {
+		c.removeLocked(c.order.Back())
+	}
+	c.entries[key] = c.order.PushFront(&ttlLRUEntry[K, V]{key: key, value: value, expiresAt: expiresAt})
+}
+
+// len 返回缓存中的条目数，包括尚未清理的过期条目
+func (c *ttlLRU[K, V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// liveLocked 返回未过期的条目，过期条目被删除；调用方须持有锁
+func (c *ttlLRU[K, V]) liveLocked(key K) (*ttlLRUEntry[K, V], bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*ttlLRUEntry[K, V])
+	if c.ttl > 0 && !c.now().Before(entry.expiresAt) {
+		c.removeLocked(elem)
+		return nil, false
+	}
+	return entry, true
+}
+
+// removeLocked 删除条目，调用方须持有锁
+func (c *ttlLRU[K, V]) removeLocked(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*ttlLRUEntry[K, V]).key)
+}
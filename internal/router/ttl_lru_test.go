@@ -0,0 +1,82 @@
+package router
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLLRU_Eviction(t *testing.T) {
+	cache := newTTLLRU[string, int](0, 2)
+
+	if _, ok := cache.get("a"); ok {
+		t.Fatal("get on empty cache: got hit")
+	}
+
+	cache.add("a", 1)
+	cache.add("b", 2)
+	if v, ok := cache.get("a"); !ok || v != 1 {
+		t.Errorf("get a: got (%d, %v)", v, ok)
+	}
+
+	// a 最近被访问，淘汰最久未使用的 b
+	cache.add("c", 3)
+	if _, ok := cache.get("b"); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.get("a"); !ok {
+		t.Error("Expected recently used entry to be kept")
+	}
+
+	// 更新已有条目不淘汰其他条目
+	cache.add("a", 10)
+	if v, _ := cache.get("a"); v != 10 {
+		t.Errorf("get a after update = %d, want 10", v)
+	}
+	if _, ok := cache.get("c"); !ok {
+		t.Error("Expected update not to evict other entries")
+	}
+	if got := cache.len(); got != 2 {
+		t.Errorf("len = %d, want 2", got)
+	}
+
+	// take 取出后删除
+	if v, ok := cache.take("c"); !ok || v != 3 {
+		t.Errorf("take c: got (%d, %v)", v, ok)
+	}
+	if _, ok := cache.take("c"); ok {
+		t.Error("Expected taken entry to be removed")
+	}
+}
+
+func TestTTLLRU_Expiry(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newTTLLRU[string, int](time.Minute, 10)
+	cache.now = func() time.Time { return now }
+
+	cache.add("a", 1)
+	now = now.Add(30 * time.Second)
+	cache.add("b", 2)
+	if _, ok := cache.get("a"); !ok {
+		t.Error("Expected entry within TTL to hit")
+	}
+
+	// 访问不延长过期时间，重新写入才会
+	now = now.Add(40 * time.Second)
+	if _, ok := cache.get("a"); ok {
+		t.Error("Expected expired entry to miss")
+	}
+	if _, ok := cache.take("b"); !ok {
+		t.Error("Expected unexpired entry to be taken")
+	}
+	if got := cache.len(); got != 0 {
+		t.Errorf("len = %d, want expired entry removed", got)
+	}
+
+	cache.add("c", 3)
+	now = now.Add(50 * time.Second)
+	cache.add("c", 4)
+	now = now.Add(50 * time.Second)
+	if v, ok := cache.get("c"); !ok || v != 4 {
+		t.Errorf("Expected add to reset TTL, got (%d, %v)", v, ok)
+	}
+}
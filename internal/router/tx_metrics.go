@@ -0,0 +1,120 @@
+package router
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/metrics"
+	"github.com/umbracle/ethgo"
+)
+
+// eth_sendTransaction 结果，用于 txOutcomes
+const (
+	txOutcomeSigned             = "signed"
+	txOutcomeSignFailed         = "sign_failed"
+	txOutcomeForwarded          = "forwarded"
+	txOutcomeForwardFailed      = "forward_failed"
+	txOutcomeDownstreamRejected = "downstream_rejected"
+)
+
+// gas limit 来源，用于 txGasUsedRatio
+const (
+	gasSourceEstimated = "estimated"
+	gasSourceProvided  = "provided"
+)
+
+// gasLimitTrackerSize 等待回执的已发送交易 gas limit 记录数上限
+const gasLimitTrackerSize = 10000
+
+var (
+	// txOutcomes eth_sendTransaction 各阶段的结果
+	txOutcomes = metrics.NewCounterVec(
+		"web3signer_transactions_total",
+		"eth_sendTransaction results: signed, sign_failed, forwarded, forward_failed (downstream unreachable) or downstream_rejected.",
+		"outcome",
+	)
+	// txDownstreamRejections 下游拒绝已签名交易的次数，按 JSON-RPC 错误码区分
+	txDownstreamRejections = metrics.NewCounterVec(
+		"web3signer_transaction_downstream_rejections_total",
+		"Transactions signed by eth_sendTransaction that the downstream node rejected, by JSON-RPC error code.",
+		"code",
+	)
+	// txGasUsedRatio 已打包交易的 gasUsed 与 gas limit 之比
+	txGasUsedRatio = metrics.NewHistogramVec(
+		"web3signer_transaction_gas_used_ratio",
+		"Gas used by mined eth_sendTransaction transactions as a fraction of their gas limit, by whether the limit was estimated (including the safety margin) or provided by the client.",
+		"gas_source",
+		[]float64{0.5, 0.6, 0.7, 0.75, 0.8, 0.85, 0.9, 0.95, 1},
+	)
+)
+
+func init() {
+	metrics.DefaultRegistry.MustRegister(txOutcomes)
+	metrics.DefaultRegistry.MustRegister(txDownstreamRejections)
+	metrics.DefaultRegistry.MustRegister(txGasUsedRatio)
+}
+
+// observeDownstreamRejection 记录下游拒绝已签名交易
+func observeDownstreamRejection(rpcErr *jsonrpc.Error) {
+	txOutcomes.Inc(txOutcomeDownstreamRejected)
+	txDownstreamRejections.Inc(strconv.Itoa(rpcErr.Code))
+}
+
+// trackedGasLimit 已发送交易的 gas limit 及其来源
+type trackedGasLimit struct {
+	gas    uint64
+	source string
+}
+
+// gasLimitTracker 记录 eth_sendTransaction 发送的交易的 gas limit，
+// 客户端通过 eth_getTransactionReceipt 查询到回执时记录 gasUsed 占比，用于调整 gas 估算的安全边界
+//
+// 每笔交易只统计一次；超过容量时淘汰最早的记录
+type gasLimitTracker struct {
+	lru *ttlLRU[ethgo.Hash, trackedGasLimit]
+}
+
+// newGasLimitTracker 创建 gas limit 记录器，记录不过期
+func newGasLimitTracker(maxSize int) *gasLimitTracker {
+	return &gasLimitTracker{lru: newTTLLRU[ethgo.Hash, trackedGasLimit](0, maxSize)}
+}
+
+// add 记录已发送交易的 gas limit
+func (t *gasLimitTracker) add(hash ethgo.Hash, gas uint64, source string) {
+	if gas == 0 {
+		return
+	}
+	t.lru.add(hash, trackedGasLimit{gas: gas, source: source})
+}
+
+// take 取出并删除交易的 gas limit 记录
+func (t *gasLimitTracker) take(hash ethgo.Hash) (trackedGasLimit, bool) {
+	return t.lru.take(hash)
+}
+
+// transactionReceipt eth_getTransactionReceipt 结果中统计所需的字段
+type transactionReceipt struct {
+	TransactionHash ethgo.Hash `json:"transactionHash"`
+	GasUsed         string     `json:"gasUsed"`
+}
+
+// observeReceipt 对已记录 gas limit 的交易回执统计 gasUsed 占比，未记录或无法解析的回执忽略
+func (t *gasLimitTracker) observeReceipt(result json.RawMessage) {
+	if len(result) == 0 || string(result) == "null" {
+		return
+	}
+	var receipt transactionReceipt
+	if err := json.Unmarshal(result, &receipt); err != nil {
+		return
+	}
+	gasUsed, err := strconv.ParseUint(receipt.GasUsed, 0, 64)
+	if err != nil {
+		return
+	}
+	tracked, ok := t.take(receipt.TransactionHash)
+	if !ok {
+		return
+	}
+	txGasUsedRatio.Observe(tracked.source, float64(gasUsed)/float64(tracked.gas))
+}
@@ -0,0 +1,173 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/jsonrpc"
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+// outcomeDownstreamClient 可配置拒绝 eth_sendRawTransaction，并为已发送交易返回 gasUsed 为 21000 的回执
+type outcomeDownstreamClient struct {
+	*testDownstreamClient
+	reject bool
+}
+
+func (c *outcomeDownstreamClient) ForwardRequest(ctx context.Context, req *jsonrpc.Request) (*jsonrpc.Response, error) {
+	switch req.Method {
+	case "eth_sendRawTransaction":
+		if c.reject {
+			return &jsonrpc.Response{JSONRPC: "2.0", ID: req.ID, Error: &jsonrpc.Error{Code: -32003, Message: "nonce too low"}}, nil
+		}
+	case "eth_getTransactionReceipt":
+		return &jsonrpc.Response{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{
+			"transactionHash":"0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+			"gasUsed":"0x5208"
+		}`)}, nil
+	}
+	return c.testDownstreamClient.ForwardRequest(ctx, req)
+}
+
+// failingKMSClient 模拟签名失败的 KMS
+type failingKMSClient struct {
+	testKMSClient
+}
+
+func (c *failingKMSClient) Sign(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+	return nil, errors.New("kms unavailable")
+}
+
+func (c *failingKMSClient) SignWithOptions(ctx context.Context, keyID string, message []byte, encoding kms.DataEncoding, summary *kms.SignSummary, callbackURL string) ([]byte, error) {
+	return c.Sign(ctx, keyID, message)
+}
+
+func TestSendTransaction_OutcomeMetrics(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	testAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
+	downstream := &outcomeDownstreamClient{testDownstreamClient: newMockDownstreamClient()}
+	defer func() { _ = downstream.Close() }()
+
+	send := func(t *testing.T, kmsClient kms.ClientInterface, body string) *jsonrpc.Response {
+		t.Helper()
+		mpcSigner := signer.NewMPCKMSSigner(kmsClient, "test-key-id", testAddress, big.NewInt(1))
+		router := NewRouterFactory(logger).CreateRouter(mpcSigner, downstream)
+		w := httptest.NewRecorder()
+		router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		var resp jsonrpc.Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v, body: %s", err, w.Body.String())
+		}
+		return &resp
+	}
+	sendTx := `{"jsonrpc":"2.0","id":1,"method":"eth_sendTransaction","params":[{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x5208"}]}`
+
+	t.Run("sign failed", func(t *testing.T) {
+		before := txOutcomes.Value(txOutcomeSignFailed)
+		if resp := send(t, &failingKMSClient{}, sendTx); resp.Error == nil {
+			t.Fatal("Expected sign error")
+		}
+		if got := txOutcomes.Value(txOutcomeSignFailed) - before; got != 1 {
+			t.Errorf("sign_failed increased by %d, want 1", got)
+		}
+	})
+
+	t.Run("downstream rejected", func(t *testing.T) {
+		downstream.reject = true
+		defer func() { downstream.reject = false }()
+
+		signed, rejected, code := txOutcomes.Value(txOutcomeSigned), txOutcomes.Value(txOutcomeDownstreamRejected), txDownstreamRejections.Value("-32003")
+		if resp := send(t, &testKMSClient{}, sendTx); resp.Error == nil || resp.Error.Code != -32003 {
+			t.Fatalf("Expected downstream error, got %+v", resp.Error)
+		}
+		if txOutcomes.Value(txOutcomeSigned)-signed != 1 || txOutcomes.Value(txOutcomeDownstreamRejected)-rejected != 1 {
+			t.Error("Expected signed and downstream_rejected to increase")
+		}
+		if txDownstreamRejections.Value("-32003")-code != 1 {
+			t.Error("Expected rejection counted under downstream error code")
+		}
+	})
+
+	t.Run("forwarded with estimated gas", func(t *testing.T) {
+		forwarded := txOutcomes.Value(txOutcomeForwarded)
+		estimated := txGasUsedRatio.Count(gasSourceEstimated)
+
+		mpcSigner := signer.NewMPCKMSSigner(&testKMSClient{}, "test-key-id", testAddress, big.NewInt(1))
+		router := NewRouterFactory(logger).CreateRouter(mpcSigner, downstream)
+		for _, body := range []string{
+			// gas 为 0 时由下游估算
+			`{"jsonrpc":"2.0","id":1,"method":"eth_sendTransaction","params":[{"from":"0x1234567890123456789012345678901234567890","to":"0x0987654321098765432109876543210987654321","gas":"0x0"}]}`,
+			// 同一交易的回执只统计一次
+			`{"jsonrpc":"2.0","id":2,"method":"eth_getTransactionReceipt","params":["0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"]}`,
+			`{"jsonrpc":"2.0","id":3,"method":"eth_getTransactionReceipt","params":["0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"]}`,
+		} {
+			w := httptest.NewRecorder()
+			router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+			if strings.Contains(w.Body.String(), `"error"`) {
+				t.Fatalf("Unexpected error response: %s", w.Body.String())
+			}
+		}
+
+		if got := txOutcomes.Value(txOutcomeForwarded) - forwarded; got != 1 {
+			t.Errorf("forwarded increased by %d, want 1", got)
+		}
+		if got := txGasUsedRatio.Count(gasSourceEstimated) - estimated; got != 1 {
+			t.Errorf("estimated gas ratio observed %d times, want 1", got)
+		}
+	})
+}
+
+// batchOutcomeDownstreamClient 将批量请求逐条交给 outcomeDownstreamClient 处理
+type batchOutcomeDownstreamClient struct {
+	*outcomeDownstreamClient
+}
+
+func (c *batchOutcomeDownstreamClient) ForwardBatchRequest(ctx context.Context, requests []jsonrpc.Request) ([]jsonrpc.Response, error) {
+	responses := make([]jsonrpc.Response, len(requests))
+	for i := range requests {
+		resp, err := c.ForwardRequest(ctx, &requests[i])
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *resp
+	}
+	return responses, nil
+}
+
+func TestForwardHandler_GasUsedRatioInBatch(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	downstream := &batchOutcomeDownstreamClient{&outcomeDownstreamClient{testDownstreamClient: newMockDownstreamClient()}}
+	defer func() { _ = downstream.Close() }()
+
+	// 默认处理器为 ForwardHandler 时批量请求合并转发，回执同样统计 gasUsed 占比
+	forwardHandler := NewForwardHandler(downstream, logger)
+	forwardHandler.gasLimits = newGasLimitTracker(gasLimitTrackerSize)
+	forwardHandler.gasLimits.add(ethgo.HexToHash("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"), 25200, gasSourceProvided)
+
+	router := NewRouter(logger)
+	router.SetDefaultHandler(forwardHandler)
+
+	provided := txGasUsedRatio.Count(gasSourceProvided)
+	body := `[{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionReceipt","params":["0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"]},{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber"}]`
+	w := httptest.NewRecorder()
+	router.HandleHTTPRequest(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	if strings.Contains(w.Body.String(), `"error"`) {
+		t.Fatalf("Unexpected error response: %s", w.Body.String())
+	}
+
+	if got := txGasUsedRatio.Count(gasSourceProvided) - provided; got != 1 {
+		t.Errorf("provided gas ratio observed %d times in batch, want 1", got)
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
@@ -12,15 +13,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mowind/web3signer-go/internal/config"
 	"github.com/mowind/web3signer-go/internal/downstream"
+	apperrors "github.com/mowind/web3signer-go/internal/errors"
 	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/metrics"
+	"github.com/mowind/web3signer-go/internal/policy"
 	"github.com/mowind/web3signer-go/internal/router"
 	"github.com/mowind/web3signer-go/internal/signer"
+	"github.com/mowind/web3signer-go/internal/utils"
 	"github.com/sirupsen/logrus"
 	ginlogrus "github.com/toorop/gin-logrus"
 	"github.com/umbracle/ethgo"
-	ethgojsonrpc "github.com/umbracle/ethgo/jsonrpc"
 )
 
+// keyDiscoveryTimeout bounds KMS key discovery at startup.
+const keyDiscoveryTimeout = 30 * time.Second
+
 // Builder builds a configured web3signer server.
 //
 // This struct provides a fluent interface for server configuration
@@ -28,8 +35,26 @@ import (
 type Builder struct {
 	cfg    *config.Config
 	logger *logrus.Logger
+	chain  *Chain
+
+	// downstream /ready 探针用于检查下游连接，nil 时不检查
+	downstream connectionTester
+
+	pkcs11Opener PKCS11SessionOpener
+}
+
+// connectionTester 由能够测试下游连接的客户端实现
+type connectionTester interface {
+	TestConnection(ctx context.Context) error
 }
 
+// PKCS11SessionOpener opens a logged-in PKCS#11 session for the pkcs11 signer backend.
+//
+// The PKCS#11 library binding requires cgo, so it is not linked into the
+// default build; web3signer built with the pkcs11 tag uses
+// signer.OpenPKCS11Session, and embedding programs may supply their own.
+type PKCS11SessionOpener func(cfg config.PKCS11Config) (signer.PKCS11Session, error)
+
 // NewBuilder creates a new server builder.
 //
 // Parameters:
@@ -38,7 +63,34 @@ type Builder struct {
 // Returns:
 //   - *Builder: A new builder instance
 func NewBuilder(cfg *config.Config) *Builder {
-	return &Builder{cfg: cfg}
+	return &Builder{cfg: cfg, chain: NewChain()}
+}
+
+// WithMiddleware appends middlewares to the chain wrapping the JSON-RPC handler.
+//
+// Middlewares run in registration order, after the Gin middlewares
+// (request ID, access log, CORS, auth, content type).
+//
+// Parameters:
+//   - middlewares: Middlewares to register
+//
+// Returns:
+//   - *Builder: The builder with the middlewares registered
+func (b *Builder) WithMiddleware(middlewares ...Middleware) *Builder {
+	b.chain.Use(middlewares...)
+	return b
+}
+
+// WithPKCS11SessionOpener sets how the pkcs11 signer backend opens its HSM session.
+//
+// Parameters:
+//   - opener: Function opening a logged-in session from the PKCS#11 configuration
+//
+// Returns:
+//   - *Builder: The builder with the session opener configured
+func (b *Builder) WithPKCS11SessionOpener(opener PKCS11SessionOpener) *Builder {
+	b.pkcs11Opener = opener
+	return b
 }
 
 // WithTLS configures TLS for the server.
@@ -90,34 +142,85 @@ func (b *Builder) Build() *Server {
 	b.logger = logger
 
 	downstreamClient := downstream.NewClient(&b.cfg.Downstream, logger)
+	b.downstream = downstreamClient
 
-	downstreamEndpoint := b.cfg.Downstream.BuildURL()
-	rpcClient, err := ethgojsonrpc.NewClient(downstreamEndpoint)
+	// 经由下游客户端查询，使用与转发请求相同的代理、TLS 与请求头配置
+	rpcClient := downstream.NewRPCClient(downstreamClient)
+	chainID, err := rpcClient.ChainID()
 	if err != nil {
-		logger.WithError(err).Fatal("Failed to create downstream RPC client")
+		logger.WithError(err).Fatal("Failed to get chainId from downstream")
 	}
 
-	chainID, err := rpcClient.Eth().ChainID()
+	logger.WithField("chainId", chainID).Info("Retrieved chainId from downstream")
+
+	chainID, err = resolveChainID(b.cfg.Signer.ChainID, chainID)
 	if err != nil {
-		logger.WithError(err).Fatal("Failed to get chainId from downstream")
+		if b.cfg.Signer.ChainIDMismatch != config.ChainIDMismatchWarn {
+			logger.WithError(err).Fatal("Downstream chain ID does not match the configured chain ID")
+		}
+		logger.WithError(err).WithField("policy", b.cfg.Signer.ChainIDPolicy).
+			Warn("Downstream chain ID does not match the configured chain ID, signing according to the chain ID policy")
 	}
 
-	logger.WithField("chainId", chainID).Info("Retrieved chainId from downstream")
+	var multiKeySigner *signer.MultiKeySigner
+	var taskManager kms.TaskManager
+	switch b.cfg.Signer.Backend {
+	case config.SignerBackendPKCS11:
+		multiKeySigner = b.createPKCS11Signer(chainID, logger)
+	case config.SignerBackendLocal:
+		multiKeySigner = b.createLocalSigner(chainID, logger)
+	default:
+		kmsClient := kms.NewClient(&b.cfg.KMS, logger)
+		multiKeySigner = b.createSigner(kmsClient, chainID, logger)
+		taskManager = kmsClient
+	}
 
-	kmsClient := kms.NewClient(&b.cfg.KMS, logger)
-	kmsAddress := ethgo.HexToAddress(b.cfg.KMS.Address)
-	mpcSigner := signer.NewMPCKMSSigner(kmsClient, b.cfg.KMS.KeyID, kmsAddress, chainID)
+	if b.cfg.Signer.ChainID != 0 && b.cfg.Signer.ChainIDMismatch == config.ChainIDMismatchWarn {
+		resolver, err := signer.NewChainIDResolver(big.NewInt(b.cfg.Signer.ChainID), signer.ChainIDPolicy(b.cfg.Signer.ChainIDPolicy),
+			rpcClient.ChainID, b.cfg.Signer.ChainIDRefreshInterval, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create chain ID resolver")
+		}
+		multiKeySigner.SetChainIDResolver(resolver)
+	}
 
-	// Create MultiKeySigner for multi-key support
-	// Currently uses default key from config for backward compatibility
-	multiKeySigner := signer.NewMultiKeySigner(b.cfg.KMS.KeyID, chainID, logger)
-	if err := multiKeySigner.AddClient(b.cfg.KMS.KeyID, mpcSigner); err != nil {
-		logger.WithError(err).Fatal("Failed to add default client to MultiKeySigner")
+	if b.cfg.KMS.StartupSelfTest {
+		if err := signer.SelfTest(multiKeySigner); err != nil {
+			logger.WithError(err).Fatal("KMS startup self-test failed")
+		}
+		logger.WithField("address", multiKeySigner.Address().String()).Info("KMS startup self-test passed")
 	}
 
 	maxRequestSize := b.cfg.HTTP.MaxRequestSizeMB * 1024 * 1024
-	routerFactory := router.NewRouterFactoryWithMaxSize(logger, maxRequestSize)
+	routerFactory := router.NewRouterFactoryWithMaxSize(logger, maxRequestSize).
+		WithTransactionConfig(b.cfg.Transaction).
+		WithCompressionThreshold(b.cfg.HTTP.ResponseCompressionThreshold).
+		WithStreamBatchThreshold(b.cfg.HTTP.StreamBatchThreshold).
+		WithAllowMissingJSONRPCVersion(b.cfg.HTTP.AllowMissingJSONRPCVersion).
+		WithRequestTimeouts(b.cfg.HTTP.RequestTimeout, b.cfg.HTTP.SignRequestTimeout).
+		WithMethodTimeouts(b.cfg.HTTP.MethodTimeouts).
+		WithBatchWorkers(b.cfg.HTTP.BatchWorkers, b.cfg.HTTP.BatchQueueSize, b.cfg.HTTP.MaxBatchWorkers).
+		WithSlowRequestThreshold(time.Duration(b.cfg.Log.SlowRequestMs)*time.Millisecond).
+		WithMaxCalldataBytes(b.cfg.Policy.MaxCalldataBytes).
+		WithMaxAccessList(b.cfg.Policy.MaxAccessListEntries, b.cfg.Policy.MaxAccessListStorageKeys).
+		WithResponseCache(b.cfg.Downstream.CacheTTL, b.cfg.Downstream.CacheSize).
+		WithFeeHistoryCache(b.cfg.Downstream.FeeHistoryCacheInterval, b.cfg.Downstream.FeeHistoryCacheMaxAge,
+			b.cfg.Downstream.FeeHistoryCacheBlocks, b.cfg.Downstream.FeeHistoryCachePercentiles).
+		WithCosmosSigning(b.cfg.Signer.CosmosEnabled).
+		WithKeyRotation(b.cfg.Signer.KeyRotationEnabled).
+		WithSignBatchLimits(b.cfg.Signer.SignBatchMaxItems, b.cfg.Signer.SignBatchConcurrency).
+		WithAllowedKeyIDs(b.cfg.Signer.AllowedKeyIDs).
+		WithTaskCancel(b.cfg.KMS.AllowTaskCancel).
+		WithDownstreamHealthCheck(b.cfg.Downstream.RPCHealthCheckInterval, b.cfg.Downstream.HealthCheckMethod)
+	if taskManager != nil {
+		routerFactory.WithTaskManager(taskManager)
+	}
+	if b.cfg.Policy.Endpoint != "" {
+		routerFactory.WithPreSignHook(policy.NewHTTPHook(b.cfg.Policy.Endpoint, b.cfg.Policy.Timeout, logger))
+		logger.WithField("endpoint", b.cfg.Policy.Endpoint).Info("Pre-sign policy checks enabled")
+	}
 	jsonRPCRouter := routerFactory.CreateRouter(multiKeySigner, downstreamClient)
+	jsonRPCRouter.LogRoutingTable()
 
 	router := b.createGinRouter(jsonRPCRouter, logger)
 
@@ -126,12 +229,131 @@ func (b *Builder) Build() *Server {
 		router:        router,
 		logger:        logger,
 		jsonRPCRouter: jsonRPCRouter,
-		kmsAddress:    b.cfg.KMS.Address,
+		kmsAddress:    multiKeySigner.Address().String(),
 	}
 
 	return s
 }
 
+// resolveChainID 返回签名使用的链 ID
+//
+// 配置了链 ID 时优先使用配置值；下游链 ID 与之不一致（指向了错误的网络）时返回错误
+func resolveChainID(configured int64, downstream *big.Int) (*big.Int, error) {
+	if configured == 0 {
+		return downstream, nil
+	}
+	chainID := big.NewInt(configured)
+	if downstream.Cmp(chainID) != 0 {
+		return chainID, fmt.Errorf("configured chain ID %s, downstream chain ID %s", chainID, downstream)
+	}
+	return chainID, nil
+}
+
+// createSigner 创建 MultiKeySigner
+//
+// 默认注册配置的单个密钥；启用 kms.discover-keys 时注册从 KMS 发现的所有密钥
+func (b *Builder) createSigner(kmsClient *kms.Client, chainID *big.Int, logger *logrus.Logger) *signer.MultiKeySigner {
+	if b.cfg.KMS.DiscoverKeys {
+		ctx, cancel := context.WithTimeout(context.Background(), keyDiscoveryTimeout)
+		defer cancel()
+
+		multiKeySigner, err := signer.NewMultiKeySignerFromKMS(ctx, kmsClient, kmsClient, b.cfg.KMS.KeyID, chainID, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to discover keys from KMS")
+		}
+		return multiKeySigner
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyDiscoveryTimeout)
+	defer cancel()
+	if err := checkKMSKeyAlgorithm(ctx, kmsClient, b.cfg.KMS.KeyID, logger); err != nil {
+		logger.WithError(err).Fatal("KMS key cannot be used for Ethereum signing")
+	}
+
+	kmsAddress := ethgo.HexToAddress(b.cfg.KMS.Address)
+	mpcSigner := signer.NewMPCKMSSigner(kmsClient, b.cfg.KMS.KeyID, kmsAddress, chainID)
+
+	// Create MultiKeySigner for multi-key support
+	// Uses the default key from config for backward compatibility
+	multiKeySigner := signer.NewMultiKeySigner(b.cfg.KMS.KeyID, chainID, logger)
+	if err := multiKeySigner.AddClient(b.cfg.KMS.KeyID, mpcSigner); err != nil {
+		logger.WithError(err).Fatal("Failed to add default client to MultiKeySigner")
+	}
+	return multiKeySigner
+}
+
+// checkKMSKeyAlgorithm 确认配置的 KMS 密钥是 secp256k1 密钥，避免用其它曲线的密钥产生无效签名
+//
+// 查询密钥详情失败或 KMS 未返回算法时无法判断，仅记录告警
+func checkKMSKeyAlgorithm(ctx context.Context, discoverer kms.KeyDiscoverer, keyID string, logger *logrus.Logger) error {
+	info, err := discoverer.GetKey(ctx, keyID)
+	if err != nil {
+		logger.WithError(err).WithField("key_id", keyID).Warn("Failed to fetch KMS key details, skipping key type check")
+		return nil
+	}
+	if info.Algorithm == "" {
+		logger.WithField("key_id", keyID).Warn("KMS did not report the key algorithm, skipping key type check")
+		return nil
+	}
+	return info.CheckSecp256k1()
+}
+
+// createPKCS11Signer 创建使用 PKCS#11 HSM 密钥的 MultiKeySigner，密钥标签作为 keyID
+func (b *Builder) createPKCS11Signer(chainID *big.Int, logger *logrus.Logger) *signer.MultiKeySigner {
+	pkcs11Cfg := b.cfg.Signer.PKCS11
+	if b.pkcs11Opener == nil {
+		logger.Fatal("pkcs11 signer backend is not available: no PKCS#11 session opener configured in this build")
+	}
+	session, err := b.pkcs11Opener(pkcs11Cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to open PKCS#11 session")
+	}
+
+	hsmSigner, err := signer.NewPKCS11Signer(session, pkcs11Cfg.KeyLabel, chainID)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load PKCS#11 signing key")
+	}
+	logger.WithFields(logrus.Fields{
+		"keyLabel": pkcs11Cfg.KeyLabel,
+		"address":  hsmSigner.Address().String(),
+	}).Info("Using PKCS#11 signer backend")
+
+	multiKeySigner := signer.NewMultiKeySigner(pkcs11Cfg.KeyLabel, chainID, logger)
+	if err := multiKeySigner.AddClient(pkcs11Cfg.KeyLabel, hsmSigner); err != nil {
+		logger.WithError(err).Fatal("Failed to add PKCS#11 client to MultiKeySigner")
+	}
+	return multiKeySigner
+}
+
+// localSignerKeyID local 后端在 MultiKeySigner 中使用的 keyID
+const localSignerKeyID = "local"
+
+// createLocalSigner 创建使用本地 keystore 或明文私钥的 MultiKeySigner（仅限开发/测试）
+func (b *Builder) createLocalSigner(chainID *big.Int, logger *logrus.Logger) *signer.MultiKeySigner {
+	localCfg := b.cfg.Signer.Local
+
+	var localSigner *signer.LocalKeystoreSigner
+	var err error
+	if localCfg.KeystoreFile != "" {
+		localSigner, err = signer.LoadLocalKeystoreSigner(localCfg.KeystoreFile, localCfg.KeystorePassword, chainID)
+	} else {
+		localSigner, err = signer.NewLocalKeystoreSignerFromHex(os.Getenv(config.LocalPrivateKeyEnv), chainID)
+	}
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load local signing key")
+	}
+	localSigner.WithNonceVerification(b.cfg.Signer.VerifyDeterministicNonce)
+
+	logger.WithField("address", localSigner.Address().String()).
+		Warn("LOCAL KEYSTORE SIGNER ENABLED: the private key is held in process memory. For development and testing only, NEVER use in production")
+
+	multiKeySigner := signer.NewMultiKeySigner(localSignerKeyID, chainID, logger)
+	if err := multiKeySigner.AddClient(localSignerKeyID, localSigner); err != nil {
+		logger.WithError(err).Fatal("Failed to add local client to MultiKeySigner")
+	}
+	return multiKeySigner
+}
+
 // setGinMode 设置 gin 模式
 func (b *Builder) setGinMode() {
 	if b.cfg.Log.Level == config.LogLevelDebug {
@@ -149,10 +371,23 @@ func (b *Builder) createGinRouter(jsonRPCRouter *router.Router, logger *logrus.L
 	if logger == nil {
 		logger = b.createLogger()
 	}
+	if b.cfg.Log.AccessOutput != "" {
+		accessLogger, err := apperrors.NewLogger(&apperrors.LoggerConfig{
+			Level:  config.LogLevelInfo,
+			Format: b.cfg.Log.AccessFormat,
+			Output: b.cfg.Log.AccessOutput,
+		})
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create access logger")
+		}
+		// 访问日志位于最外层，认证失败、限流、健康检查与 CORS 预检等请求同样会被记录
+		router.Use(accessLogMiddleware(accessLogger.GetUnderlying()))
+	}
 	router.Use(ginlogrus.Logger(logger))
 	router.Use(gin.Recovery())
 	router.Use(b.corsMiddleware())
 	router.Use(AuthMiddleware(b.cfg.Auth.Enabled, b.cfg.Auth.Secret, b.cfg.Auth.Whitelist))
+	router.Use(ContentTypeMiddleware(b.cfg.HTTP.RequireJSONContentType))
 
 	// 如果启用 TLS 自动重定向，添加重定向中间件
 	if b.cfg.HTTP.TLSAutoRedirect && b.cfg.HTTP.TLSCertFile != "" {
@@ -169,6 +404,14 @@ func (b *Builder) createGinRouter(jsonRPCRouter *router.Router, logger *logrus.L
 	// 就绪检查端点
 	router.GET("/ready", b.readyHandler(logger))
 
+	// Prometheus 指标端点
+	router.GET("/metrics", gin.WrapH(metrics.DefaultRegistry.Handler()))
+
+	// 调试端点：返回脱敏后的生效配置，与签名接口使用相同的认证
+	if b.cfg.HTTP.DebugConfigEnabled {
+		router.GET("/debug/config", b.debugConfigHandler())
+	}
+
 	return router
 }
 
@@ -189,6 +432,22 @@ func (b *Builder) requestIDMiddleware() gin.HandlerFunc {
 	}
 }
 
+// accessLogMiddleware 以 gin 中间件形式记录访问日志，须注册在请求 ID 中间件之后
+func accessLogMiddleware(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx, info := router.WithAccessInfo(c.Request.Context())
+		if requestID := c.GetString("request_id"); requestID != "" {
+			ctx = WithRequestID(ctx, requestID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		logAccess(logger, c.Request, c.Writer.Status(), max(c.Writer.Size(), 0), start, info)
+	}
+}
+
 func generateRequestID() string {
 	return fmt.Sprintf("%s-%d", randomString(8), time.Now().UnixNano())
 }
@@ -211,6 +470,14 @@ func (b *Builder) createLogger() *logrus.Logger {
 	// 根据配置设置格式（替换硬编码的 JSONFormatter）
 	logger.SetFormatter(b.createLogFormatter())
 
+	if b.cfg.Log.MaskAddresses {
+		logger.AddHook(utils.AddressMaskingHook{})
+	}
+
+	if rate := b.cfg.Log.DebugSampleRate; rate > 0 && rate < 1 {
+		logger.AddHook(utils.NewBodySamplingHook(rate))
+	}
+
 	return logger
 }
 
@@ -243,41 +510,72 @@ func (b *Builder) isTerminal() bool {
 		b.cfg.Log.Level == config.LogLevelDebug
 }
 
+// debugConfigHandler 返回 Config.String() 生成的脱敏配置摘要
+func (b *Builder) debugConfigHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"config": b.cfg.String(),
+		})
+	}
+}
+
 // healthHandler 处理健康检查请求
 func (b *Builder) healthHandler(logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "healthy",
-			"time":   time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
-		})
+		writeProbeStatus(c, http.StatusOK, "healthy")
 	}
 }
 
 // readyHandler 处理就绪检查请求
 func (b *Builder) readyHandler(logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ready",
-			"time":   time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
-		})
+		// 以配置的健康检查方法探测下游节点，节点不可达或拒绝该方法时不接收流量
+		if b.downstream != nil {
+			if err := b.downstream.TestConnection(c.Request.Context()); err != nil {
+				logger.WithError(err).Warn("Readiness check failed: downstream is unavailable")
+				writeProbeStatus(c, http.StatusServiceUnavailable, "not ready")
+				return
+			}
+		}
+		writeProbeStatus(c, http.StatusOK, "ready")
+	}
+}
+
+// writeProbeStatus 根据 Accept 头返回探针状态：text/plain 时返回纯文本状态，否则返回 JSON
+func writeProbeStatus(c *gin.Context, code int, status string) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEPlain) == gin.MIMEPlain {
+		c.String(code, status+"\n")
+		return
 	}
+	c.JSON(code, gin.H{
+		"status": status,
+		"time":   time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+	})
 }
 
 // handleJSONRPCRequest 处理JSON-RPC请求
+//
+// JSON-RPC 处理器由注册的中间件链包装；Gin 生成的请求 ID 通过 context 传入中间件链
 func (b *Builder) handleJSONRPCRequest(jsonRPCRouter *router.Router) gin.HandlerFunc {
+	handler := b.chain.Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jsonRPCRouter.HandleHTTPRequestWithContext(w, r, b.getLoggerWithContext(r.Context()))
+	}))
+
 	return func(c *gin.Context) {
-		logger := b.getLoggerWithContext(c)
-		jsonRPCRouter.HandleHTTPRequestWithContext(c.Writer, c.Request, logger)
+		if requestID := c.GetString("request_id"); requestID != "" {
+			c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), requestID))
+		}
+		handler.ServeHTTP(c.Writer, c.Request)
 	}
 }
 
-func (b *Builder) getLoggerWithContext(c *gin.Context) *logrus.Entry {
+func (b *Builder) getLoggerWithContext(ctx context.Context) *logrus.Entry {
 	logger := b.logger
 	if logger == nil {
 		logger = b.createLogger()
 	}
 	entry := logger.WithField("component", "http_server")
-	if requestID, exists := c.Get("request_id"); exists {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
 		entry = entry.WithField("request_id", requestID)
 	}
 	return entry
@@ -298,14 +596,15 @@ func (b *Builder) corsMiddleware() gin.HandlerFunc {
 			}).Debug("CORS check")
 		}
 
-		// 设置允许的方法和头（预检请求必需）
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		// 响应内容随 Origin 变化，避免缓存将一个源的 CORS 头返回给另一个源
+		c.Writer.Header().Add("Vary", "Origin")
 
-		// 如果origin在允许列表中，设置CORS头
+		// 仅当 origin 在允许列表中时设置 CORS 头
 		if origin != "" && b.isOriginAllowed(origin, allowedOrigins) {
 			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", strings.Join(b.corsAllowedHeaders(), ", "))
 		}
 
 		// 处理OPTIONS预检请求
@@ -318,16 +617,42 @@ func (b *Builder) corsMiddleware() gin.HandlerFunc {
 	}
 }
 
+// corsAllowedHeaders 返回预检响应允许的请求头
+func (b *Builder) corsAllowedHeaders() []string {
+	if len(b.cfg.HTTP.AllowedHeaders) == 0 {
+		return []string{"Content-Type", "Authorization"}
+	}
+	return b.cfg.HTTP.AllowedHeaders
+}
+
 // isOriginAllowed 检查请求源是否在允许列表中
+//
+// 支持 "*" 允许所有源，以及 "http://localhost:*" 形式的任意端口匹配
 func (b *Builder) isOriginAllowed(origin string, allowedOrigins []string) bool {
 	for _, allowed := range allowedOrigins {
 		if allowed == "*" || allowed == origin {
 			return true
 		}
+		if prefix, ok := strings.CutSuffix(allowed, ":*"); ok {
+			port, found := strings.CutPrefix(origin, prefix+":")
+			if found && port != "" && isAllDigits(port) {
+				return true
+			}
+		}
 	}
 	return false
 }
 
+// isAllDigits 检查字符串是否只包含数字
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // tlsRedirectMiddleware HTTP到HTTPS重定向中间件
 func (b *Builder) tlsRedirectMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
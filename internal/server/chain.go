@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mowind/web3signer-go/internal/router"
+	"github.com/sirupsen/logrus"
+)
+
+// Middleware wraps an http.Handler with a cross-cutting concern such as
+// authentication, rate limiting, logging or metrics.
+type Middleware func(http.Handler) http.Handler
+
+// Chain is an ordered list of middlewares applied to the JSON-RPC handler.
+//
+// The first registered middleware is the outermost one: it sees the request
+// first and the response last.
+type Chain struct {
+	middlewares []Middleware
+}
+
+// NewChain creates a middleware chain.
+//
+// Parameters:
+//   - middlewares: Middlewares in outermost-first order
+//
+// Returns:
+//   - *Chain: A new middleware chain
+func NewChain(middlewares ...Middleware) *Chain {
+	return &Chain{middlewares: append([]Middleware(nil), middlewares...)}
+}
+
+// Use appends middlewares to the end (innermost position) of the chain.
+//
+// Parameters:
+//   - middlewares: Middlewares to append
+//
+// Returns:
+//   - *Chain: The chain for method chaining
+func (c *Chain) Use(middlewares ...Middleware) *Chain {
+	c.middlewares = append(c.middlewares, middlewares...)
+	return c
+}
+
+// Then wraps the handler with every middleware of the chain.
+//
+// Parameters:
+//   - handler: The final handler
+//
+// Returns:
+//   - http.Handler: The composed handler
+func (c *Chain) Then(handler http.Handler) http.Handler {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+	return handler
+}
+
+// requestIDKey is the context key holding the request ID.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// RequestIDMiddleware injects a request ID into the request context.
+//
+// An ID already present in the context is kept; otherwise the X-Request-ID
+// header is used, and a new ID is generated when the header is empty.
+// The ID is echoed in the X-Request-ID response header.
+func RequestIDMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := RequestIDFromContext(r.Context())
+			if requestID == "" {
+				requestID = r.Header.Get("X-Request-ID")
+			}
+			if requestID == "" {
+				requestID = generateRequestID()
+			}
+
+			w.Header().Set("X-Request-ID", requestID)
+			next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), requestID)))
+		})
+	}
+}
+
+// AccessLogMiddleware logs one line per request with method, path, status,
+// response size, duration and request ID.
+//
+// When the request is handled by the JSON-RPC router, the routed JSON-RPC
+// methods and the number of error responses are logged as well.
+func AccessLogMiddleware(logger *logrus.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			ctx, info := router.WithAccessInfo(r.Context())
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			logAccess(logger, r, rec.status, rec.bytes, start, info)
+		})
+	}
+}
+
+// logAccess 输出一条访问日志
+func logAccess(logger *logrus.Logger, r *http.Request, status, bytes int, start time.Time, info *router.AccessInfo) {
+	fields := logrus.Fields{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status":      status,
+		"bytes":       bytes,
+		"duration_ms": time.Since(start).Milliseconds(),
+		"remote_addr": r.RemoteAddr,
+	}
+	if requestID := RequestIDFromContext(r.Context()); requestID != "" {
+		fields["request_id"] = requestID
+	}
+	if methods := info.Methods(); len(methods) > 0 {
+		fields["rpc_methods"] = strings.Join(methods, ",")
+		fields["rpc_errors"] = info.RPCErrors()
+	}
+	logger.WithFields(fields).Info("Access")
+}
+
+// statusRecorder captures the status code and body size written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush forwards to the underlying writer when it supports flushing.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
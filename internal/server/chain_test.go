@@ -0,0 +1,210 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/router"
+	"github.com/sirupsen/logrus"
+)
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name+":before")
+				next.ServeHTTP(w, r)
+				calls = append(calls, name+":after")
+			})
+		}
+	}
+
+	handler := NewChain(record("a")).Use(record("b"), record("c")).Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	want := []string{"a:before", "b:before", "c:before", "handler", "c:after", "b:after", "a:after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var got string
+	handler := RequestIDMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = RequestIDFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		ctxID  string
+		want   string
+	}{
+		{name: "from header", header: "req-1", want: "req-1"},
+		{name: "context takes precedence", header: "req-1", ctxID: "ctx-1", want: "ctx-1"},
+		{name: "generated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			if tt.ctxID != "" {
+				req = req.WithContext(WithRequestID(req.Context(), tt.ctxID))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if got == "" || (tt.want != "" && got != tt.want) {
+				t.Errorf("request ID = %q, want %q", got, tt.want)
+			}
+			if w.Header().Get("X-Request-ID") != got {
+				t.Errorf("X-Request-ID header = %q, want %q", w.Header().Get("X-Request-ID"), got)
+			}
+		})
+	}
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	handler := NewChain(RequestIDMiddleware(), AccessLogMiddleware(logger)).Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	for _, want := range []string{`"status":418`, `"bytes":5`, `"request_id":"req-42"`, `"method":"POST"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("access log %q missing %s", line, want)
+		}
+	}
+}
+
+func TestBuilder_WithMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	builder := NewBuilder(&config.Config{Log: config.LogConfig{Level: config.LogLevelError}})
+	var seenRequestID string
+	builder.WithMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seenRequestID = RequestIDFromContext(r.Context())
+			w.Header().Set("X-Test-Middleware", "1")
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	jsonRPCRouter := router.NewRouterFactory(builder.createLogger()).CreateSimpleRouter()
+	engine := builder.createGinRouter(jsonRPCRouter, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","method":"test","id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "gin-req")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	if w.Header().Get("X-Test-Middleware") != "1" {
+		t.Error("Expected registered middleware to wrap the JSON-RPC handler")
+	}
+	if seenRequestID != "gin-req" {
+		t.Errorf("Expected request ID gin-req in middleware context, got %q", seenRequestID)
+	}
+
+	// 中间件只包装 JSON-RPC 端点
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Header().Get("X-Test-Middleware") != "" {
+		t.Error("Expected middleware not to wrap /health")
+	}
+}
+
+func TestBuilder_AccessLogOutput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	accessLog := filepath.Join(t.TempDir(), "access.log")
+	builder := NewBuilder(&config.Config{Log: config.LogConfig{
+		Level:        config.LogLevelError,
+		AccessOutput: accessLog,
+		AccessFormat: config.LogFormatJSON,
+	}})
+	jsonRPCRouter := router.NewRouterFactory(builder.createLogger()).CreateSimpleRouter()
+	engine := builder.createGinRouter(jsonRPCRouter, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"jsonrpc":"2.0","method":"test","id":1},{"jsonrpc":"2.0","method":"other","id":2}]`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "access-req")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	data, err := os.ReadFile(accessLog)
+	if err != nil {
+		t.Fatalf("Failed to read access log: %v", err)
+	}
+	line := string(data)
+	for _, want := range []string{`"rpc_methods":"test,other"`, `"rpc_errors":2`, `"status":200`, `"request_id":"access-req"`, `"msg":"Access"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("access log %q missing %s", line, want)
+		}
+	}
+}
+
+func TestBuilder_AccessLogRejectedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 访问日志位于认证等中间件之外，被拒绝的请求与健康检查同样被记录
+	accessLog := filepath.Join(t.TempDir(), "access.log")
+	builder := NewBuilder(&config.Config{
+		Log: config.LogConfig{
+			Level:        config.LogLevelError,
+			AccessOutput: accessLog,
+			AccessFormat: config.LogFormatJSON,
+		},
+		Auth: config.AuthConfig{Enabled: true, Secret: "test-secret", Whitelist: []string{"/health"}},
+	})
+	jsonRPCRouter := router.NewRouterFactory(builder.createLogger()).CreateSimpleRouter()
+	engine := builder.createGinRouter(jsonRPCRouter, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","method":"test","id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "unauthorized-req")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	data, err := os.ReadFile(accessLog)
+	if err != nil {
+		t.Fatalf("Failed to read access log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("access log has %d entries, want 2: %s", len(lines), data)
+	}
+	for _, want := range []string{`"status":401`, `"request_id":"unauthorized-req"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("access log %q missing %s", lines[0], want)
+		}
+	}
+	if !strings.Contains(lines[1], `"path":"/health"`) {
+		t.Errorf("access log %q missing health check", lines[1])
+	}
+}
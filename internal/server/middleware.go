@@ -2,6 +2,7 @@ package server
 
 import (
 	"crypto/subtle"
+	"mime"
 	"net/http"
 	"strings"
 
@@ -80,3 +81,26 @@ func AuthMiddleware(enabled bool, secret string, whitelist []string) gin.Handler
 		})
 	}
 }
+
+// ContentTypeMiddleware rejects POST requests whose Content-Type is not application/json.
+//
+// The check runs before the body is read. Media type parameters such as charset are allowed.
+func ContentTypeMiddleware(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !required || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+				"error": "unsupported media type",
+				"code":  http.StatusUnsupportedMediaType,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
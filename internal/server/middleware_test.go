@@ -403,3 +403,81 @@ func TestAuthMiddleware_SecurePathMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestContentTypeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		required       bool
+		method         string
+		contentType    string
+		expectedStatus int
+	}{
+		{
+			name:           "disabled - missing content type passes",
+			required:       false,
+			method:         "POST",
+			contentType:    "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "enabled - application/json passes",
+			required:       true,
+			method:         "POST",
+			contentType:    "application/json",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "enabled - application/json with charset passes",
+			required:       true,
+			method:         "POST",
+			contentType:    "application/json; charset=utf-8",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "enabled - missing content type rejected",
+			required:       true,
+			method:         "POST",
+			contentType:    "",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "enabled - text/plain rejected",
+			required:       true,
+			method:         "POST",
+			contentType:    "text/plain",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "enabled - GET not checked",
+			required:       true,
+			method:         "GET",
+			contentType:    "",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ContentTypeMiddleware(tt.required))
+
+			router.Any("/*path", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "ok"})
+			})
+
+			req := httptest.NewRequest(tt.method, "/", strings.NewReader(`{}`))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mowind/web3signer-go/internal/config"
@@ -20,6 +19,8 @@ type Server struct {
 	logger        *logrus.Logger
 	jsonRPCRouter *router.Router
 	kmsAddress    string
+
+	stopBackground context.CancelFunc // 停止路由器后台任务
 }
 
 // New 创建新的 HTTP 服务器
@@ -35,7 +36,10 @@ func (s *Server) Start() error {
 	s.server = &http.Server{
 		Addr:              addr,
 		Handler:           s.router,
-		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       s.config.HTTP.ReadTimeout,
+		ReadHeaderTimeout: s.config.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      s.config.HTTP.WriteTimeout,
+		IdleTimeout:       s.config.HTTP.IdleTimeout,
 	}
 
 	s.logger.WithFields(logrus.Fields{
@@ -45,6 +49,12 @@ func (s *Server) Start() error {
 		"tls-auto-redirect": s.config.HTTP.TLSAutoRedirect,
 	}).Info("Starting HTTP server")
 
+	if s.jsonRPCRouter != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopBackground = cancel
+		s.jsonRPCRouter.StartBackgroundTasks(ctx)
+	}
+
 	go func() {
 		var err error
 		if s.config.HTTP.TLSCertFile != "" {
@@ -62,6 +72,9 @@ func (s *Server) Start() error {
 
 // Stop 优雅停止 HTTP 服务器
 func (s *Server) Stop(ctx context.Context) error {
+	if s.stopBackground != nil {
+		s.stopBackground()
+	}
 	if s.server != nil {
 		s.logger.Info("Shutting down HTTP server")
 		return s.server.Shutdown(ctx)
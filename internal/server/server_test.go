@@ -2,14 +2,20 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mowind/web3signer-go/internal/config"
+	"github.com/mowind/web3signer-go/internal/kms"
 	"github.com/mowind/web3signer-go/internal/router"
+	"github.com/sirupsen/logrus"
 )
 
 func TestBuilder_setGinMode(t *testing.T) {
@@ -138,6 +144,93 @@ func TestBuilder_readyHandler(t *testing.T) {
 	}
 }
 
+// stubConnectionTester 返回固定结果的下游连接测试
+type stubConnectionTester struct {
+	err error
+}
+
+func (s stubConnectionTester) TestConnection(context.Context) error {
+	return s.err
+}
+
+func TestBuilder_readyHandler_Downstream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "downstream reachable", wantCode: http.StatusOK, wantStatus: "ready"},
+		{name: "downstream unavailable", err: errors.New("method disabled"), wantCode: http.StatusServiceUnavailable, wantStatus: "not ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(&config.Config{Log: config.LogConfig{Level: config.LogLevelError}})
+			builder.downstream = stubConnectionTester{err: tt.err}
+
+			router := gin.New()
+			router.GET("/ready", builder.readyHandler(builder.createLogger()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/ready", nil))
+
+			var response map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if w.Code != tt.wantCode || response["status"] != tt.wantStatus {
+				t.Errorf("got %d %v, want %d %s", w.Code, response["status"], tt.wantCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestBuilder_probeHandlers_AcceptNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	builder := NewBuilder(&config.Config{Log: config.LogConfig{Level: config.LogLevelError}})
+	router := gin.New()
+	router.GET("/health", builder.healthHandler(builder.createLogger()))
+	router.GET("/ready", builder.readyHandler(builder.createLogger()))
+
+	tests := []struct {
+		path        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{path: "/health", accept: "", contentType: "application/json"},
+		{path: "/health", accept: "*/*", contentType: "application/json"},
+		{path: "/health", accept: "application/json", contentType: "application/json"},
+		{path: "/health", accept: "text/plain", contentType: "text/plain", body: "healthy\n"},
+		{path: "/ready", accept: "text/plain, application/json;q=0.5", contentType: "text/plain", body: "ready\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", got, tt.contentType)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("Body = %q, want %q", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
 func TestBuilder_createGinRouter_healthHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -317,3 +410,225 @@ func TestBuilder_createGinRouter_Build(t *testing.T) {
 		t.Error("Expected kmsAddress to be set")
 	}
 }
+
+func TestBuilder_corsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		allowedHeaders []string
+		method         string
+		origin         string
+		expectOrigin   string
+		expectHeaders  string
+		expectStatus   int
+	}{
+		{
+			name:           "preflight from allowed origin",
+			allowedOrigins: []string{"https://dapp.example.com"},
+			method:         "OPTIONS",
+			origin:         "https://dapp.example.com",
+			expectOrigin:   "https://dapp.example.com",
+			expectHeaders:  "Content-Type, Authorization",
+			expectStatus:   http.StatusNoContent,
+		},
+		{
+			name:           "preflight from disallowed origin",
+			allowedOrigins: []string{"https://dapp.example.com"},
+			method:         "OPTIONS",
+			origin:         "https://evil.example.com",
+			expectStatus:   http.StatusNoContent,
+		},
+		{
+			name:           "actual request with custom headers",
+			allowedOrigins: []string{"https://dapp.example.com"},
+			allowedHeaders: []string{"Content-Type", "X-Request-ID"},
+			method:         "POST",
+			origin:         "https://dapp.example.com",
+			expectOrigin:   "https://dapp.example.com",
+			expectHeaders:  "Content-Type, X-Request-ID",
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "wildcard port matches",
+			allowedOrigins: []string{"http://localhost:*"},
+			method:         "POST",
+			origin:         "http://localhost:3000",
+			expectOrigin:   "http://localhost:3000",
+			expectHeaders:  "Content-Type, Authorization",
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "wildcard port does not match other hosts",
+			allowedOrigins: []string{"http://localhost:*"},
+			method:         "POST",
+			origin:         "http://localhost.evil.com:3000",
+			expectStatus:   http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(&config.Config{
+				HTTP: config.HTTPConfig{AllowedOrigins: tt.allowedOrigins, AllowedHeaders: tt.allowedHeaders},
+			})
+
+			router := gin.New()
+			router.Use(builder.corsMiddleware())
+			router.POST("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectStatus, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.expectOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.expectOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Headers"); got != tt.expectHeaders {
+				t.Errorf("Access-Control-Allow-Headers = %q, want %q", got, tt.expectHeaders)
+			}
+		})
+	}
+}
+
+func TestBuilder_createGinRouter_metricsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	builder := NewBuilder(&config.Config{Log: config.LogConfig{Level: config.LogLevelError}})
+	router := builder.createGinRouter(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "web3signer_kms_approval_wait_seconds") {
+		t.Errorf("Expected approval wait histogram in /metrics output, got:\n%s", w.Body.String())
+	}
+}
+
+func TestBuilder_createGinRouter_debugConfigEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		authHeader string
+		wantStatus int
+	}{
+		{name: "disabled", enabled: false, authHeader: "Bearer test-secret", wantStatus: http.StatusNotFound},
+		{name: "enabled without token", enabled: true, wantStatus: http.StatusUnauthorized},
+		{name: "enabled with token", enabled: true, authHeader: "Bearer test-secret", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(&config.Config{
+				HTTP: config.HTTPConfig{DebugConfigEnabled: tt.enabled},
+				KMS:  config.KMSConfig{Endpoint: "https://kms.example.com", KeyID: "key-1", SecretKey: "kms-secret"},
+				Log:  config.LogConfig{Level: config.LogLevelError},
+				Auth: config.AuthConfig{Enabled: true, Secret: "test-secret"},
+			})
+			router := builder.createGinRouter(nil, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/config", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Config string `json:"config"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !strings.Contains(body.Config, "KeyID: key-1") {
+				t.Errorf("Expected effective config in response, got %q", body.Config)
+			}
+			if strings.Contains(body.Config, "kms-secret") || strings.Contains(body.Config, "test-secret") {
+				t.Errorf("Secrets must be redacted, got %q", body.Config)
+			}
+		})
+	}
+}
+
+func TestResolveChainID(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured int64
+		downstream int64
+		want       int64
+		wantErr    bool
+	}{
+		{name: "not configured uses downstream", configured: 0, downstream: 5, want: 5},
+		{name: "configured matches downstream", configured: 1, downstream: 1, want: 1},
+		{name: "configured differs from downstream", configured: 1, downstream: 11155111, want: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveChainID(tt.configured, big.NewInt(tt.downstream))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveChainID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Int64() != tt.want {
+				t.Errorf("resolveChainID() = %s, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeKeyDiscoverer 返回预设的密钥详情
+type fakeKeyDiscoverer struct {
+	info *kms.KeyInfo
+	err  error
+}
+
+func (f *fakeKeyDiscoverer) ListKeys(ctx context.Context) ([]kms.KeyInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeKeyDiscoverer) GetKey(ctx context.Context, keyID string) (*kms.KeyInfo, error) {
+	return f.info, f.err
+}
+
+func TestCheckKMSKeyAlgorithm(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	tests := []struct {
+		name       string
+		discoverer *fakeKeyDiscoverer
+		wantErr    bool
+	}{
+		{name: "secp256k1 key", discoverer: &fakeKeyDiscoverer{info: &kms.KeyInfo{KeyID: "key-1", Algorithm: "secp256k1"}}},
+		{name: "algorithm not reported", discoverer: &fakeKeyDiscoverer{info: &kms.KeyInfo{KeyID: "key-1"}}},
+		{name: "lookup failure", discoverer: &fakeKeyDiscoverer{err: errors.New("not found")}},
+		{name: "ed25519 key", discoverer: &fakeKeyDiscoverer{info: &kms.KeyInfo{KeyID: "key-1", Algorithm: "ed25519"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkKMSKeyAlgorithm(context.Background(), tt.discoverer, "key-1", logger)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkKMSKeyAlgorithm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
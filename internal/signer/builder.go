@@ -4,11 +4,16 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+
+	"github.com/umbracle/ethgo"
 )
 
 // ParseSignParams from JSON-RPC parameters parses signature parameters
 //
 // Parameters format: ["0xAddress", "0xData"]
+//
+// As in geth, the data is 0x-prefixed hex of arbitrary length ("0x" is an
+// empty message) and is returned decoded to raw bytes.
 func ParseSignParams(params json.RawMessage) (address string, data []byte, err error) {
 	var paramsArray []interface{}
 	if err := json.Unmarshal(params, &paramsArray); err != nil {
@@ -31,16 +36,83 @@ func ParseSignParams(params json.RawMessage) (address string, data []byte, err e
 		return "", nil, fmt.Errorf("invalid data parameter")
 	}
 
-	data, err = parseHex(dataStr)
+	data, err = decodeHexData(dataStr)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to parse data: %v", err)
 	}
 
-	if len(data) != 32 {
-		return "", nil, fmt.Errorf("invalid data length: expected 32 bytes, got %d", len(data))
+	return address, data, nil
+}
+
+// SignBatchItem is one message of a web3signer_signBatch request.
+type SignBatchItem struct {
+	KeyID   string `json:"keyId"`   // 签名密钥 ID，空表示默认密钥
+	Message string `json:"message"` // 0x 前缀的十六进制消息，与 eth_sign 的 data 相同
+}
+
+// Data decodes the item's message the way eth_sign decodes its data.
+//
+// Returns:
+//   - []byte: The decoded message
+//   - error: An error if the message is not 0x-prefixed, even-length hex
+func (i SignBatchItem) Data() ([]byte, error) {
+	data, err := decodeHexData(i.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message: %v", err)
 	}
+	return data, nil
+}
 
-	return address, data, nil
+// ParseSignBatchParams parses web3signer_signBatch parameters.
+//
+// Parameters format: [[{"keyId": "...", "message": "0xData"}, ...]]
+//
+// Messages are not decoded here so that a malformed message fails only its
+// own item; see SignBatchItem.Data.
+func ParseSignBatchParams(params json.RawMessage) ([]SignBatchItem, error) {
+	var paramsArray []json.RawMessage
+	if err := json.Unmarshal(params, &paramsArray); err != nil {
+		return nil, fmt.Errorf("failed to parse sign batch params: %v", err)
+	}
+	if len(paramsArray) < 1 {
+		return nil, fmt.Errorf("insufficient parameters for web3signer_signBatch")
+	}
+
+	var items []SignBatchItem
+	if err := json.Unmarshal(paramsArray[0], &items); err != nil {
+		return nil, fmt.Errorf("invalid sign batch items: %v", err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("empty sign batch")
+	}
+	return items, nil
+}
+
+// TextHash returns the EIP-191 personal message hash signed by eth_sign.
+//
+// The hash is keccak256("\x19Ethereum Signed Message:\n" + len(data) + data),
+// matching geth's accounts.TextHash.
+func TextHash(data []byte) []byte {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(data))
+	return ethgo.Keccak256(append([]byte(prefix), data...))
+}
+
+// decodeHexData 严格解码 0x 前缀的十六进制数据（与 geth hexutil.Bytes 一致）
+//
+// 须带 0x 前缀且长度为偶数；"0x" 解码为空字节
+func decodeHexData(s string) ([]byte, error) {
+	if len(s) < 2 || (s[0:2] != "0x" && s[0:2] != "0X") {
+		return nil, fmt.Errorf("hex string without 0x prefix")
+	}
+	s = s[2:]
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("hex string of odd length")
+	}
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string: %v", err)
+	}
+	return data, nil
 }
 
 // parseHex parses a hex string to bytes
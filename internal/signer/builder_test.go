@@ -0,0 +1,108 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSignParams_DataEncoding(t *testing.T) {
+	const address = "0x1234567890123456789012345678901234567890"
+
+	tests := []struct {
+		name     string
+		data     string
+		wantData []byte
+		wantErr  bool
+	}{
+		{name: "short message", data: "0xdeadbeef", wantData: []byte{0xde, 0xad, 0xbe, 0xef}},
+		{name: "empty message", data: "0x", wantData: []byte{}},
+		{name: "uppercase prefix and digits", data: "0XDEADBEEF", wantData: []byte{0xde, 0xad, 0xbe, 0xef}},
+		{name: "32-byte message", data: "0x" + hex.EncodeToString(bytes.Repeat([]byte{0xab}, 32)), wantData: bytes.Repeat([]byte{0xab}, 32)},
+		{name: "missing prefix", data: "deadbeef", wantErr: true},
+		{name: "odd length", data: "0xdeadbee", wantErr: true},
+		{name: "invalid hex", data: "0xdeadbeeg", wantErr: true},
+		{name: "empty string", data: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _ := json.Marshal([]string{address, tt.data})
+			gotAddress, data, err := ParseSignParams(params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSignParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotAddress != address {
+				t.Errorf("address = %s, want %s", gotAddress, address)
+			}
+			if data == nil || !bytes.Equal(data, tt.wantData) {
+				t.Errorf("data = %x, want %x", data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestTextHash(t *testing.T) {
+	// 与 geth accounts.TextHash / ethers hashMessage 的结果一致
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{data: []byte("Hello World"), want: "a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2"},
+		{data: []byte{}, want: "5f35dce98ba4fba25530a026ed80b2cecdaa31091ba4958b99b52ea1d068adad"},
+	}
+
+	for _, tt := range tests {
+		if got := hex.EncodeToString(TextHash(tt.data)); got != tt.want {
+			t.Errorf("TextHash(%q) = %s, want %s", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestParseSignBatchParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		want    []SignBatchItem
+		wantErr bool
+	}{
+		{
+			name:   "items with and without key",
+			params: `[[{"keyId":"key-1","message":"0x01"},{"message":"0xzz"}]]`,
+			want:   []SignBatchItem{{KeyID: "key-1", Message: "0x01"}, {Message: "0xzz"}},
+		},
+		{name: "empty batch", params: `[[]]`, wantErr: true},
+		{name: "missing items", params: `[]`, wantErr: true},
+		{name: "items not an array", params: `[{"message":"0x01"}]`, wantErr: true},
+		{name: "not an array", params: `{}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ParseSignBatchParams(json.RawMessage(tt.params))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSignBatchParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(items) != len(tt.want) {
+				t.Fatalf("items = %+v, want %+v", items, tt.want)
+			}
+			for i := range items {
+				if items[i] != tt.want[i] {
+					t.Errorf("items[%d] = %+v, want %+v", i, items[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	// 格式错误的消息只影响对应条目
+	if _, err := (SignBatchItem{Message: "0xzz"}).Data(); err == nil {
+		t.Error("Expected error decoding invalid message")
+	}
+}
@@ -0,0 +1,159 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ChainIDPolicy decides which chain ID to sign with when the configured
+// chain ID and the downstream-reported chain ID disagree.
+type ChainIDPolicy string
+
+const (
+	// ChainIDPolicyConfig keeps signing with the configured chain ID.
+	ChainIDPolicyConfig ChainIDPolicy = "config"
+	// ChainIDPolicyDownstream signs with the chain ID reported by downstream.
+	ChainIDPolicyDownstream ChainIDPolicy = "downstream"
+	// ChainIDPolicyFail refuses to sign until the chain IDs agree again.
+	ChainIDPolicyFail ChainIDPolicy = "fail"
+)
+
+// ChainIDMismatchError is returned by ChainIDResolver.Resolve under
+// ChainIDPolicyFail when the configured and downstream chain IDs differ.
+type ChainIDMismatchError struct {
+	Configured *big.Int
+	Downstream *big.Int
+}
+
+// Error implements the error interface.
+func (e *ChainIDMismatchError) Error() string {
+	return fmt.Sprintf("configured chain ID %s does not match downstream chain ID %s", e.Configured, e.Downstream)
+}
+
+// ChainIDResolver resolves the chain ID used to compute signing hashes.
+//
+// The downstream chain ID is fetched at most once per refresh interval. When
+// it differs from the configured chain ID the policy picks the winner, and
+// each newly observed mismatch is logged once at warn level so chain
+// upgrades and forks are visible without flooding the log.
+type ChainIDResolver struct {
+	configured *big.Int
+	policy     ChainIDPolicy
+	fetch      func() (*big.Int, error)
+	refresh    time.Duration
+	logger     *logrus.Logger
+
+	mu          sync.Mutex
+	downstream  *big.Int
+	fetchedAt   time.Time
+	lastWarnFor *big.Int
+}
+
+// NewChainIDResolver creates a chain ID resolver.
+//
+// Parameters:
+//   - configured: The configured chain ID
+//   - policy: Which chain ID to trust on mismatch
+//   - fetch: Queries the downstream chain ID (eth_chainId)
+//   - refresh: How long a fetched downstream chain ID is reused; 0 fetches on every call
+//   - logger: Logger for mismatch warnings
+//
+// Returns:
+//   - *ChainIDResolver: A new resolver
+//   - error: An error if configured is missing or the policy is unknown
+func NewChainIDResolver(configured *big.Int, policy ChainIDPolicy, fetch func() (*big.Int, error), refresh time.Duration, logger *logrus.Logger) (*ChainIDResolver, error) {
+	if configured == nil || configured.Sign() <= 0 {
+		return nil, fmt.Errorf("configured chain ID is required")
+	}
+	switch policy {
+	case ChainIDPolicyConfig, ChainIDPolicyDownstream, ChainIDPolicyFail:
+	default:
+		return nil, fmt.Errorf("unknown chain ID policy: %s", policy)
+	}
+	return &ChainIDResolver{
+		configured: new(big.Int).Set(configured),
+		policy:     policy,
+		fetch:      fetch,
+		refresh:    refresh,
+		logger:     logger,
+	}, nil
+}
+
+// Resolve returns the chain ID to sign with.
+//
+// If downstream cannot be queried the configured chain ID is used under
+// ChainIDPolicyConfig, and an error is returned under the other policies
+// since the chain ID they depend on is unknown.
+//
+// Returns:
+//   - *big.Int: The chain ID to compute signing hashes under
+//   - error: A *ChainIDMismatchError under ChainIDPolicyFail, or a fetch error
+func (r *ChainIDResolver) Resolve() (*big.Int, error) {
+	downstream, err := r.downstreamChainID()
+	if err != nil {
+		if r.policy == ChainIDPolicyConfig {
+			r.logger.WithError(err).Warn("Failed to query downstream chain ID, signing with the configured chain ID")
+			return new(big.Int).Set(r.configured), nil
+		}
+		return nil, fmt.Errorf("failed to query downstream chain ID: %w", err)
+	}
+
+	if downstream.Cmp(r.configured) == 0 {
+		r.mu.Lock()
+		r.lastWarnFor = nil
+		r.mu.Unlock()
+		return new(big.Int).Set(r.configured), nil
+	}
+
+	r.mu.Lock()
+	if r.lastWarnFor == nil || r.lastWarnFor.Cmp(downstream) != 0 {
+		r.lastWarnFor = downstream
+		r.logger.WithFields(logrus.Fields{
+			"configured_chain_id": r.configured.String(),
+			"downstream_chain_id": downstream.String(),
+			"policy":              string(r.policy),
+		}).Warn("Downstream chain ID does not match the configured chain ID")
+	}
+	r.mu.Unlock()
+
+	switch r.policy {
+	case ChainIDPolicyDownstream:
+		return new(big.Int).Set(downstream), nil
+	case ChainIDPolicyFail:
+		return nil, &ChainIDMismatchError{Configured: new(big.Int).Set(r.configured), Downstream: new(big.Int).Set(downstream)}
+	default:
+		return new(big.Int).Set(r.configured), nil
+	}
+}
+
+// downstreamChainID 返回缓存的下游链 ID，过期时重新查询
+//
+// 查询在锁外进行，慢速或超时的下游不会阻塞其他使用缓存值的签名请求；
+// 并发过期时可能重复查询，以最后一次结果为准
+func (r *ChainIDResolver) downstreamChainID() (*big.Int, error) {
+	r.mu.Lock()
+	if r.downstream != nil && r.refresh > 0 && time.Since(r.fetchedAt) < r.refresh {
+		cached := r.downstream
+		r.mu.Unlock()
+		return cached, nil
+	}
+	r.mu.Unlock()
+
+	chainID, err := r.fetch()
+	if err != nil {
+		return nil, err
+	}
+	if chainID == nil {
+		return nil, fmt.Errorf("downstream returned no chain ID")
+	}
+
+	r.mu.Lock()
+	r.downstream = chainID
+	r.fetchedAt = time.Now()
+	r.mu.Unlock()
+	return chainID, nil
+}
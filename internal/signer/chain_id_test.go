@@ -0,0 +1,211 @@
+package signer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+func TestChainIDResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     ChainIDPolicy
+		downstream *big.Int
+		fetchErr   error
+		want       int64
+		wantErr    bool
+		wantWarn   bool
+	}{
+		{name: "match with config policy", policy: ChainIDPolicyConfig, downstream: big.NewInt(1), want: 1},
+		{name: "match with fail policy", policy: ChainIDPolicyFail, downstream: big.NewInt(1), want: 1},
+		{name: "mismatch trusts config", policy: ChainIDPolicyConfig, downstream: big.NewInt(5), want: 1, wantWarn: true},
+		{name: "mismatch trusts downstream", policy: ChainIDPolicyDownstream, downstream: big.NewInt(5), want: 5, wantWarn: true},
+		{name: "mismatch fails", policy: ChainIDPolicyFail, downstream: big.NewInt(5), wantErr: true, wantWarn: true},
+		{name: "fetch error with config policy", policy: ChainIDPolicyConfig, fetchErr: errors.New("down"), want: 1, wantWarn: true},
+		{name: "fetch error with downstream policy", policy: ChainIDPolicyDownstream, fetchErr: errors.New("down"), wantErr: true},
+		{name: "fetch error with fail policy", policy: ChainIDPolicyFail, fetchErr: errors.New("down"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			fetch := func() (*big.Int, error) { return tt.downstream, tt.fetchErr }
+			resolver, err := NewChainIDResolver(big.NewInt(1), tt.policy, fetch, 0, logger)
+			if err != nil {
+				t.Fatalf("NewChainIDResolver() error: %v", err)
+			}
+
+			got, err := resolver.Resolve()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Int64() != tt.want {
+				t.Errorf("Resolve() = %s, want %d", got, tt.want)
+			}
+			if tt.policy == ChainIDPolicyFail && tt.downstream != nil && tt.wantErr {
+				var mismatch *ChainIDMismatchError
+				if !errors.As(err, &mismatch) || mismatch.Downstream.Int64() != 5 {
+					t.Errorf("Expected ChainIDMismatchError, got %v", err)
+				}
+			}
+			warned := false
+			for _, entry := range hook.AllEntries() {
+				if entry.Level == logrus.WarnLevel {
+					warned = true
+				}
+			}
+			if warned != tt.wantWarn {
+				t.Errorf("warned = %v, want %v", warned, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestChainIDResolver_CachesAndLogsMismatchOnce(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	calls := 0
+	fetch := func() (*big.Int, error) {
+		calls++
+		return big.NewInt(5), nil
+	}
+	resolver, err := NewChainIDResolver(big.NewInt(1), ChainIDPolicyDownstream, fetch, time.Hour, logger)
+	if err != nil {
+		t.Fatalf("NewChainIDResolver() error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := resolver.Resolve(); err != nil {
+			t.Fatalf("Resolve() error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+	if len(hook.AllEntries()) != 1 {
+		t.Errorf("Expected a single mismatch warning, got %d entries", len(hook.AllEntries()))
+	}
+}
+
+func TestChainIDResolver_FetchesWithoutLock(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	var resolver *ChainIDResolver
+	// 下游查询期间不持有锁，其他请求可以继续读取缓存
+	fetch := func() (*big.Int, error) {
+		if !resolver.mu.TryLock() {
+			t.Error("fetch called while holding the resolver lock")
+		} else {
+			resolver.mu.Unlock()
+		}
+		return big.NewInt(1), nil
+	}
+	resolver, err := NewChainIDResolver(big.NewInt(1), ChainIDPolicyFail, fetch, 0, logger)
+	if err != nil {
+		t.Fatalf("NewChainIDResolver() error: %v", err)
+	}
+	if _, err := resolver.Resolve(); err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+}
+
+func TestNewChainIDResolver_Invalid(t *testing.T) {
+	logger := logrus.New()
+	fetch := func() (*big.Int, error) { return big.NewInt(1), nil }
+	if _, err := NewChainIDResolver(nil, ChainIDPolicyConfig, fetch, 0, logger); err == nil {
+		t.Error("Expected error for missing configured chain ID")
+	}
+	if _, err := NewChainIDResolver(big.NewInt(1), "latest", fetch, 0, logger); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
+
+func TestMultiKeySigner_SignTransactionUsesChainIDResolver(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	logger, _ := test.NewNullLogger()
+	m := NewMultiKeySigner("local", big.NewInt(1), logger)
+	if err := m.AddClient("local", NewLocalKeystoreSigner(key, big.NewInt(1))); err != nil {
+		t.Fatalf("AddClient() error: %v", err)
+	}
+	resolver, err := NewChainIDResolver(big.NewInt(1), ChainIDPolicyDownstream, func() (*big.Int, error) {
+		return big.NewInt(5), nil
+	}, 0, logger)
+	if err != nil {
+		t.Fatalf("NewChainIDResolver() error: %v", err)
+	}
+	m.SetChainIDResolver(resolver)
+	if got, err := m.ResolvedChainID(); err != nil || got.Int64() != 5 {
+		t.Errorf("ResolvedChainID() = (%v, %v), want 5", got, err)
+	}
+	if got := m.ChainID(); got.Int64() != 1 {
+		t.Errorf("ChainID() = %v, want configured 1", got)
+	}
+
+	to := ethgo.HexToAddress("0x0000000000000000000000000000000000000001")
+	tx := &ethgo.Transaction{
+		Type:                 ethgo.TransactionDynamicFee,
+		To:                   &to,
+		Gas:                  21000,
+		MaxFeePerGas:         big.NewInt(2),
+		MaxPriorityFeePerGas: big.NewInt(1),
+		Value:                big.NewInt(0),
+	}
+	signed, err := m.SignTransaction(tx)
+	if err != nil {
+		t.Fatalf("SignTransaction() error: %v", err)
+	}
+	if signed.ChainID == nil || signed.ChainID.Int64() != 5 {
+		t.Errorf("signed chainId = %v, want 5", signed.ChainID)
+	}
+	if tx.ChainID != nil {
+		t.Error("SignTransaction() must not modify the caller's transaction")
+	}
+
+	// downstream 策略下显式 chainId 须与解析出的链 ID 一致
+	tx.ChainID = big.NewInt(5)
+	if signed, err = m.SignTransaction(tx); err != nil {
+		t.Fatalf("SignTransaction() error: %v", err)
+	}
+	if signed.ChainID.Int64() != 5 {
+		t.Errorf("signed chainId = %s, want explicit 5", signed.ChainID)
+	}
+	tx.ChainID = big.NewInt(1)
+	if _, err = m.SignTransaction(tx); err == nil {
+		t.Error("Expected explicit chainId 1 to be rejected when downstream reports 5")
+	}
+
+	// config 策略下显式 chainId 原样使用
+	configured, err := NewChainIDResolver(big.NewInt(1), ChainIDPolicyConfig, func() (*big.Int, error) {
+		return big.NewInt(5), nil
+	}, 0, logger)
+	if err != nil {
+		t.Fatalf("NewChainIDResolver() error: %v", err)
+	}
+	m.SetChainIDResolver(configured)
+	tx.ChainID = big.NewInt(7)
+	if signed, err = m.SignTransaction(tx); err != nil {
+		t.Fatalf("SignTransaction() error: %v", err)
+	}
+	if signed.ChainID.Int64() != 7 {
+		t.Errorf("signed chainId = %s, want explicit 7", signed.ChainID)
+	}
+
+	failing, err := NewChainIDResolver(big.NewInt(1), ChainIDPolicyFail, func() (*big.Int, error) {
+		return big.NewInt(5), nil
+	}, 0, logger)
+	if err != nil {
+		t.Fatalf("NewChainIDResolver() error: %v", err)
+	}
+	m.SetChainIDResolver(failing)
+	tx.ChainID = nil
+	if _, err := m.SignTransaction(tx); err == nil {
+		t.Error("Expected SignTransaction() to fail under the fail policy")
+	}
+}
@@ -0,0 +1,130 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// CosmosSignature is a Cosmos SDK secp256k1 signature with its public key.
+type CosmosSignature struct {
+	// Signature is the 64-byte r || s signature with s in the lower half of the curve order
+	Signature []byte
+	// PubKey is the 33-byte compressed secp256k1 public key of the signer
+	PubKey []byte
+}
+
+// CosmosAminoSignBytes returns the sign bytes of a legacy Amino JSON StdSignDoc.
+//
+// Like the Cosmos SDK, the document is re-encoded as compact JSON with object
+// keys sorted and <, > and & escaped.
+//
+// Parameters:
+//   - signDoc: The StdSignDoc JSON object
+//
+// Returns:
+//   - []byte: The canonical sign bytes
+//   - error: An error if signDoc is not a JSON object
+func CosmosAminoSignBytes(signDoc json.RawMessage) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(signDoc, &doc); err != nil {
+		return nil, fmt.Errorf("sign doc must be a JSON object: %w", err)
+	}
+	// encoding/json 对 map 的键排序并转义 HTML 字符，与 Cosmos SDK 的 MustSortJSON 一致
+	return json.Marshal(doc)
+}
+
+// CosmosDirectSignBytes returns the sign bytes of a protobuf SignDoc (SIGN_MODE_DIRECT).
+//
+// Parameters:
+//   - bodyBytes: Protobuf-encoded TxBody
+//   - authInfoBytes: Protobuf-encoded AuthInfo
+//   - chainID: The Cosmos chain ID
+//   - accountNumber: The signer's account number
+//
+// Returns:
+//   - []byte: The protobuf-encoded SignDoc
+func CosmosDirectSignBytes(bodyBytes, authInfoBytes []byte, chainID string, accountNumber uint64) []byte {
+	// SignDoc 字段：1 body_bytes, 2 auth_info_bytes, 3 chain_id, 4 account_number；
+	// 与 proto3 编码一致，零值字段不输出
+	var buf []byte
+	appendBytesField := func(field byte, value []byte) {
+		if len(value) == 0 {
+			return
+		}
+		buf = append(buf, field<<3|2)
+		buf = appendUvarint(buf, uint64(len(value)))
+		buf = append(buf, value...)
+	}
+	appendBytesField(1, bodyBytes)
+	appendBytesField(2, authInfoBytes)
+	appendBytesField(3, []byte(chainID))
+	if accountNumber != 0 {
+		buf = append(buf, 4<<3)
+		buf = appendUvarint(buf, accountNumber)
+	}
+	return buf
+}
+
+// appendUvarint 追加 protobuf varint 编码
+func appendUvarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+// SignCosmos signs Cosmos SDK sign bytes with an Ethereum signer's secp256k1 key.
+//
+// The sign bytes are hashed with SHA-256 rather than Keccak-256, and the
+// Ethereum recovery id is dropped. The signer's public key is recovered from
+// the signature so the caller can build the Cosmos signer info.
+//
+// Parameters:
+//   - client: The signer holding the secp256k1 key
+//   - signBytes: The Amino JSON or protobuf sign bytes
+//
+// Returns:
+//   - *CosmosSignature: The 64-byte signature and compressed public key
+//   - error: An error if signing fails
+func SignCosmos(client Client, signBytes []byte) (*CosmosSignature, error) {
+	hash := sha256.Sum256(signBytes)
+	signature, err := client.Sign(hash[:])
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSignatureValues(signature); err != nil {
+		return nil, err
+	}
+
+	pub, err := wallet.RecoverPubkey(signature, hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover public key: %w", err)
+	}
+	uncompressed := make([]byte, 65)
+	uncompressed[0] = 0x04
+	pub.X.FillBytes(uncompressed[1:33])
+	pub.Y.FillBytes(uncompressed[33:])
+	if address, err := addressFromPublicKey(uncompressed); err != nil || address != client.Address() {
+		return nil, fmt.Errorf("signature does not match signer address %s", client.Address())
+	}
+
+	pubKey := make([]byte, 33)
+	pubKey[0] = 0x02 + byte(pub.Y.Bit(0))
+	pub.X.FillBytes(pubKey[1:])
+
+	// Cosmos SDK 只接受 low-S 签名
+	sig := make([]byte, 64)
+	copy(sig, signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(secp256k1N, s)
+	}
+	s.FillBytes(sig[32:])
+
+	return &CosmosSignature{Signature: sig, PubKey: pubKey}, nil
+}
@@ -0,0 +1,81 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/umbracle/ethgo/wallet"
+)
+
+func TestCosmosAminoSignBytes(t *testing.T) {
+	doc := json.RawMessage(`{
+		"chain_id": "cosmoshub-4",
+		"account_number": "7",
+		"memo": "a<b",
+		"msgs": [{"type": "cosmos-sdk/MsgSend", "value": {"to_address": "cosmos1x", "amount": [{"denom": "uatom", "amount": "1"}]}}],
+		"fee": {"gas": "200000", "amount": []},
+		"sequence": "0"
+	}`)
+
+	got, err := CosmosAminoSignBytes(doc)
+	if err != nil {
+		t.Fatalf("CosmosAminoSignBytes() error: %v", err)
+	}
+	want := `{"account_number":"7","chain_id":"cosmoshub-4","fee":{"amount":[],"gas":"200000"},"memo":"a\u003cb",` +
+		`"msgs":[{"type":"cosmos-sdk/MsgSend","value":{"amount":[{"amount":"1","denom":"uatom"}],"to_address":"cosmos1x"}}],"sequence":"0"}`
+	if string(got) != want {
+		t.Errorf("CosmosAminoSignBytes() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := CosmosAminoSignBytes(json.RawMessage(`["not", "an", "object"]`)); err == nil {
+		t.Error("Expected error for non-object sign doc")
+	}
+}
+
+func TestCosmosDirectSignBytes(t *testing.T) {
+	got := CosmosDirectSignBytes([]byte{0x0a, 0x01}, []byte{0x12}, "c", 300)
+	want := []byte{0x0a, 0x02, 0x0a, 0x01, 0x12, 0x01, 0x12, 0x1a, 0x01, 'c', 0x20, 0xac, 0x02}
+	if !bytes.Equal(got, want) {
+		t.Errorf("CosmosDirectSignBytes() = %x, want %x", got, want)
+	}
+
+	// account number 0 与 proto3 一致不编码
+	if got := CosmosDirectSignBytes([]byte{0x01}, []byte{0x02}, "c", 0); !bytes.Equal(got, []byte{0x0a, 0x01, 0x01, 0x12, 0x01, 0x02, 0x1a, 0x01, 'c'}) {
+		t.Errorf("CosmosDirectSignBytes() with zero account number = %x", got)
+	}
+}
+
+func TestSignCosmos(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	s := NewLocalKeystoreSigner(key, big.NewInt(1))
+	signBytes := []byte(`{"chain_id":"cosmoshub-4"}`)
+
+	result, err := SignCosmos(s, signBytes)
+	if err != nil {
+		t.Fatalf("SignCosmos() error: %v", err)
+	}
+	if len(result.Signature) != 64 || len(result.PubKey) != 33 {
+		t.Fatalf("unexpected lengths: signature %d, pubkey %d", len(result.Signature), len(result.PubKey))
+	}
+	if new(big.Int).SetBytes(result.Signature[32:]).Cmp(secp256k1HalfN) > 0 {
+		t.Error("SignCosmos() returned high-S signature")
+	}
+
+	// 签名针对 SHA-256 哈希，可恢复出签名密钥
+	hash := sha256.Sum256(signBytes)
+	recovered := false
+	for v := byte(0); v <= 1; v++ {
+		if addr, err := wallet.Ecrecover(hash[:], append(append([]byte{}, result.Signature...), v)); err == nil && addr == key.Address() {
+			recovered = true
+		}
+	}
+	if !recovered {
+		t.Error("signature does not recover to the signer address")
+	}
+}
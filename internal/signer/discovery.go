@@ -0,0 +1,94 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/mowind/web3signer-go/internal/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+// NewMultiKeySignerFromKMS discovers every key accessible with the KMS credentials
+// and registers an MPCKMSSigner for each of them.
+//
+// Keys whose address is missing from the list response are resolved with GetKey.
+// Keys that cannot be resolved, and keys the KMS reports as using a curve
+// other than secp256k1, are skipped with a warning.
+//
+// Parameters:
+//   - ctx: Context bounding the discovery requests
+//   - client: KMS client used by the created signers
+//   - discoverer: KMS key discovery client (usually the same *kms.Client)
+//   - defaultKeyID: The default key ID; if empty, the first discovered key is used
+//   - chainID: The chain ID for transaction signing
+//   - logger: Logger for operation tracking
+//
+// Returns:
+//   - *MultiKeySigner: A signer with all discovered keys registered
+//   - error: An error if listing fails, no key could be registered, or the default key was not discovered
+func NewMultiKeySignerFromKMS(ctx context.Context, client kms.ClientInterface, discoverer kms.KeyDiscoverer, defaultKeyID string, chainID *big.Int, logger *logrus.Logger) (*MultiKeySigner, error) {
+	keys, err := discoverer.ListKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KMS keys: %w", err)
+	}
+
+	var resolved []kms.KeyInfo
+	for _, key := range keys {
+		if key.KeyID == "" {
+			continue
+		}
+		if key.Address == "" {
+			info, err := discoverer.GetKey(ctx, key.KeyID)
+			if err != nil {
+				logger.WithError(err).WithField("key_id", key.KeyID).Warn("Skipping KMS key: failed to fetch address")
+				continue
+			}
+			key.Address = info.Address
+			if key.Algorithm == "" {
+				key.Algorithm = info.Algorithm
+			}
+		}
+		if err := key.CheckSecp256k1(); err != nil {
+			logger.WithError(err).WithField("key_id", key.KeyID).Warn("Skipping KMS key: unsupported key type")
+			continue
+		}
+		if !utils.IsValidEthAddress(key.Address) {
+			logger.WithFields(logrus.Fields{
+				"key_id":  key.KeyID,
+				"address": key.Address,
+			}).Warn("Skipping KMS key: invalid address")
+			continue
+		}
+		resolved = append(resolved, key)
+	}
+
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no usable keys discovered from KMS")
+	}
+
+	if defaultKeyID == "" {
+		defaultKeyID = resolved[0].KeyID
+	}
+
+	m := NewMultiKeySigner(defaultKeyID, chainID, logger)
+	for _, key := range resolved {
+		mpcSigner := NewMPCKMSSigner(client, key.KeyID, ethgo.HexToAddress(key.Address), chainID)
+		if err := m.AddClient(key.KeyID, mpcSigner); err != nil {
+			logger.WithError(err).WithField("key_id", key.KeyID).Warn("Skipping duplicate KMS key")
+		}
+	}
+
+	if _, err := m.GetClient(defaultKeyID); err != nil {
+		return nil, fmt.Errorf("default key %s was not discovered from KMS", defaultKeyID)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"keys":           len(resolved),
+		"default_key_id": defaultKeyID,
+	}).Info("Registered keys discovered from KMS")
+
+	return m, nil
+}
@@ -0,0 +1,125 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mowind/web3signer-go/internal/kms"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeKeyDiscoverer 返回预设的密钥列表
+type fakeKeyDiscoverer struct {
+	keys       []kms.KeyInfo
+	details    map[string]string
+	algorithms map[string]string
+	listErr    error
+}
+
+func (f *fakeKeyDiscoverer) ListKeys(ctx context.Context) ([]kms.KeyInfo, error) {
+	return f.keys, f.listErr
+}
+
+func (f *fakeKeyDiscoverer) GetKey(ctx context.Context, keyID string) (*kms.KeyInfo, error) {
+	address, ok := f.details[keyID]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+	return &kms.KeyInfo{KeyID: keyID, Address: address, Algorithm: f.algorithms[keyID]}, nil
+}
+
+func TestNewMultiKeySignerFromKMS(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	discoverer := &fakeKeyDiscoverer{
+		keys: []kms.KeyInfo{
+			{KeyID: "key-1", Address: "0x1111111111111111111111111111111111111111"},
+			{KeyID: "key-2"},
+			{KeyID: "key-3"},
+			{KeyID: "key-4", Address: "not-an-address"},
+			{KeyID: "key-5", Address: "0x5555555555555555555555555555555555555555", Algorithm: "ed25519"},
+			{KeyID: "key-6"},
+		},
+		details: map[string]string{
+			"key-2": "0x2222222222222222222222222222222222222222",
+			"key-6": "0x6666666666666666666666666666666666666666",
+		},
+		algorithms: map[string]string{"key-2": "SECP256K1", "key-6": "ed25519"},
+	}
+
+	tests := []struct {
+		name         string
+		discoverer   *fakeKeyDiscoverer
+		defaultKeyID string
+		wantDefault  string
+		wantKeys     []string
+		wantErr      string
+	}{
+		{
+			name:        "first key becomes default",
+			discoverer:  discoverer,
+			wantDefault: "0x1111111111111111111111111111111111111111",
+			wantKeys:    []string{"key-1", "key-2"},
+		},
+		{
+			name:         "configured default key",
+			discoverer:   discoverer,
+			defaultKeyID: "key-2",
+			wantDefault:  "0x2222222222222222222222222222222222222222",
+			wantKeys:     []string{"key-1", "key-2"},
+		},
+		{
+			name:         "default key not discovered",
+			discoverer:   discoverer,
+			defaultKeyID: "key-3",
+			wantErr:      "default key key-3 was not discovered",
+		},
+		{
+			name:         "default key with unsupported algorithm",
+			discoverer:   discoverer,
+			defaultKeyID: "key-5",
+			wantErr:      "default key key-5 was not discovered",
+		},
+		{
+			name:       "list failure",
+			discoverer: &fakeKeyDiscoverer{listErr: errors.New("unauthorized")},
+			wantErr:    "failed to list KMS keys",
+		},
+		{
+			name:       "no usable keys",
+			discoverer: &fakeKeyDiscoverer{keys: []kms.KeyInfo{{KeyID: "key-3"}}},
+			wantErr:    "no usable keys",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMultiKeySignerFromKMS(context.Background(), &mockKMSClient{}, tt.discoverer, tt.defaultKeyID, big.NewInt(1), logger)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := strings.ToLower(m.Address().String()); got != tt.wantDefault {
+				t.Errorf("default address = %s, want %s", got, tt.wantDefault)
+			}
+			for _, keyID := range tt.wantKeys {
+				if _, err := m.GetClient(keyID); err != nil {
+					t.Errorf("expected key %s to be registered: %v", keyID, err)
+				}
+			}
+			if stats := m.KeyStats(); len(stats) != len(tt.wantKeys) {
+				t.Errorf("registered %d keys, want %d", len(stats), len(tt.wantKeys))
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/umbracle/ethgo"
+)
+
+// eip712DomainType EIP-712 域类型名称
+const eip712DomainType = "EIP712Domain"
+
+// TypedDataField EIP-712 类型中的单个字段
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData EIP-712 结构化数据（eth_signTypedData_v4 格式）
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// eip712DomainFields 域字段的规范顺序，用于在 types 缺少 EIP712Domain 时推导域类型
+var eip712DomainFields = []TypedDataField{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+	{Name: "salt", Type: "bytes32"},
+}
+
+var typedArrayPattern = regexp.MustCompile(`^(.+)\[(\d*)\]$`)
+
+// ParseSignTypedDataParams parses eth_signTypedData parameters.
+//
+// Parameters format: ["0xAddress", typedData], where typedData is either a
+// JSON object or a JSON-encoded string (as sent by MetaMask).
+func ParseSignTypedDataParams(params json.RawMessage) (address string, typedData *TypedData, err error) {
+	var paramsArray []json.RawMessage
+	if err := json.Unmarshal(params, &paramsArray); err != nil {
+		return "", nil, fmt.Errorf("failed to parse typed data params: %v", err)
+	}
+
+	if len(paramsArray) < 2 {
+		return "", nil, fmt.Errorf("insufficient parameters for eth_signTypedData")
+	}
+
+	if err := json.Unmarshal(paramsArray[0], &address); err != nil {
+		return "", nil, fmt.Errorf("invalid address parameter")
+	}
+
+	raw := []byte(paramsArray[1])
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		raw = []byte(encoded)
+	}
+
+	typedData, err = ParseTypedData(raw)
+	if err != nil {
+		return "", nil, err
+	}
+	return address, typedData, nil
+}
+
+// ParseTypedData decodes EIP-712 typed data, keeping numbers as json.Number
+// so that uint256 values do not lose precision.
+func ParseTypedData(data []byte) (*TypedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var typedData TypedData
+	if err := decoder.Decode(&typedData); err != nil {
+		return nil, fmt.Errorf("failed to parse typed data: %v", err)
+	}
+	if typedData.PrimaryType == "" {
+		return nil, fmt.Errorf("typed data is missing primaryType")
+	}
+	if typedData.Types == nil {
+		return nil, fmt.Errorf("typed data is missing types")
+	}
+	if typedData.Domain == nil {
+		typedData.Domain = map[string]interface{}{}
+	}
+	return &typedData, nil
+}
+
+// DomainChainID returns the chainId declared in the domain, or nil if the
+// domain omits it.
+func (td *TypedData) DomainChainID() (*big.Int, error) {
+	value, ok := td.Domain["chainId"]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	chainID, err := parseTypedInteger(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid domain chainId: %v", err)
+	}
+	return chainID, nil
+}
+
+// Hash computes the EIP-712 signing digest:
+// keccak256(0x19 0x01 || domainSeparator || hashStruct(message)).
+func (td *TypedData) Hash() ([]byte, error) {
+	if _, ok := td.Types[td.PrimaryType]; !ok && td.PrimaryType != eip712DomainType {
+		return nil, fmt.Errorf("primaryType %q is not defined in types", td.PrimaryType)
+	}
+
+	domainSeparator, err := td.hashStruct(eip712DomainType, td.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash domain: %v", err)
+	}
+
+	buf := []byte{0x19, 0x01}
+	buf = append(buf, domainSeparator...)
+
+	// primaryType 为 EIP712Domain 时仅对域签名
+	if td.PrimaryType != eip712DomainType {
+		messageHash, err := td.hashStruct(td.PrimaryType, td.Message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash message: %v", err)
+		}
+		buf = append(buf, messageHash...)
+	}
+
+	return ethgo.Keccak256(buf), nil
+}
+
+// fields 返回类型的字段定义，EIP712Domain 缺失时根据域中出现的字段推导
+func (td *TypedData) fields(typeName string) ([]TypedDataField, bool) {
+	if fields, ok := td.Types[typeName]; ok {
+		return fields, true
+	}
+	if typeName != eip712DomainType {
+		return nil, false
+	}
+	var fields []TypedDataField
+	for _, field := range eip712DomainFields {
+		if _, ok := td.Domain[field.Name]; ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields, true
+}
+
+// hashStruct = keccak256(typeHash || encodeData(s))
+func (td *TypedData) hashStruct(typeName string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := td.encodeData(typeName, data)
+	if err != nil {
+		return nil, err
+	}
+	return ethgo.Keccak256(encoded), nil
+}
+
+// encodeType 返回类型的规范编码，依赖类型按名称排序追加在主类型之后
+func (td *TypedData) encodeType(typeName string) (string, error) {
+	deps := map[string]bool{}
+	if err := td.collectDependencies(typeName, deps); err != nil {
+		return "", err
+	}
+	delete(deps, typeName)
+
+	sorted := make([]string, 0, len(deps))
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	for _, name := range append([]string{typeName}, sorted...) {
+		fields, _ := td.fields(name)
+		b.WriteString(name)
+		b.WriteByte('(')
+		for i, field := range fields {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(field.Type)
+			b.WriteByte(' ')
+			b.WriteString(field.Name)
+		}
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+func (td *TypedData) collectDependencies(typeName string, deps map[string]bool) error {
+	if deps[typeName] {
+		return nil
+	}
+	fields, ok := td.fields(typeName)
+	if !ok {
+		return fmt.Errorf("type %q is not defined", typeName)
+	}
+	deps[typeName] = true
+	for _, field := range fields {
+		base := baseTypeName(field.Type)
+		if _, ok := td.Types[base]; ok {
+			if err := td.collectDependencies(base, deps); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// encodeData 编码结构体：typeHash 后依次追加每个字段的 32 字节编码
+func (td *TypedData) encodeData(typeName string, data map[string]interface{}) ([]byte, error) {
+	encodedType, err := td.encodeType(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, _ := td.fields(typeName)
+	buf := make([]byte, 0, 32*(len(fields)+1))
+	buf = append(buf, ethgo.Keccak256([]byte(encodedType))...)
+
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for field %s.%s", typeName, field.Name)
+		}
+		encoded, err := td.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %v", typeName, field.Name, err)
+		}
+		buf = append(buf, encoded...)
+	}
+	return buf, nil
+}
+
+// encodeValue 按 EIP-712 规则将单个值编码为 32 字节
+func (td *TypedData) encodeValue(typeName string, value interface{}) ([]byte, error) {
+	if match := typedArrayPattern.FindStringSubmatch(typeName); match != nil {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected array for type %s", typeName)
+		}
+		if match[2] != "" {
+			length, err := strconv.Atoi(match[2])
+			if err != nil || length != len(items) {
+				return nil, fmt.Errorf("expected %s elements for type %s, got %d", match[2], typeName, len(items))
+			}
+		}
+		var buf []byte
+		for _, item := range items {
+			encoded, err := td.encodeValue(match[1], item)
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, encoded...)
+		}
+		return ethgo.Keccak256(buf), nil
+	}
+
+	if _, ok := td.Types[typeName]; ok {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object for type %s", typeName)
+		}
+		return td.hashStruct(typeName, nested)
+	}
+
+	switch {
+	case typeName == "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string")
+		}
+		return ethgo.Keccak256([]byte(s)), nil
+	case typeName == "bytes":
+		b, err := typedHexBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return ethgo.Keccak256(b), nil
+	case typeName == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool")
+		}
+		word := make([]byte, 32)
+		if b {
+			word[31] = 1
+		}
+		return word, nil
+	case typeName == "address":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected address string")
+		}
+		b, err := parseHex(s)
+		if err != nil || len(b) != 20 {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		return leftPad32(b), nil
+	case strings.HasPrefix(typeName, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(typeName, "bytes"))
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("unsupported type %s", typeName)
+		}
+		b, err := typedHexBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > size {
+			return nil, fmt.Errorf("value exceeds %d bytes", size)
+		}
+		word := make([]byte, 32)
+		copy(word, b)
+		return word, nil
+	case strings.HasPrefix(typeName, "uint"), strings.HasPrefix(typeName, "int"):
+		return encodeTypedInteger(typeName, value)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", typeName)
+}
+
+// encodeTypedInteger 编码 intN/uintN，负数使用 256 位补码
+func encodeTypedInteger(typeName string, value interface{}) ([]byte, error) {
+	signed := strings.HasPrefix(typeName, "int")
+	bitsStr := strings.TrimPrefix(strings.TrimPrefix(typeName, "u"), "int")
+	bits := 256
+	if bitsStr != "" {
+		var err error
+		bits, err = strconv.Atoi(bitsStr)
+		if err != nil || bits < 8 || bits > 256 || bits%8 != 0 {
+			return nil, fmt.Errorf("unsupported type %s", typeName)
+		}
+	}
+
+	n, err := parseTypedInteger(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if !signed {
+		if n.Sign() < 0 || n.BitLen() > bits {
+			return nil, fmt.Errorf("value %s out of range for %s", n, typeName)
+		}
+		return leftPad32(n.Bytes()), nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("value %s out of range for %s", n, typeName)
+	}
+	if n.Sign() < 0 {
+		n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	return leftPad32(n.Bytes()), nil
+}
+
+// parseTypedInteger 解析整数值，支持 JSON 数字、十进制字符串和 0x 十六进制字符串
+func parseTypedInteger(value interface{}) (*big.Int, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = strings.TrimSpace(v)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("expected integer, got %T", value)
+	}
+
+	n := new(big.Int)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	var ok bool
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		_, ok = n.SetString(digits[2:], 16)
+	} else {
+		_, ok = n.SetString(digits, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	if negative {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+func typedHexBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected hex string")
+	}
+	b, err := parseHex(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex %q: %v", s, err)
+	}
+	return b, nil
+}
+
+// baseTypeName 去掉数组后缀，返回元素类型名
+func baseTypeName(typeName string) string {
+	for {
+		match := typedArrayPattern.FindStringSubmatch(typeName)
+		if match == nil {
+			return typeName
+		}
+		typeName = match[1]
+	}
+}
+
+func leftPad32(b []byte) []byte {
+	word := make([]byte, 32)
+	copy(word[32-len(b):], b)
+	return word
+}
@@ -0,0 +1,156 @@
+package signer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+// mailTypedData EIP-712 规范中的 Mail 示例
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedData_Hash(t *testing.T) {
+	typedData, err := ParseTypedData([]byte(mailTypedData))
+	if err != nil {
+		t.Fatalf("ParseTypedData() error = %v", err)
+	}
+
+	encodedType, err := typedData.encodeType("Mail")
+	if err != nil {
+		t.Fatalf("encodeType() error = %v", err)
+	}
+	if want := "Mail(Person from,Person to,string contents)Person(string name,address wallet)"; encodedType != want {
+		t.Errorf("encodeType() = %s, want %s", encodedType, want)
+	}
+
+	domainSeparator, err := typedData.hashStruct(eip712DomainType, typedData.Domain)
+	if err != nil {
+		t.Fatalf("hashStruct(domain) error = %v", err)
+	}
+	if got, want := hex.EncodeToString(domainSeparator), "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"; got != want {
+		t.Errorf("domain separator = %s, want %s", got, want)
+	}
+
+	digest, err := typedData.Hash()
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if got, want := hex.EncodeToString(digest), "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"; got != want {
+		t.Errorf("Hash() = %s, want %s", got, want)
+	}
+}
+
+func TestTypedData_DerivedDomainType(t *testing.T) {
+	withDomain, err := ParseTypedData([]byte(mailTypedData))
+	if err != nil {
+		t.Fatalf("ParseTypedData() error = %v", err)
+	}
+	withoutDomain, _ := ParseTypedData([]byte(mailTypedData))
+	delete(withoutDomain.Types, eip712DomainType)
+
+	want, _ := withDomain.Hash()
+	got, err := withoutDomain.Hash()
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if hex.EncodeToString(got) != hex.EncodeToString(want) {
+		t.Errorf("derived domain hash = %x, want %x", got, want)
+	}
+}
+
+func TestTypedData_DomainChainID(t *testing.T) {
+	tests := []struct {
+		name    string
+		chainID interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "number", chainID: json.Number("137"), want: "137"},
+		{name: "decimal string", chainID: "137", want: "137"},
+		{name: "hex string", chainID: "0x89", want: "137"},
+		{name: "omitted", chainID: nil, want: ""},
+		{name: "invalid", chainID: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typedData := &TypedData{Domain: map[string]interface{}{}}
+			if tt.chainID != nil {
+				typedData.Domain["chainId"] = tt.chainID
+			}
+			got, err := typedData.DomainChainID()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DomainChainID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("DomainChainID() = %s, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.String() != tt.want {
+				t.Errorf("DomainChainID() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSignTypedDataParams(t *testing.T) {
+	encoded, _ := json.Marshal(mailTypedData)
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "object", params: `["0x1234567890123456789012345678901234567890",` + mailTypedData + `]`},
+		{name: "json string", params: `["0x1234567890123456789012345678901234567890",` + string(encoded) + `]`},
+		{name: "missing typed data", params: `["0x1234567890123456789012345678901234567890"]`, wantErr: true},
+		{name: "missing primaryType", params: `["0x1234567890123456789012345678901234567890",{"types":{}}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, typedData, err := ParseSignTypedDataParams(json.RawMessage(tt.params))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSignTypedDataParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && typedData.PrimaryType != "Mail" {
+				t.Errorf("PrimaryType = %s, want Mail", typedData.PrimaryType)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// LocalKeystoreSigner implements Client with a private key held in process memory.
+//
+// It is intended for local development and integration testing of the
+// routing and forwarding pipeline without a KMS. It must never be used
+// in production: the private key is exposed to the server process.
+type LocalKeystoreSigner struct {
+	key     *wallet.Key
+	chainID *big.Int
+
+	verifyNonce bool // 签名后校验是否使用 RFC 6979 确定性 k
+}
+
+// NewLocalKeystoreSigner creates a signer for an in-memory key.
+//
+// Parameters:
+//   - key: The private key to sign with
+//   - chainID: The chain ID for transaction signing
+//
+// Returns:
+//   - *LocalKeystoreSigner: A new signer instance
+func NewLocalKeystoreSigner(key *wallet.Key, chainID *big.Int) *LocalKeystoreSigner {
+	return &LocalKeystoreSigner{key: key, chainID: chainID}
+}
+
+// WithNonceVerification enables checking that every signature uses the RFC 6979
+// deterministic nonce.
+//
+// A random or repeated ECDSA nonce leaks the private key; with verification
+// enabled, a signature whose r does not match the deterministic nonce is
+// rejected instead of returned.
+//
+// Parameters:
+//   - enabled: Whether to verify the nonce of each signature
+//
+// Returns:
+//   - *LocalKeystoreSigner: The signer with nonce verification configured
+func (s *LocalKeystoreSigner) WithNonceVerification(enabled bool) *LocalKeystoreSigner {
+	s.verifyNonce = enabled
+	return s
+}
+
+// LoadLocalKeystoreSigner creates a signer from an encrypted JSON (V3) keystore file.
+//
+// Parameters:
+//   - path: Path to the keystore file
+//   - password: Keystore password
+//   - chainID: The chain ID for transaction signing
+//
+// Returns:
+//   - *LocalKeystoreSigner: A new signer instance
+//   - error: An error if the file cannot be read or decrypted
+func LoadLocalKeystoreSigner(path, password string, chainID *big.Int) (*LocalKeystoreSigner, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	key, err := wallet.NewJSONWalletFromContent(content, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	return NewLocalKeystoreSigner(key, chainID), nil
+}
+
+// NewLocalKeystoreSignerFromHex creates a signer from a hex-encoded raw private key.
+//
+// Parameters:
+//   - privateKeyHex: 32-byte private key, with or without 0x prefix
+//   - chainID: The chain ID for transaction signing
+//
+// Returns:
+//   - *LocalKeystoreSigner: A new signer instance
+//   - error: An error if the key is malformed
+func NewLocalKeystoreSignerFromHex(privateKeyHex string, chainID *big.Int) (*LocalKeystoreSigner, error) {
+	privateKey, err := parseHex(strings.TrimSpace(privateKeyHex))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	if len(privateKey) != 32 {
+		return nil, fmt.Errorf("invalid private key length: expected 32 bytes, got %d", len(privateKey))
+	}
+	key, err := wallet.NewWalletFromPrivKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return NewLocalKeystoreSigner(key, chainID), nil
+}
+
+// Address returns the address of the local key.
+//
+// Returns:
+//   - ethgo.Address: The address associated with this signer
+func (s *LocalKeystoreSigner) Address() ethgo.Address {
+	return s.key.Address()
+}
+
+// ChainID returns the chain ID the signer was configured with.
+//
+// Returns:
+//   - *big.Int: The configured chain ID, or nil if none was provided
+func (s *LocalKeystoreSigner) ChainID() *big.Int {
+	return s.chainID
+}
+
+// Sign signs a 32-byte hash with the local key.
+//
+// Parameters:
+//   - hash: 32-byte hash to sign (typically Keccak-256)
+//
+// Returns:
+//   - []byte: 65-byte signature (r, s, v values)
+//   - error: An error if hash is invalid or signing fails
+func (s *LocalKeystoreSigner) Sign(hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("invalid hash length: expected 32 bytes, got %d", len(hash))
+	}
+	signature, err := s.key.Sign(hash)
+	if err != nil || !s.verifyNonce {
+		return signature, err
+	}
+	privateKey, err := s.key.MarshallPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify signature nonce: %w", err)
+	}
+	if err := verifyDeterministicNonce(privateKey, hash, signature); err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+// SignTransaction signs an Ethereum transaction with the local key.
+//
+// Parameters:
+//   - tx: The transaction to sign
+//
+// Returns:
+//   - *ethgo.Transaction: A new transaction with signature applied
+//   - error: An error if signing fails
+func (s *LocalKeystoreSigner) SignTransaction(tx *ethgo.Transaction) (*ethgo.Transaction, error) {
+	return assembleSignedTransaction(copyTransactionForSigning(tx, s.Address()), s.chainID, s.Sign)
+}
+
+// VerifyInterface 验证接口实现
+var (
+	_ Client    = (*LocalKeystoreSigner)(nil)
+	_ ethgo.Key = (*LocalKeystoreSigner)(nil)
+)
@@ -0,0 +1,134 @@
+package signer
+
+import (
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/keystore"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+func TestLoadLocalKeystoreSigner(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	privateKey, err := key.MarshallPrivateKey()
+	if err != nil {
+		t.Fatalf("MarshallPrivateKey() error: %v", err)
+	}
+
+	// 使用较小的 scrypt 参数加快测试
+	content, err := keystore.EncryptV3(privateKey, "secret", 1<<10)
+	if err != nil {
+		t.Fatalf("EncryptV3() error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	s, err := LoadLocalKeystoreSigner(path, "secret", big.NewInt(1))
+	if err != nil {
+		t.Fatalf("LoadLocalKeystoreSigner() error: %v", err)
+	}
+	if s.Address() != key.Address() {
+		t.Errorf("Address() = %s, want %s", s.Address(), key.Address())
+	}
+
+	if _, err := LoadLocalKeystoreSigner(path, "wrong", big.NewInt(1)); err == nil {
+		t.Error("Expected error for wrong keystore password")
+	}
+	if _, err := LoadLocalKeystoreSigner(filepath.Join(t.TempDir(), "missing.json"), "secret", big.NewInt(1)); err == nil {
+		t.Error("Expected error for missing keystore file")
+	}
+}
+
+func TestLocalKeystoreSigner_SignTransaction(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	privateKey, err := key.MarshallPrivateKey()
+	if err != nil {
+		t.Fatalf("MarshallPrivateKey() error: %v", err)
+	}
+
+	chainID := big.NewInt(1337)
+	s, err := NewLocalKeystoreSignerFromHex("0x"+hex.EncodeToString(privateKey), chainID)
+	if err != nil {
+		t.Fatalf("NewLocalKeystoreSignerFromHex() error: %v", err)
+	}
+
+	to := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+	signedTx, err := s.SignTransaction(&ethgo.Transaction{
+		Type:     ethgo.TransactionLegacy,
+		Nonce:    3,
+		To:       &to,
+		Value:    big.NewInt(1000),
+		Gas:      21000,
+		GasPrice: 20000000000,
+	})
+	if err != nil {
+		t.Fatalf("SignTransaction() error: %v", err)
+	}
+
+	sender, err := wallet.NewEIP155Signer(chainID.Uint64()).RecoverSender(signedTx)
+	if err != nil {
+		t.Fatalf("RecoverSender() error: %v", err)
+	}
+	if sender != key.Address() || signedTx.From != key.Address() {
+		t.Errorf("sender = %s, From = %s, want %s", sender, signedTx.From, key.Address())
+	}
+}
+
+func TestLocalKeystoreSigner_SignTransaction_ChainIDOverride(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	s := NewLocalKeystoreSigner(key, big.NewInt(1))
+	to := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+
+	for _, txType := range []ethgo.TransactionType{ethgo.TransactionLegacy, ethgo.TransactionDynamicFee} {
+		signedTx, err := s.SignTransaction(&ethgo.Transaction{
+			Type: txType, ChainID: big.NewInt(137), Nonce: 1, To: &to, Gas: 21000,
+			GasPrice: 1, MaxFeePerGas: big.NewInt(2), MaxPriorityFeePerGas: big.NewInt(1),
+		})
+		if err != nil {
+			t.Fatalf("type %d: SignTransaction() error: %v", txType, err)
+		}
+		raw, err := EncodeRawTransaction(signedTx)
+		if err != nil {
+			t.Fatalf("type %d: EncodeRawTransaction() error: %v", txType, err)
+		}
+
+		// 签名哈希、v 和编码中的 chainId 均使用请求的链 ID
+		decoded, err := DecodeRawTransaction(raw)
+		if err != nil {
+			t.Fatalf("type %d: DecodeRawTransaction() error: %v", txType, err)
+		}
+		if decoded.From != key.Address() {
+			t.Errorf("type %d: decoded sender = %s, want %s", txType, decoded.From, key.Address())
+		}
+		if txType == ethgo.TransactionLegacy {
+			if v := new(big.Int).SetBytes(decoded.V); v.Cmp(big.NewInt(137*2+35)) < 0 {
+				t.Errorf("type %d: v = %s, want EIP-155 v for chain 137", txType, v)
+			}
+		} else if decoded.ChainID == nil || decoded.ChainID.Int64() != 137 {
+			t.Errorf("type %d: decoded chainId = %v, want 137", txType, decoded.ChainID)
+		}
+	}
+}
+
+func TestNewLocalKeystoreSignerFromHex_Invalid(t *testing.T) {
+	for _, input := range []string{"", "0x1234", "not-hex"} {
+		if _, err := NewLocalKeystoreSignerFromHex(input, big.NewInt(1)); err == nil {
+			t.Errorf("Expected error for private key %q", input)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/mowind/web3signer-go/internal/kms"
 	"github.com/sirupsen/logrus"
@@ -24,14 +25,28 @@ type Client interface {
 // This signer implements the ethgo.Key interface and allows:
 //   - Multiple key IDs to be registered with their respective clients
 //   - Dynamic addition and removal of keys
-//   - A default key for backward compatibility
+//   - A default key for backward compatibility, switchable at runtime via SetDefaultKeyID
 //   - Per-transaction key selection via SignTransactionWithKeyID
+//   - Per-message key selection via SignWithKeyID
+//   - Per-key usage statistics via KeyStats
 type MultiKeySigner struct {
 	mu           sync.RWMutex
 	clients      map[string]Client // keyID -> Client mapping
 	defaultKeyID string            // default key ID for backward compatibility
 	logger       *logrus.Logger
 	chainID      *big.Int
+	chainIDs     *ChainIDResolver // 可选，为未指定 chainId 的交易解析签名链 ID
+
+	statsMu sync.Mutex
+	stats   map[string]*KeyStats // keyID -> usage statistics
+}
+
+// KeyStats holds usage statistics for a single key.
+type KeyStats struct {
+	SignCount  uint64    // 签名尝试次数（包括失败）
+	ErrorCount uint64    // 签名失败次数
+	LastUsed   time.Time // 最近一次签名时间，零值表示从未使用
+	LastError  string    // 最近一次签名错误，空表示从未失败
 }
 
 // NewMultiKeySigner creates a new MultiKeySigner instance.
@@ -49,6 +64,7 @@ func NewMultiKeySigner(defaultKeyID string, chainID *big.Int, logger *logrus.Log
 		defaultKeyID: defaultKeyID,
 		logger:       logger,
 		chainID:      chainID,
+		stats:        make(map[string]*KeyStats),
 	}
 }
 
@@ -101,6 +117,9 @@ func (m *MultiKeySigner) RemoveClient(keyID string) error {
 	}
 
 	delete(m.clients, keyID)
+	m.statsMu.Lock()
+	delete(m.stats, keyID)
+	m.statsMu.Unlock()
 	m.logger.WithField("key_id", keyID).Info("Client removed from MultiKeySigner")
 
 	return nil
@@ -125,6 +144,161 @@ func (m *MultiKeySigner) GetClient(keyID string) (Client, error) {
 	return client, nil
 }
 
+// DefaultKeyID returns the key ID used when no specific key is requested.
+//
+// Returns:
+//   - string: The default key ID
+func (m *MultiKeySigner) DefaultKeyID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.defaultKeyID
+}
+
+// SetDefaultKeyID switches the default key to another registered key.
+//
+// Signing calls already in progress finish with the key they started with;
+// later calls use the new key. The switch is logged as an audit event.
+//
+// Parameters:
+//   - keyID: The registered key ID to make the default
+//
+// Returns:
+//   - error: An error if keyID is not registered
+func (m *MultiKeySigner) SetDefaultKeyID(keyID string) error {
+	m.mu.Lock()
+	client, exists := m.clients[keyID]
+	if !exists {
+		m.mu.Unlock()
+		return fmt.Errorf("keyID %s not found", keyID)
+	}
+	previous := m.defaultKeyID
+	m.defaultKeyID = keyID
+	m.mu.Unlock()
+
+	m.logger.WithFields(logrus.Fields{
+		"audit":           true,
+		"event":           "default_key_rotated",
+		"previous_key_id": previous,
+		"key_id":          keyID,
+		"address":         client.Address().String(),
+	}).Warn("Default signing key changed")
+	return nil
+}
+
+// defaultClient 在同一次加锁内读取默认 keyID 及其客户端，避免与 SetDefaultKeyID 并发时两者不一致
+func (m *MultiKeySigner) defaultClient() (string, Client, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	client, exists := m.clients[m.defaultKeyID]
+	if !exists {
+		return m.defaultKeyID, nil, fmt.Errorf("keyID %s not found", m.defaultKeyID)
+	}
+	return m.defaultKeyID, client, nil
+}
+
+// Snapshot pins the current default key.
+//
+// The returned KeySnapshot signs with the key that was the default when it
+// was taken, even if SetDefaultKeyID switches the default afterwards. Use it
+// to handle one request with a single key from the sender check to signing.
+//
+// Returns:
+//   - *KeySnapshot: A signer bound to the current default key
+//   - error: An error if the default key is not registered
+func (m *MultiKeySigner) Snapshot() (*KeySnapshot, error) {
+	keyID, client, err := m.defaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return &KeySnapshot{signer: m, keyID: keyID, client: client}, nil
+}
+
+// KeySnapshot is a MultiKeySigner view pinned to one key.
+//
+// It signs like the MultiKeySigner's default key did when the snapshot was
+// taken: chain ID resolution and usage statistics still go through the
+// MultiKeySigner, while the key, client and address stay fixed. Key-selecting
+// methods (GetClient, SignWithKeyID) delegate to the MultiKeySigner unchanged.
+type KeySnapshot struct {
+	signer *MultiKeySigner
+	keyID  string
+	client Client
+}
+
+// DefaultKeyID returns the pinned key ID.
+func (s *KeySnapshot) DefaultKeyID() string {
+	return s.keyID
+}
+
+// Address returns the pinned key's Ethereum address.
+func (s *KeySnapshot) Address() ethgo.Address {
+	return s.client.Address()
+}
+
+// ChainID returns the chain ID the MultiKeySigner was configured with.
+func (s *KeySnapshot) ChainID() *big.Int {
+	return s.signer.ChainID()
+}
+
+// ResolvedChainID returns the chain ID the MultiKeySigner currently signs under.
+func (s *KeySnapshot) ResolvedChainID() (*big.Int, error) {
+	return s.signer.ResolvedChainID()
+}
+
+// Sign signs a 32-byte hash with the pinned key.
+func (s *KeySnapshot) Sign(hash []byte) ([]byte, error) {
+	signature, err := s.client.Sign(hash)
+	s.signer.recordUsage(s.keyID, err)
+	return signature, err
+}
+
+// SignTransaction signs an Ethereum transaction with the pinned key.
+func (s *KeySnapshot) SignTransaction(tx *ethgo.Transaction) (*ethgo.Transaction, error) {
+	tx, err := s.signer.resolveChainID(tx)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := s.client.SignTransaction(tx)
+	s.signer.recordUsage(s.keyID, err)
+	return signedTx, err
+}
+
+// SupportsSummary reports whether the pinned key accepts approval summaries.
+func (s *KeySnapshot) SupportsSummary() bool {
+	_, ok := s.client.(*MPCKMSSigner)
+	return ok
+}
+
+// SignTransactionWithSummary signs an Ethereum transaction with the pinned key and an approval summary.
+//
+// Returns:
+//   - *ethgo.Transaction: A new transaction with signature applied
+//   - error: An error if the pinned key is not an MPCKMSSigner or signing fails
+func (s *KeySnapshot) SignTransactionWithSummary(tx *ethgo.Transaction, summary *kms.SignSummary) (*ethgo.Transaction, error) {
+	mpcSigner, ok := s.client.(*MPCKMSSigner)
+	if !ok {
+		return nil, fmt.Errorf("client for keyID %s does not support SignTransactionWithSummary", s.keyID)
+	}
+	tx, err := s.signer.resolveChainID(tx)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := mpcSigner.SignTransactionWithSummary(tx, summary)
+	s.signer.recordUsage(s.keyID, err)
+	return signedTx, err
+}
+
+// GetClient returns the client registered for keyID on the MultiKeySigner.
+func (s *KeySnapshot) GetClient(keyID string) (Client, error) {
+	return s.signer.GetClient(keyID)
+}
+
+// SignWithKeyID signs a hash with an explicitly selected key of the MultiKeySigner.
+func (s *KeySnapshot) SignWithKeyID(hash []byte, keyID string) ([]byte, error) {
+	return s.signer.SignWithKeyID(hash, keyID)
+}
+
 // Address returns the default key's Ethereum address.
 //
 // This implements the ethgo.Key interface.
@@ -132,7 +306,7 @@ func (m *MultiKeySigner) GetClient(keyID string) (Client, error) {
 // Returns:
 //   - ethgo.Address: The address of the default key
 func (m *MultiKeySigner) Address() ethgo.Address {
-	client, err := m.GetClient(m.defaultKeyID)
+	_, client, err := m.defaultClient()
 	if err != nil {
 		m.logger.WithError(err).Error("Failed to get default client for Address")
 		return ethgo.Address{}
@@ -140,6 +314,79 @@ func (m *MultiKeySigner) Address() ethgo.Address {
 	return client.Address()
 }
 
+// ChainID returns the chain ID the signer was configured with.
+//
+// Returns:
+//   - *big.Int: The configured chain ID, or nil if none was provided
+func (m *MultiKeySigner) ChainID() *big.Int {
+	return m.chainID
+}
+
+// ResolvedChainID returns the chain ID transactions are currently signed under.
+//
+// With a chain ID resolver this is the chain ID chosen by its policy, which
+// may differ from the configured ChainID; without one it is ChainID.
+//
+// Returns:
+//   - *big.Int: The resolved chain ID, or nil if none was configured
+//   - error: An error if the resolver cannot determine the chain ID
+func (m *MultiKeySigner) ResolvedChainID() (*big.Int, error) {
+	m.mu.RLock()
+	resolver := m.chainIDs
+	m.mu.RUnlock()
+
+	if resolver == nil {
+		return m.chainID, nil
+	}
+	chainID, err := resolver.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve chain ID: %w", err)
+	}
+	return chainID, nil
+}
+
+// SetChainIDResolver makes transaction signing consult resolver for the chain ID.
+//
+// Transactions that already carry a chainId are signed unchanged; the others
+// are signed under the chain ID returned by the resolver. Pass nil to sign
+// with each key's configured chain ID.
+//
+// Parameters:
+//   - resolver: The chain ID resolver, or nil to disable resolution
+func (m *MultiKeySigner) SetChainIDResolver(resolver *ChainIDResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chainIDs = resolver
+}
+
+// resolveChainID 在配置了解析器且交易未指定 chainId 时返回带解析链 ID 的交易副本
+//
+// 交易显式指定 chainId 时，config 策略下原样使用；fail 与 downstream 策略下
+// 须与解析出的链 ID 一致，否则拒绝签名
+func (m *MultiKeySigner) resolveChainID(tx *ethgo.Transaction) (*ethgo.Transaction, error) {
+	m.mu.RLock()
+	resolver := m.chainIDs
+	m.mu.RUnlock()
+
+	explicit := tx.ChainID != nil && tx.ChainID.Sign() > 0
+	if resolver == nil || (explicit && resolver.policy == ChainIDPolicyConfig) {
+		return tx, nil
+	}
+	chainID, err := resolver.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve chain ID: %w", err)
+	}
+	if explicit {
+		if tx.ChainID.Cmp(chainID) != 0 {
+			return nil, fmt.Errorf("transaction chain ID %s does not match resolved chain ID %s", tx.ChainID, chainID)
+		}
+		return tx, nil
+	}
+	resolved := tx.Copy()
+	resolved.ChainID = chainID
+	return resolved, nil
+}
+
 // Sign signs a 32-byte hash using the default key.
 //
 // This implements the ethgo.Key interface.
@@ -151,11 +398,32 @@ func (m *MultiKeySigner) Address() ethgo.Address {
 //   - []byte: The signature bytes
 //   - error: An error if signing fails
 func (m *MultiKeySigner) Sign(hash []byte) ([]byte, error) {
-	client, err := m.GetClient(m.defaultKeyID)
+	keyID, client, err := m.defaultClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get default client: %w", err)
 	}
-	return client.Sign(hash)
+	signature, err := client.Sign(hash)
+	m.recordUsage(keyID, err)
+	return signature, err
+}
+
+// SignWithKeyID signs a hash using a specific key ID.
+//
+// Parameters:
+//   - hash: 32-byte hash to sign (typically Keccak-256)
+//   - keyID: The specific key ID to use for signing
+//
+// Returns:
+//   - []byte: The signature bytes
+//   - error: An error if the keyID is not found or signing fails
+func (m *MultiKeySigner) SignWithKeyID(hash []byte, keyID string) ([]byte, error) {
+	client, err := m.GetClient(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for keyID %s: %w", keyID, err)
+	}
+	signature, err := client.Sign(hash)
+	m.recordUsage(keyID, err)
+	return signature, err
 }
 
 // SignTransaction signs an Ethereum transaction using the default key.
@@ -169,11 +437,16 @@ func (m *MultiKeySigner) Sign(hash []byte) ([]byte, error) {
 //   - *ethgo.Transaction: A new transaction with signature applied
 //   - error: An error if signing fails
 func (m *MultiKeySigner) SignTransaction(tx *ethgo.Transaction) (*ethgo.Transaction, error) {
-	client, err := m.GetClient(m.defaultKeyID)
+	keyID, client, err := m.defaultClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get default client: %w", err)
 	}
-	return client.SignTransaction(tx)
+	if tx, err = m.resolveChainID(tx); err != nil {
+		return nil, err
+	}
+	signedTx, err := client.SignTransaction(tx)
+	m.recordUsage(keyID, err)
+	return signedTx, err
 }
 
 // SignTransactionWithKeyID signs an Ethereum transaction using a specific key ID.
@@ -193,7 +466,12 @@ func (m *MultiKeySigner) SignTransactionWithKeyID(tx *ethgo.Transaction, keyID s
 	if err != nil {
 		return nil, fmt.Errorf("failed to get client for keyID %s: %w", keyID, err)
 	}
-	return client.SignTransaction(tx)
+	if tx, err = m.resolveChainID(tx); err != nil {
+		return nil, err
+	}
+	signedTx, err := client.SignTransaction(tx)
+	m.recordUsage(keyID, err)
+	return signedTx, err
 }
 
 // SignTransactionWithSummary signs an Ethereum transaction using a specific key ID with approval summary.
@@ -219,8 +497,13 @@ func (m *MultiKeySigner) SignTransactionWithSummary(tx *ethgo.Transaction, keyID
 	if !ok {
 		return nil, fmt.Errorf("client for keyID %s does not support SignTransactionWithSummary", keyID)
 	}
+	if tx, err = m.resolveChainID(tx); err != nil {
+		return nil, err
+	}
 
-	return mpcSigner.SignTransactionWithSummary(tx, summary)
+	signedTx, err := mpcSigner.SignTransactionWithSummary(tx, summary)
+	m.recordUsage(keyID, err)
+	return signedTx, err
 }
 
 // CreateTransferSummary creates a transfer summary from transaction details for a specific key.
@@ -251,5 +534,56 @@ func (m *MultiKeySigner) CreateTransferSummary(tx *ethgo.Transaction, keyID stri
 	return mpcSigner.CreateTransferSummary(tx, token, remark), nil
 }
 
+// KeyStats returns a snapshot of usage statistics for every registered key.
+//
+// Keys that have never been used are included with zero values,
+// which makes unused keys easy to spot.
+//
+// Returns:
+//   - map[string]KeyStats: Usage statistics keyed by key ID
+func (m *MultiKeySigner) KeyStats() map[string]KeyStats {
+	m.mu.RLock()
+	keyIDs := make([]string, 0, len(m.clients))
+	for keyID := range m.clients {
+		keyIDs = append(keyIDs, keyID)
+	}
+	m.mu.RUnlock()
+
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+
+	snapshot := make(map[string]KeyStats, len(keyIDs))
+	for _, keyID := range keyIDs {
+		if stats, ok := m.stats[keyID]; ok {
+			snapshot[keyID] = *stats
+		} else {
+			snapshot[keyID] = KeyStats{}
+		}
+	}
+	return snapshot
+}
+
+// recordUsage 记录一次签名尝试
+func (m *MultiKeySigner) recordUsage(keyID string, err error) {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+
+	stats, ok := m.stats[keyID]
+	if !ok {
+		stats = &KeyStats{}
+		m.stats[keyID] = stats
+	}
+
+	stats.SignCount++
+	stats.LastUsed = time.Now()
+	if err != nil {
+		stats.ErrorCount++
+		stats.LastError = err.Error()
+	}
+}
+
 // VerifyInterface verifies that MultiKeySigner implements the required interfaces.
-var _ ethgo.Key = (*MultiKeySigner)(nil)
+var (
+	_ ethgo.Key = (*MultiKeySigner)(nil)
+	_ Client    = (*KeySnapshot)(nil)
+)
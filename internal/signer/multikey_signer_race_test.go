@@ -96,3 +96,39 @@ func TestMultiKeySigner_ConcurrentAddRemove(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestMultiKeySigner_ConcurrentSetDefaultKeyID switches the default key while
+// other goroutines sign; each sign must be recorded against a registered key.
+func TestMultiKeySigner_ConcurrentSetDefaultKeyID(t *testing.T) {
+	signer := NewMultiKeySigner("key-0", big.NewInt(1), logrus.New())
+	for i := 0; i < 2; i++ {
+		keyID := fmt.Sprintf("key-%d", i)
+		if err := signer.AddClient(keyID, &mockClient{address: ethgo.HexToAddress(fmt.Sprintf("0x%040x", i+1))}); err != nil {
+			t.Fatalf("Failed to add client %s: %v", keyID, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(id int) {
+			defer wg.Done()
+			if err := signer.SetDefaultKeyID(fmt.Sprintf("key-%d", id%2)); err != nil {
+				t.Errorf("SetDefaultKeyID() error = %v", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := signer.Sign(make([]byte, 32)); err != nil {
+				t.Errorf("Sign() error = %v", err)
+			}
+			_ = signer.Address()
+		}()
+	}
+	wg.Wait()
+
+	stats := signer.KeyStats()
+	if total := stats["key-0"].SignCount + stats["key-1"].SignCount; total != 10 {
+		t.Errorf("recorded %d signs, want 10", total)
+	}
+}
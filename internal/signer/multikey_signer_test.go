@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/mowind/web3signer-go/internal/kms"
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/umbracle/ethgo"
 )
 
@@ -31,6 +34,7 @@ func (m *mockClient) Sign(hash []byte) ([]byte, error) {
 	for i := 0; i < 65; i++ {
 		signature[i] = byte(i + 1)
 	}
+	signature[64] = 1 // 恢复 ID 须为 0 或 1
 	return signature, nil
 }
 
@@ -305,6 +309,52 @@ func TestMultiKeySigner_Address(t *testing.T) {
 	}
 }
 
+func TestMultiKeySigner_SetDefaultKeyID(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	signer := NewMultiKeySigner("old-key", big.NewInt(1), logger)
+	oldAddress := ethgo.HexToAddress("0x1111111111111111111111111111111111111111")
+	newAddress := ethgo.HexToAddress("0x2222222222222222222222222222222222222222")
+	if err := signer.AddClient("old-key", &mockClient{address: oldAddress}); err != nil {
+		t.Fatalf("Failed to add client: %v", err)
+	}
+	if err := signer.AddClient("new-key", &mockClient{address: newAddress}); err != nil {
+		t.Fatalf("Failed to add client: %v", err)
+	}
+
+	if err := signer.SetDefaultKeyID("missing-key"); err == nil {
+		t.Fatal("Expected error for unknown key ID")
+	}
+	if signer.DefaultKeyID() != "old-key" {
+		t.Errorf("DefaultKeyID() = %s after failed switch, want old-key", signer.DefaultKeyID())
+	}
+
+	if err := signer.SetDefaultKeyID("new-key"); err != nil {
+		t.Fatalf("SetDefaultKeyID() error = %v", err)
+	}
+	if signer.DefaultKeyID() != "new-key" || signer.Address() != newAddress {
+		t.Errorf("default = %s (%s), want new-key (%s)", signer.DefaultKeyID(), signer.Address(), newAddress)
+	}
+	if _, err := signer.Sign(make([]byte, 32)); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if stats := signer.KeyStats(); stats["new-key"].SignCount != 1 || stats["old-key"].SignCount != 0 {
+		t.Errorf("KeyStats() = %+v, want the sign recorded for new-key", stats)
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Data["event"] != "default_key_rotated" || entry.Data["previous_key_id"] != "old-key" || entry.Data["key_id"] != "new-key" {
+		t.Errorf("audit log entry = %+v, want default_key_rotated from old-key to new-key", entry)
+	}
+
+	// 原默认密钥不再受保护，可以移除；新的默认密钥不能移除
+	if err := signer.RemoveClient("old-key"); err != nil {
+		t.Errorf("RemoveClient(old-key) error = %v", err)
+	}
+	if err := signer.RemoveClient("new-key"); err == nil {
+		t.Error("Expected error removing the new default key")
+	}
+}
+
 func TestMultiKeySigner_Sign(t *testing.T) {
 	defaultKeyID := "default-key"
 	expectedAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
@@ -345,6 +395,43 @@ func TestMultiKeySigner_Sign(t *testing.T) {
 	}
 }
 
+func TestMultiKeySigner_SignWithKeyID(t *testing.T) {
+	logger := logrus.New()
+	signer := NewMultiKeySigner("default-key", big.NewInt(1), logger)
+
+	if err := signer.AddClient("default-key", &mockClient{
+		address: ethgo.HexToAddress("0x1234567890123456789012345678901234567890"),
+		signFunc: func(hash []byte) ([]byte, error) {
+			return []byte{0}, nil
+		},
+	}); err != nil {
+		t.Fatalf("Failed to add default client: %v", err)
+	}
+	if err := signer.AddClient("key-1", &mockClient{
+		address: ethgo.HexToAddress("0x1111111111111111111111111111111111111111"),
+		signFunc: func(hash []byte) ([]byte, error) {
+			return []byte{1}, nil
+		},
+	}); err != nil {
+		t.Fatalf("Failed to add client: %v", err)
+	}
+
+	signature, err := signer.SignWithKeyID(make([]byte, 32), "key-1")
+	if err != nil {
+		t.Fatalf("Failed to sign with keyID: %v", err)
+	}
+	if !bytes.Equal(signature, []byte{1}) {
+		t.Errorf("Expected signature from key-1, got %x", signature)
+	}
+	if stats := signer.KeyStats()["key-1"]; stats.SignCount != 1 {
+		t.Errorf("Expected key-1 usage to be recorded, got %+v", stats)
+	}
+
+	if _, err := signer.SignWithKeyID(make([]byte, 32), "unknown"); err == nil {
+		t.Error("Expected error for unknown keyID")
+	}
+}
+
 func TestMultiKeySigner_SignTransaction(t *testing.T) {
 	defaultKeyID := "default-key"
 	expectedAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
@@ -527,6 +614,7 @@ func TestMultiKeySigner_SignTransactionWithSummary(t *testing.T) {
 			for i := 0; i < 65; i++ {
 				signature[i] = byte(i + 1)
 			}
+			signature[64] = 1 // 恢复 ID 须为 0 或 1
 			return []byte(hex.EncodeToString(signature)), nil
 		},
 	}
@@ -676,3 +764,105 @@ func TestMultiKeySigner_MultipleKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestMultiKeySigner_KeyStats(t *testing.T) {
+	signer := NewMultiKeySigner("default-key", big.NewInt(1), logrus.New())
+
+	failing := &mockClient{
+		address: ethgo.HexToAddress("0x2222222222222222222222222222222222222222"),
+		signTxFunc: func(tx *ethgo.Transaction) (*ethgo.Transaction, error) {
+			return nil, errors.New("kms unavailable")
+		},
+	}
+	if err := signer.AddClient("default-key", &mockClient{address: ethgo.HexToAddress("0x1111111111111111111111111111111111111111")}); err != nil {
+		t.Fatalf("Failed to add default client: %v", err)
+	}
+	if err := signer.AddClient("failing-key", failing); err != nil {
+		t.Fatalf("Failed to add failing client: %v", err)
+	}
+	if err := signer.AddClient("unused-key", &mockClient{}); err != nil {
+		t.Fatalf("Failed to add unused client: %v", err)
+	}
+
+	before := time.Now()
+	if _, err := signer.Sign(make([]byte, 32)); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if _, err := signer.SignTransaction(&ethgo.Transaction{}); err != nil {
+		t.Fatalf("SignTransaction failed: %v", err)
+	}
+	if _, err := signer.SignTransactionWithKeyID(&ethgo.Transaction{}, "failing-key"); err == nil {
+		t.Fatal("Expected error from failing key")
+	}
+
+	stats := signer.KeyStats()
+	if len(stats) != 3 {
+		t.Fatalf("Expected stats for 3 keys, got %d", len(stats))
+	}
+
+	def := stats["default-key"]
+	if def.SignCount != 2 || def.ErrorCount != 0 || def.LastError != "" {
+		t.Errorf("Unexpected default-key stats: %+v", def)
+	}
+	if def.LastUsed.Before(before) {
+		t.Errorf("Expected LastUsed to be updated, got %v", def.LastUsed)
+	}
+
+	failed := stats["failing-key"]
+	if failed.SignCount != 1 || failed.ErrorCount != 1 || failed.LastError != "kms unavailable" {
+		t.Errorf("Unexpected failing-key stats: %+v", failed)
+	}
+
+	if unused := stats["unused-key"]; unused.SignCount != 0 || !unused.LastUsed.IsZero() {
+		t.Errorf("Expected zero stats for unused key, got %+v", unused)
+	}
+
+	if err := signer.RemoveClient("failing-key"); err != nil {
+		t.Fatalf("RemoveClient failed: %v", err)
+	}
+	if _, ok := signer.KeyStats()["failing-key"]; ok {
+		t.Error("Expected stats to be dropped for removed key")
+	}
+}
+
+func TestMultiKeySigner_Snapshot(t *testing.T) {
+	signer := NewMultiKeySigner("old-key", big.NewInt(1), logrus.New())
+	oldAddress := ethgo.HexToAddress("0x1111111111111111111111111111111111111111")
+	if err := signer.AddClient("old-key", &mockClient{address: oldAddress}); err != nil {
+		t.Fatalf("Failed to add client: %v", err)
+	}
+	if err := signer.AddClient("new-key", &mockClient{address: ethgo.HexToAddress("0x2222222222222222222222222222222222222222")}); err != nil {
+		t.Fatalf("Failed to add client: %v", err)
+	}
+
+	snapshot, err := signer.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error: %v", err)
+	}
+	if err := signer.SetDefaultKeyID("new-key"); err != nil {
+		t.Fatalf("SetDefaultKeyID() error: %v", err)
+	}
+
+	// 快照保持创建时的默认密钥
+	if snapshot.DefaultKeyID() != "old-key" || snapshot.Address() != oldAddress {
+		t.Errorf("snapshot = %s/%s, want old-key/%s", snapshot.DefaultKeyID(), snapshot.Address(), oldAddress)
+	}
+	if _, err := snapshot.Sign(make([]byte, 32)); err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	if _, err := snapshot.SignTransaction(&ethgo.Transaction{}); err != nil {
+		t.Fatalf("SignTransaction() error: %v", err)
+	}
+	stats := signer.KeyStats()
+	if stats["old-key"].SignCount != 2 || stats["new-key"].SignCount != 0 {
+		t.Errorf("Expected both signatures on old-key, got %+v", stats)
+	}
+	if snapshot.SupportsSummary() {
+		t.Error("Expected mock client not to support summaries")
+	}
+
+	missing := NewMultiKeySigner("missing-key", big.NewInt(1), logrus.New())
+	if _, err := missing.Snapshot(); err == nil {
+		t.Error("Expected Snapshot() to fail without a registered default key")
+	}
+}
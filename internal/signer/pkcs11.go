@@ -0,0 +1,166 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// secp256k1HalfN 是 secp256k1 曲线阶的一半，用于将 s 规范化为 low-S
+var secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+
+// PKCS11Session is the subset of an open PKCS#11 session used for signing.
+//
+// Implementations wrap a PKCS#11 library (for example github.com/miekg/pkcs11)
+// that has already been initialized and logged in to the token. Keys are
+// located by their CKA_LABEL.
+type PKCS11Session interface {
+	// PublicKey returns the public key of the secp256k1 key pair with the given label.
+	//
+	// Parameters:
+	//   - label: The CKA_LABEL of the key pair
+	//
+	// Returns:
+	//   - []byte: The 65-byte uncompressed SEC1 point (0x04 || X || Y)
+	//   - error: An error if the key cannot be found
+	PublicKey(label string) ([]byte, error)
+
+	// SignECDSA signs a 32-byte hash with the private key with the given label (CKM_ECDSA).
+	//
+	// Parameters:
+	//   - label: The CKA_LABEL of the private key
+	//   - hash: 32-byte hash to sign
+	//
+	// Returns:
+	//   - []byte: The 64-byte raw signature r || s
+	//   - error: An error if signing fails
+	SignECDSA(label string, hash []byte) ([]byte, error)
+}
+
+// PKCS11Signer implements Client using a secp256k1 key held in a PKCS#11 HSM.
+//
+// The HSM returns raw ECDSA signatures without a recovery id, so the signer
+// normalizes s to the lower half of the curve order and determines the
+// recovery id by trial recovery against the key's address.
+type PKCS11Signer struct {
+	session PKCS11Session
+	label   string
+	address ethgo.Address
+	chainID *big.Int
+}
+
+// NewPKCS11Signer creates a signer for the HSM key with the given label.
+//
+// Parameters:
+//   - session: An open, logged-in PKCS#11 session
+//   - label: The CKA_LABEL of the key pair to sign with
+//   - chainID: The chain ID for transaction signing
+//
+// Returns:
+//   - *PKCS11Signer: A new signer instance
+//   - error: An error if the key cannot be loaded
+func NewPKCS11Signer(session PKCS11Session, label string, chainID *big.Int) (*PKCS11Signer, error) {
+	pub, err := session.PublicKey(label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 key %q: %w", label, err)
+	}
+	address, err := addressFromPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key for PKCS#11 key %q: %w", label, err)
+	}
+
+	return &PKCS11Signer{
+		session: session,
+		label:   label,
+		address: address,
+		chainID: chainID,
+	}, nil
+}
+
+// Address returns the address derived from the HSM key's public key.
+//
+// Returns:
+//   - ethgo.Address: The address associated with this signer
+func (s *PKCS11Signer) Address() ethgo.Address {
+	return s.address
+}
+
+// ChainID returns the chain ID the signer was configured with.
+//
+// Returns:
+//   - *big.Int: The configured chain ID, or nil if none was provided
+func (s *PKCS11Signer) ChainID() *big.Int {
+	return s.chainID
+}
+
+// Sign signs a 32-byte hash with the HSM key.
+//
+// Parameters:
+//   - hash: 32-byte hash to sign (typically Keccak-256)
+//
+// Returns:
+//   - []byte: 65-byte signature (r, s, v values)
+//   - error: An error if hash is invalid or signing fails
+func (s *PKCS11Signer) Sign(hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("invalid hash length: expected 32 bytes, got %d", len(hash))
+	}
+
+	raw, err := s.session.SignECDSA(s.label, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with PKCS#11: %w", err)
+	}
+	if len(raw) != 64 {
+		return nil, fmt.Errorf("invalid PKCS#11 signature length: expected 64, got %d", len(raw))
+	}
+
+	signature := make([]byte, 65)
+	copy(signature, raw[:32])
+
+	// HSM 不保证 low-S，以太坊要求 s <= N/2
+	sv := new(big.Int).SetBytes(raw[32:])
+	if sv.Cmp(secp256k1HalfN) > 0 {
+		sv.Sub(secp256k1N, sv)
+	}
+	sv.FillBytes(signature[32:64])
+
+	// 依次尝试恢复 ID，恢复出的地址与密钥地址一致即为正确的 v
+	for v := byte(0); v <= 1; v++ {
+		signature[64] = v
+		recovered, err := wallet.Ecrecover(hash, signature)
+		if err == nil && recovered == s.address {
+			return signature, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to determine recovery id for PKCS#11 signature")
+}
+
+// SignTransaction signs an Ethereum transaction with the HSM key.
+//
+// Parameters:
+//   - tx: The transaction to sign
+//
+// Returns:
+//   - *ethgo.Transaction: A new transaction with signature applied
+//   - error: An error if signing fails
+func (s *PKCS11Signer) SignTransaction(tx *ethgo.Transaction) (*ethgo.Transaction, error) {
+	return assembleSignedTransaction(copyTransactionForSigning(tx, s.address), s.chainID, s.Sign)
+}
+
+// addressFromPublicKey 从 65 字节未压缩公钥计算以太坊地址
+func addressFromPublicKey(pub []byte) (ethgo.Address, error) {
+	if len(pub) != 65 || pub[0] != 0x04 {
+		return ethgo.Address{}, fmt.Errorf("expected 65-byte uncompressed public key, got %d bytes", len(pub))
+	}
+	var address ethgo.Address
+	copy(address[:], ethgo.Keccak256(pub[1:])[12:])
+	return address, nil
+}
+
+// VerifyInterface 验证接口实现
+var (
+	_ Client    = (*PKCS11Signer)(nil)
+	_ ethgo.Key = (*PKCS11Signer)(nil)
+)
@@ -0,0 +1,200 @@
+//go:build pkcs11
+
+package signer
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+)
+
+// PKCS11Module is a PKCS11Session backed by a PKCS#11 library loaded with cgo.
+//
+// It holds one logged-in session on the selected token. PKCS#11 sessions must
+// not be used concurrently, so calls are serialized.
+type PKCS11Module struct {
+	mu      sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+}
+
+// OpenPKCS11Session loads a PKCS#11 library and opens a logged-in session.
+//
+// Parameters:
+//   - module: Path to the PKCS#11 library
+//   - tokenLabel: Label of the token holding the key; empty selects the first token
+//   - pin: User PIN; empty skips login
+//
+// Returns:
+//   - *PKCS11Module: An open session, released with Close
+//   - error: An error if the library, token or session cannot be opened
+func OpenPKCS11Session(module, tokenLabel, pin string) (*PKCS11Module, error) {
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 library %q", module)
+	}
+	if err := ctx.Initialize(); err != nil && !isPKCS11Error(err, pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize PKCS#11 library: %w", err)
+	}
+
+	m := &PKCS11Module{ctx: ctx}
+	if err := m.open(tokenLabel, pin); err != nil {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+	return m, nil
+}
+
+// open 在指定 token 上打开会话并登录
+func (m *PKCS11Module) open(tokenLabel, pin string) error {
+	slots, err := m.ctx.GetSlotList(true)
+	if err != nil {
+		return fmt.Errorf("failed to list PKCS#11 slots: %w", err)
+	}
+
+	var slot uint
+	found := false
+	for _, s := range slots {
+		info, err := m.ctx.GetTokenInfo(s)
+		if err != nil {
+			continue
+		}
+		if tokenLabel == "" || strings.TrimSpace(info.Label) == tokenLabel {
+			slot, found = s, true
+			break
+		}
+	}
+	if !found {
+		if tokenLabel == "" {
+			return fmt.Errorf("no PKCS#11 token present")
+		}
+		return fmt.Errorf("PKCS#11 token %q not found", tokenLabel)
+	}
+
+	session, err := m.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("failed to open PKCS#11 session: %w", err)
+	}
+	if pin != "" {
+		if err := m.ctx.Login(session, pkcs11.CKU_USER, pin); err != nil && !isPKCS11Error(err, pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+			_ = m.ctx.CloseSession(session)
+			return fmt.Errorf("failed to log in to PKCS#11 token: %w", err)
+		}
+	}
+	m.session = session
+	return nil
+}
+
+// PublicKey returns the uncompressed public key of the key pair with the given label.
+//
+// Parameters:
+//   - label: The CKA_LABEL of the public key object
+//
+// Returns:
+//   - []byte: The 65-byte uncompressed SEC1 point (0x04 || X || Y)
+//   - error: An error if the key cannot be found or read
+func (m *PKCS11Module) PublicKey(label string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	handle, err := m.findObject(pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := m.ctx.GetAttributeValue(m.session, handle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CKA_EC_POINT: %w", err)
+	}
+	if len(attrs) == 0 {
+		return nil, fmt.Errorf("public key %q has no CKA_EC_POINT", label)
+	}
+
+	// CKA_EC_POINT 按规范为 DER 编码的 OCTET STRING，部分 HSM 直接返回原始点
+	point := attrs[0].Value
+	var unwrapped []byte
+	if rest, err := asn1.Unmarshal(point, &unwrapped); err == nil && len(rest) == 0 {
+		point = unwrapped
+	}
+	return point, nil
+}
+
+// SignECDSA signs a 32-byte hash with the private key with the given label (CKM_ECDSA).
+//
+// Parameters:
+//   - label: The CKA_LABEL of the private key object
+//   - hash: 32-byte hash to sign
+//
+// Returns:
+//   - []byte: The 64-byte raw signature r || s
+//   - error: An error if the key cannot be found or signing fails
+func (m *PKCS11Module) SignECDSA(label string, hash []byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	handle, err := m.findObject(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ctx.SignInit(m.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, handle); err != nil {
+		return nil, fmt.Errorf("failed to initialize PKCS#11 signing: %w", err)
+	}
+	return m.ctx.Sign(m.session, hash)
+}
+
+// Close logs out and closes the session and unloads the library.
+//
+// Returns:
+//   - error: An error if the session cannot be closed
+func (m *PKCS11Module) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_ = m.ctx.Logout(m.session)
+	err := m.ctx.CloseSession(m.session)
+	_ = m.ctx.Finalize()
+	m.ctx.Destroy()
+	if err != nil {
+		return fmt.Errorf("failed to close PKCS#11 session: %w", err)
+	}
+	return nil
+}
+
+// findObject 按类别和 CKA_LABEL 查找唯一对象，调用方须持有锁
+func (m *PKCS11Module) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := m.ctx.FindObjectsInit(m.session, template); err != nil {
+		return 0, fmt.Errorf("failed to search PKCS#11 objects: %w", err)
+	}
+	handles, _, err := m.ctx.FindObjects(m.session, 2)
+	if finalErr := m.ctx.FindObjectsFinal(m.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to search PKCS#11 objects: %w", err)
+	}
+
+	switch len(handles) {
+	case 0:
+		return 0, fmt.Errorf("no PKCS#11 key with label %q", label)
+	case 1:
+		return handles[0], nil
+	default:
+		return 0, fmt.Errorf("multiple PKCS#11 keys with label %q", label)
+	}
+}
+
+// isPKCS11Error 判断 err 是否为指定的 PKCS#11 返回码
+func isPKCS11Error(err error, code uint) bool {
+	e, ok := err.(pkcs11.Error)
+	return ok && uint(e) == code
+}
@@ -0,0 +1,60 @@
+//go:build pkcs11
+
+package signer
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// TestPKCS11Module_Sign 需要真实的 PKCS#11 库（如 SoftHSM）与 secp256k1 密钥：
+// WEB3SIGNER_TEST_PKCS11_MODULE、WEB3SIGNER_TEST_PKCS11_TOKEN、WEB3SIGNER_TEST_PKCS11_PIN、WEB3SIGNER_TEST_PKCS11_KEY
+func TestPKCS11Module_Sign(t *testing.T) {
+	module := os.Getenv("WEB3SIGNER_TEST_PKCS11_MODULE")
+	label := os.Getenv("WEB3SIGNER_TEST_PKCS11_KEY")
+	if module == "" || label == "" {
+		t.Skip("WEB3SIGNER_TEST_PKCS11_MODULE and WEB3SIGNER_TEST_PKCS11_KEY not set")
+	}
+
+	session, err := OpenPKCS11Session(module, os.Getenv("WEB3SIGNER_TEST_PKCS11_TOKEN"), os.Getenv("WEB3SIGNER_TEST_PKCS11_PIN"))
+	if err != nil {
+		t.Fatalf("OpenPKCS11Session() error: %v", err)
+	}
+	defer func() {
+		if err := session.Close(); err != nil {
+			t.Errorf("Close() error: %v", err)
+		}
+	}()
+
+	hsmSigner, err := NewPKCS11Signer(session, label, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("NewPKCS11Signer() error: %v", err)
+	}
+
+	hash := ethgo.Keccak256([]byte("hsm"))
+	signature, err := hsmSigner.Sign(hash)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	recovered, err := wallet.Ecrecover(hash, signature)
+	if err != nil {
+		t.Fatalf("Ecrecover() error: %v", err)
+	}
+	if recovered != hsmSigner.Address() {
+		t.Errorf("recovered address = %s, want %s", recovered, hsmSigner.Address())
+	}
+
+	if _, err := NewPKCS11Signer(session, label+"-missing", big.NewInt(1)); err == nil {
+		t.Error("Expected error for unknown key label")
+	}
+}
+
+func TestOpenPKCS11Session_MissingModule(t *testing.T) {
+	if _, err := OpenPKCS11Session("/nonexistent/libpkcs11.so", "", ""); err == nil {
+		t.Error("Expected error for missing PKCS#11 library")
+	}
+}
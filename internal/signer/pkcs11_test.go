@@ -0,0 +1,161 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// fakePKCS11Session 使用内存密钥模拟 HSM，返回不含恢复 ID 的 r || s 签名
+type fakePKCS11Session struct {
+	key   *wallet.Key
+	pub   []byte
+	highS bool
+}
+
+func newFakePKCS11Session(t *testing.T) *fakePKCS11Session {
+	t.Helper()
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+
+	hash := ethgo.Keccak256([]byte("public key"))
+	signature, err := key.Sign(hash)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	pubKey, err := wallet.RecoverPubkey(signature, hash)
+	if err != nil {
+		t.Fatalf("RecoverPubkey() error: %v", err)
+	}
+	pub := make([]byte, 65)
+	pub[0] = 0x04
+	pubKey.X.FillBytes(pub[1:33])
+	pubKey.Y.FillBytes(pub[33:])
+
+	return &fakePKCS11Session{key: key, pub: pub}
+}
+
+func (f *fakePKCS11Session) PublicKey(label string) ([]byte, error) {
+	if label != "eth-key" {
+		return nil, errors.New("key not found")
+	}
+	return f.pub, nil
+}
+
+func (f *fakePKCS11Session) SignECDSA(label string, hash []byte) ([]byte, error) {
+	signature, err := f.key.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+	raw := signature[:64]
+	if f.highS {
+		// 模拟 HSM 返回 high-S 签名
+		s := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(raw[32:]))
+		s.FillBytes(raw[32:])
+	}
+	return raw, nil
+}
+
+func TestPKCS11Signer_Sign(t *testing.T) {
+	for _, highS := range []bool{false, true} {
+		session := newFakePKCS11Session(t)
+		session.highS = highS
+
+		s, err := NewPKCS11Signer(session, "eth-key", big.NewInt(1))
+		if err != nil {
+			t.Fatalf("NewPKCS11Signer() error: %v", err)
+		}
+		if s.Address() != session.key.Address() {
+			t.Fatalf("Address() = %s, want %s", s.Address(), session.key.Address())
+		}
+
+		hash := ethgo.Keccak256([]byte("message"))
+		signature, err := s.Sign(hash)
+		if err != nil {
+			t.Fatalf("Sign() error (highS=%v): %v", highS, err)
+		}
+		if err := validateSignatureValues(signature); err != nil {
+			t.Errorf("Sign() returned non-canonical signature (highS=%v): %v", highS, err)
+		}
+		if new(big.Int).SetBytes(signature[32:64]).Cmp(secp256k1HalfN) > 0 {
+			t.Errorf("Sign() returned high-S signature (highS=%v)", highS)
+		}
+		recovered, err := wallet.Ecrecover(hash, signature)
+		if err != nil || recovered != s.Address() {
+			t.Errorf("Ecrecover() = (%s, %v), want %s", recovered, err, s.Address())
+		}
+	}
+}
+
+func TestPKCS11Signer_SignTransaction(t *testing.T) {
+	session := newFakePKCS11Session(t)
+	chainID := big.NewInt(1)
+
+	hsmSigner, err := NewPKCS11Signer(session, "eth-key", chainID)
+	if err != nil {
+		t.Fatalf("NewPKCS11Signer() error: %v", err)
+	}
+
+	// 相同密钥下 MPC-KMS 签名器应组装出相同的已签名交易
+	kmsSigner := NewMPCKMSSigner(&mockKMSClient{
+		signFunc: func(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+			signature, err := session.key.Sign(message)
+			if err != nil {
+				return nil, err
+			}
+			return []byte(hex.EncodeToString(signature)), nil
+		},
+	}, "kms-key", session.key.Address(), chainID)
+
+	to := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+	for _, txType := range []ethgo.TransactionType{ethgo.TransactionLegacy, ethgo.TransactionDynamicFee} {
+		tx := &ethgo.Transaction{
+			Type:                 txType,
+			To:                   &to,
+			Value:                big.NewInt(1000),
+			Gas:                  21000,
+			GasPrice:             20000000000,
+			MaxFeePerGas:         big.NewInt(30000000000),
+			MaxPriorityFeePerGas: big.NewInt(1000000000),
+			ChainID:              chainID,
+		}
+
+		got, err := hsmSigner.SignTransaction(tx)
+		if err != nil {
+			t.Fatalf("SignTransaction() error: %v", err)
+		}
+		want, err := kmsSigner.SignTransaction(tx)
+		if err != nil {
+			t.Fatalf("MPCKMSSigner.SignTransaction() error: %v", err)
+		}
+
+		if got.From != session.key.Address() {
+			t.Errorf("From = %s, want %s", got.From, session.key.Address())
+		}
+		if !bytes.Equal(got.R, want.R) || !bytes.Equal(got.S, want.S) || !bytes.Equal(got.V, want.V) {
+			t.Errorf("signature mismatch for type %d: got (%x, %x, %x), want (%x, %x, %x)",
+				txType, got.R, got.S, got.V, want.R, want.S, want.V)
+		}
+	}
+}
+
+func TestNewPKCS11Signer_Errors(t *testing.T) {
+	session := newFakePKCS11Session(t)
+
+	if _, err := NewPKCS11Signer(session, "missing", big.NewInt(1)); err == nil {
+		t.Error("Expected error for unknown key label")
+	}
+
+	session.pub = session.pub[1:]
+	if _, err := NewPKCS11Signer(session, "eth-key", big.NewInt(1)); err == nil {
+		t.Error("Expected error for malformed public key")
+	}
+}
@@ -0,0 +1,127 @@
+package signer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// EncodeRawTransaction encodes a signed transaction as an EIP-2718 envelope.
+//
+// Legacy transactions are plain RLP lists. Typed transactions (EIP-2930 access
+// list, EIP-1559 dynamic fee) are the type byte followed by the RLP payload.
+//
+// Parameters:
+//   - tx: The signed transaction to encode
+//
+// Returns:
+//   - []byte: The raw transaction bytes, ready for eth_sendRawTransaction
+//   - error: An error if the type is unsupported or the envelope is malformed
+func EncodeRawTransaction(tx *ethgo.Transaction) ([]byte, error) {
+	switch tx.Type {
+	case ethgo.TransactionLegacy, ethgo.TransactionAccessList, ethgo.TransactionDynamicFee:
+	default:
+		return nil, fmt.Errorf("unsupported transaction type: %d", tx.Type)
+	}
+
+	raw, err := tx.MarshalRLPTo(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	// RLP 列表前缀从 0xc0 开始；类型化交易在其前面加一个类型字节（0x00-0x7f）
+	payload := raw
+	if tx.Type != ethgo.TransactionLegacy {
+		if len(raw) < 2 || raw[0] != byte(tx.Type) {
+			return nil, fmt.Errorf("typed transaction envelope missing type byte 0x%02x", byte(tx.Type))
+		}
+		payload = raw[1:]
+	}
+	if len(payload) == 0 || payload[0] < 0xc0 {
+		return nil, fmt.Errorf("transaction payload is not an RLP list")
+	}
+
+	return raw, nil
+}
+
+// EncodeRawTransactionHex encodes a signed transaction as a 0x-prefixed hex EIP-2718 envelope.
+//
+// Parameters:
+//   - tx: The signed transaction to encode
+//
+// Returns:
+//   - string: The 0x-prefixed raw transaction hex
+//   - error: An error if encoding fails
+func EncodeRawTransactionHex(tx *ethgo.Transaction) (string, error) {
+	raw, err := EncodeRawTransaction(tx)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(raw), nil
+}
+
+// DecodeRawTransaction decodes an EIP-2718 raw transaction and recovers its sender.
+//
+// Parameters:
+//   - raw: The raw transaction bytes, as accepted by eth_sendRawTransaction
+//
+// Returns:
+//   - *ethgo.Transaction: The decoded transaction with Hash and From populated
+//   - error: An error if the envelope is malformed or the signature is invalid
+func DecodeRawTransaction(raw []byte) (*ethgo.Transaction, error) {
+	tx := new(ethgo.Transaction)
+	if err := tx.UnmarshalRLP(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+
+	from, err := recoverSender(tx)
+	if err != nil {
+		return nil, err
+	}
+	tx.From = from
+	return tx, nil
+}
+
+// recoverSender 从交易签名恢复发送方地址
+func recoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	if len(tx.R) > 32 || len(tx.S) > 32 {
+		return ethgo.Address{}, fmt.Errorf("invalid signature values")
+	}
+
+	v := new(big.Int).SetBytes(tx.V)
+	chainID := tx.ChainID
+	if tx.Type == ethgo.TransactionLegacy {
+		// Legacy 交易: v = 27/28（EIP-155 之前）或 recoveryID + 35 + chainID * 2
+		chainID = nil
+		switch {
+		case v.Cmp(big.NewInt(35)) >= 0:
+			v.Sub(v, big.NewInt(35))
+			chainID = new(big.Int).Rsh(v, 1)
+			v.And(v, big.NewInt(1))
+		case v.Cmp(big.NewInt(27)) >= 0:
+			v.Sub(v, big.NewInt(27))
+		}
+	}
+	if v.Cmp(big.NewInt(1)) > 0 {
+		return ethgo.Address{}, fmt.Errorf("invalid signature recovery id")
+	}
+
+	hash, err := signHash(tx, chainID)
+	if err != nil {
+		return ethgo.Address{}, fmt.Errorf("failed to compute signing hash: %w", err)
+	}
+
+	signature := make([]byte, 65)
+	copy(signature[32-len(tx.R):32], tx.R)
+	copy(signature[64-len(tx.S):64], tx.S)
+	signature[64] = byte(v.Uint64())
+
+	from, err := wallet.Ecrecover(hash, signature)
+	if err != nil {
+		return ethgo.Address{}, fmt.Errorf("failed to recover sender: %w", err)
+	}
+	return from, nil
+}
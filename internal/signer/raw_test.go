@@ -0,0 +1,147 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+func TestEncodeRawTransaction_RoundTrip(t *testing.T) {
+	to := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+	accessList := ethgo.AccessList{{
+		Address: ethgo.HexToAddress("0x1111111111111111111111111111111111111111"),
+		Storage: []ethgo.Hash{ethgo.HexToHash("0x01")},
+	}}
+	sig := func(b byte) []byte { return bytes.Repeat([]byte{b}, 32) }
+
+	tests := []struct {
+		name      string
+		tx        *ethgo.Transaction
+		wantFirst byte
+	}{
+		{
+			name: "legacy",
+			tx: &ethgo.Transaction{
+				Type: ethgo.TransactionLegacy, Nonce: 5, GasPrice: 20000000000, Gas: 21000,
+				To: &to, Value: big.NewInt(1000), Input: []byte{0xde, 0xad},
+				V: []byte{0x25}, R: sig(0x11), S: sig(0x22),
+			},
+		},
+		{
+			name: "access list (EIP-2930)",
+			tx: &ethgo.Transaction{
+				Type: ethgo.TransactionAccessList, ChainID: big.NewInt(1), Nonce: 6, GasPrice: 20000000000, Gas: 30000,
+				To: &to, Value: big.NewInt(1), AccessList: accessList,
+				V: []byte{0x01}, R: sig(0x33), S: sig(0x44),
+			},
+			wantFirst: 0x01,
+		},
+		{
+			name: "dynamic fee (EIP-1559)",
+			tx: &ethgo.Transaction{
+				Type: ethgo.TransactionDynamicFee, ChainID: big.NewInt(1), Nonce: 7, Gas: 21000,
+				MaxPriorityFeePerGas: big.NewInt(2000000000), MaxFeePerGas: big.NewInt(50000000000),
+				To: &to, Value: big.NewInt(2), AccessList: accessList, Input: []byte{0x01},
+				V: []byte{0x01}, R: sig(0x55), S: sig(0x66),
+			},
+			wantFirst: 0x02,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawHex, err := EncodeRawTransactionHex(tt.tx)
+			if err != nil {
+				t.Fatalf("EncodeRawTransactionHex() error = %v", err)
+			}
+			raw, err := hex.DecodeString(strings.TrimPrefix(rawHex, "0x"))
+			if err != nil {
+				t.Fatalf("invalid hex: %v", err)
+			}
+
+			if tt.tx.Type == ethgo.TransactionLegacy {
+				if raw[0] < 0xc0 {
+					t.Errorf("legacy tx should start with RLP list prefix, got 0x%02x", raw[0])
+				}
+			} else if raw[0] != tt.wantFirst || raw[1] < 0xc0 {
+				t.Errorf("typed tx should start with type 0x%02x then RLP list, got 0x%02x 0x%02x", tt.wantFirst, raw[0], raw[1])
+			}
+
+			var decoded ethgo.Transaction
+			if err := decoded.UnmarshalRLP(raw); err != nil {
+				t.Fatalf("UnmarshalRLP() error = %v", err)
+			}
+
+			if decoded.Type != tt.tx.Type || decoded.Nonce != tt.tx.Nonce || decoded.Gas != tt.tx.Gas ||
+				decoded.GasPrice != tt.tx.GasPrice || *decoded.To != *tt.tx.To ||
+				decoded.Value.Cmp(tt.tx.Value) != 0 || !bytes.Equal(decoded.Input, tt.tx.Input) {
+				t.Errorf("decoded tx mismatch: got %+v, want %+v", decoded, tt.tx)
+			}
+			if !bytes.Equal(decoded.R, tt.tx.R) || !bytes.Equal(decoded.S, tt.tx.S) {
+				t.Errorf("signature mismatch after round trip")
+			}
+			if tt.tx.Type != ethgo.TransactionLegacy {
+				if decoded.ChainID.Cmp(tt.tx.ChainID) != 0 || !reflect.DeepEqual(decoded.AccessList, tt.tx.AccessList) {
+					t.Errorf("typed fields mismatch: chainId %v accessList %v", decoded.ChainID, decoded.AccessList)
+				}
+			}
+			if tt.tx.Type == ethgo.TransactionDynamicFee {
+				if decoded.MaxFeePerGas.Cmp(tt.tx.MaxFeePerGas) != 0 || decoded.MaxPriorityFeePerGas.Cmp(tt.tx.MaxPriorityFeePerGas) != 0 {
+					t.Errorf("fee fields mismatch: got %v/%v", decoded.MaxFeePerGas, decoded.MaxPriorityFeePerGas)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeRawTransaction_UnsupportedType(t *testing.T) {
+	if _, err := EncodeRawTransaction(&ethgo.Transaction{Type: ethgo.TransactionType(3)}); err == nil {
+		t.Error("expected error for unsupported transaction type")
+	}
+}
+
+func TestDecodeRawTransaction_RecoversSender(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	chainID := big.NewInt(1337)
+	s := NewLocalKeystoreSigner(key, chainID)
+	to := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+
+	for _, txType := range []ethgo.TransactionType{ethgo.TransactionLegacy, ethgo.TransactionAccessList, ethgo.TransactionDynamicFee} {
+		signed, err := s.SignTransaction(&ethgo.Transaction{
+			Type: txType, ChainID: chainID, Nonce: 3, Gas: 21000, GasPrice: 1000,
+			MaxFeePerGas: big.NewInt(2000), MaxPriorityFeePerGas: big.NewInt(100),
+			To: &to, Value: big.NewInt(1),
+		})
+		if err != nil {
+			t.Fatalf("SignTransaction() error: %v", err)
+		}
+		raw, err := EncodeRawTransaction(signed)
+		if err != nil {
+			t.Fatalf("EncodeRawTransaction() error: %v", err)
+		}
+
+		decoded, err := DecodeRawTransaction(raw)
+		if err != nil {
+			t.Fatalf("DecodeRawTransaction() error for type %d: %v", txType, err)
+		}
+		if decoded.From != key.Address() {
+			t.Errorf("From = %s, want %s (type %d)", decoded.From, key.Address(), txType)
+		}
+		if decoded.Hash != ethgo.BytesToHash(ethgo.Keccak256(raw)) {
+			t.Errorf("Hash = %s, want keccak of raw transaction (type %d)", decoded.Hash, txType)
+		}
+	}
+
+	if _, err := DecodeRawTransaction([]byte{0xde, 0xad}); err == nil {
+		t.Error("Expected error for malformed raw transaction")
+	}
+}
@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// errNonDeterministicNonce 签名的 r 与 RFC 6979 确定性 k 计算出的 r 不一致
+var errNonDeterministicNonce = errors.New("signature nonce is not the RFC 6979 deterministic nonce")
+
+// rfc6979Nonce 按 RFC 6979 第 3.2 节（HMAC-SHA256）为 secp256k1 计算确定性 k
+//
+// hash 为 32 字节，与曲线阶等长，bits2int 无需截断
+func rfc6979Nonce(privateKey *big.Int, hash []byte) *big.Int {
+	x := make([]byte, 32)
+	privateKey.FillBytes(x)
+	h := make([]byte, 32)
+	new(big.Int).Mod(new(big.Int).SetBytes(hash), secp256k1N).FillBytes(h)
+	bx := append(x, h...)
+
+	mac := func(key []byte, parts ...[]byte) []byte {
+		m := hmac.New(sha256.New, key)
+		for _, p := range parts {
+			m.Write(p)
+		}
+		return m.Sum(nil)
+	}
+
+	v := bytes.Repeat([]byte{0x01}, 32)
+	k := make([]byte, 32)
+	k = mac(k, v, []byte{0x00}, bx)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, bx)
+	v = mac(k, v)
+
+	for {
+		v = mac(k, v)
+		nonce := new(big.Int).SetBytes(v)
+		if nonce.Sign() > 0 && nonce.Cmp(secp256k1N) < 0 {
+			return nonce
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
+
+// verifyDeterministicNonce 检查签名是否使用了 RFC 6979 确定性 k
+//
+// 重新计算 k 并比较 (k·G).x mod n 与签名的 r；随机或重复使用的 k 会导致不一致
+func verifyDeterministicNonce(privateKey, hash, signature []byte) error {
+	if len(signature) < 32 {
+		return fmt.Errorf("invalid signature length: %d", len(signature))
+	}
+	k := rfc6979Nonce(new(big.Int).SetBytes(privateKey), hash)
+	r, _ := wallet.S256.ScalarBaseMult(k.Bytes())
+	r.Mod(r, secp256k1N)
+	if r.Cmp(new(big.Int).SetBytes(signature[:32])) != 0 {
+		return errNonDeterministicNonce
+	}
+	return nil
+}
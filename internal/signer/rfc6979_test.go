@@ -0,0 +1,52 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/umbracle/ethgo/wallet"
+)
+
+func TestRFC6979Nonce(t *testing.T) {
+	// secp256k1/SHA-256 测试向量：私钥 1，消息 "Satoshi Nakamoto"
+	hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+	want, _ := new(big.Int).SetString("8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15", 16)
+
+	if got := rfc6979Nonce(big.NewInt(1), hash[:]); got.Cmp(want) != 0 {
+		t.Errorf("rfc6979Nonce() = %x, want %x", got, want)
+	}
+}
+
+func TestLocalKeystoreSigner_DeterministicNonce(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	s := NewLocalKeystoreSigner(key, big.NewInt(1)).WithNonceVerification(true)
+	hash := sha256.Sum256([]byte("hello"))
+
+	first, err := s.Sign(hash[:])
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	second, err := s.Sign(hash[:])
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("signing the same hash twice gave different signatures:\n%x\n%x", first, second)
+	}
+
+	privateKey, err := key.MarshallPrivateKey()
+	if err != nil {
+		t.Fatalf("MarshallPrivateKey() error: %v", err)
+	}
+	tampered := append([]byte(nil), first...)
+	tampered[31] ^= 0x01
+	if err := verifyDeterministicNonce(privateKey, hash[:], tampered); !errors.Is(err, errNonDeterministicNonce) {
+		t.Errorf("verifyDeterministicNonce() on a foreign nonce = %v, want errNonDeterministicNonce", err)
+	}
+}
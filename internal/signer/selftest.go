@@ -0,0 +1,39 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// selfTestHash is the fixed 32-byte hash signed by SelfTest.
+var selfTestHash = ethgo.Keccak256([]byte("web3signer-go startup self-test"))
+
+// SelfTest signs a fixed test hash and verifies that the recovered address
+// equals the signer's configured address.
+//
+// It catches KMS connectivity problems and key/address misconfiguration
+// before the server starts serving traffic.
+//
+// Parameters:
+//   - s: The signer to test
+//
+// Returns:
+//   - error: An error if signing fails or the recovered address does not match
+func SelfTest(s Client) error {
+	signature, err := s.Sign(selfTestHash)
+	if err != nil {
+		return fmt.Errorf("failed to sign self-test hash: %w", err)
+	}
+
+	recovered, err := wallet.Ecrecover(selfTestHash, signature)
+	if err != nil {
+		return fmt.Errorf("failed to recover address from self-test signature: %w", err)
+	}
+
+	if recovered != s.Address() {
+		return fmt.Errorf("self-test signature recovered address %s, expected configured address %s", recovered, s.Address())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package signer
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+func TestSelfTest(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+
+	walletKMS := &mockKMSClient{
+		signFunc: func(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+			signature, err := key.Sign(message)
+			if err != nil {
+				return nil, err
+			}
+			return []byte(hex.EncodeToString(signature)), nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		client  *mockKMSClient
+		address ethgo.Address
+		wantErr string
+	}{
+		{
+			name:    "recovered address matches",
+			client:  walletKMS,
+			address: key.Address(),
+		},
+		{
+			name:    "configured address mismatch",
+			client:  walletKMS,
+			address: ethgo.HexToAddress("0x1234567890123456789012345678901234567890"),
+			wantErr: "expected configured address",
+		},
+		{
+			name: "KMS unavailable",
+			client: &mockKMSClient{signFunc: func(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+				return nil, errors.New("connection refused")
+			}},
+			address: key.Address(),
+			wantErr: "failed to sign self-test hash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SelfTest(NewMPCKMSSigner(tt.client, "test-key-id", tt.address, big.NewInt(1)))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("SelfTest() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SelfTest() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
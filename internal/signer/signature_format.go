@@ -0,0 +1,75 @@
+package signer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SignatureRSV is a signature split into its r, s and v components.
+//
+// R and S are 0x-prefixed 32-byte hex strings; V is the recovery id as a
+// hex quantity ("0x0" or "0x1"), matching the last byte of the compact form.
+type SignatureRSV struct {
+	R string `json:"r"`
+	S string `json:"s"`
+	V string `json:"v"`
+}
+
+// NewSignatureRSV splits a 65-byte r || s || v signature into its components.
+//
+// Parameters:
+//   - signature: The 65-byte compact signature
+//
+// Returns:
+//   - *SignatureRSV: The split signature
+//   - error: An error if the signature is not 65 bytes long
+func NewSignatureRSV(signature []byte) (*SignatureRSV, error) {
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("invalid signature length: expected 65, got %d", len(signature))
+	}
+
+	return &SignatureRSV{
+		R: "0x" + hex.EncodeToString(signature[0:32]),
+		S: "0x" + hex.EncodeToString(signature[32:64]),
+		V: "0x" + strconv.FormatUint(uint64(signature[64]), 16),
+	}, nil
+}
+
+// Bytes reassembles the 65-byte r || s || v signature.
+//
+// Returns:
+//   - []byte: The compact signature
+//   - error: An error if any component is malformed
+func (s *SignatureRSV) Bytes() ([]byte, error) {
+	r, err := decodeSignatureWord(s.R)
+	if err != nil {
+		return nil, fmt.Errorf("invalid r: %w", err)
+	}
+	sv, err := decodeSignatureWord(s.S)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s: %w", err)
+	}
+	v, err := strconv.ParseUint(strings.TrimPrefix(s.V, "0x"), 16, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid v: %w", err)
+	}
+
+	signature := make([]byte, 0, 65)
+	signature = append(signature, r...)
+	signature = append(signature, sv...)
+	return append(signature, byte(v)), nil
+}
+
+// decodeSignatureWord 解码 0x 前缀的 32 字节十六进制值
+func decodeSignatureWord(value string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 32 {
+		return nil, fmt.Errorf("expected 32 bytes, got %d", len(b))
+	}
+	return b, nil
+}
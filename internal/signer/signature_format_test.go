@@ -0,0 +1,58 @@
+package signer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSignatureRSV_RoundTrip(t *testing.T) {
+	for _, v := range []byte{0, 1} {
+		signature := make([]byte, 65)
+		for i := 0; i < 64; i++ {
+			signature[i] = byte(i + 1)
+		}
+		signature[64] = v
+
+		rsv, err := NewSignatureRSV(signature)
+		if err != nil {
+			t.Fatalf("NewSignatureRSV() error: %v", err)
+		}
+		if len(rsv.R) != 66 || len(rsv.S) != 66 {
+			t.Errorf("Expected 0x-prefixed 32-byte r and s, got r=%s s=%s", rsv.R, rsv.S)
+		}
+		if want := map[byte]string{0: "0x0", 1: "0x1"}[v]; rsv.V != want {
+			t.Errorf("V = %s, want %s", rsv.V, want)
+		}
+
+		got, err := rsv.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes() error: %v", err)
+		}
+		if !bytes.Equal(got, signature) {
+			t.Errorf("round trip mismatch: got %x, want %x", got, signature)
+		}
+	}
+}
+
+func TestSignatureRSV_Invalid(t *testing.T) {
+	if _, err := NewSignatureRSV(make([]byte, 64)); err == nil {
+		t.Error("Expected error for 64-byte signature")
+	}
+
+	word := "0x" + string(bytes.Repeat([]byte("11"), 32))
+	tests := []struct {
+		name string
+		rsv  SignatureRSV
+	}{
+		{name: "short r", rsv: SignatureRSV{R: "0x11", S: word, V: "0x0"}},
+		{name: "non-hex s", rsv: SignatureRSV{R: word, S: "0xzz", V: "0x0"}},
+		{name: "v too large", rsv: SignatureRSV{R: word, S: word, V: "0x100"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.rsv.Bytes(); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -2,12 +2,16 @@ package signer
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strings"
 
+	apperrors "github.com/mowind/web3signer-go/internal/errors"
 	"github.com/mowind/web3signer-go/internal/kms"
 	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -51,6 +55,14 @@ func (s *MPCKMSSigner) Address() ethgo.Address {
 	return s.address
 }
 
+// ChainID returns the chain ID the signer was configured with.
+//
+// Returns:
+//   - *big.Int: The configured chain ID, or nil if none was provided
+func (s *MPCKMSSigner) ChainID() *big.Int {
+	return s.chainID
+}
+
 // Sign signs a 32-byte hash using MPC-KMS.
 //
 // This implements the ethgo.Key interface for signing message hashes.
@@ -63,21 +75,21 @@ func (s *MPCKMSSigner) Address() ethgo.Address {
 //   - []byte: 65-byte signature (r, s, v values)
 //   - error: An error if hash is invalid or signing fails
 func (s *MPCKMSSigner) Sign(hash []byte) ([]byte, error) {
+	return s.signHash(context.Background(), hash)
+}
+
+// signHash 使用 MPC-KMS 签名 32 字节哈希
+func (s *MPCKMSSigner) signHash(ctx context.Context, hash []byte) ([]byte, error) {
 	if len(hash) != 32 {
 		return nil, fmt.Errorf("invalid hash length: expected 32 bytes, got %d", len(hash))
 	}
 
-	signatureHex, err := s.client.Sign(context.Background(), s.keyID, hash)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign with MPC-KMS: %v", err)
-	}
-
-	signature, err := hex.DecodeString(string(signatureHex))
+	signatureHex, err := s.client.Sign(ctx, s.keyID, hash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode signature: %v", err)
+		return nil, fmt.Errorf("failed to sign with MPC-KMS: %w", err)
 	}
 
-	return signature, nil
+	return s.validateSignature(signatureHex, kms.DataEncodingHex, hash)
 }
 
 // SignTransaction signs an Ethereum transaction.
@@ -92,9 +104,21 @@ func (s *MPCKMSSigner) Sign(hash []byte) ([]byte, error) {
 //   - *ethgo.Transaction: A new transaction with signature applied
 //   - error: An error if signing fails
 func (s *MPCKMSSigner) SignTransaction(tx *ethgo.Transaction) (*ethgo.Transaction, error) {
+	signedTx := copyTransactionForSigning(tx, s.address)
+
+	// 签名后需在服务端组装交易，异步审批模式下也等待审批完成
+	return s.signTransactionInternal(signedTx, func(hash []byte) ([]byte, error) {
+		return s.signHash(kms.WithSyncApproval(context.Background()), hash)
+	})
+}
+
+// copyTransactionForSigning 复制待签名交易的字段并将 From 设为签名地址
+//
+// ethgo.Transaction.Copy 不复制 Type、Nonce、Gas 等字段，因此手动复制
+func copyTransactionForSigning(tx *ethgo.Transaction, from ethgo.Address) *ethgo.Transaction {
 	// 创建新的交易，手动复制所有字段
 	signedTx := &ethgo.Transaction{
-		From:     s.address,
+		From:     from,
 		Nonce:    tx.Nonce,
 		Gas:      tx.Gas,
 		GasPrice: tx.GasPrice,
@@ -134,30 +158,43 @@ func (s *MPCKMSSigner) SignTransaction(tx *ethgo.Transaction) (*ethgo.Transactio
 		signedTx.AccessList = tx.AccessList
 	}
 
-	// 使用内部签名方法
-	return s.signTransactionInternal(signedTx, func(hash []byte) ([]byte, error) {
-		return s.Sign(hash)
-	})
+	return signedTx
 }
 
 // signTransactionInternal 内部签名逻辑，处理签名应用
 func (s *MPCKMSSigner) signTransactionInternal(tx *ethgo.Transaction, signFunc func([]byte) ([]byte, error)) (*ethgo.Transaction, error) {
-	hash, err := s.signHash(tx)
+	return assembleSignedTransaction(tx, s.chainID, signFunc)
+}
+
+// assembleSignedTransaction 计算交易签名哈希，调用 signFunc 签名并将 r、s、v 写入 tx
+//
+// 所有签名后端（MPC-KMS、PKCS#11 等）共用该逻辑，signFunc 须返回 65 字节 r || s || v 签名。
+// 交易指定了 chainId 时使用该链 ID 而非签名器的默认链 ID（调用方负责校验是否允许）
+func assembleSignedTransaction(tx *ethgo.Transaction, chainID *big.Int, signFunc func([]byte) ([]byte, error)) (*ethgo.Transaction, error) {
+	if tx.ChainID != nil && tx.ChainID.Sign() > 0 {
+		chainID = tx.ChainID
+	}
+	// 类型化交易的编码包含 chainId，须与签名哈希使用的链 ID 一致
+	if tx.Type != ethgo.TransactionLegacy && chainID != nil {
+		tx.ChainID = new(big.Int).Set(chainID)
+	}
+
+	hash, err := signHash(tx, chainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute transaction hash: %w", err)
 	}
 
 	signature, err := signFunc(hash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %v", err)
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	if len(signature) != 65 {
-		return nil, fmt.Errorf("invalid signature length: expected 65, got %d", len(signature))
+	if err := validateSignatureValues(signature); err != nil {
+		return nil, err
 	}
 
-	tx.R = s.trimBytesZeros(signature[0:32])
-	tx.S = s.trimBytesZeros(signature[32:64])
+	tx.R = trimBytesZeros(signature[0:32])
+	tx.S = trimBytesZeros(signature[32:64])
 
 	// 使用 big.Int 计算 V 值，防止 chainID 增长导致的溢出
 	vBigInt := new(big.Int).SetUint64(uint64(signature[64]))
@@ -165,8 +202,8 @@ func (s *MPCKMSSigner) signTransactionInternal(tx *ethgo.Transaction, signFunc f
 	if tx.Type == ethgo.TransactionLegacy {
 		// Legacy 交易: v = signature_v + 35 + chainID * 2
 		vBigInt.Add(vBigInt, big.NewInt(35))
-		if s.chainID != nil {
-			chainIDBigInt := new(big.Int).Mul(s.chainID, big.NewInt(2))
+		if chainID != nil {
+			chainIDBigInt := new(big.Int).Mul(chainID, big.NewInt(2))
 			vBigInt.Add(vBigInt, chainIDBigInt)
 		}
 	}
@@ -176,15 +213,131 @@ func (s *MPCKMSSigner) signTransactionInternal(tx *ethgo.Transaction, signFunc f
 	return tx, nil
 }
 
-// signHash 计算交易的签名哈希
-func (s *MPCKMSSigner) signHash(tx *ethgo.Transaction) ([]byte, error) {
+// secp256k1N secp256k1 曲线的阶
+var secp256k1N, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+// validateSignatureValues 校验 KMS 返回的签名是否规范
+//
+// 签名须为 65 字节 r || s || v，其中 r、s 在 [1, N) 内，v 为原始恢复 ID（0 或 1）
+func validateSignatureValues(signature []byte) error {
+	if len(signature) != 65 {
+		return fmt.Errorf("invalid signature length: expected 65, got %d", len(signature))
+	}
+
+	r := new(big.Int).SetBytes(signature[0:32])
+	if r.Sign() == 0 || r.Cmp(secp256k1N) >= 0 {
+		return fmt.Errorf("non-canonical signature from KMS: r is zero or not below the curve order")
+	}
+
+	sv := new(big.Int).SetBytes(signature[32:64])
+	if sv.Sign() == 0 || sv.Cmp(secp256k1N) >= 0 {
+		return fmt.Errorf("non-canonical signature from KMS: s is zero or not below the curve order")
+	}
+
+	if v := signature[64]; v > 1 {
+		return fmt.Errorf("non-canonical signature from KMS: invalid recovery id %d (expected 0 or 1)", v)
+	}
+	return nil
+}
+
+// validateSignature 解码并校验 KMS 返回的签名，返回 65 字节 r || s || v
+//
+// 按请求使用的编码解码后，接受 65 字节签名或不含恢复 ID 的 64 字节签名（逐个尝试恢复 ID，
+// 恢复出签名地址者即为 v），再检查 r、s、v 是否规范。失败时返回 ErrorTypeKMSSign 类型的 AppError，
+// Details 说明原因，Context 记录 signature_length
+func (s *MPCKMSSigner) validateSignature(encoded []byte, encoding kms.DataEncoding, hash []byte) ([]byte, error) {
+	signature, err := decodeSignature(encoded, encoding)
+	if err != nil {
+		return nil, invalidSignatureError(err, len(encoded))
+	}
+
+	switch len(signature) {
+	case 65:
+	case 64:
+		signature = append(signature, 0)
+		recovered := false
+		for v := byte(0); v <= 1; v++ {
+			signature[64] = v
+			if addr, err := wallet.Ecrecover(hash, signature); err == nil && addr == s.address {
+				recovered = true
+				break
+			}
+		}
+		if !recovered {
+			return nil, invalidSignatureError(fmt.Errorf("invalid signature length: 64-byte signature without recovery id does not recover to %s", s.address), 64)
+		}
+	default:
+		return nil, invalidSignatureError(fmt.Errorf("invalid signature length: expected 64 or 65 bytes, got %d", len(signature)), len(signature))
+	}
+
+	if err := validateSignatureValues(signature); err != nil {
+		return nil, invalidSignatureError(err, len(signature))
+	}
+	return signature, nil
+}
+
+// decodeSignature 按数据编码解码签名，HEX 编码允许 0x 前缀及首尾空白
+func decodeSignature(encoded []byte, encoding kms.DataEncoding) ([]byte, error) {
+	switch encoding {
+	case kms.DataEncodingHex:
+		text := strings.TrimSpace(string(encoded))
+		text = strings.TrimPrefix(strings.TrimPrefix(text, "0x"), "0X")
+		signature, err := hex.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode hex signature: %w", err)
+		}
+		return signature, nil
+	case kms.DataEncodingBase64:
+		signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 signature: %w", err)
+		}
+		return signature, nil
+	default:
+		return encoded, nil
+	}
+}
+
+// invalidSignatureError 将签名校验失败包装为 KMS 签名错误
+func invalidSignatureError(err error, length int) error {
+	return apperrors.New(apperrors.ErrorTypeKMSSign, apperrors.ErrKMSSign.Code, "invalid signature from KMS").
+		WithDetails(err.Error()).
+		WithContext("signature_length", length)
+}
+
+// SigningHash returns the hash a transaction is signed over.
+//
+// A chainId set on tx takes precedence over chainID, as when signing. The
+// hash covers every signed field but not the signature, so it identifies a
+// transaction before it is signed, even with a non-deterministic signer.
+//
+// Parameters:
+//   - tx: The unsigned transaction
+//   - chainID: The signer's chain ID, used when tx carries none
+//
+// Returns:
+//   - ethgo.Hash: The signing hash
+//   - error: An error if the transaction cannot be encoded
+func SigningHash(tx *ethgo.Transaction, chainID *big.Int) (ethgo.Hash, error) {
+	if tx.ChainID != nil && tx.ChainID.Sign() > 0 {
+		chainID = tx.ChainID
+	}
+	hash, err := signHash(tx, chainID)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return ethgo.BytesToHash(hash), nil
+}
+
+// signHash 按交易类型计算 chainID 下的签名哈希
+func signHash(tx *ethgo.Transaction, chainID *big.Int) ([]byte, error) {
 	a := fastrlp.DefaultArenaPool.Get()
 	defer fastrlp.DefaultArenaPool.Put(a)
 
 	v := a.NewArray()
 
 	if tx.Type != ethgo.TransactionLegacy {
-		v.Set(a.NewBigInt(s.chainID))
+		v.Set(a.NewBigInt(chainID))
 	}
 
 	v.Set(a.NewUint(tx.Nonce))
@@ -213,8 +366,8 @@ func (s *MPCKMSSigner) signHash(tx *ethgo.Transaction) ([]byte, error) {
 		v.Set(accessList)
 	}
 
-	if s.chainID != nil && s.chainID.Uint64() != 0 && tx.Type == ethgo.TransactionLegacy {
-		v.Set(a.NewUint(s.chainID.Uint64()))
+	if chainID != nil && chainID.Uint64() != 0 && tx.Type == ethgo.TransactionLegacy {
+		v.Set(a.NewUint(chainID.Uint64()))
 		v.Set(a.NewUint(0))
 		v.Set(a.NewUint(0))
 	}
@@ -229,7 +382,7 @@ func (s *MPCKMSSigner) signHash(tx *ethgo.Transaction) ([]byte, error) {
 }
 
 // trimBytesZeros 移除字节切片的前导零
-func (s *MPCKMSSigner) trimBytesZeros(b []byte) []byte {
+func trimBytesZeros(b []byte) []byte {
 	var i int
 	for i = 0; i < len(b); i++ {
 		if b[i] != 0x0 {
@@ -255,13 +408,12 @@ func (s *MPCKMSSigner) trimBytesZeros(b []byte) []byte {
 //   - *ethgo.Transaction: A new transaction with signature applied
 //   - error: An error if signing fails
 func (s *MPCKMSSigner) SignTransactionWithSummary(tx *ethgo.Transaction, summary *kms.SignSummary) (*ethgo.Transaction, error) {
-	txCopy := tx.Copy()
-	txCopy.From = s.address
+	txCopy := copyTransactionForSigning(tx, s.address)
 
-	// 使用内部签名方法
+	// 签名后需在服务端组装交易，异步审批模式下也等待审批完成
 	return s.signTransactionInternal(txCopy, func(hash []byte) ([]byte, error) {
 		signatureHex, err := s.client.SignWithOptions(
-			context.Background(),
+			kms.WithSyncApproval(context.Background()),
 			s.keyID,
 			hash,
 			kms.DataEncodingHex,
@@ -271,13 +423,15 @@ func (s *MPCKMSSigner) SignTransactionWithSummary(tx *ethgo.Transaction, summary
 		if err != nil {
 			return nil, err
 		}
-		return hex.DecodeString(string(signatureHex))
+		return s.validateSignature(signatureHex, kms.DataEncodingHex, hash)
 	})
 }
 
 // CreateTransferSummary creates a transfer summary from transaction details.
 //
 // This method extracts relevant transaction information for approval display.
+// The amount is the raw value in wei; the KMS client converts it to
+// human-readable units before sending (see kms.SignSummary.WithDisplayAmount).
 //
 // Parameters:
 //   - tx: The transaction to extract details from
@@ -305,7 +459,7 @@ func (s *MPCKMSSigner) CreateTransferSummary(tx *ethgo.Transaction, token string
 		token = "ETH"
 	}
 
-	return kms.NewTransferSummary(from, to, amount, token, remark)
+	return kms.NewTransferSummary(from, to, amount, token, remark).WithAccessList(tx.AccessList)
 }
 
 // VerifyInterface 验证接口实现
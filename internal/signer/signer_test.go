@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	apperrors "github.com/mowind/web3signer-go/internal/errors"
 	"github.com/mowind/web3signer-go/internal/kms"
 	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
 )
 
 // mockKMSClient 是 MPC-KMS 客户端的 mock 实现
@@ -32,6 +37,7 @@ func (m *mockKMSClient) Sign(ctx context.Context, keyID string, message []byte)
 	for i := 0; i < 65; i++ {
 		signature[i] = byte(i + 1)
 	}
+	signature[64] = 1 // 恢复 ID 须为 0 或 1
 	return []byte(hex.EncodeToString(signature)), nil
 }
 
@@ -96,6 +102,7 @@ func TestMPCKMSSigner_Sign(t *testing.T) {
 			for i := 0; i < 65; i++ {
 				signature[i] = byte(i + 50)
 			}
+			signature[64] = 1 // 恢复 ID 须为 0 或 1
 			return []byte(hex.EncodeToString(signature)), nil
 		},
 	}
@@ -133,6 +140,7 @@ func TestMPCKMSSigner_SignTransaction(t *testing.T) {
 			for i := 0; i < 65; i++ {
 				signature[i] = byte(i + 1)
 			}
+			signature[64] = 1 // 恢复 ID 须为 0 或 1
 			return []byte(hex.EncodeToString(signature)), nil
 		},
 	}
@@ -207,6 +215,7 @@ func TestMPCKMSSigner_SignTransactionWithSummary(t *testing.T) {
 			for i := 0; i < 65; i++ {
 				signature[i] = byte(i + 100)
 			}
+			signature[64] = 1 // 恢复 ID 须为 0 或 1
 			return []byte(hex.EncodeToString(signature)), nil
 		},
 	}
@@ -289,6 +298,45 @@ func TestMPCKMSSigner_CreateTransferSummary_ContractCreation(t *testing.T) {
 	}
 }
 
+func TestMPCKMSSigner_CreateTransferSummary_AccessList(t *testing.T) {
+	toAddr := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+	accessList := ethgo.AccessList{
+		{
+			Address: ethgo.HexToAddress("0x1111111111111111111111111111111111111111"),
+			Storage: []ethgo.Hash{ethgo.HexToHash("0x01"), ethgo.HexToHash("0x02")},
+		},
+	}
+	tx := &ethgo.Transaction{
+		Type:       ethgo.TransactionAccessList,
+		To:         &toAddr,
+		AccessList: accessList,
+	}
+
+	signer := NewMPCKMSSigner(&mockKMSClient{}, "test-key-id", ethgo.HexToAddress("0x1234567890123456789012345678901234567890"), big.NewInt(1))
+
+	summary := signer.CreateTransferSummary(tx, "", "")
+	if len(summary.AccessList) != 1 || summary.AccessList[0].Address != accessList[0].Address ||
+		len(summary.AccessList[0].Storage) != 2 {
+		t.Fatalf("Expected access list in summary, got %+v", summary.AccessList)
+	}
+
+	// 摘要中的访问列表为副本，修改交易不影响已生成的摘要
+	tx.AccessList[0].Storage[0] = ethgo.HexToHash("0xff")
+	if summary.AccessList[0].Storage[0] != ethgo.HexToHash("0x01") {
+		t.Error("Expected summary access list to be independent of the transaction")
+	}
+
+	// 无访问列表时不输出该字段
+	tx.AccessList = nil
+	data, err := json.Marshal(signer.CreateTransferSummary(tx, "", ""))
+	if err != nil {
+		t.Fatalf("Failed to marshal summary: %v", err)
+	}
+	if strings.Contains(string(data), "access_list") {
+		t.Errorf("Expected access_list to be omitted, got %s", data)
+	}
+}
+
 func TestMPCKMSSigner_Sign_InvalidSignatureLength(t *testing.T) {
 	client := &mockKMSClient{
 		signFunc: func(ctx context.Context, keyID string, message []byte) ([]byte, error) {
@@ -333,6 +381,72 @@ func TestMPCKMSSigner_SignTransaction_InvalidSignatureLength(t *testing.T) {
 	}
 }
 
+func TestMPCKMSSigner_ValidateSignature(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	hash := ethgo.Keccak256([]byte("validate signature"))
+	signature, err := key.Sign(hash)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		signature []byte
+		wantErr   string
+	}{
+		{name: "0 bytes", signature: []byte{}, wantErr: "expected 64 or 65 bytes, got 0"},
+		{name: "64 bytes", signature: signature[:64]},
+		{name: "65 bytes", signature: signature},
+		{name: "66 bytes", signature: append(append([]byte{}, signature...), 0), wantErr: "expected 64 or 65 bytes, got 66"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockKMSClient{
+				signFunc: func(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+					return []byte(hex.EncodeToString(tt.signature)), nil
+				},
+				signWithOptionsFunc: func(ctx context.Context, keyID string, message []byte, encoding kms.DataEncoding, summary *kms.SignSummary, callbackURL string) ([]byte, error) {
+					return []byte(hex.EncodeToString(tt.signature)), nil
+				},
+			}
+			s := NewMPCKMSSigner(client, "test-key-id", key.Address(), big.NewInt(1))
+
+			got, signErr := s.Sign(hash)
+			_, txErr := s.SignTransactionWithSummary(&ethgo.Transaction{Gas: 21000, GasPrice: 1}, nil)
+
+			if tt.wantErr == "" {
+				if signErr != nil {
+					t.Fatalf("Sign() error = %v", signErr)
+				}
+				if !bytes.Equal(got, signature) {
+					t.Errorf("Sign() = %x, want %x", got, signature)
+				}
+				return
+			}
+
+			for _, err := range []error{signErr, txErr} {
+				var appErr *apperrors.AppError
+				if !errors.As(err, &appErr) {
+					t.Fatalf("expected *AppError, got %v", err)
+				}
+				if appErr.Type != apperrors.ErrorTypeKMSSign {
+					t.Errorf("Type = %s, want %s", appErr.Type, apperrors.ErrorTypeKMSSign)
+				}
+				if !strings.Contains(appErr.Details, tt.wantErr) {
+					t.Errorf("Details = %q, want to contain %q", appErr.Details, tt.wantErr)
+				}
+				if appErr.Context["signature_length"] != len(tt.signature) {
+					t.Errorf("signature_length = %v, want %d", appErr.Context["signature_length"], len(tt.signature))
+				}
+			}
+		})
+	}
+}
+
 func TestMPCKMSSigner_Sign_KMSError(t *testing.T) {
 	client := &mockKMSClient{
 		signFunc: func(ctx context.Context, keyID string, message []byte) ([]byte, error) {
@@ -377,6 +491,7 @@ func TestMPCKMSSigner_SignTransaction_AccessListTransaction(t *testing.T) {
 			for i := 0; i < 65; i++ {
 				signature[i] = byte(i + 1)
 			}
+			signature[64] = 1 // 恢复 ID 须为 0 或 1
 			return []byte(hex.EncodeToString(signature)), nil
 		},
 	}
@@ -418,6 +533,7 @@ func TestMPCKMSSigner_SignTransaction_DynamicFeeTransaction(t *testing.T) {
 			for i := 0; i < 65; i++ {
 				signature[i] = byte(i + 1)
 			}
+			signature[64] = 1 // 恢复 ID 须为 0 或 1
 			return []byte(hex.EncodeToString(signature)), nil
 		},
 	}
@@ -438,3 +554,65 @@ func TestMPCKMSSigner_SignTransaction_DynamicFeeTransaction(t *testing.T) {
 		t.Errorf("Expected S length 32, got %d", len(signedTx.S))
 	}
 }
+
+func TestMPCKMSSigner_RejectsNonCanonicalSignatures(t *testing.T) {
+	valid := func() []byte {
+		signature := make([]byte, 65)
+		for i := 0; i < 64; i++ {
+			signature[i] = byte(i + 1)
+		}
+		return signature
+	}
+	curveOrder := secp256k1N.FillBytes(make([]byte, 32))
+
+	tests := []struct {
+		name    string
+		mutate  func(sig []byte) []byte
+		wantErr string
+	}{
+		{name: "valid", mutate: func(sig []byte) []byte { return sig }},
+		{name: "valid recovery id 1", mutate: func(sig []byte) []byte { sig[64] = 1; return sig }},
+		{name: "zero r", mutate: func(sig []byte) []byte { copy(sig[0:32], make([]byte, 32)); return sig }, wantErr: "r is zero"},
+		{name: "r equal to curve order", mutate: func(sig []byte) []byte { copy(sig[0:32], curveOrder); return sig }, wantErr: "r is zero"},
+		{name: "zero s", mutate: func(sig []byte) []byte { copy(sig[32:64], make([]byte, 32)); return sig }, wantErr: "s is zero"},
+		{name: "s out of range", mutate: func(sig []byte) []byte { copy(sig[32:64], bytes.Repeat([]byte{0xff}, 32)); return sig }, wantErr: "s is zero"},
+		{name: "legacy 27 recovery id", mutate: func(sig []byte) []byte { sig[64] = 27; return sig }, wantErr: "invalid recovery id 27"},
+		{name: "short signature", mutate: func(sig []byte) []byte { return sig[:64] }, wantErr: "invalid signature length"},
+	}
+
+	toAddr := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := tt.mutate(valid())
+			client := &mockKMSClient{
+				signFunc: func(ctx context.Context, keyID string, message []byte) ([]byte, error) {
+					return []byte(hex.EncodeToString(signature)), nil
+				},
+			}
+			signer := NewMPCKMSSigner(client, "test-key-id", ethgo.HexToAddress("0x1234567890123456789012345678901234567890"), big.NewInt(5))
+
+			_, signErr := signer.Sign(make([]byte, 32))
+			signedTx, txErr := signer.SignTransaction(&ethgo.Transaction{To: &toAddr, Gas: 21000, GasPrice: 1})
+
+			for _, err := range []error{signErr, txErr} {
+				if tt.wantErr == "" {
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					continue
+				}
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			}
+
+			if tt.wantErr == "" {
+				// EIP-155: v = recoveryID + 35 + chainID*2
+				wantV := int64(signature[64]) + 35 + 2*5
+				if got := new(big.Int).SetBytes(signedTx.V).Int64(); got != wantV {
+					t.Errorf("V = %d, want %d", got, wantV)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package signer
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -20,10 +22,43 @@ import (
 // - Handles string-formatted numeric fields (0x prefix)
 type JSONRPCTransaction struct {
 	ethgo.Transaction
+
+	// FromSet reports whether the parameters included a non-null from field
+	FromSet bool
+
+	// Remark is the optional "remark" extension field: free text shown to
+	// KMS approvers. It is not part of the signed transaction.
+	Remark string
 }
 
 var defaultPool fastjson.ParserPool
 
+// FieldError reports a transaction parameter that could not be decoded.
+//
+// The message names the offending field, e.g. "invalid hex in field 'gasPrice': '0xzz'",
+// so that JSON-RPC clients can tell which parameter to fix.
+type FieldError struct {
+	// Field is the JSON field name; access list entries use paths such as "accessList[0].address"
+	Field string
+	// Problem describes what is wrong, e.g. "invalid hex" or "missing 0x prefix"
+	Problem string
+	// Value is the offending raw value, empty when the field is missing
+	Value string
+}
+
+// Error implements the error interface
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s in field '%s'", e.Problem, e.Field)
+	}
+	return fmt.Sprintf("%s in field '%s': '%s'", e.Problem, e.Field, e.Value)
+}
+
+// fieldError 创建字段解析错误
+func fieldError(field, problem, value string) error {
+	return &FieldError{Field: field, Problem: problem, Value: value}
+}
+
 // UnmarshalJSON implements json.Unmarshaler for JSON-RPC transaction parameters
 func (jt *JSONRPCTransaction) UnmarshalJSON(data []byte) error {
 	p := defaultPool.Get()
@@ -42,21 +77,24 @@ func (jt *JSONRPCTransaction) unmarshalJSON(v *fastjson.Value) error {
 
 	// Parse required field: gas
 	if jt.Gas, err = decodeUint(v, "gas"); err != nil {
-		return fmt.Errorf("failed to decode gas: %w", err)
+		return err
 	}
 
 	// Parse input/data field (optional)
-	if jt.Input, err = decodeBytes(jt.Input[:0], v, "data"); err != nil {
-		return fmt.Errorf("failed to decode data: %w", err)
+	if jt.Input, err = decodeInput(v); err != nil {
+		return err
 	}
 
-	// Parse optional fields
+	// Parse optional fields; a missing or null value means zero
 	if jt.Value, err = decodeBigIntOptional(v, "value"); err != nil {
-		return fmt.Errorf("failed to decode value: %w", err)
+		return err
+	}
+	if jt.Value == nil {
+		jt.Value = new(big.Int)
 	}
 
 	if jt.Nonce, err = decodeUintOptional(v, "nonce"); err != nil {
-		return fmt.Errorf("failed to decode nonce: %w", err)
+		return err
 	}
 
 	// Parse to field (optional, can be null for contract creation)
@@ -64,7 +102,7 @@ func (jt *JSONRPCTransaction) unmarshalJSON(v *fastjson.Value) error {
 		if v.Get("to").String() != "null" {
 			var to ethgo.Address
 			if err := decodeAddr(&to, v, "to"); err != nil {
-				return fmt.Errorf("failed to decode to: %w", err)
+				return err
 			}
 			jt.To = &to
 		}
@@ -73,8 +111,17 @@ func (jt *JSONRPCTransaction) unmarshalJSON(v *fastjson.Value) error {
 	// Parse from field (optional, used for address validation)
 	if isKeySet(v, "from") {
 		if err := decodeAddr(&jt.From, v, "from"); err != nil {
-			return fmt.Errorf("failed to decode from: %w", err)
+			return err
 		}
+		jt.FromSet = true
+	}
+
+	if isKeySet(v, "remark") {
+		remark, err := v.Get("remark").StringBytes()
+		if err != nil {
+			return fieldError("remark", "expected string", v.Get("remark").String())
+		}
+		jt.Remark = string(remark)
 	}
 
 	// Determine transaction type based on fields
@@ -83,65 +130,92 @@ func (jt *JSONRPCTransaction) unmarshalJSON(v *fastjson.Value) error {
 	if isKeySet(v, "maxFeePerGas") || isKeySet(v, "maxPriorityFeePerGas") {
 		jt.Type = ethgo.TransactionDynamicFee
 		if jt.MaxPriorityFeePerGas, err = decodeBigIntOptional(v, "maxPriorityFeePerGas"); err != nil {
-			return fmt.Errorf("failed to decode maxPriorityFeePerGas: %w", err)
+			return err
 		}
 		if jt.MaxFeePerGas, err = decodeBigIntOptional(v, "maxFeePerGas"); err != nil {
-			return fmt.Errorf("failed to decode maxFeePerGas: %w", err)
-		}
-		if jt.ChainID, err = decodeBigIntOptional(v, "chainId"); err != nil {
-			return fmt.Errorf("failed to decode chainId: %w", err)
+			return err
 		}
 	} else if isKeySet(v, "accessList") {
 		// Check for EIP-2930 (Type 1) - has accessList
 		jt.Type = ethgo.TransactionAccessList
 		if jt.GasPrice, err = decodeUintOptional(v, "gasPrice"); err != nil {
-			return fmt.Errorf("failed to decode gasPrice: %w", err)
-		}
-		if jt.ChainID, err = decodeBigIntOptional(v, "chainId"); err != nil {
-			return fmt.Errorf("failed to decode chainId: %w", err)
+			return err
 		}
 	} else {
 		// Legacy transaction (Type 0)
 		jt.Type = ethgo.TransactionLegacy
 		if jt.GasPrice, err = decodeUintOptional(v, "gasPrice"); err != nil {
-			return fmt.Errorf("failed to decode gasPrice: %w", err)
+			return err
 		}
 	}
 
+	// Parse chainId (optional, overrides the signer's chain ID for this transaction)
+	if jt.ChainID, err = decodeBigIntOptional(v, "chainId"); err != nil {
+		return err
+	}
+
 	// Parse accessList if present (for EIP-2930 and EIP-1559)
 	if isKeySet(v, "accessList") {
 		if err := unmarshalAccessList(&jt.AccessList, v.Get("accessList")); err != nil {
-			return fmt.Errorf("failed to decode accessList: %w", err)
+			return err
 		}
 	}
 
 	return nil
 }
 
+// decodeInput decodes transaction calldata from "input" or its legacy alias "data".
+// When both are present "input" wins, but they must carry the same value (matches go-ethereum).
+func decodeInput(v *fastjson.Value) ([]byte, error) {
+	input, err := decodeBytes(nil, v, "input")
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeBytes(nil, v, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	if !isKeySet(v, "input") {
+		return data, nil
+	}
+	if isKeySet(v, "data") && !bytes.Equal(input, data) {
+		return nil, fmt.Errorf(`both "data" and "input" are set and not equal. Please use "input" to pass transaction call data`)
+	}
+	return input, nil
+}
+
 // unmarshalAccessList decodes an access list from JSON
+// Errors name the offending entry, e.g. "accessList[1].storageKeys[0]"
 func unmarshalAccessList(al *ethgo.AccessList, v *fastjson.Value) error {
 	elems, err := v.Array()
 	if err != nil {
-		return err
+		return fieldError("accessList", "expected array", v.String())
 	}
-	for _, elem := range elems {
+	for i, elem := range elems {
 		entry := ethgo.AccessEntry{}
 		if err = decodeAddr(&entry.Address, elem, "address"); err != nil {
-			return err
+			return prefixFieldError(err, fmt.Sprintf("accessList[%d].", i))
+		}
+		storageField := fmt.Sprintf("accessList[%d].storageKeys", i)
+		storageValue := elem.Get("storageKeys")
+		if storageValue == nil {
+			return fieldError(storageField, "missing value", "")
 		}
-		storage, err := elem.Get("storageKeys").Array()
+		storage, err := storageValue.Array()
 		if err != nil {
-			return err
+			return fieldError(storageField, "expected array", storageValue.String())
 		}
 
 		entry.Storage = make([]ethgo.Hash, len(storage))
 		for indx, stg := range storage {
+			keyField := fmt.Sprintf("%s[%d]", storageField, indx)
 			b, err := stg.StringBytes()
 			if err != nil {
-				return err
+				return fieldError(keyField, "expected hex string", stg.String())
 			}
 			if err := entry.Storage[indx].UnmarshalText(b); err != nil {
-				return err
+				return fieldError(keyField, "invalid 32-byte hex", string(b))
 			}
 		}
 		*al = append(*al, entry)
@@ -149,6 +223,15 @@ func unmarshalAccessList(al *ethgo.AccessList, v *fastjson.Value) error {
 	return nil
 }
 
+// prefixFieldError 为嵌套字段的 FieldError 添加路径前缀
+func prefixFieldError(err error, prefix string) error {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return fieldError(prefix+fe.Field, fe.Problem, fe.Value)
+	}
+	return err
+}
+
 // isKeySet checks if a key exists and is not null
 func isKeySet(v *fastjson.Value, key string) bool {
 	value := v.Get(key)
@@ -163,32 +246,47 @@ func decodeBigIntOptional(v *fastjson.Value, key string) (*big.Int, error) {
 	return decodeBigInt(nil, v, key)
 }
 
+// maxBigIntHexDigits 256 位数值去掉前导零后的最大十六进制位数
+const maxBigIntHexDigits = 64
+
 // decodeBigInt decodes a big.Int field
-// Requires hex format with 0x prefix (per Ethereum JSON-RPC spec)
+// Requires hex format with 0x prefix (per Ethereum JSON-RPC spec); "0x" decodes as zero.
+// Signs ("0x-1") and values wider than 256 bits are rejected.
 func decodeBigInt(b *big.Int, v *fastjson.Value, key string) (*big.Int, error) {
 	vv := v.Get(key)
 	if vv == nil {
-		return nil, fmt.Errorf("field '%s' not found", key)
+		return nil, fieldError(key, "missing value", "")
 	}
 	str := vv.String()
 	str = strings.Trim(str, "\"")
 
-	if !strings.HasPrefix(str, "0x") {
-		return nil, fmt.Errorf("field '%s' does not have 0x prefix: '%s'", key, str)
+	if str == "" {
+		return nil, fieldError(key, "empty hex string", "")
 	}
-
-	if b == nil {
-		b = new(big.Int)
+	if !strings.HasPrefix(str, "0x") {
+		return nil, fieldError(key, "missing 0x prefix", str)
 	}
 
+	// 先逐字符校验，big.Int.SetString 会接受 "-"/"+" 符号
 	hexStr := str[2:]
+	for _, c := range hexStr {
+		if !isHexDigit(c) {
+			return nil, fieldError(key, "invalid hex", str)
+		}
+	}
+	// 超长数值在解析前拒绝，避免为任意长度的输入分配内存
+	if len(strings.TrimLeft(hexStr, "0")) > maxBigIntHexDigits {
+		return nil, fieldError(key, "value exceeds 256 bits", str)
+	}
 	if hexStr == "" {
 		hexStr = "0"
 	}
 
-	_, ok := b.SetString(hexStr, 16)
-	if !ok {
-		return nil, fmt.Errorf("field '%s' failed to decode big int: '%s'", key, str)
+	if b == nil {
+		b = new(big.Int)
+	}
+	if _, ok := b.SetString(hexStr, 16); !ok {
+		return nil, fieldError(key, "invalid hex", str)
 	}
 
 	return b, nil
@@ -207,13 +305,13 @@ func decodeUintOptional(v *fastjson.Value, key string) (uint64, error) {
 func decodeUint(v *fastjson.Value, key string) (uint64, error) {
 	vv := v.Get(key)
 	if vv == nil {
-		return 0, fmt.Errorf("field '%s' not found", key)
+		return 0, fieldError(key, "missing value", "")
 	}
 	str := vv.String()
 	str = strings.Trim(str, "\"")
 
 	if !strings.HasPrefix(str, "0x") {
-		return 0, fmt.Errorf("field '%s' does not have 0x prefix: '%s'", key, str)
+		return 0, fieldError(key, "missing 0x prefix", str)
 	}
 
 	hexStr := str[2:]
@@ -222,8 +320,11 @@ func decodeUint(v *fastjson.Value, key string) (uint64, error) {
 	}
 
 	num, err := strconv.ParseUint(hexStr, 16, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fieldError(key, "value exceeds 64 bits", str)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("field '%s' failed to decode uint: %s", key, str)
+		return 0, fieldError(key, "invalid hex", str)
 	}
 
 	return num, nil
@@ -240,15 +341,15 @@ func decodeBytes(dst []byte, v *fastjson.Value, key string) ([]byte, error) {
 	str = strings.Trim(str, "\"")
 
 	if !strings.HasPrefix(str, "0x") {
-		return nil, fmt.Errorf("field '%s' does not have 0x prefix: '%s'", key, str)
+		return nil, fieldError(key, "missing 0x prefix", str)
 	}
-	str = str[2:]
-	if len(str)%2 != 0 {
-		str = "0" + str
+	hexStr := str[2:]
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
 	}
-	buf, err := hex.DecodeString(str)
+	buf, err := hex.DecodeString(hexStr)
 	if err != nil {
-		return nil, err
+		return nil, fieldError(key, "invalid hex", str)
 	}
 	dst = append(dst, buf...)
 	return dst, nil
@@ -296,18 +397,18 @@ func isHexDigit(c rune) bool {
 func decodeAddr(a *ethgo.Address, v *fastjson.Value, key string) error {
 	b := v.GetStringBytes(key)
 	if len(b) == 0 {
-		return fmt.Errorf("field '%s' not found", key)
+		return fieldError(key, "missing value", "")
 	}
 
 	addrStr := string(b)
 
 	// Validate address format before unmarshalling
 	if !isValidEthAddress(addrStr) {
-		return fmt.Errorf("field '%s' has invalid address format: '%s'", key, addrStr)
+		return fieldError(key, "invalid address format", addrStr)
 	}
 
 	if err := a.UnmarshalText(b); err != nil {
-		return fmt.Errorf("field '%s' failed to decode address: %w", key, err)
+		return fieldError(key, "invalid address format", addrStr)
 	}
 	return nil
 }
@@ -2,7 +2,10 @@ package signer
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+
+	"github.com/mowind/web3signer-go/internal/kms"
 )
 
 // ParseJSONRPCTransaction parses JSON-RPC transaction parameters
@@ -13,6 +16,7 @@ import (
 //	Object format: {"from": "...", "to": "...", ...}
 //
 // This function is designed for eth_signTransaction and eth_sendTransaction methods.
+// A field that cannot be decoded is reported as a *FieldError naming the field.
 func ParseJSONRPCTransaction(params json.RawMessage) (JSONRPCTransaction, error) {
 	var tx JSONRPCTransaction
 
@@ -21,14 +25,49 @@ func ParseJSONRPCTransaction(params json.RawMessage) (JSONRPCTransaction, error)
 	if err := json.Unmarshal(params, &paramsArray); err == nil && len(paramsArray) > 0 {
 		// Array format, take first element
 		if err := json.Unmarshal(paramsArray[0], &tx); err != nil {
-			return tx, fmt.Errorf("failed to parse transaction params: %w", err)
+			return tx, transactionParamsError(err)
 		}
 	} else {
 		// Direct object format
 		if err := json.Unmarshal(params, &tx); err != nil {
-			return tx, fmt.Errorf("failed to parse transaction params: %w", err)
+			return tx, transactionParamsError(err)
 		}
 	}
 
 	return tx, nil
 }
+
+// transactionParamsError 字段解析错误原样返回，其它错误（如 JSON 语法错误）添加上下文
+func transactionParamsError(err error) error {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return fe
+	}
+	return fmt.Errorf("failed to parse transaction params: %w", err)
+}
+
+// ParseTransactionWithSummaryParams parses web3signer_signTransactionWithSummary parameters
+//
+// Parameters format: [{"from": "...", "to": "...", ...}, {"type": "TRANSFER", "remark": "...", ...}]
+func ParseTransactionWithSummaryParams(params json.RawMessage) (JSONRPCTransaction, *kms.SignSummary, error) {
+	var tx JSONRPCTransaction
+
+	var paramsArray []json.RawMessage
+	if err := json.Unmarshal(params, &paramsArray); err != nil {
+		return tx, nil, fmt.Errorf("failed to parse params: %w", err)
+	}
+	if len(paramsArray) < 2 {
+		return tx, nil, fmt.Errorf("insufficient parameters: expected transaction and summary")
+	}
+
+	if err := json.Unmarshal(paramsArray[0], &tx); err != nil {
+		return tx, nil, transactionParamsError(err)
+	}
+
+	var summary kms.SignSummary
+	if err := json.Unmarshal(paramsArray[1], &summary); err != nil {
+		return tx, nil, fmt.Errorf("failed to parse summary params: %w", err)
+	}
+
+	return tx, &summary, nil
+}
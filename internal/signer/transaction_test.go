@@ -1,7 +1,10 @@
 package signer
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/umbracle/ethgo"
@@ -159,6 +162,106 @@ func TestJSONRPCTransaction_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestJSONRPCTransaction_UnmarshalJSON_Value(t *testing.T) {
+	const prefix = `{"from":"0x1234567890123456789012345678901234567890","gas":"0x5208"`
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "missing value", value: ``, want: "0"},
+		{name: "null value", value: `,"value":null`, want: "0"},
+		{name: "empty hex", value: `,"value":"0x"`, want: "0"},
+		{name: "leading zeros", value: `,"value":"0x000064"`, want: "100"},
+		{name: "uint256 max", value: `,"value":"0x` + strings.Repeat("f", 64) + `"`,
+			want: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{name: "uint256 max with leading zeros", value: `,"value":"0x00` + strings.Repeat("f", 64) + `"`,
+			want: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{name: "empty string", value: `,"value":""`, wantErr: "empty hex string in field 'value'"},
+		{name: "negative", value: `,"value":"0x-1"`, wantErr: "invalid hex in field 'value': '0x-1'"},
+		{name: "plus sign", value: `,"value":"0x+1"`, wantErr: "invalid hex in field 'value': '0x+1'"},
+		{name: "invalid hex", value: `,"value":"0xzz"`, wantErr: "invalid hex in field 'value': '0xzz'"},
+		{name: "decimal number", value: `,"value":100`, wantErr: "missing 0x prefix in field 'value': '100'"},
+		{name: "above 256 bits", value: `,"value":"0x1` + strings.Repeat("0", 64) + `"`, wantErr: "value exceeds 256 bits in field 'value'"},
+		{name: "enormous value", value: `,"value":"0x` + strings.Repeat("f", 1<<20) + `"`, wantErr: "value exceeds 256 bits in field 'value'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got JSONRPCTransaction
+			err := json.Unmarshal([]byte(prefix+tt.value+`}`), &got)
+			if tt.wantErr != "" {
+				var fe *FieldError
+				if !errors.As(err, &fe) || fe.Field != "value" || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("UnmarshalJSON() error = %.100v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if got.Value == nil || got.Value.String() != tt.want {
+				t.Errorf("Value = %v, want %s", got.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONRPCTransaction_UnmarshalJSON_InputAlias(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantInput string
+		wantErr   bool
+	}{
+		{
+			name:      "input only",
+			input:     `{"gas": "0x5208", "input": "0xa9059cbb"}`,
+			wantInput: "a9059cbb",
+		},
+		{
+			name:      "data only",
+			input:     `{"gas": "0x5208", "data": "0xa9059cbb"}`,
+			wantInput: "a9059cbb",
+		},
+		{
+			name:      "input and data matching",
+			input:     `{"gas": "0x5208", "input": "0xa9059cbb", "data": "0xa9059cbb"}`,
+			wantInput: "a9059cbb",
+		},
+		{
+			name:    "input and data conflicting",
+			input:   `{"gas": "0x5208", "input": "0xa9059cbb", "data": "0x095ea7b3"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid input",
+			input:   `{"gas": "0x5208", "input": "a9059cbb"}`,
+			wantErr: true,
+		},
+		{
+			name:      "null input falls back to data",
+			input:     `{"gas": "0x5208", "input": null, "data": "0xa9059cbb"}`,
+			wantInput: "a9059cbb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got JSONRPCTransaction
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && hex.EncodeToString(got.Input) != tt.wantInput {
+				t.Errorf("Input = %x, want %s", got.Input, tt.wantInput)
+			}
+		})
+	}
+}
+
 func TestParseJSONRPCTransaction(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -274,6 +377,83 @@ func TestDecodeUint(t *testing.T) {
 	}
 }
 
+func TestDecodeHelpers_FieldErrors(t *testing.T) {
+	decodeUintField := func(v *fastjson.Value, key string) error {
+		_, err := decodeUint(v, key)
+		return err
+	}
+	decodeBigIntField := func(v *fastjson.Value, key string) error {
+		_, err := decodeBigInt(nil, v, key)
+		return err
+	}
+	decodeBytesField := func(v *fastjson.Value, key string) error {
+		_, err := decodeBytes(nil, v, key)
+		return err
+	}
+	decodeAddrField := func(v *fastjson.Value, key string) error {
+		var addr ethgo.Address
+		return decodeAddr(&addr, v, key)
+	}
+
+	tests := []struct {
+		name   string
+		decode func(v *fastjson.Value, key string) error
+		input  string
+		key    string
+		want   string
+	}{
+		{name: "uint invalid hex", decode: decodeUintField, input: `{"gasPrice": "0xzz"}`, key: "gasPrice", want: "invalid hex in field 'gasPrice': '0xzz'"},
+		{name: "uint no prefix", decode: decodeUintField, input: `{"gas": 21000}`, key: "gas", want: "missing 0x prefix in field 'gas': '21000'"},
+		{name: "uint overflow", decode: decodeUintField, input: `{"nonce": "0x10000000000000000"}`, key: "nonce", want: "value exceeds 64 bits in field 'nonce': '0x10000000000000000'"},
+		{name: "uint missing", decode: decodeUintField, input: `{}`, key: "gas", want: "missing value in field 'gas'"},
+		{name: "big int invalid hex", decode: decodeBigIntField, input: `{"maxFeePerGas": "0x1g"}`, key: "maxFeePerGas", want: "invalid hex in field 'maxFeePerGas': '0x1g'"},
+		{name: "big int no prefix", decode: decodeBigIntField, input: `{"value": "100"}`, key: "value", want: "missing 0x prefix in field 'value': '100'"},
+		{name: "bytes invalid hex", decode: decodeBytesField, input: `{"data": "0xzz"}`, key: "data", want: "invalid hex in field 'data': '0xzz'"},
+		{name: "bytes no prefix", decode: decodeBytesField, input: `{"input": "abcd"}`, key: "input", want: "missing 0x prefix in field 'input': 'abcd'"},
+		{name: "address invalid", decode: decodeAddrField, input: `{"to": "0x1234"}`, key: "to", want: "invalid address format in field 'to': '0x1234'"},
+		{name: "address missing", decode: decodeAddrField, input: `{}`, key: "from", want: "missing value in field 'from'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pool fastjsonParserPool
+			p, _ := pool.Get().Parse(tt.input)
+			err := tt.decode(p, tt.key)
+
+			var fe *FieldError
+			if !errors.As(err, &fe) || fe.Field != tt.key {
+				t.Fatalf("error = %v, want *FieldError for field %s", err, tt.key)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("error = %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONRPCTransaction_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{name: "gasPrice", params: `[{"gas":"0x5208","gasPrice":"0xzz"}]`, want: "invalid hex in field 'gasPrice': '0xzz'"},
+		{name: "chainId", params: `{"gas":"0x5208","chainId":"1"}`, want: "missing 0x prefix in field 'chainId': '1'"},
+		{name: "access list address", params: `[{"gas":"0x5208","accessList":[{"address":"0x12","storageKeys":[]}]}]`, want: "invalid address format in field 'accessList[0].address': '0x12'"},
+		{name: "access list storage key", params: `[{"gas":"0x5208","accessList":[{"address":"0x1234567890123456789012345678901234567890","storageKeys":["0x01"]}]}]`, want: "invalid 32-byte hex in field 'accessList[0].storageKeys[0]': '0x01'"},
+		{name: "remark", params: `[{"gas":"0x5208","remark":42}]`, want: "expected string in field 'remark': '42'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJSONRPCTransaction([]byte(tt.params))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ParseJSONRPCTransaction() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
 func TestDecodeBytes(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff describes an exponential backoff schedule.
+//
+// The delay before retry n (0-based) is Initial * Multiplier^n, capped at Max.
+// With Jitter enabled ("full jitter"), the actual delay is drawn uniformly
+// from (0, delay], which spreads out clients that failed at the same time.
+type Backoff struct {
+	Initial    time.Duration // Delay before the first retry
+	Max        time.Duration // Upper bound on any delay; 0 means no bound
+	Multiplier float64       // Growth factor per retry; values below 1 are treated as 1
+	Jitter     bool          // Draw each delay uniformly from (0, delay]
+}
+
+// Ceiling returns the un-jittered delay before the given retry.
+//
+// Parameters:
+//   - attempt: The 0-based retry number
+//
+// Returns:
+//   - time.Duration: Initial * Multiplier^attempt, capped at Max
+func (b Backoff) Ceiling(attempt int) time.Duration {
+	multiplier := math.Max(b.Multiplier, 1)
+	delay := float64(b.Initial) * math.Pow(multiplier, float64(attempt))
+	limit := float64(math.MaxInt64)
+	if b.Max > 0 {
+		limit = float64(b.Max)
+	}
+	if delay >= limit || math.IsInf(delay, 0) || math.IsNaN(delay) {
+		if b.Max > 0 {
+			return b.Max
+		}
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// Delay returns the delay to wait before the given retry, with jitter applied.
+//
+// Parameters:
+//   - attempt: The 0-based retry number
+//
+// Returns:
+//   - time.Duration: The delay to wait
+func (b Backoff) Delay(attempt int) time.Duration {
+	ceiling := b.Ceiling(attempt)
+	if !b.Jitter || ceiling <= 0 {
+		return ceiling
+	}
+	return time.Duration(rand.Int64N(int64(ceiling)) + 1) //nolint:gosec // jitter does not need a cryptographic source
+}
+
+// Retry calls fn until it succeeds, fails with a non-retryable error, or
+// attempts calls have been made, waiting b.Delay between calls.
+//
+// Waiting stops early when ctx is cancelled; the last error from fn is
+// returned in that case, as after the final attempt.
+//
+// Parameters:
+//   - ctx: Context bounding the retries
+//   - b: The backoff schedule
+//   - attempts: Maximum number of calls to fn, including the first; values below 1 are treated as 1
+//   - fn: The operation, receiving the 0-based attempt number
+//   - isRetryable: Reports whether an error from fn should be retried; nil retries every error
+//
+// Returns:
+//   - error: nil on success, otherwise the last error from fn
+func Retry(ctx context.Context, b Backoff, attempts int, fn func(attempt int) error, isRetryable func(error) bool) error {
+	for attempt := 0; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || attempt+1 >= attempts || (isRetryable != nil && !isRetryable(err)) {
+			return err
+		}
+
+		timer := time.NewTimer(b.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
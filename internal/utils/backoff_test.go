@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Ceiling(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for attempt, w := range want {
+		if got := b.Ceiling(attempt); got != w {
+			t.Errorf("Ceiling(%d) = %v, want %v", attempt, got, w)
+		}
+		// 未启用 jitter 时 Delay 与 Ceiling 相同
+		if got := b.Delay(attempt); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", attempt, got, w)
+		}
+	}
+
+	// 溢出时取上限
+	if got := b.Ceiling(1000); got != time.Second {
+		t.Errorf("Ceiling(1000) = %v, want %v", got, time.Second)
+	}
+	// Multiplier 小于 1 按 1 处理
+	if got := (Backoff{Initial: time.Second}).Ceiling(5); got != time.Second {
+		t.Errorf("constant backoff Ceiling(5) = %v, want %v", got, time.Second)
+	}
+}
+
+func TestBackoff_DelayWithJitter(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: 2 * time.Second, Multiplier: 2, Jitter: true}
+	for attempt := 0; attempt < 70; attempt++ {
+		ceiling := b.Ceiling(attempt)
+		if delay := b.Delay(attempt); delay <= 0 || delay > ceiling {
+			t.Errorf("attempt %d: delay %v out of range (0, %v]", attempt, delay, ceiling)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	b := Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2}
+	errTemporary := errors.New("temporary")
+	errPermanent := errors.New("permanent")
+	isRetryable := func(err error) bool { return errors.Is(err, errTemporary) }
+
+	tests := []struct {
+		name      string
+		attempts  int
+		failures  int
+		failWith  error
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "succeeds after retries", attempts: 3, failures: 2, failWith: errTemporary, wantCalls: 3},
+		{name: "gives up after attempts", attempts: 2, failures: 5, failWith: errTemporary, wantErr: errTemporary, wantCalls: 2},
+		{name: "non-retryable error", attempts: 3, failures: 1, failWith: errPermanent, wantErr: errPermanent, wantCalls: 1},
+		{name: "zero attempts calls once", attempts: 0, failures: 1, failWith: errTemporary, wantErr: errTemporary, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Retry(context.Background(), b, tt.attempts, func(attempt int) error {
+				if attempt != calls {
+					t.Errorf("attempt = %d, want %d", attempt, calls)
+				}
+				calls++
+				if calls <= tt.failures {
+					return tt.failWith
+				}
+				return nil
+			}, isRetryable)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Retry() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetry_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errTemporary := errors.New("temporary")
+
+	calls := 0
+	start := time.Now()
+	err := Retry(ctx, Backoff{Initial: time.Hour}, 5, func(int) error {
+		calls++
+		cancel()
+		return errTemporary
+	}, nil)
+	if !errors.Is(err, errTemporary) || calls != 1 {
+		t.Errorf("Retry() = (%v, %d calls), want last error after 1 call", err, calls)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Retry() did not stop waiting on context cancellation")
+	}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/umbracle/ethgo"
+)
+
+// addressPattern 匹配独立的以太坊地址；前后为十六进制字符时（如 32 字节哈希）不匹配
+var addressPattern = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+
+// MaskAddress shortens an Ethereum address to its first four and last four
+// hex digits, e.g. 0x1234…7890.
+//
+// Parameters:
+//   - address: The 0x-prefixed address
+//
+// Returns:
+//   - string: The masked address, or address unchanged if it is not 42 characters long
+func MaskAddress(address string) string {
+	if len(address) != 42 {
+		return address
+	}
+	return address[:6] + "…" + address[38:]
+}
+
+// MaskAddresses masks every Ethereum address embedded in s with MaskAddress.
+//
+// Parameters:
+//   - s: Text that may contain addresses, such as an error message
+//
+// Returns:
+//   - string: s with each address masked
+func MaskAddresses(s string) string {
+	return addressPattern.ReplaceAllStringFunc(s, MaskAddress)
+}
+
+// AddressMaskingHook is a logrus hook that masks Ethereum addresses in the
+// fields of log entries at info level and above.
+//
+// Debug and trace entries keep full addresses, so they remain available for
+// troubleshooting when the log level is lowered. String, error and
+// ethgo.Address field values are masked; the log message itself is not.
+type AddressMaskingHook struct{}
+
+// Levels returns the levels whose entries are masked.
+//
+// Returns:
+//   - []logrus.Level: Info and all more severe levels
+func (AddressMaskingHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel, logrus.InfoLevel}
+}
+
+// Fire masks the addresses in the entry's fields.
+//
+// Parameters:
+//   - entry: The log entry about to be written
+//
+// Returns:
+//   - error: Always nil
+func (AddressMaskingHook) Fire(entry *logrus.Entry) error {
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = MaskAddresses(v)
+		case ethgo.Address:
+			entry.Data[key] = MaskAddress(v.String())
+		case *ethgo.Address:
+			if v != nil {
+				entry.Data[key] = MaskAddress(v.String())
+			}
+		case error:
+			if masked := MaskAddresses(v.Error()); masked != v.Error() {
+				entry.Data[key] = errors.New(masked)
+			}
+		}
+	}
+	return nil
+}
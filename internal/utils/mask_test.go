@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/umbracle/ethgo"
+)
+
+func TestMaskAddresses(t *testing.T) {
+	hash := "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "0x1234567890123456789012345678901234567890", want: "0x1234…7890"},
+		{input: "signature does not match signer address 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", want: "signature does not match signer address 0x5aAe…eAed"},
+		{input: hash, want: hash},
+		{input: "not an address", want: "not an address"},
+	}
+
+	for _, tt := range tests {
+		if got := MaskAddresses(tt.input); got != tt.want {
+			t.Errorf("MaskAddresses(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAddressMaskingHook(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	logger.AddHook(AddressMaskingHook{})
+
+	to := ethgo.HexToAddress("0x0987654321098765432109876543210987654321")
+	entry := logger.WithFields(logrus.Fields{
+		"from":  "0x1234567890123456789012345678901234567890",
+		"to":    &to,
+		"error": errors.New("unknown key for 0x1234567890123456789012345678901234567890"),
+		"nonce": 7,
+	})
+
+	entry.Info("Signing transaction")
+	info := hook.LastEntry().Data
+	if info["from"] != "0x1234…7890" || info["to"] != "0x0987…4321" || info["nonce"] != 7 {
+		t.Errorf("info fields = %v, want masked addresses", info)
+	}
+	if err, ok := info["error"].(error); !ok || err.Error() != "unknown key for 0x1234…7890" {
+		t.Errorf("info error field = %v, want masked address", info["error"])
+	}
+
+	entry.Debug("Signing transaction")
+	if debug := hook.LastEntry().Data; debug["from"] != "0x1234567890123456789012345678901234567890" {
+		t.Errorf("debug from = %v, want full address", debug["from"])
+	}
+}
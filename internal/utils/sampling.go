@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sampledBodyFields 调试日志中记录完整请求/响应体的字段
+var sampledBodyFields = []string{"request_body", "response_body", "params", "result"}
+
+// BodySamplingHook is a logrus hook that keeps the request and response
+// bodies logged for debugging (request_body, response_body, params and result
+// fields) on only a fraction of entries.
+//
+// Entries that are not sampled are still written, with the body fields
+// replaced by body_sampled_out=true, so the request flow stays traceable
+// while the bulk of the volume is dropped. Warn and more severe entries, and
+// entries with an HTTP status_code of 400 or above, always keep their bodies.
+type BodySamplingHook struct {
+	rate  float64
+	count atomic.Uint64
+}
+
+// NewBodySamplingHook creates a hook that keeps bodies on the given fraction
+// of entries.
+//
+// Sampling is deterministic: with rate 0.1 exactly one in every ten entries
+// carrying a body keeps it.
+//
+// Parameters:
+//   - rate: Fraction of entries that keep their bodies, in (0, 1]
+//
+// Returns:
+//   - *BodySamplingHook: The hook
+func NewBodySamplingHook(rate float64) *BodySamplingHook {
+	return &BodySamplingHook{rate: rate}
+}
+
+// Levels returns the levels whose entries are sampled.
+//
+// Returns:
+//   - []logrus.Level: Info, debug and trace
+func (h *BodySamplingHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.InfoLevel, logrus.DebugLevel, logrus.TraceLevel}
+}
+
+// Fire removes the body fields from entries that are not sampled.
+//
+// Parameters:
+//   - entry: The log entry about to be written
+//
+// Returns:
+//   - error: Always nil
+func (h *BodySamplingHook) Fire(entry *logrus.Entry) error {
+	hasBody := false
+	for _, field := range sampledBodyFields {
+		if _, ok := entry.Data[field]; ok {
+			hasBody = true
+			break
+		}
+	}
+	if !hasBody {
+		return nil
+	}
+	if status, ok := entry.Data["status_code"].(int); ok && status >= http.StatusBadRequest {
+		return nil
+	}
+
+	// 第 n 条（从 0 开始）在 floor((n+1)*rate) 增加时保留，长期比例精确等于 rate
+	n := h.count.Add(1) - 1
+	if uint64(float64(n+1)*h.rate) > uint64(float64(n)*h.rate) {
+		return nil
+	}
+
+	for _, field := range sampledBodyFields {
+		delete(entry.Data, field)
+	}
+	entry.Data["body_sampled_out"] = true
+	return nil
+}
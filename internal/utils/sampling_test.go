@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestBodySamplingHook(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	logger.AddHook(NewBodySamplingHook(0.25))
+
+	kept := 0
+	for i := 0; i < 100; i++ {
+		logger.WithFields(logrus.Fields{"key_id": "key-1", "request_body": "{}"}).Debug("Sign request body")
+		data := hook.LastEntry().Data
+		if _, ok := data["request_body"]; ok {
+			kept++
+		} else if data["body_sampled_out"] != true || data["key_id"] != "key-1" {
+			t.Fatalf("sampled out entry = %v, want body_sampled_out and other fields kept", data)
+		}
+	}
+	if kept != 25 {
+		t.Errorf("kept %d of 100 bodies, want 25", kept)
+	}
+
+	tests := []struct {
+		name   string
+		level  logrus.Level
+		fields logrus.Fields
+	}{
+		{name: "error entry", level: logrus.ErrorLevel, fields: logrus.Fields{"response_body": "{}"}},
+		{name: "warn entry", level: logrus.WarnLevel, fields: logrus.Fields{"params": "[]"}},
+		{name: "warn result", level: logrus.WarnLevel, fields: logrus.Fields{"result": "\"0x1\""}},
+		{name: "error status", level: logrus.DebugLevel, fields: logrus.Fields{"status_code": 401, "response_body": "{}"}},
+		{name: "no body", level: logrus.DebugLevel, fields: logrus.Fields{"key_id": "key-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 4; i++ {
+				logger.WithFields(tt.fields).Log(tt.level, "entry")
+				if _, ok := hook.LastEntry().Data["body_sampled_out"]; ok {
+					t.Fatalf("entry %v was sampled out", hook.LastEntry().Data)
+				}
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 // Package utils provides common utility functions for internal packages.
 //
 // This package contains shared functionality that is used across
-// multiple internal modules, including validation and HTTP utilities.
+// multiple internal modules, including validation, HTTP, retry backoff and log masking utilities.
 package utils
 
 import (
+	"encoding/hex"
+	"fmt"
 	"strings"
+
+	"github.com/umbracle/ethgo"
 )
 
 // IsValidEthAddress validates an Ethereum address format.
@@ -62,3 +66,39 @@ func IsValidEthAddress(addr string) bool {
 func isHexDigit(c rune) bool {
 	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
 }
+
+// ToChecksumAddress encodes an Ethereum address in EIP-55 mixed-case checksum format.
+//
+// The input may use any letter case. Address comparisons should remain
+// case-insensitive; this encoding is only meant for output to clients.
+//
+// Parameters:
+//   - addr: The address string to encode
+//
+// Returns:
+//   - string: The EIP-55 checksummed address
+//   - error: An error if the address format is invalid
+//
+// Example:
+//
+//	.ToChecksumAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed") // "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+func ToChecksumAddress(addr string) (string, error) {
+	if !IsValidEthAddress(addr) {
+		return "", fmt.Errorf("invalid Ethereum address: %s", addr)
+	}
+
+	lower := strings.ToLower(addr[2:])
+	hash := hex.EncodeToString(ethgo.Keccak256([]byte(lower)))
+
+	result := make([]byte, 0, len(addr))
+	result = append(result, "0x"...)
+	for i := 0; i < len(lower); i++ {
+		c := lower[i]
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			c -= 'a' - 'A'
+		}
+		result = append(result, c)
+	}
+
+	return string(result), nil
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToChecksumAddress(t *testing.T) {
+	// EIP-55 参考向量
+	vectors := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	}
+
+	for _, want := range vectors {
+		for _, input := range []string{want, strings.ToLower(want), "0x" + strings.ToUpper(want[2:])} {
+			got, err := ToChecksumAddress(input)
+			if err != nil {
+				t.Fatalf("ToChecksumAddress(%s) error: %v", input, err)
+			}
+			if got != want {
+				t.Errorf("ToChecksumAddress(%s) = %s, want %s", input, got, want)
+			}
+		}
+	}
+
+	for _, invalid := range []string{"", "0x1234", "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0xZZAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"} {
+		if _, err := ToChecksumAddress(invalid); err == nil {
+			t.Errorf("ToChecksumAddress(%q) expected error", invalid)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/mowind/web3signer-go/internal/jsonrpc"
@@ -179,6 +181,79 @@ func TestEndToEnd_CompleteIntegration(t *testing.T) {
 	}
 }
 
+// TestEndToEnd_SignTransactionHash 验证 eth_signTransaction 返回的哈希与下游广播后返回的哈希一致
+func TestEndToEnd_SignTransactionHash(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	kmsServer := NewMockKMSServer()
+	defer kmsServer.Close()
+
+	downstreamServer := NewMockDownstreamServer()
+	defer downstreamServer.Close()
+
+	kmsServer.AddValidKey("test-key-id")
+	kmsServer.SetAccessKey("test-access-key", "test-secret-key")
+
+	kmsClient := NewMockKMSClient(kmsServer)
+	kmsClient.SetCredentials("test-access-key", "test-secret-key")
+
+	// 与节点一致：交易哈希为原始交易字节的 keccak256
+	downstreamServer.RegisterHandler("eth_sendRawTransaction", func(params json.RawMessage) (interface{}, error) {
+		var args []string
+		if err := json.Unmarshal(params, &args); err != nil || len(args) != 1 {
+			return nil, fmt.Errorf("invalid params")
+		}
+		raw, err := hex.DecodeString(strings.TrimPrefix(args[0], "0x"))
+		if err != nil {
+			return nil, err
+		}
+		return ethgo.BytesToHash(ethgo.Keccak256(raw)).String(), nil
+	})
+
+	testAddress := ethgo.HexToAddress("0x1234567890123456789012345678901234567890")
+	mpcSigner := signer.NewMPCKMSSigner(kmsClient, "test-key-id", testAddress, big.NewInt(1))
+	router := router.NewRouterFactory(logger).CreateRouter(mpcSigner, NewMockDownstreamClient(downstreamServer))
+
+	ts := httptest.NewServer(createTestHandler(router, logger))
+	defer ts.Close()
+
+	for _, txParams := range []map[string]interface{}{
+		{"gasPrice": "0x4a817c800"},
+		{"maxFeePerGas": "0x4a817c800", "maxPriorityFeePerGas": "0x3b9aca00"},
+	} {
+		txParams["from"] = testAddress.String()
+		txParams["to"] = "0x0987654321098765432109876543210987654321"
+		txParams["gas"] = "0x5208"
+		txParams["nonce"] = "0x5"
+
+		resp, err := sendJSONRPCRequest(ts.URL, map[string]interface{}{
+			"jsonrpc": "2.0", "method": "eth_signTransaction", "params": []interface{}{txParams}, "id": 1,
+		})
+		if err != nil {
+			t.Fatalf("eth_signTransaction failed: %v", err)
+		}
+		result, ok := resp.(map[string]interface{})["result"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("eth_signTransaction returned no result: %v", resp)
+		}
+		hash, _ := result["hash"].(string)
+		if tx, _ := result["tx"].(map[string]interface{}); tx["hash"] != hash {
+			t.Errorf("tx.hash = %v, want %s", tx["hash"], hash)
+		}
+
+		resp, err = sendJSONRPCRequest(ts.URL, map[string]interface{}{
+			"jsonrpc": "2.0", "method": "eth_sendRawTransaction", "params": []interface{}{result["raw"]}, "id": 2,
+		})
+		if err != nil {
+			t.Fatalf("eth_sendRawTransaction failed: %v", err)
+		}
+		if sent := resp.(map[string]interface{})["result"]; sent != hash || hash == "" {
+			t.Errorf("eth_sendRawTransaction hash = %v, eth_signTransaction hash = %s", sent, hash)
+		}
+	}
+}
+
 // TestEndToEnd_BatchRequests 批量请求测试
 func TestEndToEnd_BatchRequests(t *testing.T) {
 	// 设置日志
@@ -280,9 +280,11 @@ func (m *MockKMSServer) generateMockSignature(data, keyID string) string {
 	// 返回十六进制编码的65字节签名（以太坊签名格式）
 	signature := make([]byte, 65)
 	copy(signature, hash)
-	for i := len(hash); i < 65; i++ {
+	for i := len(hash); i < 64; i++ {
 		signature[i] = byte(i)
 	}
+	// 恢复 ID 须为 0 或 1
+	signature[64] = hash[0] & 1
 
 	return hex.EncodeToString(signature)
 }